@@ -0,0 +1,111 @@
+// Command cas-import copies clients from the legacy CAS database into CIC.
+//
+// It reads CAS from CAS_DATABASE_URL and writes to the CIC database configured as for the API
+// server (DATABASE_URL or POSTGRES_*). Runs are recorded like those started from
+// POST /api/v1/imports/cas, so either can resume the other's failed run, and share their
+// database lock. Imported customers are tiered by the rules at TIER_RULES_PATH.
+//
+//	cas-import -dry-run -report rejects.csv
+//	cas-import -resume <run-id>
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/amnuaym/cic/go/internal/adapter/cas"
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/api"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/core/service"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/amnuaym/cic/go/internal/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "map and validate clients and record rejects without writing customers")
+	resume := flag.String("resume", "", "continue the unfinished run with this ID from its checkpoint")
+	limit := flag.Int("limit", 0, "stop after this many CAS clients (0 for all)")
+	report := flag.String("report", "", "write the reject report to this CSV file")
+	flag.Parse()
+
+	godotenv.Load()
+
+	opts := ports.ImportOptions{DryRun: *dryRun, Limit: *limit, StartedBy: "cas-import"}
+	if *resume != "" {
+		id, err := uuid.Parse(*resume)
+		if err != nil {
+			log.Fatalf("Invalid -resume run ID: %v", err)
+		}
+		opts.ResumeRunID = &id
+	}
+
+	casURL := os.Getenv("CAS_DATABASE_URL")
+	if casURL == "" {
+		log.Fatal("CAS_DATABASE_URL is not set")
+	}
+	casDB, err := database.Open(casURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to CAS database: %v", err)
+	}
+	defer casDB.Close()
+	db, err := database.NewDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	numberFormat, err := api.CustomerNumberFormatFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid customer number format: %v", err)
+	}
+	tierEngine, err := tiering.Load(os.Getenv("TIER_RULES_PATH"))
+	if err != nil {
+		log.Fatalf("Failed to load tier rules: %v", err)
+	}
+	audit := service.NewAuditService(repository.NewAuditRepository(db))
+	imports := repository.NewImportRepository(db)
+	svc := service.NewCASImportService(cas.NewSource(casDB), imports, repository.NewExternalRefRepository(db),
+		service.NewCustomerNumberService(repository.NewCustomerRepository(db), numberFormat, audit),
+		service.NewTierService(repository.NewTierRepository(db), tierEngine, audit), audit)
+
+	// Interrupting stops at the current client; the run is marked FAILED and can be resumed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run, runErr := svc.Run(ctx, opts)
+	if run != nil {
+		log.Printf("Run %s %s: processed=%d imported=%d skipped=%d rejected=%d dry_run=%t",
+			run.ID, run.Status, run.Processed, run.Imported, run.Skipped, run.Rejected, run.DryRun)
+		if *report != "" {
+			if err := writeReport(imports, run.ID, *report); err != nil {
+				log.Printf("Failed to write reject report: %v", err)
+			}
+		}
+	}
+	if runErr != nil {
+		log.Fatalf("Import failed: %v", runErr)
+	}
+}
+
+// writeReport runs even after an interrupt, so it does not use the run context.
+func writeReport(imports ports.ImportRepository, runID uuid.UUID, path string) error {
+	rejects, err := imports.ListRejects(context.Background(), runID)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := cas.WriteRejects(f, rejects); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
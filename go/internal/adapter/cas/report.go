@@ -0,0 +1,20 @@
+package cas
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+)
+
+// WriteRejects writes a reject report as CSV with a header row.
+func WriteRejects(w io.Writer, rejects []*domain.ImportReject) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"cli_num", "reason", "rejected_at"})
+	for _, r := range rejects {
+		cw.Write([]string{r.SourceKey, r.Reason, r.CreatedAt.Format(time.RFC3339)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,186 @@
+// Package cas reads clients from the legacy CAS database migrated to Postgres
+// (scripts/generated/deploy_cas_schema.sql, schema "cas").
+package cas
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/lib/pq"
+)
+
+type Source struct {
+	db *sql.DB
+}
+
+func NewSource(db *sql.DB) *Source {
+	return &Source{db: db}
+}
+
+// Clients returns clients with cli_num after the given one, with their addresses, FATCA
+// nationality and the id numbers recorded in cas.tclient_detail_log. Oracle CHAR padding is
+// trimmed from every text column; the child tables are matched on the stored cli_num as is so
+// their cli_num indexes stay usable.
+func (s *Source) Clients(ctx context.Context, after string, limit int) ([]*domain.CASClient, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cli_num, cli_nm, cli_title, birth_dt, sex_code, id_typ, id_num,
+		       reg_addr_typ, res_addr_typ, offce_addr_typ, bill_addr_typ
+		FROM cas.tclient_details
+		WHERE cli_num > $1
+		ORDER BY cli_num
+		LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []*domain.CASClient
+	var nums []string
+	byNum := map[string]*domain.CASClient{}
+	for rows.Next() {
+		c := &domain.CASClient{}
+		var title, sex, idType, idNum sql.NullString
+		var birth sql.NullTime
+		var reg, res, office, bill sql.NullInt64
+		if err := rows.Scan(&c.CliNum, &c.Name, &title, &birth, &sex, &idType, &idNum,
+			&reg, &res, &office, &bill); err != nil {
+			return nil, err
+		}
+		nums = append(nums, c.CliNum)
+		byNum[c.CliNum] = c
+		c.CliNum = strings.TrimSpace(c.CliNum)
+		c.Name = strings.TrimSpace(c.Name)
+		c.Title = strings.TrimSpace(title.String)
+		c.SexCode = strings.TrimSpace(sex.String)
+		c.IDType = strings.TrimSpace(idType.String)
+		c.IDNumber = strings.TrimSpace(idNum.String)
+		if birth.Valid {
+			c.BirthDate = &birth.Time
+		}
+		c.RegAddrType = nullInt(reg)
+		c.ResAddrType = nullInt(res)
+		c.OfficeAddrType = nullInt(office)
+		c.BillingAddrType = nullInt(bill)
+		clients = append(clients, c)
+	}
+	if err := rows.Err(); err != nil || len(clients) == 0 {
+		return clients, err
+	}
+
+	if err := s.addresses(ctx, nums, byNum); err != nil {
+		return nil, err
+	}
+	if err := s.fatca(ctx, nums, byNum); err != nil {
+		return nil, err
+	}
+	if err := s.previousIDs(ctx, nums, byNum); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (s *Source) addresses(ctx context.Context, nums []string, byNum map[string]*domain.CASClient) error {
+	addrRows, err := s.db.QueryContext(ctx, `
+		SELECT cli_num, addr_typ, addr_1, addr_2, addr_3, addr_4, zip_code, invalid_addr_ind
+		FROM cas.tclient_addresses
+		WHERE cli_num = ANY($1)
+		ORDER BY cli_num, addr_typ`, pq.Array(nums))
+	if err != nil {
+		return err
+	}
+	defer addrRows.Close()
+
+	for addrRows.Next() {
+		var cliNum, line1 string
+		var a domain.CASAddress
+		var line2, line3, line4, zip, invalid sql.NullString
+		if err := addrRows.Scan(&cliNum, &a.AddrType, &line1, &line2, &line3, &line4, &zip, &invalid); err != nil {
+			return err
+		}
+		a.Lines = [4]string{strings.TrimSpace(line1), strings.TrimSpace(line2.String),
+			strings.TrimSpace(line3.String), strings.TrimSpace(line4.String)}
+		a.ZipCode = strings.TrimSpace(zip.String)
+		a.Invalid = strings.TrimSpace(invalid.String) == "Y"
+		if c, ok := byNum[cliNum]; ok {
+			c.Addresses = append(c.Addresses, a)
+		}
+	}
+	return addrRows.Err()
+}
+
+// fatca fills Nationality and Country from cas.tclient_detail_fatca. CAS defaults both columns
+// to '1' when they were never captured, which is treated as unknown.
+func (s *Source) fatca(ctx context.Context, nums []string, byNum map[string]*domain.CASClient) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cli_num, nationality, country
+		FROM cas.tclient_detail_fatca
+		WHERE cli_num = ANY($1)`, pq.Array(nums))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cliNum string
+		var nationality, country sql.NullString
+		if err := rows.Scan(&cliNum, &nationality, &country); err != nil {
+			return err
+		}
+		if c, ok := byNum[cliNum]; ok {
+			c.Nationality = fatcaValue(nationality)
+			c.Country = fatcaValue(country)
+		}
+	}
+	return rows.Err()
+}
+
+// previousIDs collects the id numbers a client held before the current one from
+// cas.tclient_detail_log, oldest first, with the date each was superseded.
+func (s *Source) previousIDs(ctx context.Context, nums []string, byNum map[string]*domain.CASClient) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cli_num, id_num, MAX(exp_date)
+		FROM cas.tclient_detail_log
+		WHERE cli_num = ANY($1) AND id_num IS NOT NULL
+		GROUP BY cli_num, id_num
+		ORDER BY cli_num, MAX(exp_date)`, pq.Array(nums))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cliNum, idNum string
+		var expired sql.NullTime
+		if err := rows.Scan(&cliNum, &idNum, &expired); err != nil {
+			return err
+		}
+		c, ok := byNum[cliNum]
+		idNum = strings.TrimSpace(idNum)
+		if !ok || idNum == "" || idNum == c.IDNumber {
+			continue
+		}
+		id := domain.CASPreviousID{Number: idNum}
+		if expired.Valid {
+			id.ExpiredAt = &expired.Time
+		}
+		c.PreviousIDs = append(c.PreviousIDs, id)
+	}
+	return rows.Err()
+}
+
+func fatcaValue(v sql.NullString) string {
+	if s := strings.TrimSpace(v.String); s != "1" {
+		return s
+	}
+	return ""
+}
+
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// AccessGrantHandler handles break-the-glass access requests for protected customers
+type AccessGrantHandler struct {
+	service ports.AccessGrantService
+}
+
+func NewAccessGrantHandler(service ports.AccessGrantService) *AccessGrantHandler {
+	return &AccessGrantHandler{service: service}
+}
+
+// @Summary Request access to a protected customer
+// @Description Request a time-limited access grant for a high-value customer. Customers that are not protected are refused with 409.
+// @Tags access-requests
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Customer ID"
+// @Success 201 {object} domain.AccessGrant
+// @Router /api/v1/customers/{id}/access-requests [post]
+func (h *AccessGrantHandler) RequestAccess(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	g, err := h.service.RequestAccess(r.Context(), customerID, userID, req.Reason)
+	if err != nil {
+		writeAccessGrantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(g)
+}
+
+// @Summary Approve an access request
+// @Description Approve a pending access grant (requester's supervisor or ADMIN+)
+// @Tags access-requests
+// @Produce  json
+// @Param grantId path string true "Access grant ID"
+// @Success 200 {object} domain.AccessGrant
+// @Router /api/v1/access-requests/{grantId}/approve [post]
+func (h *AccessGrantHandler) ApproveAccess(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.ApproveAccess)
+}
+
+// @Summary Reject an access request
+// @Description Reject a pending access grant (requester's supervisor or ADMIN+)
+// @Tags access-requests
+// @Produce  json
+// @Param grantId path string true "Access grant ID"
+// @Success 200 {object} domain.AccessGrant
+// @Router /api/v1/access-requests/{grantId}/reject [post]
+func (h *AccessGrantHandler) RejectAccess(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.RejectAccess)
+}
+
+func (h *AccessGrantHandler) decide(
+	w http.ResponseWriter, r *http.Request,
+	fn func(ctx context.Context, grantID, approverID uuid.UUID, role string) (*domain.AccessGrant, error),
+) {
+	vars := mux.Vars(r)
+	grantID, err := uuid.Parse(vars["grantId"])
+	if err != nil {
+		http.Error(w, "Invalid access grant ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	g, err := fn(r.Context(), grantID, userID, claims.Role)
+	if err != nil {
+		writeAccessGrantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g)
+}
+
+// @Summary Access grant report
+// @Description List access grants with usage counts, optionally filtered by customer or user
+// @Tags access-requests
+// @Produce  json
+// @Param customer_id query string false "Filter by customer ID"
+// @Param user_id query string false "Filter by requesting user ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.AccessGrant
+// @Router /api/v1/access-requests [get]
+func (h *AccessGrantHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	var customerID, userID *uuid.UUID
+	if v := r.URL.Query().Get("customer_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid customer_id", http.StatusBadRequest)
+			return
+		}
+		customerID = &id
+	}
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid user_id", http.StatusBadRequest)
+			return
+		}
+		userID = &id
+	}
+
+	grants, err := h.service.ListGrants(r.Context(), customerID, userID, limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if grants == nil {
+		grants = []*domain.AccessGrant{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grants)
+}
+
+func writeAccessGrantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
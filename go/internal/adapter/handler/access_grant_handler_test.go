@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func TestRequestAccess_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("customer %w", domain.ErrNotFound), http.StatusNotFound},
+		{domain.ErrForbidden, http.StatusForbidden},
+		{fmt.Errorf("%w: customer is not protected", domain.ErrConflict), http.StatusConflict},
+		{fmt.Errorf("reason is required"), http.StatusBadRequest},
+	} {
+		h := NewAccessGrantHandler(&mockAccessGrantService{err: tc.err})
+		id := uuid.New()
+		req, _ := http.NewRequest("POST", "/api/v1/customers/"+id.String()+"/access-requests", strings.NewReader(`{"reason":"claim"}`))
+		req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+		claims := &auth.JWTClaims{UserID: uuid.New().String(), Role: middleware.RoleOperator}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+
+		rr := httptest.NewRecorder()
+		h.RequestAccess(rr, req)
+		if rr.Code != tc.want {
+			t.Errorf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+		}
+	}
+}
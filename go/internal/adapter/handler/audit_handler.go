@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/amnuaym/cic/go/internal/adapter/repository"
 	"github.com/google/uuid"
@@ -21,35 +20,37 @@ func NewAuditLogHandler(repo *repository.AuditRepository) *AuditLogHandler {
 
 // ListAuditLogs returns paginated audit logs
 // @Summary List audit logs
-// @Description List audit logs with optional action filter
+// @Description List audit logs with optional action filter. Send cursor (empty for the first page) to page by
+// @Description cursor and get a {items, next_cursor, prev_cursor} envelope; otherwise offset paging returns an array.
 // @Tags audit-logs
 // @Produce json
 // @Param limit query int false "Limit" default(50)
 // @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page"
 // @Param action query string false "Filter by action (CREATE, UPDATE, DELETE, RESTORE, ANONYMIZE)"
 // @Success 200 {array} repository.AuditLog
 // @Router /api/v1/audit-logs [get]
 func (h *AuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 {
-		limit = 50
+	p, cursorMode, err := pageRequestFromQuery(r.URL.Query(), 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 	action := r.URL.Query().Get("action")
 	entityID := r.URL.Query().Get("entity_id")
 
-	logs, err := h.repo.List(r.Context(), limit, offset, action, entityID)
+	page, err := h.repo.List(r.Context(), p, action, entityID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if logs == nil {
-		logs = []*repository.AuditLog{}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(logs)
+	if cursorMode {
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+	json.NewEncoder(w).Encode(page.Items)
 }
 
 // GetAuditLog returns a single audit log by ID
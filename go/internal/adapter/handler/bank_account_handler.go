@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// BankAccountHandler handles customer bank accounts. Account numbers are masked in every
+// response except Reveal.
+type BankAccountHandler struct {
+	service ports.BankAccountService
+}
+
+func NewBankAccountHandler(service ports.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{service: service}
+}
+
+// @Summary List bank accounts
+// @Description Account numbers are masked to their last four digits, whichever fields are selected
+// @Tags bank-accounts
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param fields query string false "Comma-separated fields to return"
+// @Success 200 {array} domain.BankAccount
+// @Router /api/v1/customers/{id}/bank-accounts [get]
+func (h *BankAccountHandler) ListBankAccounts(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	fields, ok := fieldsFromQuery(w, r, domain.BankAccount{}, nil)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.service.List(r.Context(), customerID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if accounts == nil {
+		accounts = []*domain.BankAccount{}
+	}
+
+	writeFields(w, accounts, fields)
+}
+
+// @Summary Add bank account
+// @Description bank_code is the Bank of Thailand three-digit code, e.g. "014" for SCB. Separators in account_number are ignored.
+// @Tags bank-accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param account body domain.BankAccount true "Bank account"
+// @Success 201 {object} domain.BankAccount
+// @Failure 409 {string} string "Bank account already exists"
+// @Router /api/v1/customers/{id}/bank-accounts [post]
+func (h *BankAccountHandler) AddBankAccount(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var b domain.BankAccount
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	b.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Add(r.Context(), &b, userID); err != nil {
+		writeBankAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(b)
+}
+
+// @Summary Update bank account
+// @Description Omit account_number, or send the masked value back, to keep the stored number
+// @Tags bank-accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param accountId path string true "Bank account ID"
+// @Param account body domain.BankAccount true "Bank account"
+// @Success 200 {object} domain.BankAccount
+// @Router /api/v1/customers/{id}/bank-accounts/{accountId} [put]
+func (h *BankAccountHandler) UpdateBankAccount(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := bankAccountIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var b domain.BankAccount
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	b.ID = id
+	b.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Update(r.Context(), &b, userID); err != nil {
+		writeBankAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(b)
+}
+
+// @Summary Remove bank account
+// @Tags bank-accounts
+// @Param id path string true "Customer ID"
+// @Param accountId path string true "Bank account ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/bank-accounts/{accountId} [delete]
+func (h *BankAccountHandler) RemoveBankAccount(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := bankAccountIDs(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Remove(r.Context(), customerID, id, userID); err != nil {
+		writeBankAccountError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Reveal bank account number
+// @Description Returns the full account number. Every reveal is audited against the caller.
+// @Tags bank-accounts
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param accountId path string true "Bank account ID"
+// @Success 200 {object} domain.BankAccount
+// @Router /api/v1/customers/{id}/bank-accounts/{accountId}/reveal [post]
+func (h *BankAccountHandler) RevealBankAccount(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := bankAccountIDs(w, r)
+	if !ok {
+		return
+	}
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	b, err := h.service.Reveal(r.Context(), customerID, id, userID.String())
+	if err != nil {
+		writeBankAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(b)
+}
+
+func bankAccountIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(vars["accountId"])
+	if err != nil {
+		http.Error(w, "Invalid bank account ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return customerID, id, true
+}
+
+func writeBankAccountError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// auditLister is the read side of the audit log used for per-request trails
+type auditLister interface {
+	List(ctx context.Context, p domain.PageRequest, action string, entityID string) (*domain.Page[*repository.AuditLog], error)
+}
+
+// ChangeRequestHandler handles the maker-checker approval endpoints
+type ChangeRequestHandler struct {
+	service ports.ChangeRequestService
+	audit   auditLister
+}
+
+func NewChangeRequestHandler(service ports.ChangeRequestService, audit auditLister) *ChangeRequestHandler {
+	return &ChangeRequestHandler{service: service, audit: audit}
+}
+
+// @Summary List change requests
+// @Description List maker-checker change requests, optionally filtered by status
+// @Tags change-requests
+// @Produce json
+// @Param status query string false "Filter by status (PENDING, APPLIED, REJECTED, EXPIRED, FAILED)"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.ChangeRequest
+// @Router /api/v1/change-requests [get]
+func (h *ChangeRequestHandler) ListChangeRequests(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	requests, err := h.service.List(r.Context(), r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if requests == nil {
+		requests = []*domain.ChangeRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// @Summary Get change request
+// @Tags change-requests
+// @Produce json
+// @Param id path string true "Change request ID"
+// @Success 200 {object} domain.ChangeRequest
+// @Router /api/v1/change-requests/{id} [get]
+func (h *ChangeRequestHandler) GetChangeRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	cr, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cr)
+}
+
+// @Summary Change request audit trail
+// @Description Audit events (submit, approve, reject, apply, expire) for one change request
+// @Tags change-requests
+// @Produce json
+// @Param id path string true "Change request ID"
+// @Success 200 {array} repository.AuditLog
+// @Router /api/v1/change-requests/{id}/audit [get]
+func (h *ChangeRequestHandler) GetChangeRequestAudit(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	logs, err := h.audit.List(r.Context(), domain.PageRequest{Limit: 100}, "", id.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs.Items)
+}
+
+// @Summary Approve change request
+// @Description Approve and apply a pending change request. The maker cannot approve their own request.
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Change request ID"
+// @Success 200 {object} domain.ChangeRequest
+// @Router /api/v1/change-requests/{id}/approve [post]
+func (h *ChangeRequestHandler) ApproveChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Approve)
+}
+
+// @Summary Reject change request
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Change request ID"
+// @Success 200 {object} domain.ChangeRequest
+// @Router /api/v1/change-requests/{id}/reject [post]
+func (h *ChangeRequestHandler) RejectChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Reject)
+}
+
+func (h *ChangeRequestHandler) decide(
+	w http.ResponseWriter, r *http.Request,
+	fn func(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error),
+) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// Comment is optional; an empty body is fine.
+	var req struct {
+		Comment string `json:"comment"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	cr, err := fn(r.Context(), id, userID, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case cr != nil && cr.Status == domain.ChangeFailed:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(cr)
+		default:
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cr)
+}
+
+// submitChangeRequest records a sensitive operation for approval and answers 202 Accepted.
+func submitChangeRequest(w http.ResponseWriter, r *http.Request, svc ports.ChangeRequestService,
+	op domain.ChangeOperation, customerID, makerID uuid.UUID, payload interface{}) {
+	cr, err := svc.Submit(r.Context(), op, customerID, makerID, payload, r.URL.Query().Get("reason"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, domain.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(cr)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// currentUser extracts the authenticated user's ID and claims set by the JWTAuth middleware.
+func currentUser(r *http.Request) (uuid.UUID, *auth.JWTClaims, error) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*auth.JWTClaims)
+	if !ok || claims == nil {
+		return uuid.Nil, nil, errors.New("Unauthorized")
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, nil, errors.New("Invalid user ID in token")
+	}
+	return userID, claims, nil
+}
+
+// isAdminRole reports whether the role may act on records owned by other users.
+func isAdminRole(role string) bool {
+	return role == middleware.RoleAdmin || role == middleware.RoleSuperAdmin
+}
@@ -13,7 +13,7 @@ import (
 
 // consentLister is the interface for listing all consents
 type consentLister interface {
-	ListAll(ctx context.Context, limit, offset int) ([]*domain.Consent, error)
+	ListAll(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Consent], error)
 }
 
 // ConsentHandler handles top-level consent endpoints (cross-customer)
@@ -27,37 +27,35 @@ func NewConsentHandler(repo consentLister) *ConsentHandler {
 
 // ListConsents returns all consents across all customers
 // @Summary List all consents
-// @Description List all consent records across all customers with pagination
+// @Description List all consent records across all customers with pagination. Send cursor (empty for the first page)
+// @Description to page by cursor and get a {items, next_cursor, prev_cursor} envelope; otherwise offset paging returns an array.
 // @Tags consents
 // @Produce json
 // @Param limit query int false "Limit" default(50)
 // @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page"
 // @Success 200 {array} domain.Consent
 // @Router /api/v1/consents [get]
 func (h *ConsentHandler) ListConsents(w http.ResponseWriter, r *http.Request) {
-	// Parse pagination from query params
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit <= 0 {
-		limit = 50
+	p, cursorMode, err := pageRequestFromQuery(r.URL.Query(), 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 
 	// Check for 'q' query param (search term)
 	searchQuery := r.URL.Query().Get("q")
 
-	var consents []*domain.Consent
-	var err error
-
+	page := &domain.Page[*domain.Consent]{Items: []*domain.Consent{}}
 	if searchQuery == "" {
 		// No search query => return empty list (search-first UX)
-		consents = []*domain.Consent{}
 	} else if searchQuery == "*" {
 		// Wildcard => return all
-		consents, err = h.repo.ListAll(r.Context(), limit, offset)
+		page, err = h.repo.ListAll(r.Context(), p)
 	} else {
 		// Topic search/filter (simple implementation for now using ListAll as placeholder)
 		// In a real scenario, this would call h.repo.Search(searchQuery)
-		consents, err = h.repo.ListAll(r.Context(), limit, offset)
+		page, err = h.repo.ListAll(r.Context(), p)
 	}
 
 	if err != nil {
@@ -65,14 +63,14 @@ func (h *ConsentHandler) ListConsents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if consents == nil {
-		consents = []*domain.Consent{}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Total-Count", strconv.Itoa(len(consents)))
+	if cursorMode {
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(page.Items)))
 	w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
-	json.NewEncoder(w).Encode(consents)
+	json.NewEncoder(w).Encode(page.Items)
 }
 
 // GetConsent returns a single consent by ID
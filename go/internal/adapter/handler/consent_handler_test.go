@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// memConsents pages newest first the way the repository's keyset queries do.
+type memConsents struct {
+	rows []*domain.Consent // Newest first
+}
+
+func (m *memConsents) ListAll(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Consent], error) {
+	key := func(c *domain.Consent) domain.PageCursor { return domain.PageCursor{At: c.Timestamp, ID: c.ID} }
+	var rows []*domain.Consent
+	switch {
+	case p.Cursor == nil:
+		for i := p.Offset; i < len(m.rows) && len(rows) <= p.Limit; i++ {
+			rows = append(rows, m.rows[i])
+		}
+	case p.Cursor.Before:
+		for i := len(m.rows) - 1; i >= 0 && len(rows) <= p.Limit; i-- {
+			if m.rows[i].Timestamp.After(p.Cursor.At) {
+				rows = append(rows, m.rows[i])
+			}
+		}
+	default:
+		for _, c := range m.rows {
+			if c.Timestamp.Before(p.Cursor.At) && len(rows) <= p.Limit {
+				rows = append(rows, c)
+			}
+		}
+	}
+	return domain.NewPage(rows, p, key), nil
+}
+
+func TestListConsents_CursorPaging(t *testing.T) {
+	repo := &memConsents{}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 5; i > 0; i-- {
+		repo.rows = append(repo.rows, &domain.Consent{ID: uuid.New(), Topic: "MARKETING", Timestamp: start.Add(time.Duration(i) * time.Hour)})
+	}
+	h := NewConsentHandler(repo)
+
+	get := func(query string) (int, domain.Page[*domain.Consent]) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/consents?q=*&limit=2&"+query, nil)
+		h.ListConsents(rr, req)
+		var page domain.Page[*domain.Consent]
+		json.NewDecoder(rr.Body).Decode(&page)
+		return rr.Code, page
+	}
+
+	_, first := get("cursor=")
+	if len(first.Items) != 2 || first.Items[0].ID != repo.rows[0].ID || first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("Unexpected first page: %+v", first)
+	}
+	_, second := get("cursor=" + url.QueryEscape(first.NextCursor))
+	if len(second.Items) != 2 || second.Items[0].ID != repo.rows[2].ID || second.NextCursor == "" || second.PrevCursor == "" {
+		t.Fatalf("Unexpected second page: %+v", second)
+	}
+	_, last := get("cursor=" + url.QueryEscape(second.NextCursor))
+	if len(last.Items) != 1 || last.Items[0].ID != repo.rows[4].ID || last.NextCursor != "" {
+		t.Fatalf("Unexpected last page: %+v", last)
+	}
+	_, back := get("cursor=" + url.QueryEscape(second.PrevCursor))
+	if len(back.Items) != 2 || back.Items[0].ID != repo.rows[0].ID || back.Items[1].ID != repo.rows[1].ID || back.PrevCursor != "" {
+		t.Fatalf("Expected prev_cursor to lead back to the first page, got %+v", back)
+	}
+
+	if code, _ := get("cursor=not-a-cursor"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad cursor, got %d", code)
+	}
+
+	// Offset mode still answers with a bare array.
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/consents?q=*&limit=2&offset=4", nil)
+	h.ListConsents(rr, req)
+	var consents []*domain.Consent
+	if err := json.NewDecoder(rr.Body).Decode(&consents); err != nil || len(consents) != 1 {
+		t.Errorf("Expected an array with the fifth consent, got %v, %v", consents, err)
+	}
+}
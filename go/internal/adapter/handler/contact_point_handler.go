@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ContactPointHandler handles customer phones, emails and LINE IDs
+type ContactPointHandler struct {
+	service ports.ContactPointService
+	access  ports.AccessGrantService
+}
+
+// NewContactPointHandler builds the handler. Customers found by contact are withheld like a
+// list's when the caller may not read them; a nil access service withholds nothing.
+func NewContactPointHandler(service ports.ContactPointService, access ports.AccessGrantService) *ContactPointHandler {
+	return &ContactPointHandler{service: service, access: access}
+}
+
+// @Summary List contact points
+// @Tags contact-points
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Success 200 {array} domain.ContactPoint
+// @Router /api/v1/customers/{id}/contact-points [get]
+func (h *ContactPointHandler) ListContactPoints(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	cps, err := h.service.List(r.Context(), customerID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if cps == nil {
+		cps = []*domain.ContactPoint{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cps)
+}
+
+// @Summary Add contact point
+// @Description Phone numbers are stored in E.164; national numbers are read as Thai. New contact points start UNVERIFIED.
+// @Tags contact-points
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param contact body domain.ContactPoint true "Contact point"
+// @Success 201 {object} domain.ContactPoint
+// @Failure 409 {string} string "Contact point already exists"
+// @Router /api/v1/customers/{id}/contact-points [post]
+func (h *ContactPointHandler) AddContactPoint(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var cp domain.ContactPoint
+	if err := json.NewDecoder(r.Body).Decode(&cp); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	cp.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Add(r.Context(), &cp, userID); err != nil {
+		writeContactPointError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cp)
+}
+
+// @Summary Update contact point
+// @Description Change the value or primary flag. A new value must be verified again.
+// @Tags contact-points
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param contactId path string true "Contact point ID"
+// @Param contact body domain.ContactPoint true "Contact point"
+// @Success 200 {object} domain.ContactPoint
+// @Router /api/v1/customers/{id}/contact-points/{contactId} [put]
+func (h *ContactPointHandler) UpdateContactPoint(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := contactPointIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var cp domain.ContactPoint
+	if err := json.NewDecoder(r.Body).Decode(&cp); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	cp.ID = id
+	cp.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Update(r.Context(), &cp, userID); err != nil {
+		writeContactPointError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cp)
+}
+
+// @Summary Remove contact point
+// @Tags contact-points
+// @Param id path string true "Customer ID"
+// @Param contactId path string true "Contact point ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/contact-points/{contactId} [delete]
+func (h *ContactPointHandler) RemoveContactPoint(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := contactPointIDs(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Remove(r.Context(), customerID, id, userID); err != nil {
+		writeContactPointError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Send verification code
+// @Description Sends a one-time code to a mobile number or email address, replacing any outstanding code
+// @Tags contact-points
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param contactId path string true "Contact point ID"
+// @Success 202 {object} domain.ContactPoint
+// @Failure 409 {string} string "A code was sent recently"
+// @Router /api/v1/customers/{id}/contact-points/{contactId}/verify [post]
+func (h *ContactPointHandler) StartVerification(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := contactPointIDs(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	cp, err := h.service.StartVerification(r.Context(), customerID, id, userID)
+	if err != nil {
+		writeContactPointError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(cp)
+}
+
+// @Summary Confirm verification code
+// @Tags contact-points
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param contactId path string true "Contact point ID"
+// @Param body body object true "{\"code\": \"123456\"}"
+// @Success 200 {object} domain.ContactPoint
+// @Failure 422 {string} string "Wrong, expired or exhausted code"
+// @Router /api/v1/customers/{id}/contact-points/{contactId}/verify/confirm [post]
+func (h *ContactPointHandler) ConfirmVerification(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := contactPointIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	cp, err := h.service.ConfirmVerification(r.Context(), customerID, id, req.Code, userID)
+	if err != nil {
+		writeContactPointError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cp)
+}
+
+// @Summary Find customers by phone or email
+// @Description Exact match on the normalized value; give either phone or email. Protected customers the caller may not read are returned withheld.
+// @Tags customers
+// @Produce json
+// @Param phone query string false "Phone number (national or international format)"
+// @Param email query string false "Email address"
+// @Success 200 {array} domain.Customer
+// @Router /api/v1/customers/by-contact [get]
+func (h *ContactPointHandler) FindCustomers(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.service.FindCustomers(r.Context(), r.URL.Query().Get("phone"), r.URL.Query().Get("email"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := withholdCustomers(r, h.access, customers); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+func contactPointIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(vars["contactId"])
+	if err != nil {
+		http.Error(w, "Invalid contact point ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return customerID, id, true
+}
+
+func writeContactPointError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrVerificationFailed):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrVerificationUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// mockContactPointService answers FindCustomers; the other methods are not used here.
+type mockContactPointService struct {
+	ports.ContactPointService
+	findFunc func(ctx context.Context, phone, email string) ([]*domain.Customer, error)
+}
+
+func (m *mockContactPointService) FindCustomers(ctx context.Context, phone, email string) ([]*domain.Customer, error) {
+	return m.findFunc(ctx, phone, email)
+}
+
+func TestFindCustomersByContact_WithholdsProtected(t *testing.T) {
+	svc := &mockContactPointService{
+		findFunc: func(ctx context.Context, phone, email string) ([]*domain.Customer, error) {
+			return []*domain.Customer{
+				{ID: uuid.New(), FirstName: "สมชาย"},
+				{ID: uuid.New(), FirstName: "สมศักดิ์", IsHighValue: true},
+			}, nil
+		},
+	}
+	h := NewContactPointHandler(svc, &mockAccessGrantService{})
+
+	req, _ := http.NewRequest("GET", "/api/v1/customers/by-contact?phone=0812345678", nil)
+	rr := httptest.NewRecorder()
+	h.FindCustomers(rr, req)
+
+	var got []domain.Customer
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil || len(got) != 2 {
+		t.Fatalf("Expected two results, got %d: %v", len(got), err)
+	}
+	if got[0].FirstName != "สมชาย" {
+		t.Errorf("Readable customer must be shown in full: %+v", got[0])
+	}
+	if got[1].FirstName != "" || !got[1].IsHighValue {
+		t.Errorf("Expected the protected customer to be withheld, got %+v", got[1])
+	}
+}
+
+func TestFindCustomersByContact_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("%w: phone or email is required", domain.ErrInvalidSearch), http.StatusBadRequest},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	} {
+		svc := &mockContactPointService{
+			findFunc: func(ctx context.Context, phone, email string) ([]*domain.Customer, error) {
+				return nil, tc.err
+			},
+		}
+		h := NewContactPointHandler(svc, &mockAccessGrantService{})
+
+		req, _ := http.NewRequest("GET", "/api/v1/customers/by-contact", nil)
+		rr := httptest.NewRecorder()
+		h.FindCustomers(rr, req)
+		if rr.Code != tc.want {
+			t.Errorf("%v: expected %d, got %d", tc.err, tc.want, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Customer360Handler serves the aggregated customer screen
+type Customer360Handler struct {
+	service ports.Customer360Service
+}
+
+func NewCustomer360Handler(service ports.Customer360Service) *Customer360Handler {
+	return &Customer360Handler{service: service}
+}
+
+// @Summary Customer 360 view
+// @Description Customer with addresses, identities, relationships (with related-customer summaries), consents and recent audit in one call.
+// @Description Sections that fail to load are listed under "errors" without failing the response.
+// @Tags customers
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param include query string false "Comma-separated sections: addresses,identities,relationships,consents,audit (default all)"
+// @Success 200 {object} domain.Customer360
+// @Router /api/v1/customers/{id}/360 [get]
+func (h *Customer360Handler) GetCustomer360(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var include []string
+	for _, v := range r.URL.Query()["include"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				include = append(include, s)
+			}
+		}
+	}
+	if _, err := domain.ParseSections360(include); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.service.Get360(r.Context(), id, include)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/amnuaym/cic/go/internal/auth"
 	"github.com/amnuaym/cic/go/internal/core/domain"
@@ -15,51 +21,272 @@ import (
 )
 
 type CustomerHandler struct {
-	service ports.CustomerService
+	service   ports.CustomerService
+	approvals ports.ChangeRequestService
+	access    ports.AccessGrantService
 }
 
-func NewCustomerHandler(service ports.CustomerService) *CustomerHandler {
-	return &CustomerHandler{service: service}
+// CustomerHandlerOption configures optional CustomerHandler behaviour
+type CustomerHandlerOption func(*CustomerHandler)
+
+// WithApprovals routes sensitive operations (delete, restore, anonymize, blacklist)
+// through the maker-checker workflow instead of applying them immediately.
+func WithApprovals(approvals ports.ChangeRequestService) CustomerHandlerOption {
+	return func(h *CustomerHandler) {
+		h.approvals = approvals
+	}
+}
+
+// WithAccessGrants withholds protected customers in lists and searches from users who may not
+// read them, showing only Customer.Withheld.
+func WithAccessGrants(access ports.AccessGrantService) CustomerHandlerOption {
+	return func(h *CustomerHandler) {
+		h.access = access
+	}
+}
+
+func NewCustomerHandler(service ports.CustomerService, opts ...CustomerHandlerOption) *CustomerHandler {
+	h := &CustomerHandler{service: service}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // @Summary List customers
-// @Description List customers with pagination
+// @Description Filter, sort and page customers. With neither q nor a filter nothing is returned (search first); q=* lists everyone.
+// @Description With a cursor parameter the body is a domain.CustomerPage envelope with next_cursor and prev_cursor instead of an array.
+// @Description Protected customers the caller may not read are returned withheld. List filters take comma-separated values. Dates are RFC3339 or YYYY-MM-DD (UTC); ranges include from and exclude to.
 // @Tags customers
 // @Produce  json
+// @Param q query string false "Name or customer number, or a typed search as for /customers/search; * for all"
+// @Param mode query string false "fuzzy matches names across Thai and romanized spellings, ignoring titles and tone marks, best match first" Enums(exact, fuzzy)
+// @Param type query string false "PERSONAL, JURISTIC"
+// @Param status query string false "ACTIVE, INACTIVE, SUSPENDED, DECEASED, BLACKLISTED"
+// @Param tier query string false "Membership tiers"
+// @Param nationality query string false "Nationalities"
+// @Param high_value query bool false "High-value flag"
+// @Param created_from query string false "Created at or after"
+// @Param created_to query string false "Created before"
+// @Param updated_from query string false "Updated at or after"
+// @Param updated_to query string false "Updated before"
+// @Param last_transaction_from query string false "Last transaction at or after"
+// @Param last_transaction_to query string false "Last transaction before"
+// @Param sort query string false "Comma-separated fields, - for descending; score only with mode=fuzzy" default(-created_at)
+// @Param limit query int false "Page size, at most 200. Without a cursor it defaults to 100 for q=* and 20 for a search term, as before filters existed; otherwise 50"
+// @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "Page by cursor instead of offset: empty for the first page, then next_cursor or prev_cursor. Needs the default sort"
+// @Param deleted query bool false "List soft-deleted customers instead"
+// @Param fields query string false "Comma-separated customer fields to return, e.g. id,first_name,status; only these columns are read"
 // @Success 200 {array} domain.Customer
+// @Header 200 {integer} X-Total-Count "Customers matching in total"
 // @Router /api/v1/customers [get]
 func (h *CustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
-	// Check for 'deleted' query param
-	showDeleted := r.URL.Query().Get("deleted") == "true"
-
-	// Check for 'q' query param (search term)
-	searchQuery := r.URL.Query().Get("q")
-
-	var customers []*domain.Customer
-	var err error
+	v := r.URL.Query()
+	var page *domain.CustomerPage
+	fields, ok := fieldsFromQuery(w, r, domain.Customer{}, nil)
+	if !ok {
+		return
+	}
 
-	if showDeleted {
-		customers, err = h.service.ListDeletedCustomers(r.Context(), 100, 0)
-	} else if searchQuery == "" {
-		// No search query => return empty list (search-first UX)
-		customers = []*domain.Customer{}
-	} else if searchQuery == "*" {
-		// Wildcard => return all
-		customers, err = h.service.ListCustomers(r.Context(), 100, 0)
+	if v.Get("deleted") == "true" {
+		p, _, err := pageRequestFromQuery(v, 100)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		deleted, err := h.service.ListDeletedCustomers(r.Context(), p)
+		if err != nil {
+			log.Printf("ERROR ListCustomers: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		total, err := h.service.CountDeletedCustomers(r.Context())
+		if err != nil {
+			log.Printf("ERROR ListCustomers: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page = &domain.CustomerPage{Customers: deleted.Items, TotalCount: total,
+			NextCursor: deleted.NextCursor, PrevCursor: deleted.PrevCursor}
 	} else {
-		// Search by term
-		customers, err = h.service.SearchCustomers(r.Context(), searchQuery)
+		q, err := customerQueryFromRequest(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q.Fields = fields
+		if q.Filter.IsEmpty() && v.Get("q") != "*" {
+			// No search term or filter => return empty list (search-first UX)
+			page = &domain.CustomerPage{Customers: []*domain.Customer{}}
+		} else if page, err = h.service.QueryCustomers(r.Context(), q); err != nil {
+			log.Printf("ERROR ListCustomers: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	withheld, err := h.withhold(r, page.Customers)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	items, err := projectCustomers(page.Customers, withheld, fields)
 	if err != nil {
-		log.Printf("ERROR ListCustomers: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Total-Count", fmt.Sprintf("%d", len(customers)))
+	w.Header().Set("X-Total-Count", fmt.Sprintf("%d", page.TotalCount))
 	w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
-	json.NewEncoder(w).Encode(customers)
+	if v.Has("cursor") {
+		json.NewEncoder(w).Encode(projectedCustomerPage{Items: items, TotalCount: page.TotalCount,
+			NextCursor: page.NextCursor, PrevCursor: page.PrevCursor})
+		return
+	}
+	json.NewEncoder(w).Encode(items)
+}
+
+// projectedCustomerPage is domain.CustomerPage with each customer already projected to the
+// requested fields.
+type projectedCustomerPage struct {
+	Items      []json.RawMessage `json:"items"`
+	TotalCount int               `json:"total_count"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
+}
+
+// customerQueryFromRequest reads ListCustomers' query parameters into a normalized query.
+func customerQueryFromRequest(v url.Values) (domain.CustomerQuery, error) {
+	q := domain.CustomerQuery{}
+	if text := v.Get("q"); text != "*" {
+		if err := domain.ParseCustomerSearch(text, &q.Filter); err != nil {
+			return q, err
+		}
+	}
+	switch mode := v.Get("mode"); mode {
+	case "", "exact":
+	case "fuzzy":
+		q.Filter.Fuzzy = true
+	default:
+		return q, fmt.Errorf("invalid mode %q, want exact or fuzzy", mode)
+	}
+	for _, t := range listParam(v, "type") {
+		q.Filter.Types = append(q.Filter.Types, domain.CustomerType(t))
+	}
+	for _, st := range listParam(v, "status") {
+		q.Filter.Statuses = append(q.Filter.Statuses, domain.CustomerStatus(st))
+	}
+	q.Filter.Tiers = listParam(v, "tier")
+	q.Filter.Nationalities = listParam(v, "nationality")
+	if hv := v.Get("high_value"); hv != "" {
+		b, err := strconv.ParseBool(hv)
+		if err != nil {
+			return q, fmt.Errorf("invalid high_value %q", hv)
+		}
+		q.Filter.HighValue = &b
+	}
+
+	times := map[string]**time.Time{
+		"created_from":          &q.Filter.CreatedFrom,
+		"created_to":            &q.Filter.CreatedTo,
+		"updated_from":          &q.Filter.UpdatedFrom,
+		"updated_to":            &q.Filter.UpdatedTo,
+		"last_transaction_from": &q.Filter.LastTransactionFrom,
+		"last_transaction_to":   &q.Filter.LastTransactionTo,
+	}
+	for key, dst := range times {
+		raw := v.Get(key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", raw); err != nil {
+				return q, fmt.Errorf("invalid %s %q, want RFC3339 or YYYY-MM-DD", key, raw)
+			}
+		}
+		*dst = &t
+	}
+
+	var err error
+	if q.Sort, err = domain.ParseSort(v.Get("sort")); err != nil {
+		return q, err
+	}
+	q.Limit, _ = strconv.Atoi(v.Get("limit"))
+	if q.Limit <= 0 && !v.Has("cursor") {
+		q.Limit = plainPageSize(v.Get("q"))
+	}
+	q.Offset, _ = strconv.Atoi(v.Get("offset"))
+	if raw := v.Get("cursor"); raw != "" {
+		if q.Cursor, err = domain.DecodePageCursor(raw); err != nil {
+			return q, err
+		}
+	}
+	return q, q.Normalize()
+}
+
+// Default page sizes of the plain array response, which callers relied on before the list took
+// filters and a limit: q=* returned 100 customers and a term search at most 20.
+const (
+	listAllPageSize = 100
+	searchPageSize  = 20
+)
+
+// plainPageSize is the page size of a request without limit or cursor. Queries made only of
+// filters, and cursor pages, use domain.DefaultCustomerPageSize.
+func plainPageSize(text string) int {
+	switch text {
+	case "":
+		return domain.DefaultCustomerPageSize
+	case "*":
+		return listAllPageSize
+	}
+	return searchPageSize
+}
+
+// withhold replaces protected customers the caller may not read with their withheld view.
+func (h *CustomerHandler) withhold(r *http.Request, customers []*domain.Customer) (map[int]bool, error) {
+	return withholdCustomers(r, h.access, customers)
+}
+
+// withholdCustomers is withhold for any handler listing customers. It reports which positions
+// were withheld; a nil access service withholds nothing.
+func withholdCustomers(r *http.Request, access ports.AccessGrantService, customers []*domain.Customer) (map[int]bool, error) {
+	withheld := map[int]bool{}
+	if access == nil {
+		return withheld, nil
+	}
+	// Without a valid user nothing protected can be read.
+	userID, claims, err := currentUser(r)
+	role := ""
+	if err == nil {
+		role = claims.Role
+	}
+	for i, c := range customers {
+		ok, err := access.CanRead(r.Context(), c, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			customers[i] = c.Withheld()
+			withheld[i] = true
+		}
+	}
+	return withheld, nil
+}
+
+// listParam splits comma-separated and repeated values of key.
+func listParam(v url.Values, key string) []string {
+	var out []string
+	for _, raw := range v[key] {
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
 }
 
 // @Summary Create a new customer
@@ -76,8 +303,13 @@ func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.service.CreateCustomer(r.Context(), &c); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.CreateCustomer(r.Context(), &c, userID); err != nil {
+		writeCustomerWriteError(w, err)
 		return
 	}
 
@@ -87,9 +319,11 @@ func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Get a customer
-// @Description Get a customer by ID
+// @Description Get a customer by ID. fields selects what to return and may embed addresses, identities, relationships
+// @Description or consents, whole or by field (addresses.zip_code).
 // @Tags customers
 // @Produce  json
+// @Param fields query string false "Comma-separated fields, e.g. id,first_name,status,addresses.zip_code"
 // @Success 200 {object} domain.Customer
 // @Router /api/v1/customers/{id} [get]
 func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
@@ -99,15 +333,79 @@ func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
+	fields, ok := fieldsFromQuery(w, r, domain.Customer{}, domain.CustomerSubResources)
+	if !ok {
+		return
+	}
 
-	c, err := h.service.GetCustomer(r.Context(), id)
+	c, err := h.service.GetCustomerFields(r.Context(), id, fields)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	if fields == nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c)
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(c)
+	raw, err := project(c, fields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for name := range domain.CustomerSubResources {
+		if !fields.Has(name) {
+			continue
+		}
+		items, err := h.subResource(r.Context(), id, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if body[name], err = project(items, fields.Nested(name)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeFields(w, body, nil)
+}
+
+// subResource loads one of domain.CustomerSubResources for embedding, as an empty list when
+// the customer has none.
+func (h *CustomerHandler) subResource(ctx context.Context, customerID uuid.UUID, name string) (interface{}, error) {
+	switch name {
+	case "addresses":
+		items, err := h.service.GetAddresses(ctx, customerID)
+		if items == nil {
+			items = []*domain.Address{}
+		}
+		return items, err
+	case "identities":
+		items, err := h.service.GetIdentities(ctx, customerID)
+		if items == nil {
+			items = []*domain.Identity{}
+		}
+		return items, err
+	case "relationships":
+		items, err := h.service.GetRelationships(ctx, customerID)
+		if items == nil {
+			items = []*domain.Relationship{}
+		}
+		return items, err
+	case "consents":
+		items, err := h.service.GetConsents(ctx, customerID)
+		if items == nil {
+			items = []*domain.Consent{}
+		}
+		return items, err
+	}
+	return nil, fmt.Errorf("unknown sub-resource %q", name)
 }
 
 // @Summary Update a customer
@@ -116,6 +414,7 @@ func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} domain.Customer
+// @Success 202 {object} domain.ChangeRequest "Other edits applied; status change to BLACKLISTED pending approval"
 // @Router /api/v1/customers/{id} [patch]
 func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -132,8 +431,33 @@ func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request)
 	}
 	c.ID = id
 
-	if err := h.service.UpdateCustomer(r.Context(), &c); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if h.approvals != nil && c.Status == domain.StatusBlacklist {
+		current, err := h.service.GetCustomer(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if current.Status != domain.StatusBlacklist {
+			// Only the status change waits for approval; the rest of the edit applies now.
+			c.Status = current.Status
+			if err := h.service.UpdateCustomer(r.Context(), &c, userID); err != nil {
+				writeCustomerWriteError(w, err)
+				return
+			}
+			submitChangeRequest(w, r, h.approvals, domain.OpBlacklistCustomer, id, userID,
+				domain.StatusChange{From: current.Status, To: domain.StatusBlacklist})
+			return
+		}
+	}
+
+	if err := h.service.UpdateCustomer(r.Context(), &c, userID); err != nil {
+		writeCustomerWriteError(w, err)
 		return
 	}
 
@@ -141,11 +465,22 @@ func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(c)
 }
 
+// writeCustomerWriteError answers 409 for a create or edit that clashes with derived or held
+// data, such as portfolio values sent while holdings drive them.
+func writeCustomerWriteError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
 // @Summary Delete a customer
 // @Description Soft delete a customer by ID
 // @Tags customers
 // @Produce  json
 // @Success 200 {object} map[string]string
+// @Success 202 {object} domain.ChangeRequest "Pending maker-checker approval"
 // @Router /api/v1/customers/{id} [delete]
 func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -161,7 +496,7 @@ func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	if claims.Role != "admin" {
+	if !isAdminRole(claims.Role) {
 		http.Error(w, "Forbidden: Only admins can delete customers", http.StatusForbidden)
 		return
 	}
@@ -172,6 +507,11 @@ func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if h.approvals != nil {
+		submitChangeRequest(w, r, h.approvals, domain.OpDeleteCustomer, id, userID, nil)
+		return
+	}
+
 	if err := h.service.DeleteCustomer(r.Context(), id, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -182,10 +522,12 @@ func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Restore a customer
-// @Description Restore a soft-deleted customer by ID
+// @Description Restore a soft-deleted customer by ID. Records with no recorded deleter
+// @Description need {"justification": "..."} in the body (admin override).
 // @Tags customers
 // @Produce  json
 // @Success 200 {object} map[string]string
+// @Success 202 {object} domain.ChangeRequest "Pending maker-checker approval"
 // @Router /api/v1/customers/{id}/restore [post]
 func (h *CustomerHandler) RestoreCustomer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -207,7 +549,27 @@ func (h *CustomerHandler) RestoreCustomer(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := h.service.RestoreCustomer(r.Context(), id, userID); err != nil {
+	// Optional body: legacy rows with no recorded deleter need an admin justification.
+	var req domain.RestoreOverride
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	if h.approvals != nil {
+		var payload interface{}
+		if req.Justification != "" {
+			payload = req
+		}
+		submitChangeRequest(w, r, h.approvals, domain.OpRestoreCustomer, id, userID, payload)
+		return
+	}
+
+	if req.Justification != "" {
+		err = h.service.OverrideRestoreCustomer(r.Context(), id, userID, req.Justification)
+	} else {
+		err = h.service.RestoreCustomer(r.Context(), id, userID)
+	}
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -217,15 +579,26 @@ func (h *CustomerHandler) RestoreCustomer(w http.ResponseWriter, r *http.Request
 }
 
 // @Summary Search customers
-// @Description Search customers by query parms
+// @Description Typed search, e.g. id:1234567890123 zip:10110 name:somchai. Fields: name, id (identity number), phone, email,
+// @Description zip, province, district; quote values with spaces. Plain words match names or the customer number.
+// @Description Protected customers the caller may not read are returned withheld.
 // @Tags customers
 // @Produce  json
+// @Param q query string false "Search terms"
 // @Success 200 {array} domain.Customer
 // @Router /api/v1/customers/search [get]
 func (h *CustomerHandler) SearchCustomers(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	customers, err := h.service.SearchCustomers(r.Context(), query)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := h.withhold(r, customers); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -251,7 +624,12 @@ func (h *CustomerHandler) AddAddress(w http.ResponseWriter, r *http.Request) {
 	}
 	a.CustomerID = customerID
 
-	if err := h.service.AddAddress(r.Context(), &a); err != nil {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.AddAddress(r.Context(), &a, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -268,6 +646,10 @@ func (h *CustomerHandler) GetAddresses(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
+	fields, ok := fieldsFromQuery(w, r, domain.Address{}, nil)
+	if !ok {
+		return
+	}
 
 	addresses, err := h.service.GetAddresses(r.Context(), customerID)
 	if err != nil {
@@ -275,8 +657,7 @@ func (h *CustomerHandler) GetAddresses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(addresses)
+	writeFields(w, addresses, fields)
 }
 
 // Similar handlers for Identity, Relationship, Consent...
@@ -297,7 +678,12 @@ func (h *CustomerHandler) AddIdentity(w http.ResponseWriter, r *http.Request) {
 	}
 	i.CustomerID = customerID
 
-	if err := h.service.AddIdentity(r.Context(), &i); err != nil {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.AddIdentity(r.Context(), &i, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -314,6 +700,10 @@ func (h *CustomerHandler) GetIdentities(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
+	fields, ok := fieldsFromQuery(w, r, domain.Identity{}, nil)
+	if !ok {
+		return
+	}
 
 	identities, err := h.service.GetIdentities(r.Context(), customerID)
 	if err != nil {
@@ -321,8 +711,7 @@ func (h *CustomerHandler) GetIdentities(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(identities)
+	writeFields(w, identities, fields)
 }
 
 // @Summary Anonymize a customer
@@ -330,6 +719,7 @@ func (h *CustomerHandler) GetIdentities(w http.ResponseWriter, r *http.Request)
 // @Tags customers
 // @Produce  json
 // @Success 204 "No Content"
+// @Success 202 {object} domain.ChangeRequest "Pending maker-checker approval"
 // @Router /api/v1/customers/{id}/anonymize [post]
 func (h *CustomerHandler) AnonymizeCustomer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -339,7 +729,22 @@ func (h *CustomerHandler) AnonymizeCustomer(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := h.service.AnonymizeCustomer(r.Context(), id); err != nil {
+	if h.approvals != nil {
+		userID, _, err := currentUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		submitChangeRequest(w, r, h.approvals, domain.OpAnonymizeCustomer, id, userID, nil)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.AnonymizeCustomer(r.Context(), id, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -347,6 +752,54 @@ func (h *CustomerHandler) AnonymizeCustomer(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Merge a customer
+// @Description Retire a duplicate customer into another: its external references move to the
+// @Description survivor and it is soft-deleted. Body: {"into_id": "<surviving customer ID>"}.
+// @Tags customers
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} map[string]string
+// @Success 202 {object} domain.ChangeRequest "Pending maker-checker approval"
+// @Router /api/v1/customers/{id}/merge [post]
+func (h *CustomerHandler) MergeCustomer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	var req domain.CustomerMerge
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IntoID == uuid.Nil {
+		http.Error(w, "into_id is required", http.StatusBadRequest)
+		return
+	}
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if h.approvals != nil {
+		submitChangeRequest(w, r, h.approvals, domain.OpMergeCustomer, id, userID, req)
+		return
+	}
+
+	if err := h.service.MergeCustomer(r.Context(), id, req.IntoID, userID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Customer merged successfully"})
+}
+
 // --- Relationships ---
 
 // @Summary Add a relationship
@@ -371,7 +824,12 @@ func (h *CustomerHandler) AddRelationship(w http.ResponseWriter, r *http.Request
 	}
 	rel.FromCustomerID = customerID
 
-	if err := h.service.AddRelationship(r.Context(), &rel); err != nil {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.AddRelationship(r.Context(), &rel, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -385,6 +843,7 @@ func (h *CustomerHandler) AddRelationship(w http.ResponseWriter, r *http.Request
 // @Description Get all relationships for a customer
 // @Tags relationships
 // @Produce  json
+// @Param fields query string false "Comma-separated fields to return"
 // @Success 200 {array} domain.Relationship
 // @Router /api/v1/customers/{id}/relationships [get]
 func (h *CustomerHandler) GetRelationships(w http.ResponseWriter, r *http.Request) {
@@ -394,6 +853,10 @@ func (h *CustomerHandler) GetRelationships(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
+	fields, ok := fieldsFromQuery(w, r, domain.Relationship{}, nil)
+	if !ok {
+		return
+	}
 
 	relationships, err := h.service.GetRelationships(r.Context(), customerID)
 	if err != nil {
@@ -401,8 +864,7 @@ func (h *CustomerHandler) GetRelationships(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(relationships)
+	writeFields(w, relationships, fields)
 }
 
 // --- Consents ---
@@ -429,7 +891,12 @@ func (h *CustomerHandler) ManageConsent(w http.ResponseWriter, r *http.Request)
 	}
 	consent.CustomerID = customerID
 
-	if err := h.service.ManageConsent(r.Context(), &consent); err != nil {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.ManageConsent(r.Context(), &consent, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -443,6 +910,7 @@ func (h *CustomerHandler) ManageConsent(w http.ResponseWriter, r *http.Request)
 // @Description Get all consents for a customer
 // @Tags consents
 // @Produce  json
+// @Param fields query string false "Comma-separated fields to return"
 // @Success 200 {array} domain.Consent
 // @Router /api/v1/customers/{id}/consents [get]
 func (h *CustomerHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
@@ -452,6 +920,10 @@ func (h *CustomerHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
+	fields, ok := fieldsFromQuery(w, r, domain.Consent{}, nil)
+	if !ok {
+		return
+	}
 
 	consents, err := h.service.GetConsents(r.Context(), customerID)
 	if err != nil {
@@ -459,6 +931,62 @@ func (h *CustomerHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(consents)
+	writeFields(w, consents, fields)
+}
+
+// --- Sub-resource removal ---
+
+// @Summary Remove an address
+// @Tags customers
+// @Param id path string true "Customer ID"
+// @Param addressId path string true "Address ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/addresses/{addressId} [delete]
+func (h *CustomerHandler) RemoveAddress(w http.ResponseWriter, r *http.Request) {
+	h.removeSubResource(w, r, "addressId", h.service.RemoveAddress)
+}
+
+// @Summary Remove an identity
+// @Tags customers
+// @Param id path string true "Customer ID"
+// @Param identityId path string true "Identity ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/identities/{identityId} [delete]
+func (h *CustomerHandler) RemoveIdentity(w http.ResponseWriter, r *http.Request) {
+	h.removeSubResource(w, r, "identityId", h.service.RemoveIdentity)
+}
+
+// @Summary Remove a relationship
+// @Tags relationships
+// @Param id path string true "Customer ID"
+// @Param relationshipId path string true "Relationship ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/relationships/{relationshipId} [delete]
+func (h *CustomerHandler) RemoveRelationship(w http.ResponseWriter, r *http.Request) {
+	h.removeSubResource(w, r, "relationshipId", h.service.RemoveRelationship)
+}
+
+func (h *CustomerHandler) removeSubResource(w http.ResponseWriter, r *http.Request, param string, remove func(ctx context.Context, customerID, id, userID uuid.UUID) error) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	id, err := uuid.Parse(vars[param])
+	if err != nil {
+		http.Error(w, "Invalid "+param, http.StatusBadRequest)
+		return
+	}
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := remove(r.Context(), customerID, id, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
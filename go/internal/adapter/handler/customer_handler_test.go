@@ -3,10 +3,12 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/amnuaym/cic/go/internal/auth"
 	"github.com/amnuaym/cic/go/internal/core/domain"
@@ -24,7 +26,9 @@ type mockCustomerService struct {
 	restoreFunc            func(ctx context.Context, id, userID uuid.UUID) error
 	searchFunc             func(ctx context.Context, query string) ([]*domain.Customer, error)
 	listFunc               func(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
+	queryFunc              func(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error)
 	listDeletedFunc        func(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
+	deletedCount           int
 	anonymizeFunc          func(ctx context.Context, id uuid.UUID) error
 	addAddressFunc         func(ctx context.Context, a *domain.Address) error
 	getAddressesFunc       func(ctx context.Context, id uuid.UUID) ([]*domain.Address, error)
@@ -40,13 +44,16 @@ type mockCustomerService struct {
 }
 
 // Implement interface methods
-func (m *mockCustomerService) CreateCustomer(ctx context.Context, c *domain.Customer) error {
+func (m *mockCustomerService) CreateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
 	return m.createFunc(ctx, c)
 }
 func (m *mockCustomerService) GetCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
 	return m.getFunc(ctx, id)
 }
-func (m *mockCustomerService) UpdateCustomer(ctx context.Context, c *domain.Customer) error {
+func (m *mockCustomerService) GetCustomerFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error) {
+	return m.getFunc(ctx, id)
+}
+func (m *mockCustomerService) UpdateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
 	return m.updateFunc(ctx, c)
 }
 func (m *mockCustomerService) DeleteCustomer(ctx context.Context, id, userID uuid.UUID) error {
@@ -58,50 +65,119 @@ func (m *mockCustomerService) RestoreCustomer(ctx context.Context, id, userID uu
 	}
 	return nil
 }
+func (m *mockCustomerService) OverrideRestoreCustomer(ctx context.Context, id, userID uuid.UUID, justification string) error {
+	return nil
+}
 func (m *mockCustomerService) SearchCustomers(ctx context.Context, query string) ([]*domain.Customer, error) {
 	return m.searchFunc(ctx, query)
 }
-func (m *mockCustomerService) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	return m.listFunc(ctx, limit, offset)
+func (m *mockCustomerService) ListCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	customers, err := m.listFunc(ctx, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Page[*domain.Customer]{Items: customers}, nil
 }
-func (m *mockCustomerService) ListDeletedCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	if m.listDeletedFunc != nil {
-		return m.listDeletedFunc(ctx, limit, offset)
+
+// QueryCustomers falls back to listFunc or searchFunc so tests written before filtering keep working.
+func (m *mockCustomerService) QueryCustomers(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, q)
 	}
+	var customers []*domain.Customer
+	var err error
+	if q.Filter.Text == "" {
+		customers, err = m.listFunc(ctx, q.Limit, q.Offset)
+	} else {
+		customers, err = m.searchFunc(ctx, q.Filter.Text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CustomerPage{Customers: customers, TotalCount: len(customers)}, nil
+}
+func (m *mockCustomerService) MatchingCustomerIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error) {
 	return nil, nil
 }
-func (m *mockCustomerService) AnonymizeCustomer(ctx context.Context, id uuid.UUID) error {
+func (m *mockCustomerService) ListDeletedCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	page := &domain.Page[*domain.Customer]{Items: []*domain.Customer{}}
+	if m.listDeletedFunc != nil {
+		customers, err := m.listDeletedFunc(ctx, p.Limit, p.Offset)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = customers
+	}
+	return page, nil
+}
+func (m *mockCustomerService) CountDeletedCustomers(ctx context.Context) (int, error) {
+	return m.deletedCount, nil
+}
+func (m *mockCustomerService) ChangeCustomerStatus(ctx context.Context, id uuid.UUID, change domain.StatusChange, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockCustomerService) AnonymizeCustomer(ctx context.Context, id, userID uuid.UUID) error {
 	return m.anonymizeFunc(ctx, id)
 }
+func (m *mockCustomerService) MergeCustomer(ctx context.Context, id, intoID, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockCustomerService) CheckChange(ctx context.Context, op domain.ChangeOperation, id, userID uuid.UUID, justification string) error {
+	return nil
+}
+func (m *mockCustomerService) CheckMerge(ctx context.Context, id, intoID uuid.UUID) error {
+	return nil
+}
 
 // Sub-resources
-func (m *mockCustomerService) AddAddress(ctx context.Context, a *domain.Address) error { return nil }
+func (m *mockCustomerService) AddAddress(ctx context.Context, a *domain.Address, userID uuid.UUID) error {
+	return nil
+}
 func (m *mockCustomerService) GetAddresses(ctx context.Context, id uuid.UUID) ([]*domain.Address, error) {
 	return nil, nil
 }
-func (m *mockCustomerService) RemoveAddress(ctx context.Context, id uuid.UUID) error { return nil }
-func (m *mockCustomerService) AddIdentity(ctx context.Context, i *domain.Identity) error {
+func (m *mockCustomerService) RemoveAddress(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockCustomerService) AddIdentity(ctx context.Context, i *domain.Identity, userID uuid.UUID) error {
 	return nil
 }
 func (m *mockCustomerService) GetIdentities(ctx context.Context, id uuid.UUID) ([]*domain.Identity, error) {
 	return nil, nil
 }
-func (m *mockCustomerService) RemoveIdentity(ctx context.Context, id uuid.UUID) error { return nil }
-func (m *mockCustomerService) AddRelationship(ctx context.Context, r *domain.Relationship) error {
+func (m *mockCustomerService) RemoveIdentity(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockCustomerService) AddRelationship(ctx context.Context, r *domain.Relationship, userID uuid.UUID) error {
 	return nil
 }
 func (m *mockCustomerService) GetRelationships(ctx context.Context, id uuid.UUID) ([]*domain.Relationship, error) {
 	return nil, nil
 }
-func (m *mockCustomerService) RemoveRelationship(ctx context.Context, id uuid.UUID) error {
+func (m *mockCustomerService) RemoveRelationship(ctx context.Context, customerID, id, userID uuid.UUID) error {
 	return nil
 }
-func (m *mockCustomerService) ManageConsent(ctx context.Context, c *domain.Consent) error {
+func (m *mockCustomerService) ManageConsent(ctx context.Context, c *domain.Consent, userID uuid.UUID) error {
 	return nil
 }
 func (m *mockCustomerService) GetConsents(ctx context.Context, id uuid.UUID) ([]*domain.Consent, error) {
 	return nil, nil
 }
+func (m *mockCustomerService) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error) {
+	return nil, nil
+}
+func (m *mockCustomerService) GetAddressesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Address, error) {
+	return nil, nil
+}
+func (m *mockCustomerService) GetIdentitiesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Identity, error) {
+	return nil, nil
+}
+func (m *mockCustomerService) GetRelationshipsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Relationship, error) {
+	return nil, nil
+}
+func (m *mockCustomerService) GetConsentsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Consent, error) {
+	return nil, nil
+}
 
 func TestListCustomers(t *testing.T) {
 	mockService := &mockCustomerService{
@@ -136,6 +212,102 @@ func TestListCustomers(t *testing.T) {
 	}
 }
 
+func TestListCustomers_Filters(t *testing.T) {
+	var got domain.CustomerQuery
+	mockService := &mockCustomerService{
+		queryFunc: func(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+			got = q
+			return &domain.CustomerPage{Customers: []*domain.Customer{{FirstName: "สมชาย"}}, TotalCount: 137}, nil
+		},
+	}
+	h := NewCustomerHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/api/v1/customers?type=personal&status=ACTIVE,suspended&tier=gold&high_value=true"+
+		"&created_from=2026-01-01&last_transaction_to=2026-06-01T00:00:00Z&sort=-clv,last_name&limit=25&offset=50", nil)
+	rr := httptest.NewRecorder()
+	h.ListCustomers(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Header().Get("X-Total-Count") != "137" {
+		t.Fatalf("Expected 200 with X-Total-Count 137, got %d %q", rr.Code, rr.Header().Get("X-Total-Count"))
+	}
+	f := got.Filter
+	if len(f.Types) != 1 || f.Types[0] != domain.TypePersonal || len(f.Statuses) != 2 || f.Statuses[1] != domain.StatusSuspended ||
+		f.Tiers[0] != "GOLD" || f.HighValue == nil || !*f.HighValue || f.CreatedFrom == nil || f.LastTransactionTo == nil {
+		t.Errorf("Filters not parsed: %+v", f)
+	}
+	if len(got.Sort) != 2 || got.Sort[0] != (domain.SortKey{Field: "clv", Desc: true}) || got.Limit != 25 || got.Offset != 50 {
+		t.Errorf("Sort or paging not parsed: %+v", got)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/customers?q="+url.QueryEscape(`id:1234567890123 zip:10110 name:"som chai"`), nil)
+	h.ListCustomers(httptest.NewRecorder(), req)
+	if got.Filter.IdentityNumber != "1234567890123" || got.Filter.ZipCode != "10110" || got.Filter.Text != "som chai" {
+		t.Errorf("Typed search not parsed: %+v", got.Filter)
+	}
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/customers?q=colour:red", nil)
+	if h.ListCustomers(rr, req); rr.Code != http.StatusBadRequest {
+		t.Errorf("Unknown search field: expected 400, got %d", rr.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/customers?q=Somchay&mode=fuzzy", nil)
+	h.ListCustomers(httptest.NewRecorder(), req)
+	if !got.Filter.Fuzzy || got.Filter.Text != "Somchay" {
+		t.Errorf("Expected a fuzzy query for Somchay, got %+v", got.Filter)
+	}
+
+	for _, bad := range []string{"limit=500", "sort=password", "status=GONE", "created_from=yesterday", "high_value=maybe", "mode=soundex"} {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/customers?q=*&"+bad, nil)
+		h.ListCustomers(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, rr.Code)
+		}
+	}
+}
+
+func TestListCustomers_DefaultPageSizes(t *testing.T) {
+	var got domain.CustomerQuery
+	h := NewCustomerHandler(&mockCustomerService{
+		queryFunc: func(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+			got = q
+			return &domain.CustomerPage{Customers: []*domain.Customer{}}, nil
+		},
+	})
+	for query, want := range map[string]int{
+		"q=*":                100,
+		"q=somchai":          20,
+		"status=ACTIVE":      domain.DefaultCustomerPageSize,
+		"q=*&cursor=":        domain.DefaultCustomerPageSize,
+		"q=somchai&limit=75": 75,
+	} {
+		req, _ := http.NewRequest("GET", "/api/v1/customers?"+query, nil)
+		h.ListCustomers(httptest.NewRecorder(), req)
+		if got.Limit != want {
+			t.Errorf("%s: expected limit %d, got %d", query, want, got.Limit)
+		}
+	}
+}
+
+func TestListCustomers_DeletedTotalCount(t *testing.T) {
+	mockService := &mockCustomerService{
+		listDeletedFunc: func(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
+			return []*domain.Customer{{FirstName: "สมชาย"}, {FirstName: "สมหญิง"}}, nil
+		},
+		deletedCount: 41,
+	}
+	h := NewCustomerHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/api/v1/customers?deleted=true&limit=2", nil)
+	rr := httptest.NewRecorder()
+	h.ListCustomers(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Header().Get("X-Total-Count") != "41" {
+		t.Errorf("Expected X-Total-Count to count every deleted customer, got %d %q", rr.Code, rr.Header().Get("X-Total-Count"))
+	}
+}
+
 func TestDeleteCustomer(t *testing.T) {
 	mockService := &mockCustomerService{
 		deleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
@@ -150,7 +322,7 @@ func TestDeleteCustomer(t *testing.T) {
 	req, _ := http.NewRequest("DELETE", "/api/v1/customers/"+id.String(), nil)
 	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
 	// Inject admin JWT claims
-	claims := &auth.JWTClaims{UserID: adminID.String(), Username: "admin", Email: "admin@test.com", Role: "admin"}
+	claims := &auth.JWTClaims{UserID: adminID.String(), Username: "admin", Email: "admin@test.com", Role: middleware.RoleAdmin}
 	ctx := context.WithValue(req.Context(), middleware.UserContextKey, claims)
 	req = req.WithContext(ctx)
 
@@ -164,10 +336,36 @@ func TestDeleteCustomer(t *testing.T) {
 	}
 }
 
+func TestDeleteCustomer_NonAdminForbidden(t *testing.T) {
+	deleted := false
+	mockService := &mockCustomerService{
+		deleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+	h := NewCustomerHandler(mockService)
+
+	for _, role := range []string{middleware.RoleOperator, middleware.RoleViewer} {
+		id := uuid.New()
+		req, _ := http.NewRequest("DELETE", "/api/v1/customers/"+id.String(), nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+		claims := &auth.JWTClaims{UserID: uuid.New().String(), Role: role}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+
+		rr := httptest.NewRecorder()
+		h.DeleteCustomer(rr, req)
+
+		if rr.Code != http.StatusForbidden || deleted {
+			t.Errorf("%s: expected 403 without deleting, got %d", role, rr.Code)
+		}
+	}
+}
+
 func TestDeleteCustomer_NotFound(t *testing.T) {
 	mockService := &mockCustomerService{
 		deleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
-			return errors.New("customer not found")
+			return fmt.Errorf("customer %w", domain.ErrNotFound)
 		},
 	}
 
@@ -178,7 +376,7 @@ func TestDeleteCustomer_NotFound(t *testing.T) {
 	req, _ := http.NewRequest("DELETE", "/api/v1/customers/"+id.String(), nil)
 	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
 	// Inject admin JWT claims
-	claims := &auth.JWTClaims{UserID: adminID.String(), Username: "admin", Email: "admin@test.com", Role: "admin"}
+	claims := &auth.JWTClaims{UserID: adminID.String(), Username: "admin", Email: "admin@test.com", Role: middleware.RoleAdmin}
 	ctx := context.WithValue(req.Context(), middleware.UserContextKey, claims)
 	req = req.WithContext(ctx)
 
@@ -191,3 +389,238 @@ func TestDeleteCustomer_NotFound(t *testing.T) {
 			status, http.StatusInternalServerError)
 	}
 }
+
+// Mock ChangeRequestService
+type mockChangeRequestService struct {
+	submitted []domain.ChangeOperation
+}
+
+func (m *mockChangeRequestService) Submit(ctx context.Context, op domain.ChangeOperation, customerID, makerID uuid.UUID, payload interface{}, reason string) (*domain.ChangeRequest, error) {
+	m.submitted = append(m.submitted, op)
+	return &domain.ChangeRequest{ID: uuid.New(), Operation: op, CustomerID: customerID, MakerID: makerID, Status: domain.ChangePending}, nil
+}
+func (m *mockChangeRequestService) Approve(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error) {
+	return nil, nil
+}
+func (m *mockChangeRequestService) Reject(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error) {
+	return nil, nil
+}
+func (m *mockChangeRequestService) Get(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	return nil, nil
+}
+func (m *mockChangeRequestService) List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error) {
+	return nil, nil
+}
+
+func TestDeleteCustomer_WithApprovals(t *testing.T) {
+	mockService := &mockCustomerService{
+		deleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
+			t.Errorf("delete must not be applied before approval")
+			return nil
+		},
+	}
+	approvals := &mockChangeRequestService{}
+
+	h := NewCustomerHandler(mockService, WithApprovals(approvals))
+
+	id := uuid.New()
+	req, _ := http.NewRequest("DELETE", "/api/v1/customers/"+id.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+	claims := &auth.JWTClaims{UserID: uuid.New().String(), Username: "admin", Email: "admin@test.com", Role: middleware.RoleSuperAdmin}
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+
+	rr := httptest.NewRecorder()
+
+	h.DeleteCustomer(rr, req)
+
+	if status := rr.Code; status != http.StatusAccepted {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+	if len(approvals.submitted) != 1 || approvals.submitted[0] != domain.OpDeleteCustomer {
+		t.Errorf("expected a DELETE_CUSTOMER change request, got %v", approvals.submitted)
+	}
+}
+
+// Mock AccessGrantService: only customers in readable may be read in full, and single reads of
+// customers in denied need a grant.
+type mockAccessGrantService struct {
+	readable map[uuid.UUID]bool
+	denied   map[uuid.UUID]bool
+	checks   map[uuid.UUID]int // CheckAccess calls, each of which would be audited
+	err      error             // Returned by RequestAccess
+}
+
+func (m *mockAccessGrantService) RequestAccess(ctx context.Context, customerID, userID uuid.UUID, reason string) (*domain.AccessGrant, error) {
+	return nil, m.err
+}
+func (m *mockAccessGrantService) ApproveAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error) {
+	return nil, nil
+}
+func (m *mockAccessGrantService) RejectAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error) {
+	return nil, nil
+}
+func (m *mockAccessGrantService) CheckAccess(ctx context.Context, customerID, userID uuid.UUID, role string) error {
+	if m.checks != nil {
+		m.checks[customerID]++
+	}
+	if m.denied[customerID] {
+		return domain.ErrAccessGrantRequired
+	}
+	return nil
+}
+func (m *mockAccessGrantService) CanRead(ctx context.Context, c *domain.Customer, userID uuid.UUID, role string) (bool, error) {
+	return !c.IsHighValue || m.readable[c.ID], nil
+}
+func (m *mockAccessGrantService) ListGrants(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error) {
+	return nil, nil
+}
+
+func TestSearchCustomers_WithholdsProtected(t *testing.T) {
+	granted := &domain.Customer{ID: uuid.New(), FirstName: "สมหญิง", IsHighValue: true}
+	mockService := &mockCustomerService{
+		searchFunc: func(ctx context.Context, query string) ([]*domain.Customer, error) {
+			return []*domain.Customer{
+				{ID: uuid.New(), FirstName: "สมชาย"},
+				{ID: uuid.New(), FirstName: "สมศักดิ์", IsHighValue: true, MembershipTier: "PLATINUM"},
+				granted,
+			}, nil
+		},
+	}
+	h := NewCustomerHandler(mockService, WithAccessGrants(&mockAccessGrantService{readable: map[uuid.UUID]bool{granted.ID: true}}))
+
+	req, _ := http.NewRequest("GET", "/api/v1/customers/search?q=zip:10110", nil)
+	rr := httptest.NewRecorder()
+	h.SearchCustomers(rr, req)
+
+	var got []domain.Customer
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil || len(got) != 3 {
+		t.Fatalf("Expected three results, got %d: %v", len(got), err)
+	}
+	if got[0].FirstName != "สมชาย" || got[2].FirstName != "สมหญิง" {
+		t.Errorf("Readable customers must be shown in full: %+v", got)
+	}
+	if got[1].FirstName != "" || !got[1].IsHighValue || got[1].MembershipTier != "PLATINUM" {
+		t.Errorf("Expected the protected customer to be withheld, got %+v", got[1])
+	}
+}
+
+func TestSearchCustomers_InvalidSearch(t *testing.T) {
+	mockService := &mockCustomerService{
+		searchFunc: func(ctx context.Context, query string) ([]*domain.Customer, error) {
+			return nil, fmt.Errorf("%w: unknown field", domain.ErrInvalidSearch)
+		},
+	}
+	h := NewCustomerHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/api/v1/customers/search?q=passport:AA123", nil)
+	rr := httptest.NewRecorder()
+	h.SearchCustomers(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rr.Code)
+	}
+}
+
+func TestListCustomers_CursorEnvelope(t *testing.T) {
+	var got domain.CustomerQuery
+	next := domain.PageCursor{At: time.Now(), ID: uuid.New()}.Encode()
+	mockService := &mockCustomerService{
+		queryFunc: func(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+			got = q
+			return &domain.CustomerPage{Customers: []*domain.Customer{{FirstName: "สมชาย"}}, TotalCount: 3, NextCursor: next}, nil
+		},
+	}
+	h := NewCustomerHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/customers?q=*&limit=1&cursor="+next, nil)
+	h.ListCustomers(rr, req)
+	var page domain.CustomerPage
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil || len(page.Customers) != 1 || page.NextCursor != next || page.TotalCount != 3 {
+		t.Fatalf("Expected the page envelope, got %+v, %v", page, err)
+	}
+	if got.Cursor == nil || got.Cursor.Encode() != next {
+		t.Errorf("Cursor not passed on: %+v", got.Cursor)
+	}
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/customers?q=*&sort=-clv&cursor="+next, nil)
+	if h.ListCustomers(rr, req); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for cursor paging with a custom sort, got %d", rr.Code)
+	}
+}
+
+func TestListCustomers_Fields(t *testing.T) {
+	var got domain.CustomerQuery
+	protected := &domain.Customer{ID: uuid.New(), FirstName: "สมศักดิ์", IsHighValue: true, Status: domain.StatusActive}
+	mockService := &mockCustomerService{
+		queryFunc: func(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+			got = q
+			return &domain.CustomerPage{Customers: []*domain.Customer{
+				{ID: uuid.New(), FirstName: "สมชาย", Status: domain.StatusActive},
+				protected,
+			}, TotalCount: 2}, nil
+		},
+	}
+	h := NewCustomerHandler(mockService, WithAccessGrants(&mockAccessGrantService{}))
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/customers?q=*&fields=id,first_name,status", nil)
+	h.ListCustomers(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !got.Fields.Has("first_name") || got.Fields.Has("clv") {
+		t.Errorf("Expected the fieldset to reach the query, got %v", got.Fields)
+	}
+	var items []map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&items); err != nil || len(items) != 2 {
+		t.Fatalf("Expected two items, got %v: %v", items, err)
+	}
+	if len(items[0]) != 3 || items[0]["first_name"] != "สมชาย" {
+		t.Errorf("Expected only id, first_name and status, got %v", items[0])
+	}
+	if _, ok := items[1]["first_name"]; ok || items[1]["status"] != "ACTIVE" {
+		t.Errorf("Expected the withheld customer to keep only withheld fields, got %v", items[1])
+	}
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/customers?q=*&fields=id,addresses.zip_code", nil)
+	h.ListCustomers(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for nested fields on a list, got %d", rr.Code)
+	}
+}
+
+func TestGetCustomer_FieldsEmbedSubResources(t *testing.T) {
+	id := uuid.New()
+	mockService := &mockCustomerService{
+		getFunc: func(ctx context.Context, gotID uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: gotID, FirstName: "สมชาย", LastName: "ใจดี"}, nil
+		},
+	}
+	h := NewCustomerHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/customers/"+id.String()+"?fields=id,first_name,addresses.zip_code", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+	h.GetCustomer(rr, req)
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(body) != 3 || body["first_name"] != "สมชาย" || body["last_name"] != nil {
+		t.Errorf("Expected id, first_name and addresses only, got %v", body)
+	}
+	if addresses, ok := body["addresses"].([]interface{}); !ok || len(addresses) != 0 {
+		t.Errorf("Expected an embedded empty address list, got %v", body["addresses"])
+	}
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/customers/"+id.String()+"?fields=addresses.colour", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+	h.GetCustomer(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown nested field, got %d", rr.Code)
+	}
+}
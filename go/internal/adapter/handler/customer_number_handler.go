@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/gorilla/mux"
+)
+
+// CustomerNumberHandler resolves customers by their human-readable number
+type CustomerNumberHandler struct {
+	service ports.CustomerNumberService
+}
+
+func NewCustomerNumberHandler(service ports.CustomerNumberService) *CustomerNumberHandler {
+	return &CustomerNumberHandler{service: service}
+}
+
+// ResolveCustomer looks up the customer for {number} and serves next as if the request were
+// for /customers/{id}, so customer reads and their access checks are shared.
+//
+// @Summary Get customer by customer number
+// @Description Spaces and dashes are ignored. A number whose check digit does not match is refused with 400.
+// @Tags customers
+// @Produce json
+// @Param number path string true "Customer number"
+// @Success 200 {object} domain.Customer
+// @Router /api/v1/customers/by-number/{number} [get]
+func (h *CustomerNumberHandler) ResolveCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := h.service.FindCustomerID(r.Context(), mux.Vars(r)["number"])
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, mux.SetURLVars(r, map[string]string{"id": id.String()}))
+	})
+}
+
+// @Summary Number existing customers
+// @Description Assigns numbers to customers created before numbering was enabled, oldest first.
+// @Tags customers
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Router /api/v1/customer-numbers/backfill [post]
+func (h *CustomerNumberHandler) Backfill(w http.ResponseWriter, r *http.Request) {
+	n, err := h.service.Backfill(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"assigned": n})
+}
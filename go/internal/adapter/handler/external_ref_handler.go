@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/gorilla/mux"
+)
+
+// ExternalRefHandler resolves customers by their keys in other systems
+type ExternalRefHandler struct {
+	service ports.ExternalRefService
+}
+
+func NewExternalRefHandler(service ports.ExternalRefService) *ExternalRefHandler {
+	return &ExternalRefHandler{service: service}
+}
+
+// ResolveCustomer looks up the customer for {system}/{key} and serves next as if the request
+// were for /customers/{id}, so customer reads and their access checks are shared.
+//
+// @Summary Get customer by external reference
+// @Description Looks up a customer by their key in another system, e.g. CAS/0000012345.
+// @Tags customers
+// @Produce json
+// @Param system path string true "CAS, CORE_BANKING or CRM"
+// @Param key path string true "Key in that system"
+// @Success 200 {object} domain.Customer
+// @Router /api/v1/customers/by-ref/{system}/{key} [get]
+func (h *ExternalRefHandler) ResolveCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := h.service.FindCustomerID(r.Context(), vars["system"], vars["key"])
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, mux.SetURLVars(r, map[string]string{"id": id.String()}))
+	})
+}
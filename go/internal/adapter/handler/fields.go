@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+)
+
+// fieldsFromQuery parses ?fields= for resources shaped like v, writing 400 for unknown fields.
+func fieldsFromQuery(w http.ResponseWriter, r *http.Request, v interface{}, subs map[string]interface{}) (domain.FieldSet, bool) {
+	fields, err := domain.ParseFieldSet(r.URL.Query().Get("fields"), v, subs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return fields, true
+}
+
+// writeFields encodes v, an object or a list of them, keeping only the selected fields.
+func writeFields(w http.ResponseWriter, v interface{}, fields domain.FieldSet) {
+	out, err := project(v, fields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(out, '\n'))
+}
+
+// project marshals v and drops the fields not selected, recursing into nested selections and
+// through lists. A nil selection keeps everything.
+func project(v interface{}, fields domain.FieldSet) (json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil || fields == nil {
+		return raw, err
+	}
+	return projectRaw(raw, fields)
+}
+
+func projectRaw(raw json.RawMessage, fields domain.FieldSet) (json.RawMessage, error) {
+	if fields == nil {
+		return raw, nil
+	}
+	var err error
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		for i, item := range list {
+			if list[i], err = projectRaw(item, fields); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(list)
+	}
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		return raw, nil // a scalar or null has no fields to select
+	}
+	kept := map[string]json.RawMessage{}
+	for name, value := range obj {
+		if !fields.Has(name) {
+			continue
+		}
+		if kept[name], err = projectRaw(value, fields.Nested(name)); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(kept)
+}
+
+// projectCustomers selects fields from each customer. Withheld customers keep at most the
+// fields their withheld view has, so a fieldset cannot widen what access control hides. A nil
+// selection keeps everything.
+func projectCustomers(customers []*domain.Customer, withheld map[int]bool, fields domain.FieldSet) ([]json.RawMessage, error) {
+	out := make([]json.RawMessage, len(customers))
+	for i, c := range customers {
+		fs := fields
+		if withheld[i] && fields != nil {
+			fs = fields.Restrict(domain.WithheldCustomerFields)
+		}
+		var err error
+		if out[i], err = project(c, fs); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
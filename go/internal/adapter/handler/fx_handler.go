@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/gorilla/mux"
+)
+
+// FXHandler manages the local FX rate table used to convert portfolio values to THB
+type FXHandler struct {
+	service ports.FXService
+}
+
+func NewFXHandler(service ports.FXService) *FXHandler {
+	return &FXHandler{service: service}
+}
+
+// @Summary List FX rates
+// @Description Local conversion rates to THB
+// @Tags fx
+// @Produce json
+// @Success 200 {array} domain.FXRate
+// @Router /api/v1/fx-rates [get]
+func (h *FXHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.Rates(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rates == nil {
+		rates = []*domain.FXRate{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+// @Summary Set FX rate
+// @Description Create or replace the THB rate for a currency. rate_to_base is a decimal string, e.g. "36.1250".
+// @Tags fx
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Param rate body domain.FXRate true "Rate"
+// @Success 200 {object} domain.FXRate
+// @Router /api/v1/fx-rates/{currency} [put]
+func (h *FXHandler) SetRate(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var rate domain.FXRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	rate.Currency = mux.Vars(r)["currency"]
+
+	if err := h.service.SetRate(r.Context(), &rate, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// GraphQLHandler serves the customer graph over GraphQL
+type GraphQLHandler struct {
+	customers ports.CustomerService
+	access    ports.AccessGrantService
+	limits    GraphQLLimits
+	schema    graphql.Schema
+}
+
+// NewGraphQLHandler withholds protected customers from users who may not read them when access
+// is not nil, the same way the REST customer routes do.
+func NewGraphQLHandler(customers ports.CustomerService, access ports.AccessGrantService, limits GraphQLLimits) *GraphQLHandler {
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		panic("graphql schema: " + err.Error())
+	}
+	return &GraphQLHandler{customers: customers, access: access, limits: limits, schema: schema}
+}
+
+// GraphQLRequest is the standard GraphQL-over-HTTP request body.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// @Summary GraphQL query
+// @Description Reads customers with their addresses, identities, consents and relationships, recursively, in one request.
+// @Description Queries over the depth or complexity limits are rejected before they run. There are no mutations.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body handler.GraphQLRequest true "query, operationName and variables"
+// @Success 200 {object} graphql.Result
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var req GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		writeGraphQLErrors(w, gqlerrors.FormatErrors(err))
+		return
+	}
+	if vr := graphql.ValidateDocument(&h.schema, doc, nil); !vr.IsValid {
+		writeGraphQLErrors(w, vr.Errors)
+		return
+	}
+	if err := h.limits.checkLimits(doc, req.Variables); err != nil {
+		writeGraphQLErrors(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	viewer := &gqlViewer{access: h.access, userID: userID, role: claims.Role}
+	ctx := context.WithValue(r.Context(), gqlRequestKey{}, &gqlRequest{
+		customers: h.customers,
+		viewer:    viewer,
+		loaders:   newGQLLoaders(h.customers, viewer),
+	})
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeGraphQLErrors answers a query that could not run with 400 and a GraphQL error body.
+func writeGraphQLErrors(w http.ResponseWriter, errs []gqlerrors.FormattedError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(graphql.Result{Errors: errs})
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// graphCustomers serves a small customer graph and counts the batch calls made against it.
+type graphCustomers struct {
+	ports.CustomerService
+	page      []*domain.Customer
+	customers map[uuid.UUID]*domain.Customer
+	addresses map[uuid.UUID][]*domain.Address
+	rels      []*domain.Relationship
+	calls     map[string]int
+}
+
+func (g *graphCustomers) QueryCustomers(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	return &domain.CustomerPage{Customers: g.page, TotalCount: len(g.page)}, nil
+}
+func (g *graphCustomers) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error) {
+	g.calls["customers"]++
+	var out []*domain.Customer
+	for _, id := range ids {
+		if c, ok := g.customers[id]; ok {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (g *graphCustomers) GetAddressesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Address, error) {
+	g.calls["addresses"]++
+	out := map[uuid.UUID][]*domain.Address{}
+	for _, id := range ids {
+		out[id] = g.addresses[id]
+	}
+	return out, nil
+}
+func (g *graphCustomers) GetRelationshipsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Relationship, error) {
+	g.calls["relationships"]++
+	out := map[uuid.UUID][]*domain.Relationship{}
+	for _, id := range ids {
+		for _, r := range g.rels {
+			if r.FromCustomerID == id || r.ToCustomerID == id {
+				out[id] = append(out[id], r)
+			}
+		}
+	}
+	return out, nil
+}
+
+func newGraphCustomers(customers ...*domain.Customer) *graphCustomers {
+	g := &graphCustomers{customers: map[uuid.UUID]*domain.Customer{}, addresses: map[uuid.UUID][]*domain.Address{}, calls: map[string]int{}}
+	for _, c := range customers {
+		g.customers[c.ID] = c
+		g.addresses[c.ID] = []*domain.Address{{ID: uuid.New(), CustomerID: c.ID, ZipCode: "10110"}}
+	}
+	return g
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, h *GraphQLHandler, query string, variables map[string]interface{}) (int, graphQLResponse) {
+	t.Helper()
+	body, _ := json.Marshal(GraphQLRequest{Query: query, Variables: variables})
+	req, _ := http.NewRequest("POST", "/api/v1/graphql", bytes.NewReader(body))
+	claims := &auth.JWTClaims{UserID: uuid.New().String(), Role: middleware.RoleViewer}
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+	h.Query(rr, req)
+
+	var resp graphQLResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid response %q: %v", rr.Body.String(), err)
+	}
+	return rr.Code, resp
+}
+
+func TestGraphQL_BatchesNestedReads(t *testing.T) {
+	a := &domain.Customer{ID: uuid.New(), FirstName: "Somchai"}
+	b := &domain.Customer{ID: uuid.New(), FirstName: "Somying"}
+	c := &domain.Customer{ID: uuid.New(), FirstName: "Somsak"}
+	outside := &domain.Customer{ID: uuid.New(), CompanyName: "Siam Holdings"}
+	g := newGraphCustomers(a, b, c, outside)
+	g.page = []*domain.Customer{a, b, c}
+	g.rels = []*domain.Relationship{
+		{ID: uuid.New(), FromCustomerID: a.ID, ToCustomerID: b.ID, Role: "SPOUSE"},
+		{ID: uuid.New(), FromCustomerID: outside.ID, ToCustomerID: c.ID, Role: "DIRECTOR"},
+	}
+	h := NewGraphQLHandler(g, nil, DefaultGraphQLLimits)
+
+	code, resp := postGraphQL(t, h, `{
+		customers {
+			totalCount
+			nodes {
+				id firstName
+				addresses { zipCode }
+				relationships { role direction customer { id companyName addresses { zipCode } } }
+			}
+		}
+	}`, nil)
+	if code != http.StatusOK || len(resp.Errors) != 0 {
+		t.Fatalf("Expected 200 without errors, got %d %+v", code, resp.Errors)
+	}
+
+	// One fetch for the listed customers' addresses and one for the related customer outside
+	// the page; the rest is answered from the list and the loaders' cache.
+	want := map[string]int{"customers": 1, "addresses": 2, "relationships": 1}
+	for name, n := range want {
+		if g.calls[name] != n {
+			t.Errorf("Expected %d %s fetches, got %d", n, name, g.calls[name])
+		}
+	}
+
+	var page struct {
+		Nodes []struct {
+			Relationships []struct {
+				Direction string
+				Customer  struct{ CompanyName string }
+			}
+		}
+	}
+	json.Unmarshal(resp.Data["customers"], &page)
+	rels := page.Nodes[2].Relationships
+	if len(rels) != 1 || rels[0].Direction != "INCOMING" || rels[0].Customer.CompanyName != "Siam Holdings" {
+		t.Errorf("Expected c's incoming relationship to reach the company, got %+v", rels)
+	}
+}
+
+func TestGraphQL_WithholdsProtectedCustomers(t *testing.T) {
+	open := &domain.Customer{ID: uuid.New(), FirstName: "Somchai"}
+	protected := &domain.Customer{ID: uuid.New(), FirstName: "Somying", IsHighValue: true, MembershipTier: "PLATINUM"}
+	g := newGraphCustomers(open, protected)
+	g.page = []*domain.Customer{open, protected}
+	access := &mockAccessGrantService{denied: map[uuid.UUID]bool{protected.ID: true}}
+	h := NewGraphQLHandler(g, access, DefaultGraphQLLimits)
+
+	_, resp := postGraphQL(t, h, `{ customers { nodes { firstName membershipTier withheld addresses { zipCode } } } }`, nil)
+	var page struct {
+		Nodes []struct {
+			FirstName      *string
+			MembershipTier string
+			Withheld       bool
+			Addresses      []interface{}
+		}
+	}
+	json.Unmarshal(resp.Data["customers"], &page)
+	if len(page.Nodes) != 2 || page.Nodes[0].Withheld || len(page.Nodes[0].Addresses) != 1 {
+		t.Fatalf("Expected the open customer in full, got %s", resp.Data["customers"])
+	}
+	if p := page.Nodes[1]; !p.Withheld || p.FirstName != nil || p.MembershipTier != "PLATINUM" || p.Addresses != nil {
+		t.Errorf("Expected the protected customer withheld without addresses, got %s", resp.Data["customers"])
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "protected customer") {
+		t.Errorf("Expected one protected customer error, got %+v", resp.Errors)
+	}
+
+	_, resp = postGraphQL(t, h, `query($id: ID!) { customer(id: $id) { firstName } }`,
+		map[string]interface{}{"id": protected.ID.String()})
+	if string(resp.Data["customer"]) != "null" || len(resp.Errors) != 1 {
+		t.Errorf("Expected a single read of a protected customer to need a grant, got %s %+v", resp.Data["customer"], resp.Errors)
+	}
+}
+
+func TestGraphQL_AuditsProtectedReads(t *testing.T) {
+	protected := &domain.Customer{ID: uuid.New(), FirstName: "Somying", IsHighValue: true}
+	g := newGraphCustomers(protected)
+	g.page = []*domain.Customer{protected}
+	access := &mockAccessGrantService{readable: map[uuid.UUID]bool{protected.ID: true}, checks: map[uuid.UUID]int{}}
+	h := NewGraphQLHandler(g, access, DefaultGraphQLLimits)
+
+	_, resp := postGraphQL(t, h, `{ customers { nodes { firstName withheld } } }`, nil)
+	if len(resp.Errors) != 0 || !strings.Contains(string(resp.Data["customers"]), "Somying") {
+		t.Fatalf("Expected a grant holder to read the protected customer, got %s %+v", resp.Data["customers"], resp.Errors)
+	}
+	if access.checks[protected.ID] != 1 {
+		t.Errorf("Expected the full read from a list audited once, got %d checks", access.checks[protected.ID])
+	}
+
+	_, resp = postGraphQL(t, h, `query($id: ID!) { customer(id: $id) { firstName } }`,
+		map[string]interface{}{"id": protected.ID.String()})
+	if len(resp.Errors) != 0 || access.checks[protected.ID] != 2 {
+		t.Errorf("Expected a single read audited once more, got %d checks %+v", access.checks[protected.ID], resp.Errors)
+	}
+}
+
+func TestGraphQL_Limits(t *testing.T) {
+	h := NewGraphQLHandler(newGraphCustomers(), nil, GraphQLLimits{MaxDepth: 4, MaxComplexity: 100})
+
+	code, resp := postGraphQL(t, h, `{ customers { nodes { relationships { customer { id } } } } }`, nil)
+	if code != http.StatusBadRequest || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "depth 5") {
+		t.Errorf("Expected depth limit error, got %d %+v", code, resp.Errors)
+	}
+
+	// 50 customers at 1 + nodes + id each.
+	code, resp = postGraphQL(t, h, `{ customers(first: 50) { nodes { id } } }`, nil)
+	if code != http.StatusBadRequest || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "complexity 150") {
+		t.Errorf("Expected complexity limit error, got %d %+v", code, resp.Errors)
+	}
+
+	code, resp = postGraphQL(t, h, `query($n: Int) { customers(first: $n) { ...ids } } fragment ids on CustomerConnection { nodes { id } }`,
+		map[string]interface{}{"n": 10})
+	if code != http.StatusOK || len(resp.Errors) != 0 {
+		t.Errorf("Expected a small page to run, got %d %+v", code, resp.Errors)
+	}
+
+	code, _ = postGraphQL(t, h, `{ customers { nodes { unknownField } } }`, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected an invalid query to be rejected, got %d", code)
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// GraphQLLimits bound how much work one GraphQL query may ask for. Depth counts nested fields,
+// so { customer { id } } has depth 2. Complexity counts one per field, multiplied through list
+// fields by how many items they may return.
+type GraphQLLimits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// DefaultGraphQLLimits let a default page of customers bring their sub-resources and the
+// customers they are related to, with theirs.
+var DefaultGraphQLLimits = GraphQLLimits{MaxDepth: 10, MaxComplexity: 50000}
+
+// gqlSubResourceFanout is the number of addresses, identities, relationships or consents a
+// customer is assumed to have when costing a query.
+const gqlSubResourceFanout = 10
+
+// checkLimits measures every operation in a validated document against the limits.
+func (l GraphQLLimits) checkLimits(doc *ast.Document, variables map[string]interface{}) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+	m := &queryMeasure{fragments: fragments, variables: variables}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth, cost := m.measure(op.SelectionSet)
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && cost > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", cost, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type queryMeasure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// measure returns the depth and cost of a selection set, expanding fragments. Introspection
+// fields are free: they are bounded by the schema, not by the data.
+func (m *queryMeasure) measure(set *ast.SelectionSet) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, c int
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, c = m.measure(s.SelectionSet)
+			d, c = d+1, m.multiplier(s)*(1+c)
+		case *ast.InlineFragment:
+			d, c = m.measure(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if f, ok := m.fragments[s.Name.Value]; ok {
+				d, c = m.measure(f.SelectionSet)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+		cost += c
+	}
+	return depth, cost
+}
+
+// multiplier is how many items a field may return.
+func (m *queryMeasure) multiplier(f *ast.Field) int {
+	switch f.Name.Value {
+	case "customers":
+		return gqlPageSize(m.intArg(f, "first"))
+	case "addresses", "identities", "relationships", "consents":
+		return gqlSubResourceFanout
+	}
+	return 1
+}
+
+func (m *queryMeasure) intArg(f *ast.Field, name string) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(v.Value)
+			return n
+		case *ast.Variable:
+			switch n := m.variables[v.Name.Value].(type) {
+			case float64:
+				return int(n)
+			case int:
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// gqlPageSize is the page size customers(first) will return. A first above the maximum fails
+// when the query runs; it is costed at the maximum.
+func gqlPageSize(first int) int {
+	if first <= 0 {
+		return domain.DefaultCustomerPageSize
+	}
+	if first > domain.MaxCustomerPageSize {
+		return domain.MaxCustomerPageSize
+	}
+	return first
+}
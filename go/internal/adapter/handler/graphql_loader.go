@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// batchLoader collects the customer ids resolvers ask for while the executor walks one level of
+// a query, then loads them all with a single fetch when the first result is needed. Results are
+// kept for the rest of the request, so each id is fetched at most once.
+type batchLoader[V any] struct {
+	fetch func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]V, error)
+
+	mu      sync.Mutex
+	pending []uuid.UUID
+	queued  map[uuid.UUID]bool
+	loaded  map[uuid.UUID]V
+	errs    map[uuid.UUID]error
+}
+
+func newBatchLoader[V any](fetch func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{
+		fetch:  fetch,
+		queued: map[uuid.UUID]bool{},
+		loaded: map[uuid.UUID]V{},
+		errs:   map[uuid.UUID]error{},
+	}
+}
+
+// load queues id and returns a thunk for the executor. Calling the thunk flushes everything
+// queued so far.
+func (l *batchLoader[V]) load(ctx context.Context, id uuid.UUID) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[id]; !ok && !l.queued[id] && l.errs[id] == nil {
+		l.queued[id] = true
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.queued[id] {
+			l.flush(ctx)
+		}
+		if err := l.errs[id]; err != nil {
+			return nil, err
+		}
+		return l.loaded[id], nil
+	}
+}
+
+// prime records a value loaded some other way, such as a customer from a list query.
+func (l *batchLoader[V]) prime(id uuid.UUID, v V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.queued[id] {
+		l.loaded[id] = v
+	}
+}
+
+func (l *batchLoader[V]) flush(ctx context.Context) {
+	ids := l.pending
+	l.pending = nil
+	for _, id := range ids {
+		delete(l.queued, id)
+	}
+	got, err := l.fetch(ctx, ids)
+	for _, id := range ids {
+		if err != nil {
+			l.errs[id] = err
+			continue
+		}
+		l.loaded[id] = got[id] // ids without a result load as the zero value
+	}
+}
+
+// gqlCustomer is a customer as the GraphQL schema sees it: withheld customers carry only the
+// fields of domain.Customer.Withheld, and their sub-resources cannot be read.
+type gqlCustomer struct {
+	*domain.Customer
+	withheld bool
+}
+
+// gqlRelationship is a relationship seen from one of its ends.
+type gqlRelationship struct {
+	*domain.Relationship
+	from uuid.UUID
+}
+
+// gqlLoaders are the per-request loaders behind the schema's resolvers.
+type gqlLoaders struct {
+	customers     *batchLoader[*gqlCustomer]
+	addresses     *batchLoader[[]*domain.Address]
+	identities    *batchLoader[[]*domain.Identity]
+	relationships *batchLoader[[]*domain.Relationship]
+	consents      *batchLoader[[]*domain.Consent]
+}
+
+func newGQLLoaders(customers ports.CustomerService, v *gqlViewer) *gqlLoaders {
+	return &gqlLoaders{
+		customers: newBatchLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*gqlCustomer, error) {
+			list, err := customers.GetCustomersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[uuid.UUID]*gqlCustomer, len(list))
+			for _, c := range list {
+				if out[c.ID], err = v.view(ctx, c); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}),
+		addresses:     newBatchLoader(customers.GetAddressesByCustomerIDs),
+		identities:    newBatchLoader(customers.GetIdentitiesByCustomerIDs),
+		relationships: newBatchLoader(customers.GetRelationshipsByCustomerIDs),
+		consents:      newBatchLoader(customers.GetConsentsByCustomerIDs),
+	}
+}
+
+// gqlViewer is the caller of a GraphQL request, for protected customer checks.
+type gqlViewer struct {
+	access ports.AccessGrantService
+	userID uuid.UUID
+	role   string
+
+	mu      sync.Mutex
+	checked map[uuid.UUID]error
+}
+
+// checkAccess runs CheckAccess once per customer and request, so each protected read returned
+// in full is audited, and the grant used, exactly once.
+func (v *gqlViewer) checkAccess(ctx context.Context, id uuid.UUID) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err, ok := v.checked[id]; ok {
+		return err
+	}
+	err := v.access.CheckAccess(ctx, id, v.userID, v.role)
+	if v.checked == nil {
+		v.checked = map[uuid.UUID]error{}
+	}
+	v.checked[id] = err
+	return err
+}
+
+// view withholds a protected customer the caller may not read, as list endpoints do. One the
+// caller may read goes through checkAccess like the REST single-customer route.
+func (v *gqlViewer) view(ctx context.Context, c *domain.Customer) (*gqlCustomer, error) {
+	if v.access == nil {
+		return &gqlCustomer{Customer: c}, nil
+	}
+	ok, err := v.access.CanRead(ctx, c, v.userID, v.role)
+	if err != nil {
+		return nil, err
+	}
+	if ok && c.IsHighValue {
+		if err := v.checkAccess(ctx, c.ID); errors.Is(err, domain.ErrAccessGrantRequired) {
+			ok = false
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		return &gqlCustomer{Customer: c.Withheld(), withheld: true}, nil
+	}
+	return &gqlCustomer{Customer: c}, nil
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+type gqlRequestKey struct{}
+
+// gqlRequest is what resolvers need from the HTTP request they serve.
+type gqlRequest struct {
+	customers ports.CustomerService
+	viewer    *gqlViewer
+	loaders   *gqlLoaders
+}
+
+func gqlRequestFrom(ctx context.Context) *gqlRequest {
+	return ctx.Value(gqlRequestKey{}).(*gqlRequest)
+}
+
+var errGQLProtectedCustomer = errors.New("Forbidden: protected customer, request access via /access-requests")
+
+// newGraphQLSchema builds the read-only customer graph. Customers come from the customer loader
+// and are withheld like in list responses when the caller may not read them; the sub-resources
+// of a withheld customer resolve to an error.
+func newGraphQLSchema() (graphql.Schema, error) {
+	addressType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Address",
+		Fields: graphql.Fields{
+			"id":           gqlField(graphql.NewNonNull(graphql.ID), func(a *domain.Address) interface{} { return a.ID.String() }),
+			"customerId":   gqlField(graphql.NewNonNull(graphql.ID), func(a *domain.Address) interface{} { return a.CustomerID.String() }),
+			"type":         gqlField(graphql.String, func(a *domain.Address) interface{} { return a.Type }),
+			"addressLine1": gqlField(graphql.String, func(a *domain.Address) interface{} { return a.AddressLine1 }),
+			"addressLine2": gqlField(graphql.String, func(a *domain.Address) interface{} { return a.AddressLine2 }),
+			"city":         gqlField(graphql.String, func(a *domain.Address) interface{} { return a.City }),
+			"state":        gqlField(graphql.String, func(a *domain.Address) interface{} { return a.State }),
+			"district":     gqlField(graphql.String, func(a *domain.Address) interface{} { return a.District }),
+			"subDistrict":  gqlField(graphql.String, func(a *domain.Address) interface{} { return a.SubDistrict }),
+			"zipCode":      gqlField(graphql.String, func(a *domain.Address) interface{} { return a.ZipCode }),
+			"country":      gqlField(graphql.String, func(a *domain.Address) interface{} { return a.Country }),
+			"createdAt":    gqlField(graphql.DateTime, func(a *domain.Address) interface{} { return a.CreatedAt }),
+			"updatedAt":    gqlField(graphql.DateTime, func(a *domain.Address) interface{} { return a.UpdatedAt }),
+		},
+	})
+
+	identityType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Identity",
+		Fields: graphql.Fields{
+			"id":              gqlField(graphql.NewNonNull(graphql.ID), func(i *domain.Identity) interface{} { return i.ID.String() }),
+			"customerId":      gqlField(graphql.NewNonNull(graphql.ID), func(i *domain.Identity) interface{} { return i.CustomerID.String() }),
+			"type":            gqlField(graphql.String, func(i *domain.Identity) interface{} { return i.Type }),
+			"number":          gqlField(graphql.String, func(i *domain.Identity) interface{} { return i.Number }),
+			"issuanceCountry": gqlField(graphql.String, func(i *domain.Identity) interface{} { return i.IssuanceCountry }),
+			"expiryDate":      gqlField(graphql.String, func(i *domain.Identity) interface{} { return gqlDate(i.ExpiryDate) }),
+			"createdAt":       gqlField(graphql.DateTime, func(i *domain.Identity) interface{} { return i.CreatedAt }),
+			"updatedAt":       gqlField(graphql.DateTime, func(i *domain.Identity) interface{} { return i.UpdatedAt }),
+		},
+	})
+
+	consentType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Consent",
+		Fields: graphql.Fields{
+			"id":         gqlField(graphql.NewNonNull(graphql.ID), func(c *domain.Consent) interface{} { return c.ID.String() }),
+			"customerId": gqlField(graphql.NewNonNull(graphql.ID), func(c *domain.Consent) interface{} { return c.CustomerID.String() }),
+			"topic":      gqlField(graphql.String, func(c *domain.Consent) interface{} { return c.Topic }),
+			"version":    gqlField(graphql.String, func(c *domain.Consent) interface{} { return c.Version }),
+			"isGranted":  gqlField(graphql.Boolean, func(c *domain.Consent) interface{} { return c.IsGranted }),
+			"timestamp":  gqlField(graphql.DateTime, func(c *domain.Consent) interface{} { return c.Timestamp }),
+			"createdAt":  gqlField(graphql.DateTime, func(c *domain.Consent) interface{} { return c.CreatedAt }),
+		},
+	})
+
+	customerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Customer",
+		Fields: graphql.Fields{
+			"id":                  gqlField(graphql.NewNonNull(graphql.ID), func(c *gqlCustomer) interface{} { return c.ID.String() }),
+			"customerNumber":      gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.CustomerNumber) }),
+			"type":                gqlField(graphql.String, func(c *gqlCustomer) interface{} { return string(c.Type) }),
+			"firstName":           gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.FirstName) }),
+			"lastName":            gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.LastName) }),
+			"title":               gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.Title) }),
+			"dateOfBirth":         gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlDate(c.DateOfBirth) }),
+			"nationality":         gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.Nationality) }),
+			"companyName":         gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.CompanyName) }),
+			"registrationDate":    gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlDate(c.RegistrationDate) }),
+			"industryCode":        gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.IndustryCode) }),
+			"status":              gqlField(graphql.String, func(c *gqlCustomer) interface{} { return string(c.Status) }),
+			"membershipTier":      gqlField(graphql.String, func(c *gqlCustomer) interface{} { return c.MembershipTier }),
+			"pointsBalance":       gqlField(graphql.String, func(c *gqlCustomer) interface{} { return c.PointsBalance.String() }),
+			"clv":                 gqlField(graphql.String, func(c *gqlCustomer) interface{} { return c.CLV.String() }),
+			"portfolioSize":       gqlField(graphql.String, func(c *gqlCustomer) interface{} { return c.PortfolioSize.String() }),
+			"currency":            gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.Currency) }),
+			"lastTransactionDate": gqlField(graphql.DateTime, func(c *gqlCustomer) interface{} { return c.LastTransactionDate }),
+			"preferredChannel":    gqlField(graphql.String, func(c *gqlCustomer) interface{} { return gqlString(c.PreferredChannel) }),
+			"isHighValue":         gqlField(graphql.Boolean, func(c *gqlCustomer) interface{} { return c.IsHighValue }),
+			"withheld":            gqlField(graphql.Boolean, func(c *gqlCustomer) interface{} { return c.withheld }),
+			"createdAt":           gqlField(graphql.DateTime, func(c *gqlCustomer) interface{} { return c.CreatedAt }),
+			"updatedAt":           gqlField(graphql.DateTime, func(c *gqlCustomer) interface{} { return c.UpdatedAt }),
+			"addresses": gqlSubResource(addressType, func(l *gqlLoaders) gqlLoad {
+				return l.addresses.load
+			}),
+			"identities": gqlSubResource(identityType, func(l *gqlLoaders) gqlLoad {
+				return l.identities.load
+			}),
+			"consents": gqlSubResource(consentType, func(l *gqlLoaders) gqlLoad {
+				return l.consents.load
+			}),
+		},
+	})
+
+	relationshipType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Relationship",
+		Description: "A relationship seen from the customer it was reached through.",
+		Fields: graphql.Fields{
+			"id":             gqlField(graphql.NewNonNull(graphql.ID), func(r *gqlRelationship) interface{} { return r.ID.String() }),
+			"role":           gqlField(graphql.String, func(r *gqlRelationship) interface{} { return r.Role }),
+			"fromCustomerId": gqlField(graphql.NewNonNull(graphql.ID), func(r *gqlRelationship) interface{} { return r.FromCustomerID.String() }),
+			"toCustomerId":   gqlField(graphql.NewNonNull(graphql.ID), func(r *gqlRelationship) interface{} { return r.ToCustomerID.String() }),
+			"direction": {
+				Type:        graphql.String,
+				Description: "OUTGOING when the customer it was reached through is the from side, INCOMING otherwise.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if r := p.Source.(*gqlRelationship); r.FromCustomerID == r.from {
+						return "OUTGOING", nil
+					}
+					return "INCOMING", nil
+				},
+			},
+			"customer": {
+				Type:        customerType,
+				Description: "The customer at the other end.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := p.Source.(*gqlRelationship)
+					other := r.ToCustomerID
+					if other == r.from {
+						other = r.FromCustomerID
+					}
+					return gqlRequestFrom(p.Context).loaders.customers.load(p.Context, other), nil
+				},
+			},
+			"createdAt": gqlField(graphql.DateTime, func(r *gqlRelationship) interface{} { return r.CreatedAt }),
+		},
+	})
+	customerType.AddFieldConfig("relationships", &graphql.Field{
+		Type: graphql.NewList(graphql.NewNonNull(relationshipType)),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			c := p.Source.(*gqlCustomer)
+			if c.withheld {
+				return nil, errGQLProtectedCustomer
+			}
+			thunk := gqlRequestFrom(p.Context).loaders.relationships.load(p.Context, c.ID)
+			return func() (interface{}, error) {
+				v, err := thunk()
+				if err != nil {
+					return nil, err
+				}
+				rels := v.([]*domain.Relationship)
+				out := make([]*gqlRelationship, len(rels))
+				for i, r := range rels {
+					out[i] = &gqlRelationship{Relationship: r, from: c.ID}
+				}
+				return out, nil
+			}, nil
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CustomerConnection",
+		Fields: graphql.Fields{
+			"nodes": gqlField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(customerType))),
+				func(c *gqlCustomerConnection) interface{} { return c.nodes }),
+			"totalCount": gqlField(graphql.NewNonNull(graphql.Int), func(c *gqlCustomerConnection) interface{} { return c.totalCount }),
+			"nextCursor": gqlField(graphql.String, func(c *gqlCustomerConnection) interface{} { return gqlString(c.nextCursor) }),
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"customer": {
+				Type:        customerType,
+				Description: "A customer by id. Protected customers need an approved access grant, as on GET /customers/{id}.",
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: resolveGQLCustomer,
+			},
+			"customers": {
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "Customers matching a filter, newest first. Protected customers the caller may not read are withheld.",
+				Args: graphql.FieldConfigArgument{
+					"search":    {Type: graphql.String, Description: "Name or customer number"},
+					"fuzzy":     {Type: graphql.Boolean},
+					"types":     {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"statuses":  {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"tiers":     {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"highValue": {Type: graphql.Boolean},
+					"first":     {Type: graphql.Int, Description: "Page size, at most 200"},
+					"after":     {Type: graphql.String, Description: "nextCursor from the previous page"},
+				},
+				Resolve: resolveGQLCustomers,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+type gqlCustomerConnection struct {
+	nodes      []*gqlCustomer
+	totalCount int
+	nextCursor string
+}
+
+func resolveGQLCustomer(p graphql.ResolveParams) (interface{}, error) {
+	req := gqlRequestFrom(p.Context)
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, errors.New("Invalid customer ID")
+	}
+	if req.viewer.access != nil {
+		if err := req.viewer.checkAccess(p.Context, id); err != nil {
+			if errors.Is(err, domain.ErrAccessGrantRequired) {
+				return nil, errGQLProtectedCustomer
+			}
+			return nil, err
+		}
+	}
+	return req.loaders.customers.load(p.Context, id), nil
+}
+
+func resolveGQLCustomers(p graphql.ResolveParams) (interface{}, error) {
+	req := gqlRequestFrom(p.Context)
+	q := domain.CustomerQuery{Limit: gqlPageSize(0)}
+	q.Filter.Text, _ = p.Args["search"].(string)
+	q.Filter.Fuzzy, _ = p.Args["fuzzy"].(bool)
+	for _, t := range gqlStrings(p.Args["types"]) {
+		q.Filter.Types = append(q.Filter.Types, domain.CustomerType(t))
+	}
+	for _, s := range gqlStrings(p.Args["statuses"]) {
+		q.Filter.Statuses = append(q.Filter.Statuses, domain.CustomerStatus(s))
+	}
+	q.Filter.Tiers = gqlStrings(p.Args["tiers"])
+	if hv, ok := p.Args["highValue"].(bool); ok {
+		q.Filter.HighValue = &hv
+	}
+	if first, ok := p.Args["first"].(int); ok {
+		q.Limit = first
+	}
+	if after, _ := p.Args["after"].(string); after != "" {
+		cursor, err := domain.DecodePageCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		q.Cursor = cursor
+	}
+
+	page, err := req.customers.QueryCustomers(p.Context, q)
+	if err != nil {
+		return nil, err
+	}
+	conn := &gqlCustomerConnection{totalCount: page.TotalCount, nextCursor: page.NextCursor}
+	for _, c := range page.Customers {
+		node, err := req.viewer.view(p.Context, c)
+		if err != nil {
+			return nil, err
+		}
+		req.loaders.customers.prime(c.ID, node)
+		conn.nodes = append(conn.nodes, node)
+	}
+	return conn, nil
+}
+
+// gqlLoad queues a customer's sub-resources on a loader.
+type gqlLoad func(ctx context.Context, id uuid.UUID) func() (interface{}, error)
+
+// gqlSubResource lists one kind of a customer's sub-resources through its loader. The list is
+// nullable so a withheld customer's error nulls only that field, not the customer.
+func gqlSubResource(t *graphql.Object, loader func(*gqlLoaders) gqlLoad) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewList(graphql.NewNonNull(t)),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			c := p.Source.(*gqlCustomer)
+			if c.withheld {
+				return nil, errGQLProtectedCustomer
+			}
+			return loader(gqlRequestFrom(p.Context).loaders)(p.Context, c.ID), nil
+		},
+	}
+}
+
+// gqlField is a field read from the source object with get.
+func gqlField[S any](t graphql.Output, get func(S) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(S)), nil
+		},
+	}
+}
+
+// gqlString maps an empty string to null.
+func gqlString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// gqlDate formats a calendar date, null when unset.
+func gqlDate(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+func gqlStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	var out []string
+	for _, s := range list {
+		out = append(out, s.(string))
+	}
+	return out
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// HoldingHandler handles the product catalog and the policies and accounts customers hold
+type HoldingHandler struct {
+	service ports.HoldingService
+}
+
+func NewHoldingHandler(service ports.HoldingService) *HoldingHandler {
+	return &HoldingHandler{service: service}
+}
+
+// @Summary List products
+// @Tags holdings
+// @Produce json
+// @Param all query bool false "Include products no longer offered"
+// @Success 200 {array} domain.Product
+// @Router /api/v1/products [get]
+func (h *HoldingHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.service.ListProducts(r.Context(), r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if products == nil {
+		products = []*domain.Product{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// @Summary Create or update product
+// @Description Set is_active to false to stop new holdings on the product; existing holdings are kept.
+// @Tags holdings
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param product body domain.Product true "Product"
+// @Success 200 {object} domain.Product
+// @Router /api/v1/products/{code} [put]
+func (h *HoldingHandler) SaveProduct(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var p domain.Product
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	p.Code = mux.Vars(r)["code"]
+
+	if err := h.service.SaveProduct(r.Context(), &p, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary List holdings
+// @Tags holdings
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Success 200 {array} domain.Holding
+// @Router /api/v1/customers/{id}/holdings [get]
+func (h *HoldingHandler) ListHoldings(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	holdings, err := h.service.List(r.Context(), customerID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if holdings == nil {
+		holdings = []*domain.Holding{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holdings)
+}
+
+// @Summary Add holding
+// @Description Links the customer to a policy or account. The customer's portfolio_size is recomputed from active OWNER holdings.
+// @Tags holdings
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param holding body domain.Holding true "Holding"
+// @Success 201 {object} domain.Holding
+// @Failure 409 {string} string "Holding already exists"
+// @Router /api/v1/customers/{id}/holdings [post]
+func (h *HoldingHandler) AddHolding(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var holding domain.Holding
+	if err := json.NewDecoder(r.Body).Decode(&holding); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	holding.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Add(r.Context(), &holding, userID); err != nil {
+		writeHoldingError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(holding)
+}
+
+// @Summary Update holding
+// @Tags holdings
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param holdingId path string true "Holding ID"
+// @Param holding body domain.Holding true "Holding"
+// @Success 200 {object} domain.Holding
+// @Router /api/v1/customers/{id}/holdings/{holdingId} [put]
+func (h *HoldingHandler) UpdateHolding(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := holdingIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var holding domain.Holding
+	if err := json.NewDecoder(r.Body).Decode(&holding); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	holding.ID = id
+	holding.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Update(r.Context(), &holding, userID); err != nil {
+		writeHoldingError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holding)
+}
+
+// @Summary Remove holding
+// @Tags holdings
+// @Param id path string true "Customer ID"
+// @Param holdingId path string true "Holding ID"
+// @Success 204 "No Content"
+// @Router /api/v1/customers/{id}/holdings/{holdingId} [delete]
+func (h *HoldingHandler) RemoveHolding(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := holdingIDs(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Remove(r.Context(), customerID, id, userID); err != nil {
+		writeHoldingError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func holdingIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(vars["holdingId"])
+	if err != nil {
+		http.Error(w, "Invalid holding ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return customerID, id, true
+}
+
+func writeHoldingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
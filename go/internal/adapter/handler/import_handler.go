@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/adapter/cas"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ImportHandler runs and reports on legacy data imports
+type ImportHandler struct {
+	service ports.CASImportService
+}
+
+func NewImportHandler(service ports.CASImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+type startImportRequest struct {
+	DryRun      bool       `json:"dry_run"`
+	ResumeRunID *uuid.UUID `json:"resume_run_id,omitempty"`
+	Limit       int        `json:"limit,omitempty"`
+}
+
+// @Summary Start CAS client import
+// @Description Imports CAS clients in the background and returns the run to poll. Clients already imported are skipped.
+// @Description Set resume_run_id to continue a failed run from its checkpoint; a dry run records rejects without writing customers.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Param request body startImportRequest false "Options"
+// @Success 202 {object} domain.ImportRun
+// @Router /api/v1/imports/cas [post]
+func (h *ImportHandler) StartCASImport(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req startImportRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	run, err := h.service.Start(r.Context(), ports.ImportOptions{
+		DryRun:      req.DryRun,
+		ResumeRunID: req.ResumeRunID,
+		Limit:       req.Limit,
+		StartedBy:   userID.String(),
+	})
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(run)
+}
+
+// @Summary List import runs
+// @Tags imports
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.ImportRun
+// @Router /api/v1/imports [get]
+func (h *ImportHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	runs, err := h.service.ListRuns(r.Context(), limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if runs == nil {
+		runs = []*domain.ImportRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// @Summary Get import run
+// @Tags imports
+// @Produce json
+// @Param id path string true "Run ID"
+// @Success 200 {object} domain.ImportRun
+// @Router /api/v1/imports/{id} [get]
+func (h *ImportHandler) GetRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := importRunID(w, r)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.GetRun(r.Context(), id)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// @Summary Import reject report
+// @Description Source records the run could not import and why. format=csv downloads the report as CSV.
+// @Tags imports
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Run ID"
+// @Param format query string false "json or csv" default(json)
+// @Success 200 {array} domain.ImportReject
+// @Router /api/v1/imports/{id}/rejects [get]
+func (h *ImportHandler) GetRejects(w http.ResponseWriter, r *http.Request) {
+	id, ok := importRunID(w, r)
+	if !ok {
+		return
+	}
+
+	rejects, err := h.service.Rejects(r.Context(), id)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="rejects-`+id.String()+`.csv"`)
+		cas.WriteRejects(w, rejects)
+		return
+	}
+	if rejects == nil {
+		rejects = []*domain.ImportReject{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rejects)
+}
+
+func importRunID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid run ID", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func writeImportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasSuffix(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.HasSuffix(err.Error(), "not configured"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
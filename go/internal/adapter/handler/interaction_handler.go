@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// InteractionHandler handles customer contacts and notes
+type InteractionHandler struct {
+	service ports.InteractionService
+}
+
+func NewInteractionHandler(service ports.InteractionService) *InteractionHandler {
+	return &InteractionHandler{service: service}
+}
+
+// @Summary List interactions
+// @Description Calls, emails, visits and notes for a customer; pinned entries first, then newest first
+// @Tags interactions
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param channel query string false "Filter by channel"
+// @Param pinned query bool false "Only pinned entries"
+// @Param from query string false "Earliest occurred_at (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest occurred_at, exclusive (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.Interaction
+// @Router /api/v1/customers/{id}/interactions [get]
+func (h *InteractionHandler) ListInteractions(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	f := domain.InteractionFilter{
+		Channel:    domain.InteractionChannel(strings.ToUpper(query.Get("channel"))),
+		PinnedOnly: query.Get("pinned") == "true",
+	}
+	if f.From, err = parseTimelineTime(query.Get("from"), false); err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return
+	}
+	if f.To, err = parseTimelineTime(query.Get("to"), true); err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return
+	}
+	f.Limit, _ = strconv.Atoi(query.Get("limit"))
+	f.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+	interactions, err := h.service.List(r.Context(), customerID, f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if interactions == nil {
+		interactions = []*domain.Interaction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(interactions)
+}
+
+// @Summary Get interaction
+// @Tags interactions
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param interactionId path string true "Interaction ID"
+// @Success 200 {object} domain.Interaction
+// @Router /api/v1/customers/{id}/interactions/{interactionId} [get]
+func (h *InteractionHandler) GetInteraction(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := interactionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	i, err := h.service.Get(r.Context(), customerID, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(i)
+}
+
+// @Summary Record interaction
+// @Description Log a call, email, visit or internal note. The current user is recorded as the author.
+// @Tags interactions
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param interaction body domain.Interaction true "Interaction"
+// @Success 201 {object} domain.Interaction
+// @Router /api/v1/customers/{id}/interactions [post]
+func (h *InteractionHandler) CreateInteraction(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var i domain.Interaction
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	i.CustomerID = customerID
+	i.StaffUserID = userID
+
+	if err := h.service.Create(r.Context(), &i); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(i)
+}
+
+// @Summary Update interaction
+// @Description Only the author or an admin may edit. Omitting occurred_at keeps the original time.
+// @Tags interactions
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param interactionId path string true "Interaction ID"
+// @Param interaction body domain.Interaction true "Interaction"
+// @Success 200 {object} domain.Interaction
+// @Failure 403 {string} string "Not the author"
+// @Router /api/v1/customers/{id}/interactions/{interactionId} [put]
+func (h *InteractionHandler) UpdateInteraction(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := interactionIDs(w, r)
+	if !ok {
+		return
+	}
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var i domain.Interaction
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	i.ID = id
+	i.CustomerID = customerID
+
+	if err := h.service.Update(r.Context(), &i, userID, isAdminRole(claims.Role)); err != nil {
+		writeInteractionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(i)
+}
+
+// @Summary Delete interaction
+// @Description Only the author or an admin may delete.
+// @Tags interactions
+// @Param id path string true "Customer ID"
+// @Param interactionId path string true "Interaction ID"
+// @Success 204 "No Content"
+// @Failure 403 {string} string "Not the author"
+// @Router /api/v1/customers/{id}/interactions/{interactionId} [delete]
+func (h *InteractionHandler) DeleteInteraction(w http.ResponseWriter, r *http.Request) {
+	customerID, id, ok := interactionIDs(w, r)
+	if !ok {
+		return
+	}
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), customerID, id, userID, isAdminRole(claims.Role)); err != nil {
+		writeInteractionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func interactionIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(vars["interactionId"])
+	if err != nil {
+		http.Error(w, "Invalid interaction ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return customerID, id, true
+}
+
+func writeInteractionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// OrgHandler handles branches, teams, supervisor chains and delegations
+type OrgHandler struct {
+	service ports.HierarchyService
+}
+
+func NewOrgHandler(service ports.HierarchyService) *OrgHandler {
+	return &OrgHandler{service: service}
+}
+
+// @Summary List branches
+// @Tags org
+// @Produce json
+// @Success 200 {array} models.Branch
+// @Router /api/v1/org/branches [get]
+func (h *OrgHandler) ListBranches(w http.ResponseWriter, r *http.Request) {
+	branches, err := h.service.ListBranches(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if branches == nil {
+		branches = []*models.Branch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(branches)
+}
+
+// @Summary Create branch
+// @Tags org
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Branch
+// @Router /api/v1/org/branches [post]
+func (h *OrgHandler) CreateBranch(w http.ResponseWriter, r *http.Request) {
+	var b models.Branch
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CreateBranch(r.Context(), &b); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(b)
+}
+
+// @Summary List teams
+// @Tags org
+// @Produce json
+// @Param branch_id query string false "Filter by branch ID"
+// @Success 200 {array} models.Team
+// @Router /api/v1/org/teams [get]
+func (h *OrgHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	var branchID *uuid.UUID
+	if v := r.URL.Query().Get("branch_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid branch_id", http.StatusBadRequest)
+			return
+		}
+		branchID = &id
+	}
+
+	teams, err := h.service.ListTeams(r.Context(), branchID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if teams == nil {
+		teams = []*models.Team{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(teams)
+}
+
+// @Summary Create team
+// @Tags org
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Team
+// @Router /api/v1/org/teams [post]
+func (h *OrgHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	var t models.Team
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CreateTeam(r.Context(), &t); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(t)
+}
+
+// @Summary Assign user to team and branch
+// @Tags org
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Router /api/v1/users/{id}/org [put]
+func (h *OrgHandler) AssignUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		TeamID   *uuid.UUID `json:"team_id"`
+		BranchID *uuid.UUID `json:"branch_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AssignUser(r.Context(), userID, req.TeamID, req.BranchID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "User assignment updated"})
+}
+
+// @Summary Supervisor chain
+// @Description Returns the user's supervisors, nearest first, up to the configured depth
+// @Tags org
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} models.User
+// @Router /api/v1/users/{id}/supervisors [get]
+func (h *OrgHandler) GetSupervisors(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	chain, err := h.service.Supervisors(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if chain == nil {
+		chain = []*models.User{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chain)
+}
+
+// @Summary List my delegations
+// @Description Delegations the current user has given or received
+// @Tags delegations
+// @Produce json
+// @Success 200 {array} models.Delegation
+// @Router /api/v1/delegations [get]
+func (h *OrgHandler) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	delegations, err := h.service.ListDelegations(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if delegations == nil {
+		delegations = []*models.Delegation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delegations)
+}
+
+// @Summary Delegate approval rights
+// @Description Hand the current user's supervisor rights to another user until ends_at
+// @Tags delegations
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Delegation
+// @Router /api/v1/delegations [post]
+func (h *OrgHandler) CreateDelegation(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var d models.Delegation
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	d.DelegatorID = userID
+
+	if err := h.service.Delegate(r.Context(), &d); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(d)
+}
+
+// @Summary Revoke delegation
+// @Tags delegations
+// @Produce json
+// @Param id path string true "Delegation ID"
+// @Success 200 {object} map[string]string
+// @Router /api/v1/delegations/{id} [delete]
+func (h *OrgHandler) RevokeDelegation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid delegation ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.RevokeDelegation(r.Context(), id, userID, isAdminRole(claims.Role)); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Delegation revoked"})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+)
+
+// pageRequestFromQuery reads limit, offset and cursor. cursorMode reports whether the client sent
+// a cursor parameter (empty for the first page) and so expects the domain.Page envelope; without
+// one, lists keep answering with a bare array for offset clients.
+func pageRequestFromQuery(v url.Values, defaultLimit int) (p domain.PageRequest, cursorMode bool, err error) {
+	p.Limit, _ = strconv.Atoi(v.Get("limit"))
+	if p.Limit <= 0 {
+		p.Limit = defaultLimit
+	}
+	if p.Offset, _ = strconv.Atoi(v.Get("offset")); p.Offset < 0 {
+		p.Offset = 0
+	}
+	if !v.Has("cursor") {
+		return p, false, nil
+	}
+	if raw := v.Get("cursor"); raw != "" {
+		if p.Cursor, err = domain.DecodePageCursor(raw); err != nil {
+			return p, true, err
+		}
+	}
+	return p, true, nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// PointsHandler handles the loyalty points ledger endpoints
+type PointsHandler struct {
+	service ports.PointsService
+}
+
+func NewPointsHandler(service ports.PointsService) *PointsHandler {
+	return &PointsHandler{service: service}
+}
+
+// @Summary List points transactions
+// @Description Ledger entries for a customer, newest first
+// @Tags points
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.PointsTransaction
+// @Router /api/v1/customers/{id}/points/transactions [get]
+func (h *PointsHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	txs, err := h.service.List(r.Context(), id, limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if txs == nil {
+		txs = []*domain.PointsTransaction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(txs)
+}
+
+// @Summary Post points transaction
+// @Description Earn, burn, adjust or reverse points. Burns draw on the oldest unexpired points first.
+// @Description Replaying an idempotency key (body or Idempotency-Key header) returns the original transaction with 200.
+// @Tags points
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param Idempotency-Key header string false "Idempotency key"
+// @Param transaction body domain.PointsRequest true "Transaction"
+// @Success 201 {object} domain.PointsTransaction
+// @Success 200 {object} domain.PointsTransaction
+// @Failure 409 {string} string "Transaction already reversed"
+// @Failure 422 {string} string "Insufficient points"
+// @Router /api/v1/customers/{id}/points/transactions [post]
+func (h *PointsHandler) PostTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.PointsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	}
+
+	tx, created, err := h.service.Post(r.Context(), id, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInsufficientPoints):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(tx)
+}
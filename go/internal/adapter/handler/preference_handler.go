@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// PreferenceHandler handles customer communication preferences
+type PreferenceHandler struct {
+	service ports.PreferenceService
+}
+
+func NewPreferenceHandler(service ports.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{service: service}
+}
+
+// @Summary Get communication preferences
+// @Tags preferences
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Success 200 {object} domain.CommunicationPreferences
+// @Router /api/v1/customers/{id}/communication-preferences [get]
+func (h *PreferenceHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.service.Get(r.Context(), customerID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary Replace communication preferences
+// @Description Replaces the whole purpose × channel matrix. Quiet hours are HH:MM in Bangkok time and may cross midnight.
+// @Tags preferences
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param preferences body domain.CommunicationPreferences true "Preferences"
+// @Success 200 {object} domain.CommunicationPreferences
+// @Router /api/v1/customers/{id}/communication-preferences [put]
+func (h *PreferenceHandler) SavePreferences(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var p domain.CommunicationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	p.CustomerID = customerID
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.service.Save(r.Context(), &p, userID); err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary Best channel for a purpose
+// @Description The most preferred channel usable now (or at "at") given quiet hours, allowed days, consent and known contacts.
+// @Description "channel" is omitted when nothing is usable; "skipped" explains each channel passed over.
+// @Tags preferences
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param purpose query string true "STATEMENT, PROMOTION, SERVICE or SECURITY"
+// @Param at query string false "Evaluate at this RFC3339 time instead of now"
+// @Success 200 {object} domain.ChannelDecision
+// @Router /api/v1/customers/{id}/communication-preferences/best-channel [get]
+func (h *PreferenceHandler) BestChannel(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	purpose, err := domain.ParseCommPurpose(r.URL.Query().Get("purpose"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	at := time.Now()
+	if v := r.URL.Query().Get("at"); v != "" {
+		if at, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "Invalid at, want RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	d, err := h.service.BestChannel(r.Context(), customerID, purpose, at)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d)
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SegmentHandler handles saved customer segments
+type SegmentHandler struct {
+	service ports.SegmentService
+	access  ports.AccessGrantService
+}
+
+// NewSegmentHandler withholds protected customers in evaluations and exports from users who
+// may not read them when access is not nil.
+func NewSegmentHandler(service ports.SegmentService, access ports.AccessGrantService) *SegmentHandler {
+	return &SegmentHandler{service: service, access: access}
+}
+
+// @Summary List segments
+// @Description Segments the caller owns or that are shared with their role; admins see all.
+// @Tags segments
+// @Produce json
+// @Success 200 {array} domain.Segment
+// @Router /api/v1/segments [get]
+func (h *SegmentHandler) ListSegments(w http.ResponseWriter, r *http.Request) {
+	u, ok := segmentUser(w, r)
+	if !ok {
+		return
+	}
+	segments, err := h.service.List(r.Context(), u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(segments)
+}
+
+// @Summary Create segment
+// @Description Saves a named customer filter owned by the caller. The filter takes the same fields as customer search;
+// @Description schedule DAILY materializes it every night.
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Param segment body domain.Segment true "Segment"
+// @Success 201 {object} domain.Segment
+// @Router /api/v1/segments [post]
+func (h *SegmentHandler) CreateSegment(w http.ResponseWriter, r *http.Request) {
+	u, ok := segmentUser(w, r)
+	if !ok {
+		return
+	}
+	var s domain.Segment
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Create(r.Context(), &s, u); err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Get segment
+// @Tags segments
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Success 200 {object} domain.Segment
+// @Router /api/v1/segments/{id} [get]
+func (h *SegmentHandler) GetSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	s, err := h.service.Get(r.Context(), id, u)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Update segment
+// @Description Replaces the name, filter, sharing and schedule. Owner or admin only.
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Param segment body domain.Segment true "Segment"
+// @Success 200 {object} domain.Segment
+// @Router /api/v1/segments/{id} [put]
+func (h *SegmentHandler) UpdateSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	var s domain.Segment
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	s.ID = id
+	if err := h.service.Update(r.Context(), &s, u); err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Delete segment
+// @Description Deletes the segment with its runs and membership history. Owner or admin only.
+// @Tags segments
+// @Param id path string true "Segment ID"
+// @Success 204
+// @Router /api/v1/segments/{id} [delete]
+func (h *SegmentHandler) DeleteSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(r.Context(), id, u); err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Evaluate segment
+// @Description Runs the segment's filter now. Protected customers the caller may not read are returned withheld.
+// @Tags segments
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Param sort query string false "Comma-separated fields, - for descending" default(-created_at)
+// @Param limit query int false "Page size, at most 200" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "next_cursor or prev_cursor from the previous page. Needs the default sort"
+// @Success 200 {object} domain.CustomerPage
+// @Router /api/v1/segments/{id}/customers [get]
+func (h *SegmentHandler) EvaluateSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	v := r.URL.Query()
+	var q domain.CustomerQuery
+	var err error
+	if q.Sort, err = domain.ParseSort(v.Get("sort")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q.Limit, _ = strconv.Atoi(v.Get("limit"))
+	q.Offset, _ = strconv.Atoi(v.Get("offset"))
+	if raw := v.Get("cursor"); raw != "" {
+		if q.Cursor, err = domain.DecodePageCursor(raw); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.service.Evaluate(r.Context(), id, u, q)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	if _, err := withholdCustomers(r, h.access, page.Customers); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// @Summary Export segment
+// @Description Every customer currently in the segment as CSV. Protected customers the caller may not read are exported withheld.
+// @Tags segments
+// @Produce text/csv
+// @Param id path string true "Segment ID"
+// @Success 200 {string} string "CSV"
+// @Router /api/v1/segments/{id}/export [get]
+func (h *SegmentHandler) ExportSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	customers, err := h.service.Export(r.Context(), id, u)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	if _, err := withholdCustomers(r, h.access, customers); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="segment-`+id.String()+`.csv"`)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "customer_number", "type", "first_name", "last_name", "company_name", "status", "membership_tier"})
+	for _, c := range customers {
+		cw.Write([]string{c.ID.String(), c.CustomerNumber, string(c.Type), c.FirstName, c.LastName, c.CompanyName,
+			string(c.Status), c.MembershipTier})
+	}
+	cw.Flush()
+}
+
+// @Summary Materialize segment
+// @Description Snapshots the segment's current members as a new run, recording who entered and left since the last one. Owner or admin only.
+// @Tags segments
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Success 201 {object} domain.SegmentRun
+// @Router /api/v1/segments/{id}/materialize [post]
+func (h *SegmentHandler) MaterializeSegment(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	run, err := h.service.Materialize(r.Context(), id, u)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(run)
+}
+
+// @Summary List segment runs
+// @Description Materializations of the segment, newest first
+// @Tags segments
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.SegmentRun
+// @Router /api/v1/segments/{id}/runs [get]
+func (h *SegmentHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	runs, err := h.service.Runs(r.Context(), id, u, limit, offset)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	if runs == nil {
+		runs = []*domain.SegmentRun{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// @Summary Segment membership changes
+// @Description Customers who entered or left the segment between two runs; by default since the run before.
+// @Tags segments
+// @Produce json
+// @Param id path string true "Segment ID"
+// @Param runId path string true "Run ID"
+// @Param from query string false "Compare with this run instead of the previous one"
+// @Success 200 {object} domain.SegmentDiff
+// @Router /api/v1/segments/{id}/runs/{runId}/changes [get]
+func (h *SegmentHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	id, u, ok := segmentRequest(w, r)
+	if !ok {
+		return
+	}
+	runID, err := uuid.Parse(mux.Vars(r)["runId"])
+	if err != nil {
+		http.Error(w, "Invalid run ID", http.StatusBadRequest)
+		return
+	}
+	var from *uuid.UUID
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		fromID, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "Invalid from run ID", http.StatusBadRequest)
+			return
+		}
+		from = &fromID
+	}
+
+	diff, err := h.service.Changes(r.Context(), id, runID, from, u)
+	if err != nil {
+		writeSegmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// segmentUser builds the acting user from the token, writing 401 when there is none.
+func segmentUser(w http.ResponseWriter, r *http.Request) (domain.SegmentUser, bool) {
+	userID, claims, err := currentUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return domain.SegmentUser{}, false
+	}
+	return domain.SegmentUser{ID: userID, Role: claims.Role, Admin: isAdminRole(claims.Role)}, true
+}
+
+func segmentRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, domain.SegmentUser, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid segment ID", http.StatusBadRequest)
+		return uuid.Nil, domain.SegmentUser{}, false
+	}
+	u, ok := segmentUser(w, r)
+	return id, u, ok
+}
+
+func writeSegmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
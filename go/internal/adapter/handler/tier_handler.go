@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// TierHandler exposes the tier rules, per-customer tier history and manual recalculation
+type TierHandler struct {
+	service ports.TierService
+}
+
+func NewTierHandler(service ports.TierService) *TierHandler {
+	return &TierHandler{service: service}
+}
+
+// @Summary Get tier rules
+// @Description Returns the active, versioned tier and high-value rules
+// @Tags tiers
+// @Produce json
+// @Success 200 {object} tiering.Config
+// @Router /api/v1/tiers/rules [get]
+func (h *TierHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.service.Rules())
+}
+
+// @Summary Customer tier history
+// @Description Tier and high-value changes for a customer, newest first, with the rule version that produced each
+// @Tags tiers
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} domain.TierChange
+// @Router /api/v1/customers/{id}/tier-history [get]
+func (h *TierHandler) GetTierHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	history, err := h.service.History(r.Context(), id, limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		history = []*domain.TierChange{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// @Summary Recalculate all tiers
+// @Description Re-derives every customer's tier with the current rules (normally run nightly)
+// @Tags tiers
+// @Produce json
+// @Success 200 {object} domain.TierRecalcResult
+// @Failure 409 {string} string "Recalculation already running"
+// @Router /api/v1/tiers/recalculate [post]
+func (h *TierHandler) Recalculate(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.RecalculateAll(r.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// TimelineHandler serves the customer activity feed
+type TimelineHandler struct {
+	service ports.TimelineService
+}
+
+func NewTimelineHandler(service ports.TimelineService) *TimelineHandler {
+	return &TimelineHandler{service: service}
+}
+
+// @Summary Customer activity timeline
+// @Description Audit events, status changes, consent grants and withdrawals, sub-resource changes and interactions, newest first.
+// @Description Pass next_cursor back as cursor to fetch the following page.
+// @Tags customers
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param type query string false "Comma-separated event types: customer,status,consent,address,identity,relationship,contact,tier,points,access,interaction"
+// @Param from query string false "Earliest event time (RFC3339 or YYYY-MM-DD), inclusive"
+// @Param to query string false "Latest event time (RFC3339 or YYYY-MM-DD), exclusive; a date includes that whole day"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Success 200 {object} domain.TimelinePage
+// @Router /api/v1/customers/{id}/timeline [get]
+func (h *TimelineHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var q domain.TimelineQuery
+	for _, v := range query["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				q.Types = append(q.Types, t)
+			}
+		}
+	}
+	if q.From, err = parseTimelineTime(query.Get("from"), false); err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return
+	}
+	if q.To, err = parseTimelineTime(query.Get("to"), true); err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("cursor"); v != "" {
+		if q.Cursor, err = domain.DecodeTimelineCursor(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.service.Timeline(r.Context(), id, q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseTimelineTime accepts RFC3339 or a plain date. A date used as the upper bound
+// means the end of that day.
+func parseTimelineTime(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
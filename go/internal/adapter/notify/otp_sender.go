@@ -0,0 +1,64 @@
+// Package notify holds OTPSender implementations. LogSender and FileSender are for
+// development only: they write the code in plain text instead of delivering it.
+// DisabledSender sends nothing, for deployments without a delivering sender.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+)
+
+// LogSender writes one-time codes to the application log.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(ctx context.Context, cp *domain.ContactPoint, code string) error {
+	log.Printf("OTP for %s %s (contact point %s): %s", cp.Type, cp.Value, cp.ID, code)
+	return nil
+}
+
+// DisabledSender refuses every code, so verification requests fail without a code being
+// written anywhere.
+type DisabledSender struct{}
+
+func NewDisabledSender() *DisabledSender {
+	return &DisabledSender{}
+}
+
+func (s *DisabledSender) Send(ctx context.Context, cp *domain.ContactPoint, code string) error {
+	return domain.ErrVerificationUnavailable
+}
+
+// FileSender appends one-time codes to a file, one line per code, so tests and local
+// tooling can pick them up.
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) Send(ctx context.Context, cp *domain.ContactPoint, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("otp sender: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\n", time.Now().UTC().Format(time.RFC3339), cp.ID, cp.Type, cp.Value, code)
+	return err
+}
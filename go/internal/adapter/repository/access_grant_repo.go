@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type accessGrantRepository struct {
+	db *sql.DB
+}
+
+func NewAccessGrantRepository(db *sql.DB) *accessGrantRepository {
+	return &accessGrantRepository{db: db}
+}
+
+const accessGrantColumns = `id, customer_id, requested_by, reason, status, approved_by, approved_at,
+		expires_at, use_count, last_used_at, created_at`
+
+func scanAccessGrant(row interface{ Scan(...interface{}) error }) (*domain.AccessGrant, error) {
+	g := &domain.AccessGrant{}
+	err := row.Scan(
+		&g.ID, &g.CustomerID, &g.RequestedBy, &g.Reason, &g.Status, &g.ApprovedBy, &g.ApprovedAt,
+		&g.ExpiresAt, &g.UseCount, &g.LastUsedAt, &g.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (r *accessGrantRepository) Create(ctx context.Context, g *domain.AccessGrant) error {
+	query := `
+		INSERT INTO customer_access_grants (
+			customer_id, requested_by, reason, status, approved_by, approved_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		g.CustomerID, g.RequestedBy, g.Reason, g.Status, g.ApprovedBy, g.ApprovedAt, g.ExpiresAt,
+	).Scan(&g.ID, &g.CreatedAt)
+}
+
+func (r *accessGrantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error) {
+	query := `SELECT ` + accessGrantColumns + ` FROM customer_access_grants WHERE id = $1`
+	g, err := scanAccessGrant(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("access grant not found")
+	}
+	return g, err
+}
+
+// Update moves a grant out of the from status. The conditional update makes sure two
+// approvers cannot both decide on the same request.
+func (r *accessGrantRepository) Update(ctx context.Context, g *domain.AccessGrant, from domain.AccessGrantStatus) error {
+	query := `
+		UPDATE customer_access_grants SET
+			status=$1, approved_by=$2, approved_at=$3, expires_at=$4
+		WHERE id=$5 AND status=$6
+	`
+	res, err := r.db.ExecContext(ctx, query, g.Status, g.ApprovedBy, g.ApprovedAt, g.ExpiresAt, g.ID, from)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: access grant is no longer %s", domain.ErrConflict, from)
+	}
+	return nil
+}
+
+// FindActive returns the most recent approved, unexpired grant for the user on the customer.
+func (r *accessGrantRepository) FindActive(ctx context.Context, customerID, userID uuid.UUID) (*domain.AccessGrant, error) {
+	query := `SELECT ` + accessGrantColumns + `
+		FROM customer_access_grants
+		WHERE customer_id = $1 AND requested_by = $2 AND status = 'APPROVED' AND expires_at > NOW()
+		ORDER BY expires_at DESC
+		LIMIT 1`
+	g, err := scanAccessGrant(r.db.QueryRowContext(ctx, query, customerID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return g, err
+}
+
+func (r *accessGrantRepository) RecordUse(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE customer_access_grants SET use_count = use_count + 1, last_used_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *accessGrantRepository) List(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error) {
+	query := `SELECT ` + accessGrantColumns + ` FROM customer_access_grants`
+	var conditions []string
+	var args []interface{}
+	argIdx := 1
+
+	if customerID != nil {
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", argIdx))
+		args = append(args, *customerID)
+		argIdx++
+	}
+	if userID != nil {
+		conditions = append(conditions, fmt.Sprintf("requested_by = $%d", argIdx))
+		args = append(args, *userID)
+		argIdx++
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []*domain.AccessGrant
+	for rows.Next() {
+		g, err := scanAccessGrant(rows)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/amnuaym/cic/go/internal/core/domain"
 	"github.com/google/uuid"
 )
 
@@ -40,32 +41,20 @@ func (r *AuditRepository) Create(ctx context.Context, log *AuditLog) error {
 	).Scan(&log.ID, &log.Timestamp)
 }
 
-func (r *AuditRepository) List(ctx context.Context, limit, offset int, action string, entityID string) ([]*AuditLog, error) {
-	query := `SELECT id, entity_id, entity_type, action, performed_by, timestamp, changes, ip_address
-		FROM audit_logs`
-	var conditions []string
-	var args []interface{}
-	argIdx := 1
-
+// List pages through audit entries, newest first, optionally for one action or entity.
+func (r *AuditRepository) List(ctx context.Context, p domain.PageRequest, action string, entityID string) (*domain.Page[*AuditLog], error) {
+	cond := &conditions{}
 	if action != "" {
-		conditions = append(conditions, fmt.Sprintf("action = $%d", argIdx))
-		args = append(args, action)
-		argIdx++
+		cond.add("action = ?", action)
 	}
 	if entityID != "" {
-		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", argIdx))
-		args = append(args, entityID)
-		argIdx++
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+		cond.add("entity_id = ?", entityID)
 	}
+	tail := keyset(cond, "timestamp", p)
+	query := `SELECT id, entity_id, entity_type, action, performed_by, timestamp, changes, ip_address
+		FROM audit_logs ` + cond.where() + " " + tail
 
-	query += fmt.Sprintf(" ORDER BY timestamp DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, limit, offset)
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, cond.args...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +69,12 @@ func (r *AuditRepository) List(ctx context.Context, limit, offset int, action st
 		}
 		logs = append(logs, l)
 	}
-	return logs, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.NewPage(logs, p, func(l *AuditLog) domain.PageCursor {
+		return domain.PageCursor{At: l.Timestamp, ID: l.ID}
+	}), nil
 }
 
 func (r *AuditRepository) GetByID(ctx context.Context, id uuid.UUID) (*AuditLog, error) {
@@ -95,3 +89,42 @@ func (r *AuditRepository) GetByID(ctx context.Context, id uuid.UUID) (*AuditLog,
 	}
 	return l, nil
 }
+
+// ListForEntity returns entries for one entity, newest first, at or before before and within [from, to).
+// Nil bounds are open.
+func (r *AuditRepository) ListForEntity(ctx context.Context, entityID uuid.UUID, from, to, before *time.Time, limit int) ([]*AuditLog, error) {
+	conditions := []string{"entity_id = $1"}
+	args := []interface{}{entityID}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("timestamp < $%d", len(args)))
+	}
+	if before != nil {
+		args = append(args, *before)
+		conditions = append(conditions, fmt.Sprintf("timestamp <= $%d", len(args)))
+	}
+	args = append(args, limit)
+	query := `SELECT id, entity_id, entity_type, action, performed_by, timestamp, changes, ip_address
+		FROM audit_logs WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY timestamp DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*AuditLog
+	for rows.Next() {
+		l := &AuditLog{}
+		if err := rows.Scan(&l.ID, &l.EntityID, &l.EntityType, &l.Action, &l.PerformedBy, &l.Timestamp, &l.Changes, &l.IPAddress); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type bankAccountRepository struct {
+	db *sql.DB
+}
+
+func NewBankAccountRepository(db *sql.DB) *bankAccountRepository {
+	return &bankAccountRepository{db: db}
+}
+
+const bankAccountColumns = `id, customer_id, bank_code, branch, account_number, account_name, account_type, purpose, is_primary, created_at, updated_at`
+
+func scanBankAccount(row interface{ Scan(...interface{}) error }) (*domain.BankAccount, error) {
+	b := &domain.BankAccount{}
+	var branch, accountName sql.NullString
+	err := row.Scan(&b.ID, &b.CustomerID, &b.BankCode, &branch, &b.AccountNumber, &accountName, &b.AccountType,
+		&b.Purpose, &b.IsPrimary, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	b.Branch = branch.String
+	b.AccountName = accountName.String
+	b.BankName = domain.ThaiBanks[b.BankCode]
+	return b, nil
+}
+
+func (r *bankAccountRepository) Create(ctx context.Context, b *domain.BankAccount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearPrimaryBankAccount(ctx, tx, b); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO customer_bank_accounts (customer_id, bank_code, branch, account_number, account_name, account_type, purpose, is_primary)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, b.CustomerID, b.BankCode, nullString(b.Branch), b.AccountNumber,
+		nullString(b.AccountName), b.AccountType, b.Purpose, b.IsPrimary,
+	).Scan(&b.ID, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return tx.Commit()
+}
+
+func (r *bankAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + ` FROM customer_bank_accounts WHERE id = $1`
+	b, err := scanBankAccount(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bank account %w", domain.ErrNotFound)
+	}
+	return b, err
+}
+
+func (r *bankAccountRepository) Update(ctx context.Context, b *domain.BankAccount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearPrimaryBankAccount(ctx, tx, b); err != nil {
+		return err
+	}
+	query := `
+		UPDATE customer_bank_accounts
+		SET bank_code=$1, branch=$2, account_number=$3, account_name=$4, account_type=$5, purpose=$6, is_primary=$7, updated_at=NOW()
+		WHERE id=$8
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, b.BankCode, nullString(b.Branch), b.AccountNumber, nullString(b.AccountName),
+		b.AccountType, b.Purpose, b.IsPrimary, b.ID,
+	).Scan(&b.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("bank account %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return tx.Commit()
+}
+
+// clearPrimaryBankAccount drops the primary flag from the customer's other accounts with the same purpose.
+func clearPrimaryBankAccount(ctx context.Context, tx *sql.Tx, b *domain.BankAccount) error {
+	if !b.IsPrimary {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE customer_bank_accounts SET is_primary = false, updated_at = NOW()
+		WHERE customer_id = $1 AND purpose = $2 AND is_primary AND id <> $3`,
+		b.CustomerID, b.Purpose, b.ID)
+	return err
+}
+
+func (r *bankAccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM customer_bank_accounts WHERE id = $1`, id)
+	return err
+}
+
+func (r *bankAccountRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.BankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + ` FROM customer_bank_accounts
+		WHERE customer_id = $1 ORDER BY purpose, is_primary DESC, created_at`
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domain.BankAccount
+	for rows.Next() {
+		b, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, b)
+	}
+	return accounts, rows.Err()
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type changeRequestRepository struct {
+	db *sql.DB
+}
+
+func NewChangeRequestRepository(db *sql.DB) *changeRequestRepository {
+	return &changeRequestRepository{db: db}
+}
+
+const changeRequestColumns = `id, operation, customer_id, payload, reason, status, maker_id, checker_id,
+		comment, error, expires_at, decided_at, created_at`
+
+func scanChangeRequest(row interface{ Scan(...interface{}) error }) (*domain.ChangeRequest, error) {
+	cr := &domain.ChangeRequest{}
+	var payload []byte
+	var reason, comment, errMsg sql.NullString
+	err := row.Scan(
+		&cr.ID, &cr.Operation, &cr.CustomerID, &payload, &reason, &cr.Status, &cr.MakerID, &cr.CheckerID,
+		&comment, &errMsg, &cr.ExpiresAt, &cr.DecidedAt, &cr.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	cr.Payload = payload
+	cr.Reason = reason.String
+	cr.Comment = comment.String
+	cr.Error = errMsg.String
+	return cr, nil
+}
+
+func (r *changeRequestRepository) Create(ctx context.Context, cr *domain.ChangeRequest) error {
+	query := `
+		INSERT INTO change_requests (
+			operation, customer_id, payload, reason, status, maker_id, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	var payload []byte
+	if len(cr.Payload) > 0 {
+		payload = cr.Payload
+	}
+	return r.db.QueryRowContext(ctx, query,
+		cr.Operation, cr.CustomerID, payload, cr.Reason, cr.Status, cr.MakerID, cr.ExpiresAt,
+	).Scan(&cr.ID, &cr.CreatedAt)
+}
+
+func (r *changeRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	query := `SELECT ` + changeRequestColumns + ` FROM change_requests WHERE id = $1`
+	cr, err := scanChangeRequest(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("change request not found")
+	}
+	return cr, err
+}
+
+// Update moves a request out of the from status. The conditional update makes sure two
+// checkers cannot both decide on the same request.
+func (r *changeRequestRepository) Update(ctx context.Context, cr *domain.ChangeRequest, from domain.ChangeRequestStatus) error {
+	query := `
+		UPDATE change_requests SET
+			status=$1, checker_id=$2, comment=$3, error=$4, decided_at=$5
+		WHERE id=$6 AND status=$7
+	`
+	res, err := r.db.ExecContext(ctx, query, cr.Status, cr.CheckerID, cr.Comment, cr.Error, cr.DecidedAt, cr.ID, from)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("change request is no longer %s", from)
+	}
+	return nil
+}
+
+func (r *changeRequestRepository) List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error) {
+	query := `SELECT ` + changeRequestColumns + ` FROM change_requests`
+	var args []interface{}
+	argIdx := 1
+	if status != "" {
+		query += fmt.Sprintf(" WHERE status = $%d", argIdx)
+		args = append(args, status)
+		argIdx++
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.ChangeRequest
+	for rows.Next() {
+		cr, err := scanChangeRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, cr)
+	}
+	return requests, nil
+}
+
+func (r *changeRequestRepository) ExpirePending(ctx context.Context) (int64, error) {
+	query := `UPDATE change_requests SET status = 'EXPIRED', decided_at = NOW()
+		WHERE status = 'PENDING' AND expires_at <= NOW()`
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
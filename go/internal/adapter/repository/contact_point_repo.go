@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type contactPointRepository struct {
+	db *sql.DB
+}
+
+func NewContactPointRepository(db *sql.DB) *contactPointRepository {
+	return &contactPointRepository{db: db}
+}
+
+const contactPointColumns = `id, customer_id, type, value, is_primary, verification_status, verified_at, created_at, updated_at`
+
+func scanContactPoint(row interface{ Scan(...interface{}) error }) (*domain.ContactPoint, error) {
+	cp := &domain.ContactPoint{}
+	err := row.Scan(&cp.ID, &cp.CustomerID, &cp.Type, &cp.Value, &cp.IsPrimary, &cp.Status, &cp.VerifiedAt,
+		&cp.CreatedAt, &cp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (r *contactPointRepository) Create(ctx context.Context, cp *domain.ContactPoint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearPrimary(ctx, tx, cp); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO customer_contact_points (customer_id, type, value, is_primary, verification_status, verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, cp.CustomerID, cp.Type, cp.Value, cp.IsPrimary, cp.Status, cp.VerifiedAt).
+		Scan(&cp.ID, &cp.CreatedAt, &cp.UpdatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return tx.Commit()
+}
+
+func (r *contactPointRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ContactPoint, error) {
+	query := `SELECT ` + contactPointColumns + ` FROM customer_contact_points WHERE id = $1`
+	cp, err := scanContactPoint(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("contact point %w", domain.ErrNotFound)
+	}
+	return cp, err
+}
+
+func (r *contactPointRepository) Update(ctx context.Context, cp *domain.ContactPoint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := clearPrimary(ctx, tx, cp); err != nil {
+		return err
+	}
+	query := `
+		UPDATE customer_contact_points
+		SET value=$1, is_primary=$2, verification_status=$3, verified_at=$4, updated_at=NOW()
+		WHERE id=$5
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, cp.Value, cp.IsPrimary, cp.Status, cp.VerifiedAt, cp.ID).Scan(&cp.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("contact point %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return tx.Commit()
+}
+
+// clearPrimary drops the primary flag from the customer's other contact points of the same type.
+func clearPrimary(ctx context.Context, tx *sql.Tx, cp *domain.ContactPoint) error {
+	if !cp.IsPrimary {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE customer_contact_points SET is_primary = false, updated_at = NOW()
+		WHERE customer_id = $1 AND type = $2 AND is_primary AND id <> $3`,
+		cp.CustomerID, cp.Type, cp.ID)
+	return err
+}
+
+func (r *contactPointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM customer_contact_points WHERE id = $1`, id)
+	return err
+}
+
+func (r *contactPointRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error) {
+	query := `SELECT ` + contactPointColumns + ` FROM customer_contact_points
+		WHERE customer_id = $1 ORDER BY type, is_primary DESC, created_at`
+	return r.query(ctx, query, customerID)
+}
+
+func (r *contactPointRepository) FindByValue(ctx context.Context, types []domain.ContactType, value string) ([]*domain.ContactPoint, error) {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	query := `SELECT ` + contactPointColumns + ` FROM customer_contact_points
+		WHERE value = $1 AND type = ANY($2) ORDER BY created_at`
+	return r.query(ctx, query, value, pq.Array(names))
+}
+
+func (r *contactPointRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ContactPoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cps []*domain.ContactPoint
+	for rows.Next() {
+		cp, err := scanContactPoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		cps = append(cps, cp)
+	}
+	return cps, rows.Err()
+}
+
+// SaveVerification replaces any outstanding code for the contact point.
+func (r *contactPointRepository) SaveVerification(ctx context.Context, v *domain.ContactVerification) error {
+	query := `
+		INSERT INTO contact_verifications (contact_point_id, code_hash, expires_at, attempts, created_at)
+		VALUES ($1, $2, $3, 0, NOW())
+		ON CONFLICT (contact_point_id) DO UPDATE SET code_hash = $2, expires_at = $3, attempts = 0, created_at = NOW()
+		RETURNING created_at
+	`
+	return r.db.QueryRowContext(ctx, query, v.ContactPointID, v.CodeHash, v.ExpiresAt).Scan(&v.CreatedAt)
+}
+
+func (r *contactPointRepository) GetVerification(ctx context.Context, contactPointID uuid.UUID) (*domain.ContactVerification, error) {
+	v := &domain.ContactVerification{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT contact_point_id, code_hash, expires_at, attempts, created_at FROM contact_verifications WHERE contact_point_id = $1`,
+		contactPointID,
+	).Scan(&v.ContactPointID, &v.CodeHash, &v.ExpiresAt, &v.Attempts, &v.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *contactPointRepository) UseVerificationAttempt(ctx context.Context, contactPointID uuid.UUID, maxAttempts int) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE contact_verifications SET attempts = attempts + 1 WHERE contact_point_id = $1 AND attempts < $2`,
+		contactPointID, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *contactPointRepository) DeleteVerification(ctx context.Context, contactPointID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM contact_verifications WHERE contact_point_id = $1`, contactPointID)
+	return err
+}
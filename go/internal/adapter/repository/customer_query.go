@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/utils/textnorm"
+	"github.com/lib/pq"
+)
+
+// Sortable fields and their columns. Only these ever reach ORDER BY.
+var customerSortColumns = map[string]string{
+	"created_at":            "created_at",
+	"updated_at":            "updated_at",
+	"last_transaction_date": "last_transaction_date",
+	"first_name":            "first_name",
+	"last_name":             "last_name",
+	"company_name":          "company_name",
+	"customer_number":       "customer_number",
+	"status":                "status",
+	"membership_tier":       "membership_tier",
+	"clv":                   "clv",
+	"portfolio_size":        "portfolio_size",
+	domain.SortByScore:      "score",
+}
+
+// searchName is the normalized name key stored for fuzzy search. It is empty rather than NULL
+// for a customer without a usable name, so IndexSearchNames does not revisit them.
+func searchName(c *domain.Customer) string {
+	return textnorm.Key(strings.Join([]string{c.FirstName, c.LastName, c.CompanyName}, " "))
+}
+
+// conditions collects WHERE clauses, numbering each ? placeholder as its argument is added.
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+func (c *conditions) add(clause string, args ...interface{}) {
+	for _, a := range args {
+		c.args = append(c.args, a)
+		clause = strings.Replace(clause, "?", fmt.Sprintf("$%d", len(c.args)), 1)
+	}
+	c.clauses = append(c.clauses, clause)
+}
+
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// customerConditions translates a filter to SQL over the customers table.
+func customerConditions(f domain.CustomerFilter) *conditions {
+	c := &conditions{}
+	c.add("deleted_at IS NULL")
+	if f.Text != "" {
+		number := strings.NewReplacer("-", "", " ", "").Replace(f.Text)
+		text := `(first_name ILIKE '%' || ? || '%' OR last_name ILIKE '%' || ? || '%' OR
+			company_name ILIKE '%' || ? || '%' OR customer_number = ?)`
+		if f.Fuzzy {
+			// <% is true when the key is close to some run of words in search_name, and can use
+			// the trigram index. Exact substring and number matches still count.
+			c.add("(? <% search_name OR "+text+")", textnorm.Key(f.Text), f.Text, f.Text, f.Text, number)
+		} else {
+			c.add(text, f.Text, f.Text, f.Text, number)
+		}
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = string(t)
+		}
+		c.add("type = ANY(?)", pq.Array(types))
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		c.add("status = ANY(?)", pq.Array(statuses))
+	}
+	if len(f.Tiers) > 0 {
+		c.add("UPPER(membership_tier) = ANY(?)", pq.Array(f.Tiers))
+	}
+	if len(f.Nationalities) > 0 {
+		c.add("nationality ILIKE ANY(?)", pq.Array(f.Nationalities))
+	}
+	if f.HighValue != nil {
+		c.add("COALESCE(is_high_value, false) = ?", *f.HighValue)
+	}
+	if f.IdentityNumber != "" {
+		c.add("EXISTS (SELECT 1 FROM identities i WHERE i.customer_id = customers.id AND i.number = ?)", f.IdentityNumber)
+	}
+	if f.Phone != "" {
+		c.add(`EXISTS (SELECT 1 FROM customer_contact_points cp WHERE cp.customer_id = customers.id
+			AND cp.type IN ('MOBILE', 'LANDLINE') AND cp.value = ?)`, f.Phone)
+	}
+	if f.Email != "" {
+		c.add(`EXISTS (SELECT 1 FROM customer_contact_points cp WHERE cp.customer_id = customers.id
+			AND cp.type = 'EMAIL' AND cp.value = ?)`, f.Email)
+	}
+	if f.ZipCode != "" || f.Province != "" || f.District != "" {
+		clause := "EXISTS (SELECT 1 FROM addresses a WHERE a.customer_id = customers.id"
+		var args []interface{}
+		if f.ZipCode != "" {
+			clause += " AND a.zip_code = ?"
+			args = append(args, f.ZipCode)
+		}
+		if f.Province != "" {
+			clause += " AND (LOWER(a.state) = LOWER(?) OR LOWER(a.city) = LOWER(?))"
+			args = append(args, f.Province, f.Province)
+		}
+		if f.District != "" {
+			clause += " AND LOWER(a.district) = LOWER(?)"
+			args = append(args, f.District)
+		}
+		c.add(clause+")", args...)
+	}
+	ranges := []struct {
+		column   string
+		from, to *time.Time
+	}{
+		{"created_at", f.CreatedFrom, f.CreatedTo},
+		{"updated_at", f.UpdatedFrom, f.UpdatedTo},
+		{"last_transaction_date", f.LastTransactionFrom, f.LastTransactionTo},
+	}
+	for _, r := range ranges {
+		if r.from != nil {
+			c.add(r.column+" >= ?", *r.from)
+		}
+		if r.to != nil {
+			c.add(r.column+" < ?", *r.to)
+		}
+	}
+	return c
+}
+
+// customerOrderBy renders sort keys, ending with id so rows with equal keys keep their order
+// between pages. Unknown fields are skipped; CustomerQuery.Normalize rejects them first.
+func customerOrderBy(keys []domain.SortKey) string {
+	var parts []string
+	for _, k := range keys {
+		col, ok := customerSortColumns[k.Field]
+		if !ok {
+			continue
+		}
+		if k.Desc {
+			parts = append(parts, col+" DESC NULLS LAST")
+		} else {
+			parts = append(parts, col+" ASC NULLS LAST")
+		}
+	}
+	return "ORDER BY " + strings.Join(append(parts, "id"), ", ")
+}
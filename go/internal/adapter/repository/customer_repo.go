@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/utils/textnorm"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 )
 
 type customerRepository struct {
@@ -22,54 +26,85 @@ func (r *customerRepository) Create(ctx context.Context, c *domain.Customer) err
 		INSERT INTO customers (
 			type, first_name, last_name, title, date_of_birth, nationality,
 			company_name, registration_date, industry_code,
-			status, membership_tier, points_balance, clv, portfolio_size,
-			last_transaction_date, preferred_channel, is_high_value
+			status, membership_tier, clv, portfolio_size,
+			last_transaction_date, preferred_channel, is_high_value, customer_number, search_name
 		) VALUES (
 			$1, $2, $3, $4, $5, $6,
 			$7, $8, $9,
-			$10, $11, $12, $13, $14,
-			$15, $16, $17
+			$10, $11, $12, $13,
+			$14, $15, $16, $17, $18
 		) RETURNING id, created_at, updated_at
 	`
+	// points_balance is maintained by the points ledger and starts at zero.
 	// Handle nullable fields / zero values if necessary
 	err := r.db.QueryRowContext(ctx, query,
 		c.Type, c.FirstName, c.LastName, c.Title, c.DateOfBirth, c.Nationality,
 		c.CompanyName, c.RegistrationDate, c.IndustryCode,
-		c.Status, c.MembershipTier, c.PointsBalance, c.CLV, c.PortfolioSize,
-		c.LastTransactionDate, c.PreferredChannel, c.IsHighValue,
+		c.Status, c.MembershipTier, c.CLV, c.PortfolioSize,
+		c.LastTransactionDate, c.PreferredChannel, c.IsHighValue, nullString(c.CustomerNumber), searchName(c),
 	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
 
-	return err
+	return uniqueViolation(err)
 }
 
-func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
-	query := `
-		SELECT id, type, first_name, last_name, title, date_of_birth, nationality,
-		       company_name, registration_date, industry_code,
-		       status, membership_tier, points_balance, clv, portfolio_size,
-		       last_transaction_date, preferred_channel, is_high_value,
-		       created_at, updated_at, deleted_at
-		FROM customers
-		WHERE id = $1 AND deleted_at IS NULL
-	`
+const customerColumns = `id, type, first_name, last_name, title, date_of_birth, nationality,
+	company_name, registration_date, industry_code,
+	status, membership_tier, points_balance, clv, portfolio_size,
+	last_transaction_date, preferred_channel, is_high_value,
+	created_at, updated_at, deleted_at, customer_number`
+
+// customerFieldColumns maps customer JSON fields to columns in customerColumns order.
+var customerFieldColumns = []struct{ field, column string }{
+	{"id", "id"}, {"type", "type"}, {"first_name", "first_name"}, {"last_name", "last_name"},
+	{"title", "title"}, {"date_of_birth", "date_of_birth"}, {"nationality", "nationality"},
+	{"company_name", "company_name"}, {"registration_date", "registration_date"}, {"industry_code", "industry_code"},
+	{"status", "status"}, {"membership_tier", "membership_tier"}, {"points_balance", "points_balance"},
+	{"clv", "clv"}, {"portfolio_size", "portfolio_size"}, {"last_transaction_date", "last_transaction_date"},
+	{"preferred_channel", "preferred_channel"}, {"is_high_value", "is_high_value"},
+	{"created_at", "created_at"}, {"updated_at", "updated_at"}, {"deleted_at", "deleted_at"},
+	{"customer_number", "customer_number"},
+}
+
+// customerAlwaysSelected are read whatever the fieldset: they are not nullable in scanCustomer,
+// and paging and access checks rely on them.
+var customerAlwaysSelected = map[string]bool{"id": true, "type": true, "is_high_value": true, "created_at": true, "updated_at": true}
+
+// customerSelect is customerColumns with the columns outside fields replaced by NULL, so
+// scanCustomer reads the same shape either way. The NULLs are left unnamed so ORDER BY still
+// resolves to the table's columns.
+func customerSelect(fields domain.FieldSet) string {
+	if fields == nil {
+		return customerColumns
+	}
+	cols := make([]string, len(customerFieldColumns))
+	for i, fc := range customerFieldColumns {
+		if fields.Has(fc.field) || customerAlwaysSelected[fc.field] {
+			cols[i] = fc.column
+		} else {
+			cols[i] = "NULL"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+// scanCustomer reads customerColumns followed by any extra selected columns into extra.
+func scanCustomer(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*domain.Customer, error) {
 	c := &domain.Customer{}
 	var firstName, lastName, title, nationality sql.NullString
 	var companyName, industryCode sql.NullString
-	var status, membershipTier, preferredChannel sql.NullString
-	var pointsBalance, clv, portfolioSize sql.NullFloat64
+	var status, membershipTier, preferredChannel, customerNumber sql.NullString
+	var pointsBalance, clv, portfolioSize decimal.NullDecimal
 	var isHighValue sql.NullBool
 	var dob, regDate, lastTx sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	dest := []interface{}{
 		&c.ID, &c.Type, &firstName, &lastName, &title, &dob, &nationality,
 		&companyName, &regDate, &industryCode,
 		&status, &membershipTier, &pointsBalance, &clv, &portfolioSize,
 		&lastTx, &preferredChannel, &isHighValue,
-		&c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("customer not found")
+		&c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &customerNumber,
 	}
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -83,10 +118,12 @@ func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	c.Status = domain.CustomerStatus(status.String)
 	c.MembershipTier = membershipTier.String
 	c.PreferredChannel = preferredChannel.String
-	c.PointsBalance = pointsBalance.Float64
-	c.CLV = clv.Float64
-	c.PortfolioSize = portfolioSize.Float64
+	c.PointsBalance = pointsBalance.Decimal
+	c.CLV = clv.Decimal
+	c.PortfolioSize = portfolioSize.Decimal
+	c.Currency = domain.BaseCurrency
 	c.IsHighValue = isHighValue.Bool
+	c.CustomerNumber = customerNumber.String
 
 	if dob.Valid {
 		c.DateOfBirth = dob.Time
@@ -97,7 +134,140 @@ func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	if lastTx.Valid {
 		c.LastTransactionDate = &lastTx.Time
 	}
+	return c, nil
+}
+
+func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	return r.GetFields(ctx, id, nil)
+}
+
+// GetFields loads a live customer reading only the columns behind fields.
+func (r *customerRepository) GetFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error) {
+	query := `SELECT ` + customerSelect(fields) + ` FROM customers WHERE id = $1 AND deleted_at IS NULL`
+	c, err := scanCustomer(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	return c, err
+}
+
+// GetByIDs loads the live customers among ids in one query.
+func (r *customerRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = ANY($1) AND deleted_at IS NULL`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []*domain.Customer
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+	return customers, rows.Err()
+}
 
+// Query returns a page of customers matching q and the total number that match.
+func (r *customerRepository) Query(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	cond := customerConditions(q.Filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM customers `+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	p := domain.PageRequest{Limit: q.Limit, Offset: q.Offset, Cursor: q.Cursor}
+	var tail string
+	if q.Pageable() {
+		tail = keyset(cond, "created_at", p)
+	} else {
+		tail = fmt.Sprintf("%s LIMIT %d OFFSET %d", customerOrderBy(q.Sort), q.Limit+1, q.Offset)
+	}
+
+	args, columns := cond.args, customerSelect(q.Fields)
+	var score float64
+	var extra []interface{}
+	if q.Filter.Fuzzy {
+		args = append(args[:len(args):len(args)], textnorm.Key(q.Filter.Text))
+		columns += fmt.Sprintf(", COALESCE(word_similarity($%d, search_name), 0) AS score", len(args))
+		extra = append(extra, &score)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM customers %s %s`, columns, cond.where(), tail), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []*domain.Customer
+	for rows.Next() {
+		c, err := scanCustomer(rows, extra...)
+		if err != nil {
+			return nil, err
+		}
+		c.SearchScore = score
+		customers = append(customers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := domain.NewPage(customers, p, func(c *domain.Customer) domain.PageCursor {
+		return domain.PageCursor{At: c.CreatedAt, ID: c.ID}
+	})
+	result := &domain.CustomerPage{Customers: page.Items, TotalCount: total}
+	if q.Pageable() {
+		result.NextCursor, result.PrevCursor = page.NextCursor, page.PrevCursor
+	}
+	return result, nil
+}
+
+// MatchingIDs returns the ids of every live customer matching a normalized filter.
+func (r *customerRepository) MatchingIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error) {
+	cond := customerConditions(f)
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM customers `+cond.where()+` ORDER BY id`, cond.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetDeletedByID loads a soft-deleted customer together with who deleted it.
+func (r *customerRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	query := `
+		SELECT id, type, first_name, last_name, company_name, status, created_at, updated_at, deleted_at, deleted_by
+		FROM customers
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	c := &domain.Customer{}
+	var firstName, lastName, companyName sql.NullString
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&c.ID, &c.Type, &firstName, &lastName, &companyName, &c.Status,
+		&c.CreatedAt, &c.UpdatedAt, &c.DeletedAt, &c.DeletedBy,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deleted customer %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.FirstName = firstName.String
+	c.LastName = lastName.String
+	c.CompanyName = companyName.String
 	return c, nil
 }
 
@@ -106,21 +276,41 @@ func (r *customerRepository) Update(ctx context.Context, c *domain.Customer) err
 		UPDATE customers SET
 			first_name=$1, last_name=$2, title=$3, date_of_birth=$4, nationality=$5,
 			company_name=$6, registration_date=$7, industry_code=$8,
-			status=$9, membership_tier=$10, points_balance=$11, clv=$12, portfolio_size=$13,
-			last_transaction_date=$14, preferred_channel=$15, is_high_value=$16,
-			updated_at=NOW()
+			status=$9, membership_tier=$10, clv=$11, portfolio_size=$12,
+			last_transaction_date=$13, preferred_channel=$14, is_high_value=$15,
+			search_name=$16, updated_at=NOW()
 		WHERE id=$17 AND deleted_at IS NULL
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		c.FirstName, c.LastName, c.Title, c.DateOfBirth, c.Nationality,
 		c.CompanyName, c.RegistrationDate, c.IndustryCode,
-		c.Status, c.MembershipTier, c.PointsBalance, c.CLV, c.PortfolioSize,
+		c.Status, c.MembershipTier, c.CLV, c.PortfolioSize,
 		c.LastTransactionDate, c.PreferredChannel, c.IsHighValue,
-		c.ID,
+		searchName(c), c.ID,
 	)
 	return err
 }
 
+func (r *customerRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.CustomerStatus) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE customers SET status=$3, updated_at=NOW() WHERE id=$1 AND status=$2 AND deleted_at IS NULL`, id, from, to)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil
+	}
+	var current domain.CustomerStatus
+	err = r.db.QueryRowContext(ctx, `SELECT status FROM customers WHERE id=$1 AND deleted_at IS NULL`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: customer status changed from %s to %s since the request was made", domain.ErrConflict, from, current)
+}
+
 func (r *customerRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
 	// Soft Delete
 	query := `UPDATE customers SET deleted_at=NOW(), deleted_by=$2 WHERE id=$1`
@@ -128,38 +318,17 @@ func (r *customerRepository) Delete(ctx context.Context, id, userID uuid.UUID) e
 	return err
 }
 
-func (r *customerRepository) List(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	query := `
-		SELECT id, type, first_name, last_name, company_name, status, created_at, deleted_at, deleted_by
-		FROM customers
-		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
+// List pages through all live customers, newest first.
+func (r *customerRepository) List(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	q := domain.CustomerQuery{Limit: p.Limit, Offset: p.Offset, Cursor: p.Cursor}
+	if err := q.Normalize(); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var customers []*domain.Customer
-	for rows.Next() {
-		c := &domain.Customer{}
-		var firstName, lastName, companyName sql.NullString
-		var deletedBy sql.NullString
-		if err := rows.Scan(&c.ID, &c.Type, &firstName, &lastName, &companyName, &c.Status, &c.CreatedAt, &c.DeletedAt, &deletedBy); err != nil {
-			return nil, err
-		}
-		c.FirstName = firstName.String
-		c.LastName = lastName.String
-		c.CompanyName = companyName.String
-		if deletedBy.Valid {
-			uid, _ := uuid.Parse(deletedBy.String)
-			c.DeletedBy = &uid
-		}
-		customers = append(customers, c)
+	result, err := r.Query(ctx, q)
+	if err != nil {
+		return nil, err
 	}
-	return customers, nil
+	return &domain.Page[*domain.Customer]{Items: result.Customers, NextCursor: result.NextCursor, PrevCursor: result.PrevCursor}, nil
 }
 
 func (r *customerRepository) Restore(ctx context.Context, id uuid.UUID) error {
@@ -168,15 +337,14 @@ func (r *customerRepository) Restore(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
-func (r *customerRepository) ListDeleted(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	query := `
-		SELECT id, type, first_name, last_name, company_name, status, created_at, deleted_at
-		FROM customers
-		WHERE deleted_at IS NOT NULL
-		ORDER BY deleted_at DESC
-		LIMIT $1 OFFSET $2
-	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+// ListDeleted pages through soft-deleted customers, most recently deleted first.
+func (r *customerRepository) ListDeleted(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	cond := &conditions{}
+	cond.add("deleted_at IS NOT NULL")
+	tail := keyset(cond, "deleted_at", p)
+	query := `SELECT id, type, first_name, last_name, company_name, status, created_at, deleted_at
+		FROM customers ` + cond.where() + " " + tail
+	rows, err := r.db.QueryContext(ctx, query, cond.args...)
 	if err != nil {
 		return nil, err
 	}
@@ -194,37 +362,114 @@ func (r *customerRepository) ListDeleted(ctx context.Context, limit, offset int)
 		c.CompanyName = companyName.String
 		customers = append(customers, c)
 	}
-	return customers, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.NewPage(customers, p, func(c *domain.Customer) domain.PageCursor {
+		return domain.PageCursor{At: *c.DeletedAt, ID: c.ID}
+	}), nil
 }
 
+func (r *customerRepository) CountDeleted(ctx context.Context) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM customers WHERE deleted_at IS NOT NULL`).Scan(&n)
+	return n, err
+}
+
+// Search matches names and customer numbers, returning at most 20 customers.
 func (r *customerRepository) Search(ctx context.Context, queryStr string) ([]*domain.Customer, error) {
-	sqlQuery := `
-		SELECT id, type, first_name, last_name, company_name, status, created_at, deleted_at
-		FROM customers
-		WHERE deleted_at IS NULL AND (
-			first_name ILIKE '%' || $1 || '%' OR
-			last_name ILIKE '%' || $1 || '%' OR
-			company_name ILIKE '%' || $1 || '%'
-		)
-		LIMIT 20
-	`
-	rows, err := r.db.QueryContext(ctx, sqlQuery, queryStr)
+	page, err := r.Query(ctx, domain.CustomerQuery{
+		Filter: domain.CustomerFilter{Text: queryStr},
+		Sort:   []domain.SortKey{{Field: "created_at", Desc: true}},
+		Limit:  20,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Customers, nil
+}
+
+// NextNumberSequence draws the next value for a customer number. Values are never reused, so
+// a failed create leaves a gap.
+func (r *customerRepository) NextNumberSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.db.QueryRowContext(ctx, `SELECT nextval('customer_number_seq')`).Scan(&seq)
+	return seq, err
+}
+
+func (r *customerRepository) FindIDByNumber(ctx context.Context, number string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM customers WHERE customer_number = $1 AND deleted_at IS NULL`, number).Scan(&id)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	return id, err
+}
+
+// ListUnnumbered returns customers created before numbers were assigned, oldest first.
+func (r *customerRepository) ListUnnumbered(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id FROM customers WHERE customer_number IS NULL ORDER BY created_at, id LIMIT $1`, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var customers []*domain.Customer
+	var ids []uuid.UUID
 	for rows.Next() {
-		c := &domain.Customer{}
-		var firstName, lastName, companyName sql.NullString
-		if err := rows.Scan(&c.ID, &c.Type, &firstName, &lastName, &companyName, &c.Status, &c.CreatedAt, &c.DeletedAt); err != nil {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		c.FirstName = firstName.String
-		c.LastName = lastName.String
-		c.CompanyName = companyName.String
-		customers = append(customers, c)
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AssignNumber sets the number of a customer that has none.
+func (r *customerRepository) AssignNumber(ctx context.Context, id uuid.UUID, number string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE customers SET customer_number = $2 WHERE id = $1 AND customer_number IS NULL`, id, number)
+	return uniqueViolation(err)
+}
+
+// IndexSearchNames fills search_name for customers written without it, such as rows loaded by
+// SQL scripts or written before fuzzy search existed, batch rows at a time. It returns how many
+// customers were indexed.
+func (r *customerRepository) IndexSearchNames(ctx context.Context, batch int) (int, error) {
+	total := 0
+	for {
+		rows, err := r.db.QueryContext(ctx,
+			`SELECT id, first_name, last_name, company_name FROM customers WHERE search_name IS NULL LIMIT $1`, batch)
+		if err != nil {
+			return total, err
+		}
+		var pending []*domain.Customer
+		for rows.Next() {
+			var c domain.Customer
+			var firstName, lastName, companyName sql.NullString
+			if err := rows.Scan(&c.ID, &firstName, &lastName, &companyName); err != nil {
+				rows.Close()
+				return total, err
+			}
+			c.FirstName, c.LastName, c.CompanyName = firstName.String, lastName.String, companyName.String
+			pending = append(pending, &c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+		if len(pending) == 0 {
+			return total, nil
+		}
+
+		for _, c := range pending {
+			if _, err := r.db.ExecContext(ctx,
+				`UPDATE customers SET search_name = $2 WHERE id = $1`, c.ID, searchName(c)); err != nil {
+				return total, err
+			}
+			total++
+		}
 	}
-	return customers, nil
 }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type externalRefRepository struct {
+	db *sql.DB
+}
+
+func NewExternalRefRepository(db *sql.DB) *externalRefRepository {
+	return &externalRefRepository{db: db}
+}
+
+const externalRefColumns = `id, customer_id, system, external_key, created_at`
+
+func scanExternalRef(row interface{ Scan(...interface{}) error }) (*domain.ExternalRef, error) {
+	ref := &domain.ExternalRef{}
+	if err := row.Scan(&ref.ID, &ref.CustomerID, &ref.System, &ref.Key, &ref.CreatedAt); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+func (r *externalRefRepository) Find(ctx context.Context, system, key string) (*domain.ExternalRef, error) {
+	query := `SELECT ` + externalRefColumns + ` FROM customer_external_refs WHERE system = $1 AND external_key = $2`
+	ref, err := scanExternalRef(r.db.QueryRowContext(ctx, query, system, key))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return ref, err
+}
+
+func (r *externalRefRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]domain.ExternalRef, error) {
+	query := `SELECT ` + externalRefColumns + ` FROM customer_external_refs WHERE customer_id = $1 ORDER BY system, external_key`
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []domain.ExternalRef{}
+	for rows.Next() {
+		ref, err := scanExternalRef(rows)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, *ref)
+	}
+	return refs, rows.Err()
+}
+
+func (r *externalRefRepository) Replace(ctx context.Context, customerID uuid.UUID, refs []domain.ExternalRef) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	kept := []string{}
+	for i := range refs {
+		ref := &refs[i]
+		// Keeps an existing row (and its created_at) for this customer; a row owned by
+		// another customer fails the WHERE and returns nothing.
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO customer_external_refs (customer_id, system, external_key)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (system, external_key) DO UPDATE SET customer_id = EXCLUDED.customer_id
+			WHERE customer_external_refs.customer_id = EXCLUDED.customer_id
+			RETURNING id, created_at`, customerID, ref.System, ref.Key,
+		).Scan(&ref.ID, &ref.CreatedAt)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s %s belongs to another customer", domain.ErrConflict, ref.System, ref.Key)
+		}
+		if err != nil {
+			return err
+		}
+		ref.CustomerID = customerID
+		kept = append(kept, ref.ID.String())
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM customer_external_refs WHERE customer_id = $1 AND id::text <> ALL($2)`, customerID, pq.Array(kept))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *externalRefRepository) Merge(ctx context.Context, fromCustomerID, toCustomerID, deletedBy uuid.UUID) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE customer_external_refs SET customer_id = $2 WHERE customer_id = $1`, fromCustomerID, toCustomerID)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err = tx.ExecContext(ctx,
+		`UPDATE customers SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`, fromCustomerID, deletedBy)
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		return 0, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	return moved, tx.Commit()
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type fxRateRepository struct {
+	db *sql.DB
+}
+
+func NewFXRateRepository(db *sql.DB) *fxRateRepository {
+	return &fxRateRepository{db: db}
+}
+
+func (r *fxRateRepository) List(ctx context.Context) ([]*domain.FXRate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT currency, rate_to_base, as_of, updated_at FROM fx_rates ORDER BY currency`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*domain.FXRate
+	for rows.Next() {
+		rate := &domain.FXRate{}
+		if err := rows.Scan(&rate.Currency, &rate.RateToBase, &rate.AsOf, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *fxRateRepository) Get(ctx context.Context, currency string) (*domain.FXRate, error) {
+	rate := &domain.FXRate{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT currency, rate_to_base, as_of, updated_at FROM fx_rates WHERE currency = $1`, currency,
+	).Scan(&rate.Currency, &rate.RateToBase, &rate.AsOf, &rate.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (r *fxRateRepository) Upsert(ctx context.Context, rate *domain.FXRate) error {
+	query := `
+		INSERT INTO fx_rates (currency, rate_to_base, as_of, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (currency) DO UPDATE SET rate_to_base = $2, as_of = $3, updated_at = NOW()
+		RETURNING updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, rate.Currency, rate.RateToBase, rate.AsOf).Scan(&rate.UpdatedAt)
+}
+
+type portfolioValueRepository struct {
+	db *sql.DB
+}
+
+func NewPortfolioValueRepository(db *sql.DB) *portfolioValueRepository {
+	return &portfolioValueRepository{db: db}
+}
+
+func (r *portfolioValueRepository) List(ctx context.Context, customerID uuid.UUID) ([]domain.Money, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT amount, currency FROM customer_portfolio_values WHERE customer_id = $1 ORDER BY currency`, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []domain.Money
+	for rows.Next() {
+		var m domain.Money
+		if err := rows.Scan(&m.Amount, &m.Currency); err != nil {
+			return nil, err
+		}
+		values = append(values, m)
+	}
+	return values, rows.Err()
+}
+
+func (r *portfolioValueRepository) Replace(ctx context.Context, customerID uuid.UUID, values []domain.Money) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_portfolio_values WHERE customer_id = $1`, customerID); err != nil {
+		return err
+	}
+	for _, m := range values {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO customer_portfolio_values (customer_id, currency, amount) VALUES ($1, $2, $3)`,
+			customerID, m.Currency, m.Amount); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
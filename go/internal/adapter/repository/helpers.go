@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/lib/pq"
+)
+
+// nullString stores an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// uniqueViolation maps a Postgres unique constraint error to domain.ErrConflict.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%w: %s", domain.ErrConflict, pqErr.Constraint)
+	}
+	return err
+}
+
+// advisoryLock takes the session advisory lock for key on a connection of its own, failing with
+// domain.ErrConflict while another session holds it. The returned func releases the lock.
+func advisoryLock(ctx context.Context, db *sql.DB, key string) (func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !locked {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s is locked", domain.ErrConflict, key)
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			// Drop the session rather than pool a connection that may still hold the lock.
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
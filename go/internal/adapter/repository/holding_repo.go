@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type productRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) *productRepository {
+	return &productRepository{db: db}
+}
+
+const productColumns = `code, name, category, is_active, created_at, updated_at`
+
+func scanProduct(row interface{ Scan(...interface{}) error }) (*domain.Product, error) {
+	p := &domain.Product{}
+	if err := row.Scan(&p.Code, &p.Name, &p.Category, &p.IsActive, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *productRepository) List(ctx context.Context, activeOnly bool) ([]*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE is_active OR NOT $1 ORDER BY category, code`
+	rows, err := r.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+func (r *productRepository) GetByCode(ctx context.Context, code string) (*domain.Product, error) {
+	p, err := scanProduct(r.db.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products WHERE code = $1`, code))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	return p, err
+}
+
+func (r *productRepository) Save(ctx context.Context, p *domain.Product) error {
+	query := `
+		INSERT INTO products (code, name, category, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (code) DO UPDATE SET name = $2, category = $3, is_active = $4, updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, p.Code, p.Name, p.Category, p.IsActive).Scan(&p.CreatedAt, &p.UpdatedAt)
+}
+
+type holdingRepository struct {
+	db *sql.DB
+}
+
+func NewHoldingRepository(db *sql.DB) *holdingRepository {
+	return &holdingRepository{db: db}
+}
+
+const holdingColumns = `h.id, h.customer_id, h.product_code, p.name, h.external_ref, h.role, h.status, h.currency,
+	h.sum_insured, h.balance, h.start_date, h.end_date, h.created_at, h.updated_at`
+
+const holdingFrom = ` FROM customer_holdings h JOIN products p ON p.code = h.product_code`
+
+func scanHolding(row interface{ Scan(...interface{}) error }) (*domain.Holding, error) {
+	h := &domain.Holding{}
+	var sumInsured, balance decimal.NullDecimal
+	var endDate sql.NullTime
+	err := row.Scan(&h.ID, &h.CustomerID, &h.ProductCode, &h.ProductName, &h.ExternalRef, &h.Role, &h.Status,
+		&h.Currency, &sumInsured, &balance, &h.StartDate, &endDate, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if sumInsured.Valid {
+		h.SumInsured = &sumInsured.Decimal
+	}
+	if balance.Valid {
+		h.Balance = &balance.Decimal
+	}
+	if endDate.Valid {
+		h.EndDate = &endDate.Time
+	}
+	return h, nil
+}
+
+func (r *holdingRepository) Create(ctx context.Context, h *domain.Holding) error {
+	query := `
+		INSERT INTO customer_holdings (customer_id, product_code, external_ref, role, status, currency,
+			sum_insured, balance, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, h.CustomerID, h.ProductCode, h.ExternalRef, h.Role, h.Status, h.Currency,
+		h.SumInsured, h.Balance, h.StartDate, h.EndDate,
+	).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+	return uniqueViolation(err)
+}
+
+func (r *holdingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Holding, error) {
+	h, err := scanHolding(r.db.QueryRowContext(ctx, `SELECT `+holdingColumns+holdingFrom+` WHERE h.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("holding %w", domain.ErrNotFound)
+	}
+	return h, err
+}
+
+func (r *holdingRepository) Update(ctx context.Context, h *domain.Holding) error {
+	query := `
+		UPDATE customer_holdings
+		SET product_code=$1, external_ref=$2, role=$3, status=$4, currency=$5, sum_insured=$6, balance=$7,
+			start_date=$8, end_date=$9, updated_at=NOW()
+		WHERE id=$10
+		RETURNING updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, h.ProductCode, h.ExternalRef, h.Role, h.Status, h.Currency,
+		h.SumInsured, h.Balance, h.StartDate, h.EndDate, h.ID,
+	).Scan(&h.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("holding %w", domain.ErrNotFound)
+	}
+	return uniqueViolation(err)
+}
+
+func (r *holdingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM customer_holdings WHERE id = $1`, id)
+	return err
+}
+
+func (r *holdingRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Holding, error) {
+	query := `SELECT ` + holdingColumns + holdingFrom + ` WHERE h.customer_id = $1 ORDER BY h.start_date DESC, h.id`
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holdings []*domain.Holding
+	for rows.Next() {
+		h, err := scanHolding(rows)
+		if err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, h)
+	}
+	return holdings, rows.Err()
+}
+
+func (r *holdingRepository) UpdatePortfolio(ctx context.Context, customerID uuid.UUID, total domain.Money, values []domain.Money) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_portfolio_values WHERE customer_id = $1`, customerID); err != nil {
+		return err
+	}
+	for _, m := range values {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO customer_portfolio_values (customer_id, currency, amount) VALUES ($1, $2, $3)`,
+			customerID, m.Currency, m.Amount); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE customers SET portfolio_size = $1, updated_at = NOW() WHERE id = $2`, total.Amount, customerID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *holdingRepository) CustomersWithDateChanges(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT customer_id FROM customer_holdings
+		WHERE (start_date > $1 AND start_date <= $2) OR (end_date > $1 AND end_date <= $2)
+	`
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// portfolioRefreshJob keys the portfolio refresh in job_watermarks.
+const portfolioRefreshJob = "portfolio_refresh"
+
+func (r *holdingRepository) RefreshWatermark(ctx context.Context) (time.Time, error) {
+	var at time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT ran_until FROM job_watermarks WHERE job = $1`, portfolioRefreshJob).Scan(&at)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return at, err
+}
+
+func (r *holdingRepository) SetRefreshWatermark(ctx context.Context, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO job_watermarks (job, ran_until) VALUES ($1, $2)
+		ON CONFLICT (job) DO UPDATE SET ran_until = EXCLUDED.ran_until
+	`, portfolioRefreshJob, at)
+	return err
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type importRepository struct {
+	db *sql.DB
+}
+
+func NewImportRepository(db *sql.DB) *importRepository {
+	return &importRepository{db: db}
+}
+
+const importRunColumns = `id, source, dry_run, status, cursor, processed, imported, skipped, rejected, error,
+	started_by, started_at, finished_at`
+
+func scanImportRun(row interface{ Scan(...interface{}) error }) (*domain.ImportRun, error) {
+	run := &domain.ImportRun{}
+	var errText sql.NullString
+	err := row.Scan(&run.ID, &run.Source, &run.DryRun, &run.Status, &run.Cursor, &run.Processed, &run.Imported,
+		&run.Skipped, &run.Rejected, &errText, &run.StartedBy, &run.StartedAt, &run.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	run.Error = errText.String
+	return run, nil
+}
+
+func (r *importRepository) CreateRun(ctx context.Context, run *domain.ImportRun) error {
+	query := `
+		INSERT INTO import_runs (source, dry_run, status, cursor, started_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, started_at
+	`
+	return r.db.QueryRowContext(ctx, query, run.Source, run.DryRun, run.Status, run.Cursor, run.StartedBy).
+		Scan(&run.ID, &run.StartedAt)
+}
+
+func (r *importRepository) UpdateRun(ctx context.Context, run *domain.ImportRun) error {
+	query := `
+		UPDATE import_runs
+		SET status=$1, cursor=$2, processed=$3, imported=$4, skipped=$5, rejected=$6, error=$7, finished_at=$8
+		WHERE id=$9
+	`
+	_, err := r.db.ExecContext(ctx, query, run.Status, run.Cursor, run.Processed, run.Imported, run.Skipped,
+		run.Rejected, nullString(run.Error), run.FinishedAt, run.ID)
+	return err
+}
+
+func (r *importRepository) GetRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	run, err := scanImportRun(r.db.QueryRowContext(ctx, `SELECT `+importRunColumns+` FROM import_runs WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("import run not found")
+	}
+	return run, err
+}
+
+func (r *importRepository) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ImportRun, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+importRunColumns+` FROM import_runs ORDER BY started_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*domain.ImportRun
+	for rows.Next() {
+		run, err := scanImportRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *importRepository) AddReject(ctx context.Context, reject *domain.ImportReject) error {
+	query := `
+		INSERT INTO import_rejects (run_id, source_key, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (run_id, source_key) DO UPDATE SET reason = $3, created_at = NOW()
+		RETURNING created_at
+	`
+	return r.db.QueryRowContext(ctx, query, reject.RunID, reject.SourceKey, reject.Reason).Scan(&reject.CreatedAt)
+}
+
+func (r *importRepository) ListRejects(ctx context.Context, runID uuid.UUID) ([]*domain.ImportReject, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT run_id, source_key, reason, created_at FROM import_rejects WHERE run_id = $1 ORDER BY source_key`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rejects []*domain.ImportReject
+	for rows.Next() {
+		rj := &domain.ImportReject{}
+		if err := rows.Scan(&rj.RunID, &rj.SourceKey, &rj.Reason, &rj.CreatedAt); err != nil {
+			return nil, err
+		}
+		rejects = append(rejects, rj)
+	}
+	return rejects, rows.Err()
+}
+
+func (r *importRepository) SaveClient(ctx context.Context, ic *domain.ImportedClient) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	c := ic.Customer
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customers (
+			type, first_name, last_name, title, date_of_birth, nationality,
+			company_name, registration_date, industry_code,
+			status, membership_tier, clv, portfolio_size,
+			last_transaction_date, preferred_channel, is_high_value, customer_number, search_name
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at`,
+		c.Type, c.FirstName, c.LastName, c.Title, c.DateOfBirth, c.Nationality,
+		c.CompanyName, c.RegistrationDate, c.IndustryCode,
+		c.Status, c.MembershipTier, c.CLV, c.PortfolioSize,
+		c.LastTransactionDate, c.PreferredChannel, c.IsHighValue, nullString(c.CustomerNumber), searchName(c),
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range ic.Addresses {
+		a.CustomerID = c.ID
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO addresses (
+				customer_id, type, address_line1, address_line2,
+				city, state, district, sub_district, zip_code, country
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id, created_at, updated_at`,
+			a.CustomerID, a.Type, a.AddressLine1, a.AddressLine2,
+			a.City, a.State, a.District, a.SubDistrict, a.ZipCode, a.Country,
+		).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range ic.Identities {
+		i.CustomerID = c.ID
+		var expiry sql.NullTime
+		if !i.ExpiryDate.IsZero() {
+			expiry = sql.NullTime{Time: i.ExpiryDate, Valid: true}
+		}
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO identities (customer_id, type, number, issuance_country, expiry_date)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at`,
+			i.CustomerID, i.Type, i.Number, i.IssuanceCountry, expiry,
+		).Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+		if err := uniqueViolation(err); errors.Is(err, domain.ErrConflict) {
+			return fmt.Errorf("%w: %s %s", domain.ErrIdentityConflict, i.Type, i.Number)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	ref := ic.Ref
+	ref.CustomerID = c.ID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customer_external_refs (customer_id, system, external_key)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`,
+		ref.CustomerID, ref.System, ref.Key,
+	).Scan(&ref.ID, &ref.CreatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return tx.Commit()
+}
+
+// Lock holds a session advisory lock, so it spans every API instance and cas-import command
+// sharing the database and is dropped if the process dies.
+func (r *importRepository) Lock(ctx context.Context, source string) (func(), error) {
+	unlock, err := advisoryLock(ctx, r.db, "import:"+source)
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, fmt.Errorf("%w: an import from %s is already running", domain.ErrConflict, source)
+	}
+	return unlock, err
+}
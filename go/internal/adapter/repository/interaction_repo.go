@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type interactionRepository struct {
+	db *sql.DB
+}
+
+func NewInteractionRepository(db *sql.DB) *interactionRepository {
+	return &interactionRepository{db: db}
+}
+
+const interactionColumns = `id, customer_id, channel, direction, subject, body, staff_user_id, pinned, attachments,
+		occurred_at, created_at, updated_at`
+
+func scanInteraction(row interface{ Scan(...interface{}) error }) (*domain.Interaction, error) {
+	i := &domain.Interaction{}
+	var body sql.NullString
+	var attachments []byte
+	err := row.Scan(
+		&i.ID, &i.CustomerID, &i.Channel, &i.Direction, &i.Subject, &body, &i.StaffUserID, &i.Pinned, &attachments,
+		&i.OccurredAt, &i.CreatedAt, &i.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	i.Body = body.String
+	if len(attachments) > 0 {
+		if err := json.Unmarshal(attachments, &i.Attachments); err != nil {
+			return nil, fmt.Errorf("interaction %s attachments: %w", i.ID, err)
+		}
+	}
+	return i, nil
+}
+
+func marshalAttachments(a []domain.AttachmentRef) ([]byte, error) {
+	if a == nil {
+		a = []domain.AttachmentRef{}
+	}
+	return json.Marshal(a)
+}
+
+func (r *interactionRepository) Create(ctx context.Context, i *domain.Interaction) error {
+	attachments, err := marshalAttachments(i.Attachments)
+	if err != nil {
+		return err
+	}
+	var occurredAt interface{}
+	if !i.OccurredAt.IsZero() {
+		occurredAt = i.OccurredAt
+	}
+
+	query := `
+		INSERT INTO customer_interactions (
+			customer_id, channel, direction, subject, body, staff_user_id, pinned, attachments, occurred_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
+		RETURNING id, occurred_at, created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		i.CustomerID, i.Channel, i.Direction, i.Subject, nullString(i.Body), i.StaffUserID, i.Pinned, attachments, occurredAt,
+	).Scan(&i.ID, &i.OccurredAt, &i.CreatedAt, &i.UpdatedAt)
+}
+
+func (r *interactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Interaction, error) {
+	query := `SELECT ` + interactionColumns + ` FROM customer_interactions WHERE id = $1`
+	i, err := scanInteraction(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("interaction %w", domain.ErrNotFound)
+	}
+	return i, err
+}
+
+// Update rewrites the editable fields. The customer, author and creation time never change.
+func (r *interactionRepository) Update(ctx context.Context, i *domain.Interaction) error {
+	attachments, err := marshalAttachments(i.Attachments)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE customer_interactions
+		SET channel=$1, direction=$2, subject=$3, body=$4, pinned=$5, attachments=$6, occurred_at=$7, updated_at=NOW()
+		WHERE id=$8
+		RETURNING updated_at
+	`
+	err = r.db.QueryRowContext(ctx, query,
+		i.Channel, i.Direction, i.Subject, nullString(i.Body), i.Pinned, attachments, i.OccurredAt, i.ID,
+	).Scan(&i.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("interaction %w", domain.ErrNotFound)
+	}
+	return err
+}
+
+func (r *interactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM customer_interactions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("interaction %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *interactionRepository) List(ctx context.Context, customerID uuid.UUID, f domain.InteractionFilter) ([]*domain.Interaction, error) {
+	conditions := []string{"customer_id = $1"}
+	args := []interface{}{customerID}
+	if f.Channel != "" {
+		args = append(args, f.Channel)
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", len(args)))
+	}
+	if f.PinnedOnly {
+		conditions = append(conditions, "pinned")
+	}
+	if f.From != nil {
+		args = append(args, *f.From)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if f.To != nil {
+		args = append(args, *f.To)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	args = append(args, f.Limit, f.Offset)
+
+	query := `SELECT ` + interactionColumns + ` FROM customer_interactions WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY pinned DESC, occurred_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	return r.query(ctx, query, args...)
+}
+
+func (r *interactionRepository) ListForTimeline(ctx context.Context, customerID uuid.UUID, from, to, before *time.Time, limit int) ([]*domain.Interaction, error) {
+	conditions := []string{"customer_id = $1"}
+	args := []interface{}{customerID}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	if before != nil {
+		args = append(args, *before)
+		conditions = append(conditions, fmt.Sprintf("occurred_at <= $%d", len(args)))
+	}
+	args = append(args, limit)
+
+	query := `SELECT ` + interactionColumns + ` FROM customer_interactions WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY occurred_at DESC, id DESC LIMIT $%d", len(args))
+	return r.query(ctx, query, args...)
+}
+
+func (r *interactionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Interaction, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var interactions []*domain.Interaction
+	for rows.Next() {
+		i, err := scanInteraction(rows)
+		if err != nil {
+			return nil, err
+		}
+		interactions = append(interactions, i)
+	}
+	return interactions, rows.Err()
+}
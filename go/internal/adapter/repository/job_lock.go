@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type jobLock struct {
+	db *sql.DB
+}
+
+func NewJobLock(db *sql.DB) *jobLock {
+	return &jobLock{db: db}
+}
+
+// Lock holds a session advisory lock, so a job runs on one API instance at a time and the lock
+// is dropped if the process dies.
+func (l *jobLock) Lock(ctx context.Context, job string) (func(), error) {
+	return advisoryLock(ctx, l.db, "job:"+job)
+}
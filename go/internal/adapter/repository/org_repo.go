@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+)
+
+// --- Org Repository (branches and teams) ---
+
+type orgRepository struct {
+	db *sql.DB
+}
+
+func NewOrgRepository(db *sql.DB) *orgRepository {
+	return &orgRepository{db: db}
+}
+
+func (r *orgRepository) CreateBranch(ctx context.Context, b *models.Branch) error {
+	query := `INSERT INTO branches (code, name) VALUES ($1, $2) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, b.Code, b.Name).Scan(&b.ID, &b.CreatedAt)
+}
+
+func (r *orgRepository) ListBranches(ctx context.Context) ([]*models.Branch, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, code, name, created_at FROM branches ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var branches []*models.Branch
+	for rows.Next() {
+		b := &models.Branch{}
+		if err := rows.Scan(&b.ID, &b.Code, &b.Name, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+func (r *orgRepository) CreateTeam(ctx context.Context, t *models.Team) error {
+	query := `INSERT INTO teams (name, branch_id, lead_id) VALUES ($1, $2, $3) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, t.Name, t.BranchID, t.LeadID).Scan(&t.ID, &t.CreatedAt)
+}
+
+func (r *orgRepository) ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*models.Team, error) {
+	query := `SELECT id, name, branch_id, lead_id, created_at FROM teams`
+	var args []interface{}
+	if branchID != nil {
+		query += ` WHERE branch_id = $1`
+		args = append(args, *branchID)
+	}
+	query += ` ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*models.Team
+	for rows.Next() {
+		t := &models.Team{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.BranchID, &t.LeadID, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, nil
+}
+
+func (r *orgRepository) GetTeam(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	t := &models.Team{}
+	err := r.db.QueryRowContext(ctx, `SELECT id, name, branch_id, lead_id, created_at FROM teams WHERE id = $1`, id).
+		Scan(&t.ID, &t.Name, &t.BranchID, &t.LeadID, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("team %w", domain.ErrNotFound)
+	}
+	return t, err
+}
+
+func (r *orgRepository) AssignUser(ctx context.Context, userID uuid.UUID, teamID, branchID *uuid.UUID) error {
+	query := `UPDATE users SET team_id = $1, branch_id = $2, updated_at = NOW() WHERE id = $3`
+	res, err := r.db.ExecContext(ctx, query, teamID, branchID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// --- Delegation Repository ---
+
+type delegationRepository struct {
+	db *sql.DB
+}
+
+func NewDelegationRepository(db *sql.DB) *delegationRepository {
+	return &delegationRepository{db: db}
+}
+
+const delegationColumns = `id, delegator_id, delegate_id, scope, reason, starts_at, ends_at, revoked_at, created_at`
+
+func scanDelegation(row interface{ Scan(...interface{}) error }) (*models.Delegation, error) {
+	d := &models.Delegation{}
+	var reason sql.NullString
+	if err := row.Scan(&d.ID, &d.DelegatorID, &d.DelegateID, &d.Scope, &reason, &d.StartsAt, &d.EndsAt, &d.RevokedAt, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	d.Reason = reason.String
+	return d, nil
+}
+
+func (r *delegationRepository) Create(ctx context.Context, d *models.Delegation) error {
+	query := `
+		INSERT INTO delegations (delegator_id, delegate_id, scope, reason, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		d.DelegatorID, d.DelegateID, d.Scope, d.Reason, d.StartsAt, d.EndsAt,
+	).Scan(&d.ID, &d.CreatedAt)
+}
+
+func (r *delegationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Delegation, error) {
+	d, err := scanDelegation(r.db.QueryRowContext(ctx, `SELECT `+delegationColumns+` FROM delegations WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("delegation not found")
+	}
+	return d, err
+}
+
+func (r *delegationRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE delegations SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+func (r *delegationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error) {
+	query := `SELECT ` + delegationColumns + ` FROM delegations
+		WHERE delegator_id = $1 OR delegate_id = $1
+		ORDER BY starts_at DESC`
+	return r.list(ctx, query, userID)
+}
+
+func (r *delegationRepository) ListActiveForDelegate(ctx context.Context, delegateID uuid.UUID) ([]*models.Delegation, error) {
+	query := `SELECT ` + delegationColumns + ` FROM delegations
+		WHERE delegate_id = $1 AND revoked_at IS NULL AND starts_at <= NOW() AND ends_at > NOW()`
+	return r.list(ctx, query, delegateID)
+}
+
+func (r *delegationRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Delegation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var delegations []*models.Delegation
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, d)
+	}
+	return delegations, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+)
+
+// keyset adds the cursor position to c for a list ordered newest first by column, then id, and
+// returns the ORDER BY and LIMIT clauses. One row beyond the page is fetched so that
+// domain.NewPage can tell whether more follow. Without a cursor it falls back to the offset.
+func keyset(c *conditions, column string, p domain.PageRequest) string {
+	if p.Cursor == nil {
+		return fmt.Sprintf("ORDER BY %s DESC, id DESC LIMIT %d OFFSET %d", column, p.Limit+1, p.Offset)
+	}
+	if p.Cursor.Before {
+		// Walk back towards the newest rows; NewPage restores newest-first order.
+		c.add("("+column+", id) > (?, ?)", p.Cursor.At, p.Cursor.ID)
+		return fmt.Sprintf("ORDER BY %s ASC, id ASC LIMIT %d", column, p.Limit+1)
+	}
+	c.add("("+column+", id) < (?, ?)", p.Cursor.At, p.Cursor.ID)
+	return fmt.Sprintf("ORDER BY %s DESC, id DESC LIMIT %d", column, p.Limit+1)
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type pointsRepository struct {
+	db *sql.DB
+}
+
+func NewPointsRepository(db *sql.DB) *pointsRepository {
+	return &pointsRepository{db: db}
+}
+
+const pointsColumns = `id, customer_id, type, points, remaining, expires_at, idempotency_key, reference, reason,
+		reverses_id, created_by, created_at`
+
+func scanPointsTransaction(row interface{ Scan(...interface{}) error }) (*domain.PointsTransaction, error) {
+	t := &domain.PointsTransaction{}
+	var key, reference, reason sql.NullString
+	err := row.Scan(
+		&t.ID, &t.CustomerID, &t.Type, &t.Points, &t.Remaining, &t.ExpiresAt, &key, &reference, &reason,
+		&t.ReversesID, &t.CreatedBy, &t.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.IdempotencyKey = key.String
+	t.Reference = reference.String
+	t.Reason = reason.String
+	return t, nil
+}
+
+func (r *pointsRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PointsTransaction, error) {
+	query := `SELECT ` + pointsColumns + ` FROM points_transactions WHERE id = $1`
+	t, err := scanPointsTransaction(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("points transaction %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT lot_id, points FROM points_allocations WHERE transaction_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a domain.PointsAllocation
+		if err := rows.Scan(&a.LotID, &a.Points); err != nil {
+			return nil, err
+		}
+		t.Allocations = append(t.Allocations, a)
+	}
+	return t, rows.Err()
+}
+
+func (r *pointsRepository) FindByIdempotencyKey(ctx context.Context, customerID uuid.UUID, key string) (*domain.PointsTransaction, error) {
+	query := `SELECT ` + pointsColumns + ` FROM points_transactions WHERE customer_id = $1 AND idempotency_key = $2`
+	t, err := scanPointsTransaction(r.db.QueryRowContext(ctx, query, customerID, key))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (r *pointsRepository) List(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.PointsTransaction, error) {
+	query := `SELECT ` + pointsColumns + `
+		FROM points_transactions
+		WHERE customer_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []*domain.PointsTransaction
+	for rows.Next() {
+		t, err := scanPointsTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+	}
+	return txs, rows.Err()
+}
+
+func (r *pointsRepository) Post(ctx context.Context, customerID uuid.UUID, build func(lots []*domain.PointsTransaction) (*domain.PointsTransaction, error)) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the customer row so concurrent postings for one customer are serialised.
+	var locked uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM customers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, customerID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	lots, err := r.openLots(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+	t, err := build(lots)
+	if err != nil || t == nil {
+		return err
+	}
+
+	t.CustomerID = customerID
+	if t.IsLot() {
+		t.Remaining = t.Points
+	}
+	insert := `
+		INSERT INTO points_transactions (
+			customer_id, type, points, remaining, expires_at, idempotency_key, reference, reason, reverses_id, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, insert,
+		t.CustomerID, t.Type, t.Points, t.Remaining, t.ExpiresAt, nullString(t.IdempotencyKey),
+		nullString(t.Reference), nullString(t.Reason), t.ReversesID, t.CreatedBy,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+
+	for _, a := range t.Allocations {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO points_allocations (transaction_id, lot_id, points) VALUES ($1, $2, $3)`,
+			t.ID, a.LotID, a.Points); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx,
+			`UPDATE points_transactions SET remaining = remaining - $1 WHERE id = $2 AND customer_id = $3`,
+			a.Points, a.LotID, customerID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			return fmt.Errorf("points lot %s %w", a.LotID, domain.ErrNotFound)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE customers SET points_balance = (
+			SELECT COALESCE(SUM(remaining), 0) FROM points_transactions
+			WHERE customer_id = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > NOW())
+		) WHERE id = $1`, customerID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *pointsRepository) openLots(ctx context.Context, tx *sql.Tx, customerID uuid.UUID) ([]*domain.PointsTransaction, error) {
+	query := `SELECT ` + pointsColumns + `
+		FROM points_transactions
+		WHERE customer_id = $1 AND remaining > 0
+		ORDER BY created_at, id`
+	rows, err := tx.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*domain.PointsTransaction
+	for rows.Next() {
+		t, err := scanPointsTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, t)
+	}
+	return lots, rows.Err()
+}
+
+// CustomersWithExpiredLots skips deleted customers, including merged duplicates: Post refuses
+// them, and their lots would otherwise come back in every batch.
+func (r *pointsRepository) CustomersWithExpiredLots(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT t.customer_id FROM points_transactions t
+		JOIN customers c ON c.id = t.customer_id AND c.deleted_at IS NULL
+		WHERE t.remaining > 0 AND t.expires_at <= NOW()
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type preferenceRepository struct {
+	db *sql.DB
+}
+
+func NewPreferenceRepository(db *sql.DB) *preferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+func (r *preferenceRepository) Get(ctx context.Context, customerID uuid.UUID) (*domain.CommunicationPreferences, error) {
+	p := &domain.CommunicationPreferences{CustomerID: customerID}
+	var language sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT language, updated_at FROM customer_communication_preferences WHERE customer_id = $1`, customerID,
+	).Scan(&language, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Language = language.String
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT purpose, channel, allowed, priority, days, quiet_start, quiet_end
+		FROM customer_channel_preferences
+		WHERE customer_id = $1
+		ORDER BY purpose, priority, channel`, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	p.Channels = []domain.ChannelPreference{}
+	for rows.Next() {
+		var c domain.ChannelPreference
+		var days, quietStart, quietEnd sql.NullString
+		if err := rows.Scan(&c.Purpose, &c.Channel, &c.Allowed, &c.Priority, &days, &quietStart, &quietEnd); err != nil {
+			return nil, err
+		}
+		if days.String != "" {
+			c.Days = strings.Split(days.String, ",")
+		}
+		c.QuietStart = quietStart.String
+		c.QuietEnd = quietEnd.String
+		p.Channels = append(p.Channels, c)
+	}
+	return p, rows.Err()
+}
+
+func (r *preferenceRepository) Save(ctx context.Context, p *domain.CommunicationPreferences) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customer_communication_preferences (customer_id, language, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (customer_id) DO UPDATE SET language = $2, updated_at = NOW()
+		RETURNING updated_at`, p.CustomerID, nullString(p.Language),
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_channel_preferences WHERE customer_id = $1`, p.CustomerID); err != nil {
+		return err
+	}
+	for _, c := range p.Channels {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO customer_channel_preferences (customer_id, purpose, channel, allowed, priority, days, quiet_start, quiet_end)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			p.CustomerID, c.Purpose, c.Channel, c.Allowed, c.Priority,
+			nullString(strings.Join(c.Days, ",")), nullString(c.QuietStart), nullString(c.QuietEnd))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
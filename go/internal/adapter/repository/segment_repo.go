@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type segmentRepository struct {
+	db *sql.DB
+}
+
+func NewSegmentRepository(db *sql.DB) *segmentRepository {
+	return &segmentRepository{db: db}
+}
+
+// segmentSelect joins each segment to its latest run, if any.
+const segmentSelect = `
+	SELECT s.id, s.name, s.description, s.filter, s.shared_with_roles, s.schedule, s.owner_id, s.created_at, s.updated_at,
+		r.id, r.run_at, r.size, r.entered, r.left_count, r.triggered_by
+	FROM segments s
+	LEFT JOIN LATERAL (
+		SELECT * FROM segment_runs WHERE segment_id = s.id ORDER BY run_at DESC LIMIT 1
+	) r ON true`
+
+const segmentRunColumns = `id, segment_id, run_at, size, entered, left_count, triggered_by`
+
+func scanSegment(row interface{ Scan(...interface{}) error }) (*domain.Segment, error) {
+	s := &domain.Segment{}
+	var description, roles, schedule sql.NullString
+	var filter []byte
+	var runID uuid.NullUUID
+	var runAt sql.NullTime
+	var size, entered, left sql.NullInt64
+	var triggeredBy sql.NullString
+	err := row.Scan(
+		&s.ID, &s.Name, &description, &filter, &roles, &schedule, &s.OwnerID, &s.CreatedAt, &s.UpdatedAt,
+		&runID, &runAt, &size, &entered, &left, &triggeredBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.Description = description.String
+	s.Schedule = schedule.String
+	if roles.String != "" {
+		s.SharedWithRoles = strings.Split(roles.String, ",")
+	}
+	if err := json.Unmarshal(filter, &s.Filter); err != nil {
+		return nil, fmt.Errorf("segment %s filter: %w", s.ID, err)
+	}
+	if runID.Valid {
+		s.LastRun = &domain.SegmentRun{
+			ID: runID.UUID, SegmentID: s.ID, RunAt: runAt.Time,
+			Size: int(size.Int64), Entered: int(entered.Int64), Left: int(left.Int64), TriggeredBy: triggeredBy.String,
+		}
+	}
+	return s, nil
+}
+
+func scanSegmentRun(row interface{ Scan(...interface{}) error }) (*domain.SegmentRun, error) {
+	run := &domain.SegmentRun{}
+	err := row.Scan(&run.ID, &run.SegmentID, &run.RunAt, &run.Size, &run.Entered, &run.Left, &run.TriggeredBy)
+	return run, err
+}
+
+func (r *segmentRepository) Create(ctx context.Context, s *domain.Segment) error {
+	filter, err := json.Marshal(s.Filter)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO segments (name, description, filter, shared_with_roles, schedule, owner_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		s.Name, nullString(s.Description), filter, nullString(strings.Join(s.SharedWithRoles, ",")), nullString(s.Schedule), s.OwnerID,
+	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *segmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Segment, error) {
+	s, err := scanSegment(r.db.QueryRowContext(ctx, segmentSelect+` WHERE s.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("segment %w", domain.ErrNotFound)
+	}
+	return s, err
+}
+
+// Update rewrites the definition. The owner and creation time never change.
+func (r *segmentRepository) Update(ctx context.Context, s *domain.Segment) error {
+	filter, err := json.Marshal(s.Filter)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE segments
+		SET name=$1, description=$2, filter=$3, shared_with_roles=$4, schedule=$5, updated_at=NOW()
+		WHERE id=$6
+		RETURNING updated_at
+	`
+	err = r.db.QueryRowContext(ctx, query,
+		s.Name, nullString(s.Description), filter, nullString(strings.Join(s.SharedWithRoles, ",")), nullString(s.Schedule), s.ID,
+	).Scan(&s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("segment %w", domain.ErrNotFound)
+	}
+	return err
+}
+
+func (r *segmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM segments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("segment %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *segmentRepository) List(ctx context.Context, schedule string) ([]*domain.Segment, error) {
+	query, args := segmentSelect, []interface{}{}
+	if schedule != "" {
+		query += ` WHERE s.schedule = $1`
+		args = append(args, schedule)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY s.name, s.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*domain.Segment
+	for rows.Next() {
+		s, err := scanSegment(rows)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+	return segments, rows.Err()
+}
+
+func (r *segmentRepository) SaveRun(ctx context.Context, run *domain.SegmentRun, memberIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the segment so concurrent runs apply in order, then stamp the run after taking the
+	// lock: membership intervals are compared by time, so run times must increase.
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM segments WHERE id = $1 FOR UPDATE`, run.SegmentID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("segment %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT clock_timestamp()`).Scan(&run.RunAt); err != nil {
+		return err
+	}
+
+	ids := make([]string, len(memberIDs))
+	for i, id := range memberIDs {
+		ids[i] = id.String()
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE segment_members SET left_at = $2
+		WHERE segment_id = $1 AND left_at IS NULL AND customer_id <> ALL($3::uuid[])`,
+		run.SegmentID, run.RunAt, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	left, _ := res.RowsAffected()
+	res, err = tx.ExecContext(ctx, `
+		INSERT INTO segment_members (segment_id, customer_id, entered_at)
+		SELECT $1, m.id, $2 FROM unnest($3::uuid[]) AS m(id)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM segment_members WHERE segment_id = $1 AND customer_id = m.id AND left_at IS NULL
+		)`,
+		run.SegmentID, run.RunAt, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	entered, _ := res.RowsAffected()
+	run.Size, run.Entered, run.Left = len(memberIDs), int(entered), int(left)
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO segment_runs (segment_id, run_at, size, entered, left_count, triggered_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		run.SegmentID, run.RunAt, run.Size, run.Entered, run.Left, run.TriggeredBy,
+	).Scan(&run.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *segmentRepository) GetRun(ctx context.Context, id uuid.UUID) (*domain.SegmentRun, error) {
+	run, err := scanSegmentRun(r.db.QueryRowContext(ctx, `SELECT `+segmentRunColumns+` FROM segment_runs WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("segment run %w", domain.ErrNotFound)
+	}
+	return run, err
+}
+
+func (r *segmentRepository) PreviousRun(ctx context.Context, run *domain.SegmentRun) (*domain.SegmentRun, error) {
+	prev, err := scanSegmentRun(r.db.QueryRowContext(ctx, `
+		SELECT `+segmentRunColumns+` FROM segment_runs
+		WHERE segment_id = $1 AND run_at < $2
+		ORDER BY run_at DESC LIMIT 1`, run.SegmentID, run.RunAt))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return prev, err
+}
+
+func (r *segmentRepository) ListRuns(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]*domain.SegmentRun, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+segmentRunColumns+` FROM segment_runs
+		WHERE segment_id = $1
+		ORDER BY run_at DESC LIMIT $2 OFFSET $3`, segmentID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*domain.SegmentRun
+	for rows.Next() {
+		run, err := scanSegmentRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Changes compares membership at the two run times. A customer is a member at time t when one
+// of their intervals has entered_at <= t < left_at (or is still open).
+func (r *segmentRepository) Changes(ctx context.Context, segmentID uuid.UUID, from, to *domain.SegmentRun) ([]domain.SegmentChange, error) {
+	var fromAt time.Time // before any run: nobody was a member
+	if from != nil {
+		fromAt = from.RunAt
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT customer_id, in_to FROM (
+			SELECT customer_id,
+				bool_or(entered_at <= $2 AND (left_at IS NULL OR left_at > $2)) AS in_from,
+				bool_or(entered_at <= $3 AND (left_at IS NULL OR left_at > $3)) AS in_to
+			FROM segment_members
+			WHERE segment_id = $1 AND entered_at <= GREATEST($2, $3) AND (left_at IS NULL OR left_at > LEAST($2, $3))
+			GROUP BY customer_id
+		) m
+		WHERE in_from <> in_to
+		ORDER BY in_to DESC, customer_id`, segmentID, fromAt, to.RunAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.SegmentChange{}
+	for rows.Next() {
+		var c domain.SegmentChange
+		var entered bool
+		if err := rows.Scan(&c.CustomerID, &entered); err != nil {
+			return nil, err
+		}
+		c.Change = domain.SegmentLeft
+		if entered {
+			c.Change = domain.SegmentEntered
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // --- Address Repository ---
@@ -22,19 +23,27 @@ func (r *addressRepository) Create(ctx context.Context, a *domain.Address) error
 	query := `
 		INSERT INTO addresses (
 			customer_id, type, address_line1, address_line2,
-			city, state, district, sub_district, zip_code, country
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			city, state, district, sub_district, zip_code, country, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
 	`
 	return r.db.QueryRowContext(ctx, query,
 		a.CustomerID, a.Type, a.AddressLine1, a.AddressLine2,
-		a.City, a.State, a.District, a.SubDistrict, a.ZipCode, a.Country,
+		a.City, a.State, a.District, a.SubDistrict, a.ZipCode, a.Country, a.CreatedBy,
 	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
 }
 
 func (r *addressRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Address, error) {
-	query := `SELECT * FROM addresses WHERE customer_id = $1`
-	rows, err := r.db.QueryContext(ctx, query, customerID)
+	return r.query(ctx, `SELECT * FROM addresses WHERE customer_id = $1`, customerID)
+}
+
+// ListByCustomerIDs loads the addresses of several customers in one query.
+func (r *addressRepository) ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Address, error) {
+	return r.query(ctx, `SELECT * FROM addresses WHERE customer_id = ANY($1)`, pq.Array(customerIDs))
+}
+
+func (r *addressRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Address, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,13 +55,13 @@ func (r *addressRepository) ListByCustomerID(ctx context.Context, customerID uui
 		if err := rows.Scan(
 			&a.ID, &a.CustomerID, &a.Type,
 			&a.AddressLine1, &a.AddressLine2, &a.City, &a.State, &a.District, &a.SubDistrict, &a.ZipCode, &a.Country,
-			&a.CreatedAt, &a.UpdatedAt,
+			&a.CreatedAt, &a.UpdatedAt, &a.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
 		addresses = append(addresses, a)
 	}
-	return addresses, nil
+	return addresses, rows.Err()
 }
 
 func (r *addressRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -73,8 +82,8 @@ func NewIdentityRepository(db *sql.DB) *identityRepository {
 func (r *identityRepository) Create(ctx context.Context, i *domain.Identity) error {
 	query := `
 		INSERT INTO identities (
-			customer_id, type, number, issuance_country, expiry_date
-		) VALUES ($1, $2, $3, $4, $5)
+			customer_id, type, number, issuance_country, expiry_date, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
 	var expiry sql.NullTime
@@ -84,7 +93,7 @@ func (r *identityRepository) Create(ctx context.Context, i *domain.Identity) err
 	}
 
 	err := r.db.QueryRowContext(ctx, query,
-		i.CustomerID, i.Type, i.Number, i.IssuanceCountry, expiry,
+		i.CustomerID, i.Type, i.Number, i.IssuanceCountry, expiry, i.CreatedBy,
 	).Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
 
 	if err == nil && expiry.Valid {
@@ -94,8 +103,16 @@ func (r *identityRepository) Create(ctx context.Context, i *domain.Identity) err
 }
 
 func (r *identityRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Identity, error) {
-	query := `SELECT * FROM identities WHERE customer_id = $1`
-	rows, err := r.db.QueryContext(ctx, query, customerID)
+	return r.query(ctx, `SELECT * FROM identities WHERE customer_id = $1`, customerID)
+}
+
+// ListByCustomerIDs loads the identities of several customers in one query.
+func (r *identityRepository) ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Identity, error) {
+	return r.query(ctx, `SELECT * FROM identities WHERE customer_id = ANY($1)`, pq.Array(customerIDs))
+}
+
+func (r *identityRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Identity, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +124,7 @@ func (r *identityRepository) ListByCustomerID(ctx context.Context, customerID uu
 		var expiry sql.NullTime
 		if err := rows.Scan(
 			&i.ID, &i.CustomerID, &i.Type, &i.Number, &i.IssuanceCountry, &expiry,
-			&i.CreatedAt, &i.UpdatedAt,
+			&i.CreatedAt, &i.UpdatedAt, &i.CreatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -116,7 +133,7 @@ func (r *identityRepository) ListByCustomerID(ctx context.Context, customerID uu
 		}
 		identities = append(identities, i)
 	}
-	return identities, nil
+	return identities, rows.Err()
 }
 
 func (r *identityRepository) GetByNumber(ctx context.Context, number string) (*domain.Identity, error) {
@@ -125,7 +142,7 @@ func (r *identityRepository) GetByNumber(ctx context.Context, number string) (*d
 	var expiry sql.NullTime
 	err := r.db.QueryRowContext(ctx, query, number).Scan(
 		&i.ID, &i.CustomerID, &i.Type, &i.Number, &i.IssuanceCountry, &expiry,
-		&i.CreatedAt, &i.UpdatedAt,
+		&i.CreatedAt, &i.UpdatedAt, &i.CreatedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -153,12 +170,12 @@ func NewRelationshipRepository(db *sql.DB) *relationshipRepository {
 
 func (r *relationshipRepository) Create(ctx context.Context, rel *domain.Relationship) error {
 	query := `
-		INSERT INTO relationships (from_customer_id, to_customer_id, role)
-		VALUES ($1, $2, $3)
+		INSERT INTO relationships (from_customer_id, to_customer_id, role, created_by)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
 	return r.db.QueryRowContext(ctx, query,
-		rel.FromCustomerID, rel.ToCustomerID, rel.Role,
+		rel.FromCustomerID, rel.ToCustomerID, rel.Role, rel.CreatedBy,
 	).Scan(&rel.ID, &rel.CreatedAt)
 }
 
@@ -167,7 +184,20 @@ func (r *relationshipRepository) ListByCustomerID(ctx context.Context, customerI
 		SELECT * FROM relationships 
 		WHERE from_customer_id = $1 OR to_customer_id = $1
 	`
-	rows, err := r.db.QueryContext(ctx, query, customerID)
+	return r.query(ctx, query, customerID)
+}
+
+// ListByCustomerIDs loads the relationships touching any of several customers in one query.
+func (r *relationshipRepository) ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Relationship, error) {
+	query := `
+		SELECT * FROM relationships
+		WHERE from_customer_id = ANY($1) OR to_customer_id = ANY($1)
+	`
+	return r.query(ctx, query, pq.Array(customerIDs))
+}
+
+func (r *relationshipRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Relationship, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -176,12 +206,12 @@ func (r *relationshipRepository) ListByCustomerID(ctx context.Context, customerI
 	var rels []*domain.Relationship
 	for rows.Next() {
 		rel := &domain.Relationship{}
-		if err := rows.Scan(&rel.ID, &rel.FromCustomerID, &rel.ToCustomerID, &rel.Role, &rel.CreatedAt); err != nil {
+		if err := rows.Scan(&rel.ID, &rel.FromCustomerID, &rel.ToCustomerID, &rel.Role, &rel.CreatedAt, &rel.CreatedBy); err != nil {
 			return nil, err
 		}
 		rels = append(rels, rel)
 	}
-	return rels, nil
+	return rels, rows.Err()
 }
 
 func (r *relationshipRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -201,18 +231,26 @@ func NewConsentRepository(db *sql.DB) *consentRepository {
 
 func (r *consentRepository) Create(ctx context.Context, c *domain.Consent) error {
 	query := `
-		INSERT INTO consents (customer_id, topic, version, is_granted)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO consents (customer_id, topic, version, is_granted, created_by)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, timestamp, created_at
 	`
 	return r.db.QueryRowContext(ctx, query,
-		c.CustomerID, c.Topic, c.Version, c.IsGranted,
+		c.CustomerID, c.Topic, c.Version, c.IsGranted, c.CreatedBy,
 	).Scan(&c.ID, &c.Timestamp, &c.CreatedAt)
 }
 
 func (r *consentRepository) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Consent, error) {
-	query := `SELECT * FROM consents WHERE customer_id = $1 ORDER BY timestamp DESC`
-	rows, err := r.db.QueryContext(ctx, query, customerID)
+	return r.query(ctx, `SELECT * FROM consents WHERE customer_id = $1 ORDER BY timestamp DESC`, customerID)
+}
+
+// ListByCustomerIDs loads the consents of several customers in one query, newest first.
+func (r *consentRepository) ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Consent, error) {
+	return r.query(ctx, `SELECT * FROM consents WHERE customer_id = ANY($1) ORDER BY timestamp DESC`, pq.Array(customerIDs))
+}
+
+func (r *consentRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Consent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -221,18 +259,21 @@ func (r *consentRepository) ListByCustomerID(ctx context.Context, customerID uui
 	var consents []*domain.Consent
 	for rows.Next() {
 		c := &domain.Consent{}
-		if err := rows.Scan(&c.ID, &c.CustomerID, &c.Topic, &c.Version, &c.IsGranted, &c.Timestamp, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.CustomerID, &c.Topic, &c.Version, &c.IsGranted, &c.Timestamp, &c.CreatedAt, &c.CreatedBy); err != nil {
 			return nil, err
 		}
 		consents = append(consents, c)
 	}
-	return consents, nil
+	return consents, rows.Err()
 }
 
-func (r *consentRepository) ListAll(ctx context.Context, limit, offset int) ([]*domain.Consent, error) {
-	query := `SELECT id, customer_id, topic, version, is_granted, timestamp, created_at
-		FROM consents ORDER BY timestamp DESC LIMIT $1 OFFSET $2`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+// ListAll pages through consents of all customers, newest first.
+func (r *consentRepository) ListAll(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Consent], error) {
+	cond := &conditions{}
+	tail := keyset(cond, "timestamp", p)
+	query := `SELECT id, customer_id, topic, version, is_granted, timestamp, created_at, created_by
+		FROM consents ` + cond.where() + " " + tail
+	rows, err := r.db.QueryContext(ctx, query, cond.args...)
 	if err != nil {
 		return nil, err
 	}
@@ -241,10 +282,15 @@ func (r *consentRepository) ListAll(ctx context.Context, limit, offset int) ([]*
 	var consents []*domain.Consent
 	for rows.Next() {
 		c := &domain.Consent{}
-		if err := rows.Scan(&c.ID, &c.CustomerID, &c.Topic, &c.Version, &c.IsGranted, &c.Timestamp, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.CustomerID, &c.Topic, &c.Version, &c.IsGranted, &c.Timestamp, &c.CreatedAt, &c.CreatedBy); err != nil {
 			return nil, err
 		}
 		consents = append(consents, c)
 	}
-	return consents, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.NewPage(consents, p, func(c *domain.Consent) domain.PageCursor {
+		return domain.PageCursor{At: c.Timestamp, ID: c.ID}
+	}), nil
 }
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type tierRepository struct {
+	db *sql.DB
+}
+
+func NewTierRepository(db *sql.DB) *tierRepository {
+	return &tierRepository{db: db}
+}
+
+// tierColumns are the fields the tier rules read.
+const tierColumns = `id, type, nationality, status, membership_tier, points_balance, clv, portfolio_size,
+		       last_transaction_date, is_high_value`
+
+// ListForRecalc loads only the fields the tier rules read.
+func (r *tierRepository) ListForRecalc(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.Customer, error) {
+	query := `
+		SELECT ` + tierColumns + `
+		FROM customers
+		WHERE deleted_at IS NULL AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []*domain.Customer
+	for rows.Next() {
+		c, err := scanTierCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+	return customers, rows.Err()
+}
+
+func (r *tierRepository) GetForRecalc(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error) {
+	query := `SELECT ` + tierColumns + ` FROM customers WHERE id = $1 AND deleted_at IS NULL`
+	c, err := scanTierCustomer(r.db.QueryRowContext(ctx, query, customerID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	return c, err
+}
+
+func scanTierCustomer(row interface{ Scan(...interface{}) error }) (*domain.Customer, error) {
+	c := &domain.Customer{}
+	var nationality, status, tier sql.NullString
+	var points, clv, portfolio decimal.NullDecimal
+	var isHighValue sql.NullBool
+	if err := row.Scan(&c.ID, &c.Type, &nationality, &status, &tier, &points, &clv, &portfolio,
+		&c.LastTransactionDate, &isHighValue); err != nil {
+		return nil, err
+	}
+	c.Nationality = nationality.String
+	c.Status = domain.CustomerStatus(status.String)
+	c.MembershipTier = tier.String
+	c.PointsBalance = points.Decimal
+	c.CLV = clv.Decimal
+	c.PortfolioSize = portfolio.Decimal
+	c.IsHighValue = isHighValue.Bool
+	return c, nil
+}
+
+func (r *tierRepository) UpdateTier(ctx context.Context, customerID uuid.UUID, tier string, isHighValue bool) error {
+	query := `UPDATE customers SET membership_tier=$1, is_high_value=$2, updated_at=NOW() WHERE id=$3`
+	_, err := r.db.ExecContext(ctx, query, tier, isHighValue, customerID)
+	return err
+}
+
+func (r *tierRepository) CreateHistory(ctx context.Context, c *domain.TierChange) error {
+	query := `
+		INSERT INTO customer_tier_history (
+			customer_id, old_tier, new_tier, old_high_value, new_high_value, rule_version, rule, source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, changed_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		c.CustomerID, c.OldTier, c.NewTier, c.OldHighValue, c.NewHighValue, c.RuleVersion, c.Rule, c.Source,
+	).Scan(&c.ID, &c.ChangedAt)
+}
+
+func (r *tierRepository) ListHistory(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.TierChange, error) {
+	query := `
+		SELECT id, customer_id, old_tier, new_tier, old_high_value, new_high_value, rule_version, rule, source, changed_at
+		FROM customer_tier_history
+		WHERE customer_id = $1
+		ORDER BY changed_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.TierChange
+	for rows.Next() {
+		c := &domain.TierChange{}
+		var oldTier, newTier, rule sql.NullString
+		if err := rows.Scan(&c.ID, &c.CustomerID, &oldTier, &newTier, &c.OldHighValue, &c.NewHighValue,
+			&c.RuleVersion, &rule, &c.Source, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		c.OldTier = oldTier.String
+		c.NewTier = newTier.String
+		c.Rule = rule.String
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -19,13 +19,13 @@ func NewUserRepository(db *sql.DB) ports.UserRepository {
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, email, username, is_active, created_at, updated_at, role, supervisor_id
+		SELECT id, email, username, is_active, created_at, updated_at, role, supervisor_id, team_id, branch_id
 		FROM users
 		WHERE id = $1
 	`
 	var user models.User
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.Username, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &user.Role, &user.SupervisorID,
+		&user.ID, &user.Email, &user.Username, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &user.Role, &user.SupervisorID, &user.TeamID, &user.BranchID,
 	)
 	if err != nil {
 		return nil, err
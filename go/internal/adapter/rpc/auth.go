@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/adapter/rpc/cicv1"
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyLookup resolves an API key to the ID of the user who owns it, as
+// middleware.APIKeyLookup does.
+type APIKeyLookup func(ctx context.Context, apiKey string) (string, error)
+
+// principal is the authenticated caller of an RPC.
+type principal struct {
+	userID uuid.UUID
+	role   string
+	// apiKey is set for callers authenticated by API key; they may only read.
+	apiKey bool
+}
+
+type principalKey struct{}
+
+// callerFrom returns the caller stored by the auth interceptors.
+func callerFrom(ctx context.Context) (*principal, error) {
+	p, ok := ctx.Value(principalKey{}).(*principal)
+	if !ok || p == nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	return p, nil
+}
+
+var (
+	writeRoles = []string{middleware.RoleSuperAdmin, middleware.RoleAdmin, middleware.RoleOperator}
+	adminRoles = []string{middleware.RoleSuperAdmin, middleware.RoleAdmin}
+)
+
+// methodRoles lists the roles allowed to call each method, following the REST routes. A nil
+// entry allows every authenticated caller, API keys included. Methods not listed are denied.
+var methodRoles = map[string][]string{
+	cicv1.CustomerService_GetCustomer_FullMethodName:          nil,
+	cicv1.CustomerService_ListCustomers_FullMethodName:        nil,
+	cicv1.CustomerService_ListDeletedCustomers_FullMethodName: nil,
+	cicv1.CustomerService_SearchCustomers_FullMethodName:      nil,
+	cicv1.CustomerService_ListAddresses_FullMethodName:        nil,
+	cicv1.CustomerService_ListIdentities_FullMethodName:       nil,
+	cicv1.CustomerService_ListRelationships_FullMethodName:    nil,
+	cicv1.CustomerService_ListConsents_FullMethodName:         nil,
+
+	cicv1.CustomerService_CreateCustomer_FullMethodName:     writeRoles,
+	cicv1.CustomerService_UpdateCustomer_FullMethodName:     writeRoles,
+	cicv1.CustomerService_AddAddress_FullMethodName:         writeRoles,
+	cicv1.CustomerService_RemoveAddress_FullMethodName:      writeRoles,
+	cicv1.CustomerService_AddIdentity_FullMethodName:        writeRoles,
+	cicv1.CustomerService_RemoveIdentity_FullMethodName:     writeRoles,
+	cicv1.CustomerService_AddRelationship_FullMethodName:    writeRoles,
+	cicv1.CustomerService_RemoveRelationship_FullMethodName: writeRoles,
+	cicv1.CustomerService_ManageConsent_FullMethodName:      writeRoles,
+
+	cicv1.CustomerService_DeleteCustomer_FullMethodName:    adminRoles,
+	cicv1.CustomerService_RestoreCustomer_FullMethodName:   adminRoles,
+	cicv1.CustomerService_AnonymizeCustomer_FullMethodName: adminRoles,
+}
+
+type authenticator struct {
+	apiKeys APIKeyLookup
+}
+
+// authorize authenticates the caller from the request metadata and checks that they may call
+// method. "authorization: Bearer <jwt>" is validated with auth.ValidateJWT; otherwise
+// "x-api-key" is looked up. A nil apiKeys lookup rejects API keys.
+func (a *authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var p *principal
+	if values := md.Get("authorization"); len(values) > 0 {
+		parts := strings.Split(values[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+		}
+		claims, err := auth.ValidateJWT(parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid user ID in token")
+		}
+		p = &principal{userID: userID, role: claims.Role}
+	} else if values := md.Get("x-api-key"); len(values) > 0 && a.apiKeys != nil {
+		id, err := a.apiKeys(ctx, values[0])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		p = &principal{userID: userID, apiKey: true}
+	} else {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+
+	roles, ok := methodRoles[method]
+	if !ok || (p.apiKey && roles != nil) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+	if roles != nil && !hasRole(roles, p.role) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+	return context.WithValue(ctx, principalKey{}, p), nil
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream carries the authenticated context into a streaming handler.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
+
+// NewServer returns a gRPC server whose every call is authenticated and checked against
+// methodRoles before it reaches a service.
+func NewServer(apiKeys APIKeyLookup, opts ...grpc.ServerOption) *grpc.Server {
+	a := &authenticator{apiKeys: apiKeys}
+	opts = append(opts, grpc.ChainUnaryInterceptor(a.unary), grpc.ChainStreamInterceptor(a.stream))
+	return grpc.NewServer(opts...)
+}
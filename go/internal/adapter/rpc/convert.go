@@ -0,0 +1,333 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/adapter/rpc/cicv1"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const dateLayout = "2006-01-02"
+
+// invalidArgument reports a malformed request field.
+func invalidArgument(field, value string) error {
+	return status.Errorf(codes.InvalidArgument, "invalid %s %q", field, value)
+}
+
+func parseID(field, raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, invalidArgument(field, raw)
+	}
+	return id, nil
+}
+
+// parseOptionalID is parseID allowing an empty value, as for IDs assigned on create.
+func parseOptionalID(field, raw string) (uuid.UUID, error) {
+	if raw == "" {
+		return uuid.Nil, nil
+	}
+	return parseID(field, raw)
+}
+
+func parseDate(field, raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(dateLayout, raw)
+	if err != nil {
+		return time.Time{}, invalidArgument(field, raw)
+	}
+	return t, nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func parseDecimal(field, raw string) (decimal.Decimal, error) {
+	if raw == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.Zero, invalidArgument(field, raw)
+	}
+	return d, nil
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toCustomer(c *domain.Customer, withheld bool) *cicv1.Customer {
+	out := &cicv1.Customer{
+		Id:                  c.ID.String(),
+		CustomerNumber:      c.CustomerNumber,
+		Type:                string(c.Type),
+		FirstName:           c.FirstName,
+		LastName:            c.LastName,
+		Title:               c.Title,
+		DateOfBirth:         formatDate(c.DateOfBirth),
+		Nationality:         c.Nationality,
+		CompanyName:         c.CompanyName,
+		RegistrationDate:    formatDate(c.RegistrationDate),
+		IndustryCode:        c.IndustryCode,
+		Status:              string(c.Status),
+		MembershipTier:      c.MembershipTier,
+		PreferredChannel:    c.PreferredChannel,
+		IsHighValue:         c.IsHighValue,
+		LastTransactionDate: optionalTimestamp(c.LastTransactionDate),
+		Withheld:            withheld,
+		CreatedAt:           timestamp(c.CreatedAt),
+		UpdatedAt:           timestamp(c.UpdatedAt),
+		DeletedAt:           optionalTimestamp(c.DeletedAt),
+	}
+	if withheld {
+		return out
+	}
+	out.PointsBalance = c.PointsBalance.String()
+	out.Clv = c.CLV.String()
+	out.PortfolioSize = c.PortfolioSize.String()
+	for _, m := range c.PortfolioValues {
+		out.PortfolioValues = append(out.PortfolioValues, &cicv1.Money{Amount: m.Amount.String(), Currency: m.Currency})
+	}
+	for _, r := range c.ExternalRefs {
+		out.ExternalRefs = append(out.ExternalRefs, &cicv1.ExternalRef{
+			Id: r.ID.String(), System: r.System, Key: r.Key, CreatedAt: timestamp(r.CreatedAt),
+		})
+	}
+	return out
+}
+
+// fromCustomer reads a customer sent by a client. Server-maintained fields such as the
+// customer number and timestamps are ignored.
+func fromCustomer(in *cicv1.Customer) (*domain.Customer, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "customer is required")
+	}
+	c := &domain.Customer{
+		Type:             domain.CustomerType(in.Type),
+		FirstName:        in.FirstName,
+		LastName:         in.LastName,
+		Title:            in.Title,
+		Nationality:      in.Nationality,
+		CompanyName:      in.CompanyName,
+		IndustryCode:     in.IndustryCode,
+		Status:           domain.CustomerStatus(in.Status),
+		MembershipTier:   in.MembershipTier,
+		PreferredChannel: in.PreferredChannel,
+		IsHighValue:      in.IsHighValue,
+	}
+	var err error
+	if c.ID, err = parseOptionalID("customer id", in.Id); err != nil {
+		return nil, err
+	}
+	if c.DateOfBirth, err = parseDate("date_of_birth", in.DateOfBirth); err != nil {
+		return nil, err
+	}
+	if c.RegistrationDate, err = parseDate("registration_date", in.RegistrationDate); err != nil {
+		return nil, err
+	}
+	if c.PointsBalance, err = parseDecimal("points_balance", in.PointsBalance); err != nil {
+		return nil, err
+	}
+	if c.CLV, err = parseDecimal("clv", in.Clv); err != nil {
+		return nil, err
+	}
+	if c.PortfolioSize, err = parseDecimal("portfolio_size", in.PortfolioSize); err != nil {
+		return nil, err
+	}
+	if in.LastTransactionDate != nil {
+		t := in.LastTransactionDate.AsTime()
+		c.LastTransactionDate = &t
+	}
+	for _, m := range in.PortfolioValues {
+		amount, err := parseDecimal("portfolio value", m.Amount)
+		if err != nil {
+			return nil, err
+		}
+		c.PortfolioValues = append(c.PortfolioValues, domain.Money{Amount: amount, Currency: m.Currency})
+	}
+	for _, r := range in.ExternalRefs {
+		c.ExternalRefs = append(c.ExternalRefs, domain.ExternalRef{System: r.System, Key: r.Key})
+	}
+	return c, nil
+}
+
+func toAddress(a *domain.Address) *cicv1.Address {
+	return &cicv1.Address{
+		Id:           a.ID.String(),
+		CustomerId:   a.CustomerID.String(),
+		Type:         a.Type,
+		AddressLine1: a.AddressLine1,
+		AddressLine2: a.AddressLine2,
+		City:         a.City,
+		State:        a.State,
+		District:     a.District,
+		SubDistrict:  a.SubDistrict,
+		ZipCode:      a.ZipCode,
+		Country:      a.Country,
+		CreatedAt:    timestamp(a.CreatedAt),
+		UpdatedAt:    timestamp(a.UpdatedAt),
+	}
+}
+
+func fromAddress(in *cicv1.Address) (*domain.Address, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	customerID, err := parseID("customer id", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Address{
+		CustomerID:   customerID,
+		Type:         in.Type,
+		AddressLine1: in.AddressLine1,
+		AddressLine2: in.AddressLine2,
+		City:         in.City,
+		State:        in.State,
+		District:     in.District,
+		SubDistrict:  in.SubDistrict,
+		ZipCode:      in.ZipCode,
+		Country:      in.Country,
+	}, nil
+}
+
+func toIdentity(i *domain.Identity) *cicv1.Identity {
+	return &cicv1.Identity{
+		Id:              i.ID.String(),
+		CustomerId:      i.CustomerID.String(),
+		Type:            i.Type,
+		Number:          i.Number,
+		IssuanceCountry: i.IssuanceCountry,
+		ExpiryDate:      formatDate(i.ExpiryDate),
+		CreatedAt:       timestamp(i.CreatedAt),
+		UpdatedAt:       timestamp(i.UpdatedAt),
+	}
+}
+
+func fromIdentity(in *cicv1.Identity) (*domain.Identity, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "identity is required")
+	}
+	customerID, err := parseID("customer id", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	expiry, err := parseDate("expiry_date", in.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Identity{
+		CustomerID:      customerID,
+		Type:            in.Type,
+		Number:          in.Number,
+		IssuanceCountry: in.IssuanceCountry,
+		ExpiryDate:      expiry,
+	}, nil
+}
+
+func toRelationship(r *domain.Relationship) *cicv1.Relationship {
+	return &cicv1.Relationship{
+		Id:             r.ID.String(),
+		FromCustomerId: r.FromCustomerID.String(),
+		ToCustomerId:   r.ToCustomerID.String(),
+		Role:           r.Role,
+		CreatedAt:      timestamp(r.CreatedAt),
+	}
+}
+
+func fromRelationship(in *cicv1.Relationship) (*domain.Relationship, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "relationship is required")
+	}
+	from, err := parseID("from customer id", in.FromCustomerId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseID("to customer id", in.ToCustomerId)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Relationship{FromCustomerID: from, ToCustomerID: to, Role: in.Role}, nil
+}
+
+func toConsent(c *domain.Consent) *cicv1.Consent {
+	return &cicv1.Consent{
+		Id:         c.ID.String(),
+		CustomerId: c.CustomerID.String(),
+		Topic:      c.Topic,
+		Version:    c.Version,
+		IsGranted:  c.IsGranted,
+		Timestamp:  timestamp(c.Timestamp),
+		CreatedAt:  timestamp(c.CreatedAt),
+	}
+}
+
+func fromConsent(in *cicv1.Consent) (*domain.Consent, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "consent is required")
+	}
+	customerID, err := parseID("customer id", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Consent{
+		CustomerID: customerID,
+		Topic:      in.Topic,
+		Version:    in.Version,
+		IsGranted:  in.IsGranted,
+		Timestamp:  fromTimestamp(in.Timestamp),
+	}, nil
+}
+
+// customerFilter reads ListCustomers' filter fields.
+func customerFilter(in *cicv1.ListCustomersRequest) domain.CustomerFilter {
+	f := domain.CustomerFilter{
+		Text:           in.Text,
+		Fuzzy:          in.Fuzzy,
+		Tiers:          in.Tiers,
+		Nationalities:  in.Nationalities,
+		HighValue:      in.HighValue,
+		IdentityNumber: in.IdentityNumber,
+		Phone:          in.Phone,
+		Email:          in.Email,
+		ZipCode:        in.ZipCode,
+		Province:       in.Province,
+		District:       in.District,
+	}
+	for _, t := range in.Types {
+		f.Types = append(f.Types, domain.CustomerType(t))
+	}
+	for _, s := range in.Statuses {
+		f.Statuses = append(f.Statuses, domain.CustomerStatus(s))
+	}
+	return f
+}
@@ -0,0 +1,463 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amnuaym/cic/go/internal/adapter/rpc/cicv1"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type customerServer struct {
+	cicv1.UnimplementedCustomerServiceServer
+	service   ports.CustomerService
+	access    ports.AccessGrantService
+	approvals ports.ChangeRequestService
+}
+
+// NewCustomerServer serves ports.CustomerService over gRPC with the rules of the REST customer
+// routes. A nil access service withholds nothing; a nil approvals service applies delete,
+// restore, anonymize and blacklisting immediately.
+func NewCustomerServer(service ports.CustomerService, access ports.AccessGrantService, approvals ports.ChangeRequestService) cicv1.CustomerServiceServer {
+	return &customerServer{service: service, access: access, approvals: approvals}
+}
+
+// rpcError maps service errors to gRPC status codes.
+func rpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, domain.ErrForbidden), errors.Is(err, domain.ErrAccessGrantRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidSearch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// checkAccess guards reads of a single protected customer.
+func (s *customerServer) checkAccess(ctx context.Context, customerID uuid.UUID) error {
+	if s.access == nil {
+		return nil
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.access.CheckAccess(ctx, customerID, p.userID, p.role); err != nil {
+		return rpcError(err)
+	}
+	return nil
+}
+
+// send streams customers, withholding protected ones the caller may not read.
+func (s *customerServer) send(ctx context.Context, customers []*domain.Customer, send func(*cicv1.Customer) error) error {
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range customers {
+		withheld := false
+		if s.access != nil {
+			ok, err := s.access.CanRead(ctx, c, p.userID, p.role)
+			if err != nil {
+				return rpcError(err)
+			}
+			if !ok {
+				c, withheld = c.Withheld(), true
+			}
+		}
+		if err := send(toCustomer(c, withheld)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submit files a maker-checker change request for a sensitive operation.
+func (s *customerServer) submit(ctx context.Context, op domain.ChangeOperation, customerID uuid.UUID, payload interface{}, reason string) (*cicv1.ChangeResult, error) {
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := s.approvals.Submit(ctx, op, customerID, p.userID, payload, reason)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return &cicv1.ChangeResult{Pending: true, ChangeRequestId: cr.ID.String()}, nil
+}
+
+func (s *customerServer) CreateCustomer(ctx context.Context, req *cicv1.CreateCustomerRequest) (*cicv1.Customer, error) {
+	c, err := fromCustomer(req.Customer)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.CreateCustomer(ctx, c, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return toCustomer(c, false), nil
+}
+
+func (s *customerServer) GetCustomer(ctx context.Context, req *cicv1.GetCustomerRequest) (*cicv1.Customer, error) {
+	id, err := parseID("customer id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAccess(ctx, id); err != nil {
+		return nil, err
+	}
+	c, err := s.service.GetCustomer(ctx, id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return toCustomer(c, false), nil
+}
+
+func (s *customerServer) UpdateCustomer(ctx context.Context, req *cicv1.UpdateCustomerRequest) (*cicv1.UpdateCustomerResponse, error) {
+	c, err := fromCustomer(req.Customer)
+	if err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "customer id is required")
+	}
+
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.approvals != nil && c.Status == domain.StatusBlacklist {
+		current, err := s.service.GetCustomer(ctx, c.ID)
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		if current.Status != domain.StatusBlacklist {
+			// Only the status change waits for approval; the rest of the edit applies now.
+			c.Status = current.Status
+			if err := s.service.UpdateCustomer(ctx, c, p.userID); err != nil {
+				return nil, rpcError(err)
+			}
+			change, err := s.submit(ctx, domain.OpBlacklistCustomer, c.ID,
+				domain.StatusChange{From: current.Status, To: domain.StatusBlacklist}, req.Reason)
+			if err != nil {
+				return nil, err
+			}
+			return &cicv1.UpdateCustomerResponse{Change: change}, nil
+		}
+	}
+
+	if err := s.service.UpdateCustomer(ctx, c, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return &cicv1.UpdateCustomerResponse{Customer: toCustomer(c, false), Change: &cicv1.ChangeResult{}}, nil
+}
+
+func (s *customerServer) DeleteCustomer(ctx context.Context, req *cicv1.DeleteCustomerRequest) (*cicv1.ChangeResult, error) {
+	id, err := parseID("customer id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if s.approvals != nil {
+		return s.submit(ctx, domain.OpDeleteCustomer, id, nil, req.Reason)
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteCustomer(ctx, id, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return &cicv1.ChangeResult{}, nil
+}
+
+func (s *customerServer) RestoreCustomer(ctx context.Context, req *cicv1.RestoreCustomerRequest) (*cicv1.ChangeResult, error) {
+	id, err := parseID("customer id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if s.approvals != nil {
+		var payload interface{}
+		if req.Justification != "" {
+			payload = domain.RestoreOverride{Justification: req.Justification}
+		}
+		return s.submit(ctx, domain.OpRestoreCustomer, id, payload, req.Reason)
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Justification != "" {
+		err = s.service.OverrideRestoreCustomer(ctx, id, p.userID, req.Justification)
+	} else {
+		err = s.service.RestoreCustomer(ctx, id, p.userID)
+	}
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return &cicv1.ChangeResult{}, nil
+}
+
+func (s *customerServer) AnonymizeCustomer(ctx context.Context, req *cicv1.AnonymizeCustomerRequest) (*cicv1.ChangeResult, error) {
+	id, err := parseID("customer id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if s.approvals != nil {
+		return s.submit(ctx, domain.OpAnonymizeCustomer, id, nil, req.Reason)
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.AnonymizeCustomer(ctx, id, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return &cicv1.ChangeResult{}, nil
+}
+
+// ListCustomers pages through QueryCustomers by cursor, or by offset for fuzzy searches, which
+// are sorted by score.
+func (s *customerServer) ListCustomers(req *cicv1.ListCustomersRequest, stream cicv1.CustomerService_ListCustomersServer) error {
+	ctx := stream.Context()
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = domain.MaxCustomerPageSize
+	}
+	q := domain.CustomerQuery{Filter: customerFilter(req), Limit: pageSize}
+	if err := q.Normalize(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	for {
+		page, err := s.service.QueryCustomers(ctx, q)
+		if err != nil {
+			return rpcError(err)
+		}
+		if err := s.send(ctx, page.Customers, stream.Send); err != nil {
+			return err
+		}
+		switch {
+		case page.NextCursor != "":
+			if q.Cursor, err = domain.DecodePageCursor(page.NextCursor); err != nil {
+				return rpcError(err)
+			}
+		case !q.Pageable() && len(page.Customers) == q.Limit:
+			q.Offset += q.Limit
+		default:
+			return nil
+		}
+	}
+}
+
+func (s *customerServer) ListDeletedCustomers(req *cicv1.ListDeletedCustomersRequest, stream cicv1.CustomerService_ListDeletedCustomersServer) error {
+	ctx := stream.Context()
+	p := domain.PageRequest{Limit: int(req.PageSize)}
+	if p.Limit <= 0 || p.Limit > domain.MaxCustomerPageSize {
+		p.Limit = domain.MaxCustomerPageSize
+	}
+	for {
+		page, err := s.service.ListDeletedCustomers(ctx, p)
+		if err != nil {
+			return rpcError(err)
+		}
+		if err := s.send(ctx, page.Items, stream.Send); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		if p.Cursor, err = domain.DecodePageCursor(page.NextCursor); err != nil {
+			return rpcError(err)
+		}
+	}
+}
+
+func (s *customerServer) SearchCustomers(req *cicv1.SearchCustomersRequest, stream cicv1.CustomerService_SearchCustomersServer) error {
+	customers, err := s.service.SearchCustomers(stream.Context(), req.Query)
+	if err != nil {
+		return rpcError(err)
+	}
+	return s.send(stream.Context(), customers, stream.Send)
+}
+
+// --- Sub-Resources ---
+
+// subResourceCustomer parses the customer a sub-resource list belongs to and checks the caller
+// may read it.
+func (s *customerServer) subResourceCustomer(ctx context.Context, raw string) (uuid.UUID, error) {
+	id, err := parseID("customer id", raw)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, s.checkAccess(ctx, id)
+}
+
+// remove deletes a sub-resource of a customer; like REST, a failure reads as not found.
+func remove(ctx context.Context, req *cicv1.RemoveSubResourceRequest, fn func(ctx context.Context, customerID, id, userID uuid.UUID) error) (*emptypb.Empty, error) {
+	customerID, err := parseID("customer id", req.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(ctx, customerID, id, p.userID); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *customerServer) AddAddress(ctx context.Context, req *cicv1.AddAddressRequest) (*cicv1.Address, error) {
+	a, err := fromAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.AddAddress(ctx, a, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return toAddress(a), nil
+}
+
+func (s *customerServer) ListAddresses(ctx context.Context, req *cicv1.ListSubResourcesRequest) (*cicv1.ListAddressesResponse, error) {
+	id, err := s.subResourceCustomer(ctx, req.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.service.GetAddresses(ctx, id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	out := &cicv1.ListAddressesResponse{}
+	for _, a := range items {
+		out.Addresses = append(out.Addresses, toAddress(a))
+	}
+	return out, nil
+}
+
+func (s *customerServer) RemoveAddress(ctx context.Context, req *cicv1.RemoveSubResourceRequest) (*emptypb.Empty, error) {
+	return remove(ctx, req, s.service.RemoveAddress)
+}
+
+func (s *customerServer) AddIdentity(ctx context.Context, req *cicv1.AddIdentityRequest) (*cicv1.Identity, error) {
+	i, err := fromIdentity(req.Identity)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.AddIdentity(ctx, i, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return toIdentity(i), nil
+}
+
+func (s *customerServer) ListIdentities(ctx context.Context, req *cicv1.ListSubResourcesRequest) (*cicv1.ListIdentitiesResponse, error) {
+	id, err := s.subResourceCustomer(ctx, req.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.service.GetIdentities(ctx, id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	out := &cicv1.ListIdentitiesResponse{}
+	for _, i := range items {
+		out.Identities = append(out.Identities, toIdentity(i))
+	}
+	return out, nil
+}
+
+func (s *customerServer) RemoveIdentity(ctx context.Context, req *cicv1.RemoveSubResourceRequest) (*emptypb.Empty, error) {
+	return remove(ctx, req, s.service.RemoveIdentity)
+}
+
+func (s *customerServer) AddRelationship(ctx context.Context, req *cicv1.AddRelationshipRequest) (*cicv1.Relationship, error) {
+	rel, err := fromRelationship(req.Relationship)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.AddRelationship(ctx, rel, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return toRelationship(rel), nil
+}
+
+func (s *customerServer) ListRelationships(ctx context.Context, req *cicv1.ListSubResourcesRequest) (*cicv1.ListRelationshipsResponse, error) {
+	id, err := s.subResourceCustomer(ctx, req.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.service.GetRelationships(ctx, id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	out := &cicv1.ListRelationshipsResponse{}
+	for _, r := range items {
+		out.Relationships = append(out.Relationships, toRelationship(r))
+	}
+	return out, nil
+}
+
+func (s *customerServer) RemoveRelationship(ctx context.Context, req *cicv1.RemoveSubResourceRequest) (*emptypb.Empty, error) {
+	return remove(ctx, req, s.service.RemoveRelationship)
+}
+
+func (s *customerServer) ManageConsent(ctx context.Context, req *cicv1.ManageConsentRequest) (*cicv1.Consent, error) {
+	c, err := fromConsent(req.Consent)
+	if err != nil {
+		return nil, err
+	}
+	p, err := callerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.ManageConsent(ctx, c, p.userID); err != nil {
+		return nil, rpcError(err)
+	}
+	return toConsent(c), nil
+}
+
+func (s *customerServer) ListConsents(ctx context.Context, req *cicv1.ListSubResourcesRequest) (*cicv1.ListConsentsResponse, error) {
+	id, err := s.subResourceCustomer(ctx, req.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.service.GetConsents(ctx, id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	out := &cicv1.ListConsentsResponse{}
+	for _, c := range items {
+		out.Consents = append(out.Consents, toConsent(c))
+	}
+	return out, nil
+}
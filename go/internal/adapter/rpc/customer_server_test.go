@@ -0,0 +1,294 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/adapter/rpc/cicv1"
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/core/service"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeCustomers pages a fixed list of customers two at a time.
+type fakeCustomers struct {
+	ports.CustomerService
+	customers []*domain.Customer
+	created   int
+}
+
+func (f *fakeCustomers) CreateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
+	f.created++
+	c.ID = uuid.New()
+	return nil
+}
+
+func (f *fakeCustomers) GetCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	for _, c := range f.customers {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+}
+
+func (f *fakeCustomers) UpdateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
+	for i, existing := range f.customers {
+		if existing.ID == c.ID {
+			copied := *c
+			f.customers[i] = &copied
+			return nil
+		}
+	}
+	return fmt.Errorf("customer %w", domain.ErrNotFound)
+}
+
+func (f *fakeCustomers) ChangeCustomerStatus(ctx context.Context, id uuid.UUID, change domain.StatusChange, userID uuid.UUID) error {
+	c, err := f.GetCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.Status != change.From {
+		return fmt.Errorf("%w: status is %s", domain.ErrConflict, c.Status)
+	}
+	c.Status = change.To
+	return nil
+}
+
+func (f *fakeCustomers) CheckChange(ctx context.Context, op domain.ChangeOperation, id, userID uuid.UUID, justification string) error {
+	return nil
+}
+
+func (f *fakeCustomers) QueryCustomers(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	start := 0
+	if q.Cursor != nil {
+		for i, c := range f.customers {
+			if c.ID == q.Cursor.ID {
+				start = i + 1
+			}
+		}
+	}
+	end := start + q.Limit
+	page := &domain.CustomerPage{}
+	if end < len(f.customers) {
+		last := f.customers[end-1]
+		page.NextCursor = domain.PageCursor{ID: last.ID, At: last.CreatedAt}.Encode()
+	} else {
+		end = len(f.customers)
+	}
+	page.Customers = f.customers[start:end]
+	return page, nil
+}
+
+type fakeAccess struct {
+	ports.AccessGrantService
+	denied map[uuid.UUID]bool
+}
+
+func (f *fakeAccess) CheckAccess(ctx context.Context, customerID, userID uuid.UUID, role string) error {
+	if f.denied[customerID] {
+		return domain.ErrAccessGrantRequired
+	}
+	return nil
+}
+
+func (f *fakeAccess) CanRead(ctx context.Context, c *domain.Customer, userID uuid.UUID, role string) (bool, error) {
+	return !f.denied[c.ID], nil
+}
+
+const testAPIKey = "cic_test_key"
+
+func dialTestServer(t *testing.T, customers ports.CustomerService, access ports.AccessGrantService, approvals ports.ChangeRequestService) cicv1.CustomerServiceClient {
+	t.Helper()
+	apiKeys := func(ctx context.Context, key string) (string, error) {
+		if key != testAPIKey {
+			return "", errors.New("unknown key")
+		}
+		return uuid.NewString(), nil
+	}
+	srv := NewServer(apiKeys)
+	cicv1.RegisterCustomerServiceServer(srv, NewCustomerServer(customers, access, approvals))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return cicv1.NewCustomerServiceClient(conn)
+}
+
+func withJWT(t *testing.T, role string) context.Context {
+	t.Helper()
+	token, err := auth.GenerateJWT(uuid.New(), "somchai", "somchai@example.com", role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestCustomerServer_Auth(t *testing.T) {
+	c := &domain.Customer{ID: uuid.New(), FirstName: "Somchai"}
+	customers := &fakeCustomers{customers: []*domain.Customer{c}}
+	client := dialTestServer(t, customers, nil, nil)
+	get := &cicv1.GetCustomerRequest{Id: c.ID.String()}
+	create := &cicv1.CreateCustomerRequest{Customer: &cicv1.Customer{Type: "PERSONAL", FirstName: "Somying"}}
+
+	if _, err := client.GetCustomer(context.Background(), get); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nonsense")
+	if _, err := client.GetCustomer(bad, get); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with a bad token, got %v", err)
+	}
+
+	got, err := client.GetCustomer(withJWT(t, middleware.RoleViewer), get)
+	if err != nil || got.FirstName != "Somchai" {
+		t.Fatalf("Expected a viewer to read the customer, got %v %v", got, err)
+	}
+	if _, err := client.CreateCustomer(withJWT(t, middleware.RoleViewer), create); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a viewer creating, got %v", err)
+	}
+	if _, err := client.DeleteCustomer(withJWT(t, middleware.RoleOperator), &cicv1.DeleteCustomerRequest{Id: c.ID.String()}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for an operator deleting, got %v", err)
+	}
+	if _, err := client.CreateCustomer(withJWT(t, middleware.RoleOperator), create); err != nil || customers.created != 1 {
+		t.Errorf("Expected an operator to create, got %v", err)
+	}
+
+	keyed := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", testAPIKey)
+	if _, err := client.GetCustomer(keyed, get); err != nil {
+		t.Errorf("Expected an API key to read, got %v", err)
+	}
+	if _, err := client.CreateCustomer(keyed, create); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for an API key writing, got %v", err)
+	}
+	wrongKey := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "other")
+	if _, err := client.GetCustomer(wrongKey, get); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with an unknown API key, got %v", err)
+	}
+
+	if _, err := client.GetCustomer(withJWT(t, middleware.RoleViewer), &cicv1.GetCustomerRequest{Id: uuid.NewString()}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing customer, got %v", err)
+	}
+}
+
+func TestCustomerServer_StreamsAllPagesWithholdingProtected(t *testing.T) {
+	var all []*domain.Customer
+	for i := 0; i < 5; i++ {
+		all = append(all, &domain.Customer{ID: uuid.New(), FirstName: "Somchai", MembershipTier: "GOLD"})
+	}
+	protected := all[3]
+	protected.IsHighValue = true
+	access := &fakeAccess{denied: map[uuid.UUID]bool{protected.ID: true}}
+	client := dialTestServer(t, &fakeCustomers{customers: all}, access, nil)
+
+	stream, err := client.ListCustomers(withJWT(t, middleware.RoleViewer), &cicv1.ListCustomersRequest{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*cicv1.Customer
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, c)
+	}
+	if len(got) != len(all) {
+		t.Fatalf("Expected all %d customers across pages, got %d", len(all), len(got))
+	}
+	if p := got[3]; !p.Withheld || p.FirstName != "" || p.MembershipTier != "GOLD" || p.Clv != "" {
+		t.Errorf("Expected the protected customer withheld, got %v", p)
+	}
+	if got[0].Withheld || got[0].FirstName != "Somchai" {
+		t.Errorf("Expected an open customer in full, got %v", got[0])
+	}
+
+	_, err = client.GetCustomer(withJWT(t, middleware.RoleViewer), &cicv1.GetCustomerRequest{Id: protected.ID.String()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied reading a protected customer, got %v", err)
+	}
+}
+
+// memChangeRequests keeps change requests in memory.
+type memChangeRequests struct {
+	requests map[uuid.UUID]*domain.ChangeRequest
+}
+
+func (m *memChangeRequests) Create(ctx context.Context, cr *domain.ChangeRequest) error {
+	cr.ID = uuid.New()
+	copied := *cr
+	m.requests[cr.ID] = &copied
+	return nil
+}
+func (m *memChangeRequests) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, ok := m.requests[id]
+	if !ok {
+		return nil, errors.New("change request not found")
+	}
+	copied := *cr
+	return &copied, nil
+}
+func (m *memChangeRequests) Update(ctx context.Context, cr *domain.ChangeRequest, from domain.ChangeRequestStatus) error {
+	copied := *cr
+	m.requests[cr.ID] = &copied
+	return nil
+}
+func (m *memChangeRequests) List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error) {
+	return nil, nil
+}
+func (m *memChangeRequests) ExpirePending(ctx context.Context) (int64, error) { return 0, nil }
+
+type nopAudit struct{}
+
+func (nopAudit) Log(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ipAddress string) {
+}
+
+func TestCustomerServer_BlacklistAwaitsApproval(t *testing.T) {
+	c := &domain.Customer{ID: uuid.New(), FirstName: "Somchai", Status: domain.StatusActive}
+	customers := &fakeCustomers{customers: []*domain.Customer{c}}
+	approvals := service.NewChangeRequestService(&memChangeRequests{requests: map[uuid.UUID]*domain.ChangeRequest{}}, customers, nopAudit{}, time.Hour)
+	client := dialTestServer(t, customers, nil, approvals)
+
+	res, err := client.UpdateCustomer(withJWT(t, middleware.RoleAdmin), &cicv1.UpdateCustomerRequest{
+		Customer: &cicv1.Customer{Id: c.ID.String(), Type: "PERSONAL", FirstName: "Somchai J.", Status: string(domain.StatusBlacklist)},
+		Reason:   "fraud",
+	})
+	if err != nil || !res.Change.GetPending() {
+		t.Fatalf("Expected a pending change request, got %v %v", res, err)
+	}
+	if got := customers.customers[0]; got.Status != domain.StatusActive || got.FirstName != "Somchai J." {
+		t.Errorf("Expected the other edits applied and the status held, got %+v", got)
+	}
+
+	cr, err := approvals.Approve(context.Background(), uuid.MustParse(res.Change.ChangeRequestId), uuid.New(), "confirmed")
+	if err != nil || cr.Status != domain.ChangeApplied {
+		t.Fatalf("Expected the approval to apply, got %+v %v", cr, err)
+	}
+	if got := customers.customers[0]; got.Status != domain.StatusBlacklist || got.FirstName != "Somchai J." {
+		t.Errorf("Expected the customer blacklisted with the edits kept, got %+v", got)
+	}
+}
@@ -1,28 +1,43 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/amnuaym/cic/go/internal/adapter/cas"
 	"github.com/amnuaym/cic/go/internal/adapter/handler"
+	"github.com/amnuaym/cic/go/internal/adapter/notify"
 	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/adapter/rpc"
+	"github.com/amnuaym/cic/go/internal/adapter/rpc/cicv1"
 	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
 	"github.com/amnuaym/cic/go/internal/core/service"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/amnuaym/cic/go/internal/database"
 	"github.com/amnuaym/cic/go/internal/middleware"
 	"github.com/amnuaym/cic/go/internal/models"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 type Handler struct {
 	DB *sql.DB
 }
 
-// SetupRoutes configures all API routes
-func SetupRoutes(router *mux.Router, db *sql.DB) {
+// SetupRoutes configures all API routes, and registers the gRPC services on grpcServer when it
+// is not nil.
+func SetupRoutes(router *mux.Router, grpcServer *grpc.Server, db *sql.DB) {
 	h := &Handler{DB: db}
 
 	// Public routes
@@ -49,8 +64,131 @@ func SetupRoutes(router *mux.Router, db *sql.DB) {
 	relationshipRepo := repository.NewRelationshipRepository(db)
 	consentRepo := repository.NewConsentRepository(db)
 
-	customerService := service.NewCustomerService(customerRepo, addressRepo, identityRepo, relationshipRepo, consentRepo, userRepo, auditService)
-	customerHandler := handler.NewCustomerHandler(customerService)
+	orgRepo := repository.NewOrgRepository(db)
+	delegationRepo := repository.NewDelegationRepository(db)
+	hierarchyService := service.NewHierarchyService(userRepo, orgRepo, delegationRepo, auditService, intFromEnv("ORG_SUPERVISOR_LEVELS", 3))
+	orgHandler := handler.NewOrgHandler(hierarchyService)
+
+	tierEngine, err := tiering.Load(os.Getenv("TIER_RULES_PATH"))
+	if err != nil {
+		log.Fatalf("Failed to load tier rules: %v", err)
+	}
+	// Jobs run on every instance; the lock lets one of them do each day's work.
+	jobLock := repository.NewJobLock(db)
+	tierService := service.NewTierService(repository.NewTierRepository(db), tierEngine, auditService, service.WithRecalcLock(jobLock))
+	tierHandler := handler.NewTierHandler(tierService)
+	// RecalculateAll takes the lock itself so the admin endpoint is covered too.
+	runDaily(context.Background(), "tier recalculation", envOr("TIER_RECALC_AT", "02:00"), jobLocation(), nil,
+		func(ctx context.Context) error {
+			_, err := tierService.RecalculateAll(ctx)
+			return err
+		})
+
+	pointsService := service.NewPointsService(repository.NewPointsRepository(db), customerRepo, tierService, auditService, intFromEnv("POINTS_EXPIRY_MONTHS", 24))
+	pointsHandler := handler.NewPointsHandler(pointsService)
+	runDaily(context.Background(), "points expiry", envOr("POINTS_EXPIRY_AT", "01:00"), jobLocation(), jobLock,
+		func(ctx context.Context) error {
+			_, err := pointsService.ExpireDue(ctx)
+			return err
+		})
+
+	fxService := service.NewFXService(repository.NewFXRateRepository(db), auditService)
+	fxHandler := handler.NewFXHandler(fxService)
+
+	holdingRepo := repository.NewHoldingRepository(db)
+	holdingService := service.NewHoldingService(holdingRepo, repository.NewProductRepository(db), customerRepo, fxService, tierService, auditService)
+	holdingHandler := handler.NewHoldingHandler(holdingService)
+	// Runs before tier recalculation so tiers see holdings that started or ended today.
+	runDaily(context.Background(), "portfolio refresh", envOr("PORTFOLIO_REFRESH_AT", "01:30"), jobLocation(), jobLock,
+		func(ctx context.Context) error {
+			_, err := holdingService.RefreshDue(ctx)
+			return err
+		})
+
+	runDaily(context.Background(), "search index", envOr("SEARCH_INDEX_AT", "03:00"), jobLocation(), jobLock,
+		func(ctx context.Context) error {
+			_, err := customerRepo.IndexSearchNames(ctx, 500)
+			return err
+		})
+
+	numberFormat, err := CustomerNumberFormatFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid customer number format: %v", err)
+	}
+	customerNumberService := service.NewCustomerNumberService(customerRepo, numberFormat, auditService)
+	customerNumberHandler := handler.NewCustomerNumberHandler(customerNumberService)
+	externalRefRepo := repository.NewExternalRefRepository(db)
+	externalRefService := service.NewExternalRefService(externalRefRepo, customerRepo, auditService)
+	externalRefHandler := handler.NewExternalRefHandler(externalRefService)
+	phoneCountry := envOr("PHONE_DEFAULT_COUNTRY", "66")
+	customerService := service.NewCustomerService(customerRepo, addressRepo, identityRepo, relationshipRepo, consentRepo, hierarchyService, auditService,
+		service.WithTierRules(tierService),
+		service.WithPortfolioValues(fxService, repository.NewPortfolioValueRepository(db)),
+		service.WithHoldings(holdingRepo),
+		service.WithExternalRefs(externalRefRepo),
+		service.WithMerging(externalRefService),
+		service.WithCustomerNumbers(customerNumberService),
+		service.WithPhoneCountry(phoneCountry))
+	changeRequestRepo := repository.NewChangeRequestRepository(db)
+	changeRequestService := service.NewChangeRequestService(changeRequestRepo, customerService, auditService, durationFromEnv("CHANGE_REQUEST_TTL", 72*time.Hour))
+	changeRequestHandler := handler.NewChangeRequestHandler(changeRequestService, auditRepo)
+
+	customer360Handler := handler.NewCustomer360Handler(service.NewCustomer360Service(customerService, auditRepo))
+	accessGrantRepo := repository.NewAccessGrantRepository(db)
+	accessGrantService := service.NewAccessGrantService(accessGrantRepo, customerRepo, hierarchyService, auditService, accessGrantConfigFromEnv())
+	customerHandler := handler.NewCustomerHandler(customerService, handler.WithApprovals(changeRequestService), handler.WithAccessGrants(accessGrantService))
+	if grpcServer != nil {
+		cicv1.RegisterCustomerServiceServer(grpcServer, rpc.NewCustomerServer(customerService, accessGrantService, changeRequestService))
+	}
+	contactPointRepo := repository.NewContactPointRepository(db)
+	contactPointService := service.NewContactPointService(contactPointRepo, customerRepo, otpSenderFromEnv(), auditService, service.ContactPointConfig{
+		OTPTTL:         durationFromEnv("OTP_TTL", 10*time.Minute),
+		OTPMaxAttempts: intFromEnv("OTP_MAX_ATTEMPTS", 5),
+		OTPResendAfter: durationFromEnv("OTP_RESEND_AFTER", time.Minute),
+		DefaultCountry: phoneCountry,
+	})
+	contactPointHandler := handler.NewContactPointHandler(contactPointService, accessGrantService)
+	bankAccountHandler := handler.NewBankAccountHandler(service.NewBankAccountService(repository.NewBankAccountRepository(db), customerRepo, auditService))
+	// Quiet hours and allowed days are customer-local times (CONTACT_TZ, default Asia/Bangkok).
+	preferenceHandler := handler.NewPreferenceHandler(service.NewPreferenceService(repository.NewPreferenceRepository(db),
+		customerRepo, consentRepo, contactPointRepo, addressRepo, auditService, locationFromEnv("CONTACT_TZ"), nil))
+
+	// The CAS importer reads the legacy database at CAS_DATABASE_URL; without it import requests fail.
+	var casSource ports.CASClientSource
+	if url := os.Getenv("CAS_DATABASE_URL"); url != "" {
+		casDB, err := database.Open(url)
+		if err != nil {
+			log.Fatalf("Failed to connect to CAS database: %v", err)
+		}
+		casSource = cas.NewSource(casDB)
+	}
+	importHandler := handler.NewImportHandler(service.NewCASImportService(casSource,
+		repository.NewImportRepository(db), externalRefRepo, customerNumberService, tierService, auditService))
+
+	interactionRepo := repository.NewInteractionRepository(db)
+	interactionHandler := handler.NewInteractionHandler(service.NewInteractionService(interactionRepo, customerRepo, auditService))
+	timelineHandler := handler.NewTimelineHandler(service.NewTimelineService(
+		service.NewAuditTimelineSource(auditRepo),
+		service.NewConsentTimelineSource(consentRepo),
+		service.NewSubResourceTimelineSource(addressRepo, identityRepo, relationshipRepo),
+		service.NewInteractionTimelineSource(interactionRepo)))
+
+	segmentService := service.NewSegmentService(repository.NewSegmentRepository(db), customerService, auditService)
+	segmentHandler := handler.NewSegmentHandler(segmentService, accessGrantService)
+	// Runs after the search index so fuzzy segments see today's names.
+	runDaily(context.Background(), "segment refresh", envOr("SEGMENT_REFRESH_AT", "04:00"), jobLocation(), jobLock,
+		func(ctx context.Context) error {
+			_, err := segmentService.MaterializeDue(ctx)
+			return err
+		})
+
+	graphqlHandler := handler.NewGraphQLHandler(customerService, accessGrantService, handler.GraphQLLimits{
+		MaxDepth:      intFromEnv("GRAPHQL_MAX_DEPTH", handler.DefaultGraphQLLimits.MaxDepth),
+		MaxComplexity: intFromEnv("GRAPHQL_MAX_COMPLEXITY", handler.DefaultGraphQLLimits.MaxComplexity),
+	})
+
+	accessGrantHandler := handler.NewAccessGrantHandler(accessGrantService)
+	protectedRead := middleware.RequireCustomerAccess(accessGrantService)
 	auditLogHandler := handler.NewAuditLogHandler(auditRepo)
 	consentHandler := handler.NewConsentHandler(consentRepo)
 
@@ -61,15 +199,46 @@ func SetupRoutes(router *mux.Router, db *sql.DB) {
 	// === Read-only routes (all authenticated users: VIEWER+) ===
 	v1.HandleFunc("/customers", customerHandler.ListCustomers).Methods("GET")
 	v1.HandleFunc("/customers/search", customerHandler.SearchCustomers).Methods("GET")
-	v1.HandleFunc("/customers/{id}", customerHandler.GetCustomer).Methods("GET")
-	v1.HandleFunc("/customers/{id}/addresses", customerHandler.GetAddresses).Methods("GET")
-	v1.HandleFunc("/customers/{id}/identities", customerHandler.GetIdentities).Methods("GET")
-	v1.HandleFunc("/customers/{id}/relationships", customerHandler.GetRelationships).Methods("GET")
-	v1.HandleFunc("/customers/{id}/consents", customerHandler.GetConsents).Methods("GET")
+	v1.HandleFunc("/customers/by-contact", contactPointHandler.FindCustomers).Methods("GET")
+	v1.Handle("/customers/by-number/{number}", customerNumberHandler.ResolveCustomer(protectedRead(http.HandlerFunc(customerHandler.GetCustomer)))).Methods("GET")
+	v1.Handle("/customers/by-ref/{system}/{key}", externalRefHandler.ResolveCustomer(protectedRead(http.HandlerFunc(customerHandler.GetCustomer)))).Methods("GET")
+	v1.Handle("/customers/{id}", protectedRead(http.HandlerFunc(customerHandler.GetCustomer))).Methods("GET")
+	v1.Handle("/customers/{id}/360", protectedRead(http.HandlerFunc(customer360Handler.GetCustomer360))).Methods("GET")
+	v1.Handle("/customers/{id}/timeline", protectedRead(http.HandlerFunc(timelineHandler.GetTimeline))).Methods("GET")
+	v1.Handle("/customers/{id}/addresses", protectedRead(http.HandlerFunc(customerHandler.GetAddresses))).Methods("GET")
+	v1.Handle("/customers/{id}/identities", protectedRead(http.HandlerFunc(customerHandler.GetIdentities))).Methods("GET")
+	v1.Handle("/customers/{id}/relationships", protectedRead(http.HandlerFunc(customerHandler.GetRelationships))).Methods("GET")
+	v1.Handle("/customers/{id}/consents", protectedRead(http.HandlerFunc(customerHandler.GetConsents))).Methods("GET")
+	v1.Handle("/customers/{id}/contact-points", protectedRead(http.HandlerFunc(contactPointHandler.ListContactPoints))).Methods("GET")
+	v1.Handle("/customers/{id}/bank-accounts", protectedRead(http.HandlerFunc(bankAccountHandler.ListBankAccounts))).Methods("GET")
+	v1.Handle("/customers/{id}/holdings", protectedRead(http.HandlerFunc(holdingHandler.ListHoldings))).Methods("GET")
+	v1.Handle("/customers/{id}/communication-preferences", protectedRead(http.HandlerFunc(preferenceHandler.GetPreferences))).Methods("GET")
+	v1.Handle("/customers/{id}/communication-preferences/best-channel", protectedRead(http.HandlerFunc(preferenceHandler.BestChannel))).Methods("GET")
+	v1.Handle("/customers/{id}/interactions", protectedRead(http.HandlerFunc(interactionHandler.ListInteractions))).Methods("GET")
+	v1.Handle("/customers/{id}/interactions/{interactionId}", protectedRead(http.HandlerFunc(interactionHandler.GetInteraction))).Methods("GET")
+	v1.Handle("/customers/{id}/tier-history", protectedRead(http.HandlerFunc(tierHandler.GetTierHistory))).Methods("GET")
+	v1.Handle("/customers/{id}/points/transactions", protectedRead(http.HandlerFunc(pointsHandler.ListTransactions))).Methods("GET")
+	v1.HandleFunc("/tiers/rules", tierHandler.GetRules).Methods("GET")
+	v1.HandleFunc("/fx-rates", fxHandler.ListRates).Methods("GET")
+	v1.HandleFunc("/products", holdingHandler.ListProducts).Methods("GET")
+	v1.HandleFunc("/customers/{id}/access-requests", accessGrantHandler.RequestAccess).Methods("POST")
+	v1.HandleFunc("/access-requests/{grantId}/approve", accessGrantHandler.ApproveAccess).Methods("POST")
+	v1.HandleFunc("/access-requests/{grantId}/reject", accessGrantHandler.RejectAccess).Methods("POST")
 	v1.HandleFunc("/audit-logs", auditLogHandler.ListAuditLogs).Methods("GET")
 	v1.HandleFunc("/audit-logs/{id}", auditLogHandler.GetAuditLog).Methods("GET")
 	v1.HandleFunc("/consents", consentHandler.ListConsents).Methods("GET")
 	v1.HandleFunc("/consents/{id}", consentHandler.GetConsent).Methods("GET")
+	v1.HandleFunc("/delegations", orgHandler.ListDelegations).Methods("GET")
+	v1.HandleFunc("/org/branches", orgHandler.ListBranches).Methods("GET")
+	v1.HandleFunc("/org/teams", orgHandler.ListTeams).Methods("GET")
+	v1.HandleFunc("/segments", segmentHandler.ListSegments).Methods("GET")
+	v1.HandleFunc("/segments/{id}", segmentHandler.GetSegment).Methods("GET")
+	v1.HandleFunc("/segments/{id}/customers", segmentHandler.EvaluateSegment).Methods("GET")
+	v1.HandleFunc("/segments/{id}/export", segmentHandler.ExportSegment).Methods("GET")
+	v1.HandleFunc("/segments/{id}/runs", segmentHandler.ListRuns).Methods("GET")
+	v1.HandleFunc("/segments/{id}/runs/{runId}/changes", segmentHandler.GetChanges).Methods("GET")
+	// GraphQL is read-only, so it sits with the reads; any mutation would belong with the writes.
+	v1.HandleFunc("/graphql", graphqlHandler.Query).Methods("POST")
 
 	// === Write routes (OPERATOR+) ===
 	operatorRoutes := v1.PathPrefix("").Subrouter()
@@ -79,16 +248,58 @@ func SetupRoutes(router *mux.Router, db *sql.DB) {
 	operatorRoutes.HandleFunc("/customers/{id}/addresses", customerHandler.AddAddress).Methods("POST")
 	operatorRoutes.HandleFunc("/customers/{id}/identities", customerHandler.AddIdentity).Methods("POST")
 	operatorRoutes.HandleFunc("/customers/{id}/relationships", customerHandler.AddRelationship).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/addresses/{addressId}", customerHandler.RemoveAddress).Methods("DELETE")
+	operatorRoutes.HandleFunc("/customers/{id}/identities/{identityId}", customerHandler.RemoveIdentity).Methods("DELETE")
+	operatorRoutes.HandleFunc("/customers/{id}/relationships/{relationshipId}", customerHandler.RemoveRelationship).Methods("DELETE")
 	operatorRoutes.HandleFunc("/customers/{id}/consents", customerHandler.ManageConsent).Methods("POST")
-
-	// === Admin routes (ADMIN+): delete, restore, anonymize ===
+	operatorRoutes.HandleFunc("/customers/{id}/points/transactions", pointsHandler.PostTransaction).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/contact-points", contactPointHandler.AddContactPoint).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/contact-points/{contactId}", contactPointHandler.UpdateContactPoint).Methods("PUT", "PATCH")
+	operatorRoutes.HandleFunc("/customers/{id}/contact-points/{contactId}", contactPointHandler.RemoveContactPoint).Methods("DELETE")
+	operatorRoutes.HandleFunc("/customers/{id}/contact-points/{contactId}/verify", contactPointHandler.StartVerification).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/contact-points/{contactId}/verify/confirm", contactPointHandler.ConfirmVerification).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/communication-preferences", preferenceHandler.SavePreferences).Methods("PUT")
+	operatorRoutes.HandleFunc("/customers/{id}/bank-accounts", bankAccountHandler.AddBankAccount).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/bank-accounts/{accountId}", bankAccountHandler.UpdateBankAccount).Methods("PUT", "PATCH")
+	operatorRoutes.HandleFunc("/customers/{id}/bank-accounts/{accountId}", bankAccountHandler.RemoveBankAccount).Methods("DELETE")
+	operatorRoutes.Handle("/customers/{id}/bank-accounts/{accountId}/reveal", protectedRead(http.HandlerFunc(bankAccountHandler.RevealBankAccount))).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/holdings", holdingHandler.AddHolding).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/holdings/{holdingId}", holdingHandler.UpdateHolding).Methods("PUT", "PATCH")
+	operatorRoutes.HandleFunc("/customers/{id}/holdings/{holdingId}", holdingHandler.RemoveHolding).Methods("DELETE")
+	operatorRoutes.HandleFunc("/customers/{id}/interactions", interactionHandler.CreateInteraction).Methods("POST")
+	operatorRoutes.HandleFunc("/customers/{id}/interactions/{interactionId}", interactionHandler.UpdateInteraction).Methods("PUT", "PATCH")
+	operatorRoutes.HandleFunc("/customers/{id}/interactions/{interactionId}", interactionHandler.DeleteInteraction).Methods("DELETE")
+	operatorRoutes.HandleFunc("/segments", segmentHandler.CreateSegment).Methods("POST")
+	operatorRoutes.HandleFunc("/segments/{id}", segmentHandler.UpdateSegment).Methods("PUT")
+	operatorRoutes.HandleFunc("/segments/{id}", segmentHandler.DeleteSegment).Methods("DELETE")
+	operatorRoutes.HandleFunc("/segments/{id}/materialize", segmentHandler.MaterializeSegment).Methods("POST")
+	operatorRoutes.HandleFunc("/delegations", orgHandler.CreateDelegation).Methods("POST")
+	operatorRoutes.HandleFunc("/delegations/{id}", orgHandler.RevokeDelegation).Methods("DELETE")
+
+	// === Admin routes (ADMIN+): delete, restore, anonymize, merge ===
 	adminRoutes := v1.PathPrefix("").Subrouter()
 	adminRoutes.Use(middleware.RequireRole(middleware.RoleSuperAdmin, middleware.RoleAdmin))
 	adminRoutes.HandleFunc("/customers/{id}", customerHandler.DeleteCustomer).Methods("DELETE")
 	adminRoutes.HandleFunc("/customers/{id}/restore", customerHandler.RestoreCustomer).Methods("POST")
 	adminRoutes.HandleFunc("/customers/{id}/anonymize", customerHandler.AnonymizeCustomer).Methods("POST")
+	adminRoutes.HandleFunc("/customers/{id}/merge", customerHandler.MergeCustomer).Methods("POST")
+	adminRoutes.HandleFunc("/access-requests", accessGrantHandler.ListGrants).Methods("GET")
+	adminRoutes.HandleFunc("/change-requests", changeRequestHandler.ListChangeRequests).Methods("GET")
+	adminRoutes.HandleFunc("/change-requests/{id}", changeRequestHandler.GetChangeRequest).Methods("GET")
+	adminRoutes.HandleFunc("/change-requests/{id}/audit", changeRequestHandler.GetChangeRequestAudit).Methods("GET")
+	adminRoutes.HandleFunc("/change-requests/{id}/approve", changeRequestHandler.ApproveChangeRequest).Methods("POST")
+	adminRoutes.HandleFunc("/change-requests/{id}/reject", changeRequestHandler.RejectChangeRequest).Methods("POST")
+	adminRoutes.HandleFunc("/tiers/recalculate", tierHandler.Recalculate).Methods("POST")
+	adminRoutes.HandleFunc("/fx-rates/{currency}", fxHandler.SetRate).Methods("PUT")
+	adminRoutes.HandleFunc("/products/{code}", holdingHandler.SaveProduct).Methods("PUT")
+	adminRoutes.HandleFunc("/customer-numbers/backfill", customerNumberHandler.Backfill).Methods("POST")
+	adminRoutes.HandleFunc("/imports", importHandler.ListRuns).Methods("GET")
+	adminRoutes.HandleFunc("/imports/cas", importHandler.StartCASImport).Methods("POST")
+	adminRoutes.HandleFunc("/imports/{id}", importHandler.GetRun).Methods("GET")
+	adminRoutes.HandleFunc("/imports/{id}/rejects", importHandler.GetRejects).Methods("GET")
 	adminRoutes.HandleFunc("/users", h.ListUsers).Methods("GET")
 	adminRoutes.HandleFunc("/users/{id}", h.GetUser).Methods("GET")
+	adminRoutes.HandleFunc("/users/{id}/supervisors", orgHandler.GetSupervisors).Methods("GET")
 
 	// === Super Admin routes: user management ===
 	superAdminRoutes := v1.PathPrefix("").Subrouter()
@@ -96,6 +307,9 @@ func SetupRoutes(router *mux.Router, db *sql.DB) {
 	superAdminRoutes.HandleFunc("/users", h.CreateUser).Methods("POST")
 	superAdminRoutes.HandleFunc("/users/{id}", h.UpdateUser).Methods("PUT")
 	superAdminRoutes.HandleFunc("/users/{id}", h.DeactivateUser).Methods("DELETE")
+	superAdminRoutes.HandleFunc("/users/{id}/org", orgHandler.AssignUser).Methods("PUT")
+	superAdminRoutes.HandleFunc("/org/branches", orgHandler.CreateBranch).Methods("POST")
+	superAdminRoutes.HandleFunc("/org/teams", orgHandler.CreateTeam).Methods("POST")
 
 	// API Key protected routes
 	apiKeyRouter := router.PathPrefix("/api/v1").Subrouter()
@@ -103,6 +317,81 @@ func SetupRoutes(router *mux.Router, db *sql.DB) {
 	apiKeyRouter.HandleFunc("/customers", customerHandler.ListCustomers).Methods("GET")
 }
 
+// accessGrantConfigFromEnv builds the break-the-glass settings.
+// ACCESS_GRANT_TTL is a Go duration (default 30m); ACCESS_GRANT_REQUIRE_APPROVAL=true routes
+// every request through the requester's supervisor.
+func accessGrantConfigFromEnv() service.AccessGrantConfig {
+	return service.AccessGrantConfig{
+		TTL:             durationFromEnv("ACCESS_GRANT_TTL", 30*time.Minute),
+		RequireApproval: os.Getenv("ACCESS_GRANT_REQUIRE_APPROVAL") == "true",
+		BypassRoles:     []string{middleware.RoleSuperAdmin, middleware.RoleAdmin},
+		ApproverRoles:   []string{middleware.RoleSuperAdmin, middleware.RoleAdmin},
+	}
+}
+
+// otpSenderFromEnv picks the one-time code sender. OTP_SENDER has no default: no delivering
+// sender exists yet, and the development senders write codes in plain text, so they must be
+// chosen on purpose. OTP_SENDER=disabled sends nothing and fails verification requests, which
+// is safe in production. OTP_SENDER=log writes codes to the application log and OTP_SENDER=file
+// appends them to OTP_SENDER_FILE.
+func otpSenderFromEnv() ports.OTPSender {
+	switch mode := os.Getenv("OTP_SENDER"); mode {
+	case "disabled":
+		log.Printf("OTP_SENDER=disabled: contact point verification is unavailable")
+		return notify.NewDisabledSender()
+	case "log":
+		log.Printf("OTP_SENDER=log: verification codes are written to the log; for development only")
+		return notify.NewLogSender()
+	case "file":
+		log.Printf("OTP_SENDER=file: verification codes are written to a file; for development only")
+		return notify.NewFileSender(envOr("OTP_SENDER_FILE", "otp_codes.log"))
+	case "":
+		log.Fatalf("OTP_SENDER is required; set it to disabled, or to log or file for development")
+		return nil
+	default:
+		log.Fatalf("Unknown OTP_SENDER %q", mode)
+		return nil
+	}
+}
+
+// durationFromEnv parses a Go duration (e.g. "72h") from the environment, falling back to def.
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// CustomerNumberFormatFromEnv reads the customer number layout: CUSTOMER_NUMBER_PREFIX (branch
+// or entity code, default none), CUSTOMER_NUMBER_DIGITS (default 8) and CUSTOMER_NUMBER_CHECK
+// (LUHN, MOD11 or NONE; default LUHN).
+func CustomerNumberFormatFromEnv() (domain.CustomerNumberFormat, error) {
+	f := domain.CustomerNumberFormat{
+		Prefix: os.Getenv("CUSTOMER_NUMBER_PREFIX"),
+		Digits: intFromEnv("CUSTOMER_NUMBER_DIGITS", 8),
+		Check:  domain.CheckDigitScheme(strings.ToUpper(envOr("CUSTOMER_NUMBER_CHECK", string(domain.CheckLuhn)))),
+	}
+	return f, f.Validate()
+}
+
+// envOr returns the environment value for key, or def when it is unset.
+func envOr(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+// intFromEnv parses a positive integer from the environment, falling back to def.
+func intFromEnv(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
 // HealthCheck returns the API health status
 // @Summary Check API Health
 // @Description Returns the status of the API
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -100,3 +101,25 @@ func TestRegisterInvalidJSON(t *testing.T) {
 		t.Fatalf("Expected status 400, got %d", resp.StatusCode)
 	}
 }
+
+func TestNextDailyRun(t *testing.T) {
+	loc := time.FixedZone("ICT", 7*3600)
+	now := time.Date(2026, 10, 19, 1, 30, 0, 0, loc)
+
+	next, err := nextDailyRun(now, "02:00", loc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 10, 19, 2, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, next)
+	}
+
+	next, _ = nextDailyRun(now, "01:00", loc)
+	if want := time.Date(2026, 10, 20, 1, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("Expected next day %v, got %v", want, next)
+	}
+
+	if _, err := nextDailyRun(now, "2am", loc); err == nil {
+		t.Error("Expected error for malformed time")
+	}
+}
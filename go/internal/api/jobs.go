@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+)
+
+// jobLocation is the timezone daily jobs are scheduled in (JOB_TZ, default Asia/Bangkok).
+func jobLocation() *time.Location {
+	return locationFromEnv("JOB_TZ")
+}
+
+// locationFromEnv loads the timezone named by key, defaulting to Asia/Bangkok.
+func locationFromEnv(key string) *time.Location {
+	name := os.Getenv(key)
+	if name == "" {
+		name = "Asia/Bangkok"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("%s %q: %v, using UTC", key, name, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// nextDailyRun returns the next time at or after now that matches the HH:MM clock time in loc.
+func nextDailyRun(now time.Time, at string, loc *time.Location) (time.Time, error) {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time of day %q, want HH:MM", at)
+	}
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if next.Before(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
+
+// runDaily starts job in the background every day at the HH:MM time in loc until ctx is done.
+// An empty or "off" schedule disables the job. With a lock, a run is skipped while another
+// instance holds the job; a nil lock leaves exclusion to the job itself.
+func runDaily(ctx context.Context, name, at string, loc *time.Location, lock ports.JobLock, job func(context.Context) error) {
+	if at == "" || at == "off" {
+		log.Printf("%s: disabled", name)
+		return
+	}
+	if _, err := nextDailyRun(time.Now(), at, loc); err != nil {
+		log.Printf("%s: %v, job disabled", name, err)
+		return
+	}
+
+	go func() {
+		for {
+			next, _ := nextDailyRun(time.Now(), at, loc)
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			start := time.Now()
+			if err := runLocked(ctx, name, lock, job); err != nil {
+				log.Printf("%s: %v", name, err)
+				continue
+			}
+			log.Printf("%s: finished in %s", name, time.Since(start).Round(time.Millisecond))
+		}
+	}()
+}
+
+// runLocked runs job while holding its lock, if any.
+func runLocked(ctx context.Context, name string, lock ports.JobLock, job func(context.Context) error) error {
+	if lock == nil {
+		return job(ctx)
+	}
+	unlock, err := lock.Lock(ctx, name)
+	if errors.Is(err, domain.ErrConflict) {
+		return errors.New("running on another instance, skipped")
+	}
+	if err != nil {
+		return fmt.Errorf("lock: %w", err)
+	}
+	defer unlock()
+	return job(ctx)
+}
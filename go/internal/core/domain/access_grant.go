@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessGrantStatus string
+
+const (
+	GrantPending  AccessGrantStatus = "PENDING"
+	GrantApproved AccessGrantStatus = "APPROVED"
+	GrantRejected AccessGrantStatus = "REJECTED"
+)
+
+// AccessGrant is a time-limited permission for one user to read a protected (high-value) customer.
+type AccessGrant struct {
+	ID          uuid.UUID         `json:"id"`
+	CustomerID  uuid.UUID         `json:"customer_id"`
+	RequestedBy uuid.UUID         `json:"requested_by"`
+	Reason      string            `json:"reason"`
+	Status      AccessGrantStatus `json:"status"`
+	ApprovedBy  *uuid.UUID        `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time        `json:"approved_at,omitempty"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+	UseCount    int               `json:"use_count"`
+	LastUsedAt  *time.Time        `json:"last_used_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsActive reports whether the grant is approved and not yet expired at the given time.
+func (g *AccessGrant) IsActive(now time.Time) bool {
+	return g.Status == GrantApproved && g.ExpiresAt != nil && now.Before(*g.ExpiresAt)
+}
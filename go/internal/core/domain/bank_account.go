@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThaiBanks maps Bank of Thailand three-digit bank codes to short names.
+var ThaiBanks = map[string]string{
+	"002": "BBL",   // Bangkok Bank
+	"004": "KBANK", // Kasikornbank
+	"006": "KTB",   // Krungthai Bank
+	"011": "TTB",   // TMBThanachart Bank
+	"014": "SCB",   // Siam Commercial Bank
+	"022": "CIMBT", // CIMB Thai Bank
+	"024": "UOBT",  // United Overseas Bank (Thai)
+	"025": "BAY",   // Bank of Ayudhya (Krungsri)
+	"030": "GSB",   // Government Savings Bank
+	"033": "GHB",   // Government Housing Bank
+	"034": "BAAC",  // Bank for Agriculture and Agricultural Cooperatives
+	"066": "IBANK", // Islamic Bank of Thailand
+	"067": "TISCO", // TISCO Bank
+	"069": "KKP",   // Kiatnakin Phatra Bank
+	"070": "ICBCT", // ICBC (Thai)
+	"071": "TCD",   // Thai Credit Bank
+	"073": "LHB",   // Land and Houses Bank
+}
+
+type BankAccountType string
+
+const (
+	BankAccountSavings      BankAccountType = "SAVINGS"
+	BankAccountCurrent      BankAccountType = "CURRENT"
+	BankAccountFixedDeposit BankAccountType = "FIXED_DEPOSIT"
+)
+
+// BankAccountPurpose says what the bank account is used for.
+type BankAccountPurpose string
+
+const (
+	BankAccountPayout      BankAccountPurpose = "PAYOUT"
+	BankAccountDirectDebit BankAccountPurpose = "DIRECT_DEBIT"
+)
+
+// BankAccount is a customer's account at a Thai bank. AccountNumber holds digits only and is
+// masked on every read except an explicit, audited reveal. At most one account per purpose is
+// primary for a customer.
+type BankAccount struct {
+	ID            uuid.UUID          `json:"id"`
+	CustomerID    uuid.UUID          `json:"customer_id"`
+	BankCode      string             `json:"bank_code"`
+	BankName      string             `json:"bank_name"`
+	Branch        string             `json:"branch"`
+	AccountNumber string             `json:"account_number"`
+	AccountName   string             `json:"account_name"`
+	AccountType   BankAccountType    `json:"account_type"`
+	Purpose       BankAccountPurpose `json:"purpose"`
+	IsPrimary     bool               `json:"is_primary"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Mask replaces all but the last four digits of the account number.
+func (b *BankAccount) Mask() {
+	b.AccountNumber = MaskAccountNumber(b.AccountNumber)
+}
+
+func MaskAccountNumber(n string) string {
+	if len(n) <= 4 {
+		return strings.Repeat("X", len(n))
+	}
+	return strings.Repeat("X", len(n)-4) + n[len(n)-4:]
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CASClient is a row of the legacy cas.tclient_details table with its addresses, the
+// nationality and country of residence from cas.tclient_detail_fatca, and the id numbers it
+// held before IDNumber from cas.tclient_detail_log. The *AddrType fields point at the AddrType
+// of one of Addresses.
+type CASClient struct {
+	CliNum          string
+	Name            string
+	Title           string
+	BirthDate       *time.Time
+	SexCode         string
+	IDType          string
+	IDNumber        string
+	RegAddrType     *int
+	ResAddrType     *int
+	OfficeAddrType  *int
+	BillingAddrType *int
+	Nationality     string
+	Country         string
+	Addresses       []CASAddress
+	PreviousIDs     []CASPreviousID
+}
+
+// CASPreviousID is an id number a client held before the current one. ExpiredAt is when CAS
+// replaced it.
+type CASPreviousID struct {
+	Number    string
+	ExpiredAt *time.Time
+}
+
+// CASAddress is a row of cas.tclient_addresses.
+type CASAddress struct {
+	AddrType int
+	Lines    [4]string
+	ZipCode  string
+	Invalid  bool
+}
+
+// ImportedClient is a legacy client mapped onto CIC, written in one transaction.
+type ImportedClient struct {
+	Customer   *Customer
+	Addresses  []*Address
+	Identities []*Identity
+	Ref        *ExternalRef
+}
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "RUNNING"
+	ImportCompleted ImportStatus = "COMPLETED"
+	ImportFailed    ImportStatus = "FAILED"
+)
+
+// ImportRun tracks one pass of an importer. Cursor is the last source key handled, so a failed
+// or interrupted run can be resumed from there.
+type ImportRun struct {
+	ID         uuid.UUID    `json:"id"`
+	Source     string       `json:"source"`
+	DryRun     bool         `json:"dry_run"`
+	Status     ImportStatus `json:"status"`
+	Cursor     string       `json:"cursor"`
+	Processed  int          `json:"processed"`
+	Imported   int          `json:"imported"`
+	Skipped    int          `json:"skipped"` // Already imported by an earlier run
+	Rejected   int          `json:"rejected"`
+	Error      string       `json:"error,omitempty"`
+	StartedBy  string       `json:"started_by"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
+
+// ImportReject is a source record an import run could not take, with why.
+type ImportReject struct {
+	RunID     uuid.UUID `json:"run_id"`
+	SourceKey string    `json:"source_key"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOperation string
+
+const (
+	OpDeleteCustomer    ChangeOperation = "DELETE_CUSTOMER"
+	OpRestoreCustomer   ChangeOperation = "RESTORE_CUSTOMER"
+	OpAnonymizeCustomer ChangeOperation = "ANONYMIZE_CUSTOMER"
+	OpBlacklistCustomer ChangeOperation = "BLACKLIST_CUSTOMER"
+	OpMergeCustomer     ChangeOperation = "MERGE_CUSTOMER"
+)
+
+type ChangeRequestStatus string
+
+const (
+	ChangePending  ChangeRequestStatus = "PENDING"
+	ChangeApproved ChangeRequestStatus = "APPROVED" // approved, being applied
+	ChangeApplied  ChangeRequestStatus = "APPLIED"
+	ChangeRejected ChangeRequestStatus = "REJECTED"
+	ChangeExpired  ChangeRequestStatus = "EXPIRED"
+	ChangeFailed   ChangeRequestStatus = "FAILED"
+)
+
+// ChangeRequest is a sensitive customer operation held for four-eyes approval.
+// Payload carries the serialized operation (e.g. the StatusChange of a blacklist).
+type ChangeRequest struct {
+	ID         uuid.UUID           `json:"id"`
+	Operation  ChangeOperation     `json:"operation"`
+	CustomerID uuid.UUID           `json:"customer_id"`
+	Payload    json.RawMessage     `json:"payload,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+	Status     ChangeRequestStatus `json:"status"`
+	MakerID    uuid.UUID           `json:"maker_id"`
+	CheckerID  *uuid.UUID          `json:"checker_id,omitempty"`
+	Comment    string              `json:"comment,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+	DecidedAt  *time.Time          `json:"decided_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreOverride is the payload of a restore that bypasses the deleter/supervisor check
+// for legacy records whose deleter was never recorded.
+type RestoreOverride struct {
+	Justification string `json:"justification"`
+}
+
+// CustomerMerge is the payload of a merge: the request's customer is retired into IntoID.
+type CustomerMerge struct {
+	IntoID uuid.UUID `json:"into_id"`
+}
+
+// StatusChange is the payload of a blacklist: only the status transition is held for approval,
+// so edits made to the customer while the request waits are kept. It applies only while the
+// customer is still in From.
+type StatusChange struct {
+	From CustomerStatus `json:"from"`
+	To   CustomerStatus `json:"to"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContactType string
+
+const (
+	ContactMobile   ContactType = "MOBILE"
+	ContactLandline ContactType = "LANDLINE"
+	ContactEmail    ContactType = "EMAIL"
+	ContactLine     ContactType = "LINE"
+)
+
+// IsPhone reports whether values of this type are stored in E.164.
+func (t ContactType) IsPhone() bool {
+	return t == ContactMobile || t == ContactLandline
+}
+
+// CanVerify reports whether a one-time code can be delivered to this type of contact point.
+func (t ContactType) CanVerify() bool {
+	return t == ContactMobile || t == ContactEmail
+}
+
+type VerificationStatus string
+
+const (
+	VerificationUnverified VerificationStatus = "UNVERIFIED"
+	VerificationPending    VerificationStatus = "PENDING"
+	VerificationVerified   VerificationStatus = "VERIFIED"
+)
+
+// ContactPoint is a phone number, email address or LINE ID. Phone values are normalized to E.164.
+// At most one contact point per type is primary for a customer.
+type ContactPoint struct {
+	ID         uuid.UUID          `json:"id"`
+	CustomerID uuid.UUID          `json:"customer_id"`
+	Type       ContactType        `json:"type"`
+	Value      string             `json:"value"`
+	IsPrimary  bool               `json:"is_primary"`
+	Status     VerificationStatus `json:"verification_status"`
+	VerifiedAt *time.Time         `json:"verified_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ContactVerification is the outstanding one-time code for a contact point. Only a hash of the code is kept.
+type ContactVerification struct {
+	ContactPointID uuid.UUID
+	CodeHash       string
+	ExpiresAt      time.Time
+	Attempts       int
+	CreatedAt      time.Time
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type CustomerType string
@@ -24,8 +25,9 @@ const (
 )
 
 type Customer struct {
-	ID   uuid.UUID    `json:"id"`
-	Type CustomerType `json:"type"`
+	ID             uuid.UUID    `json:"id"`
+	CustomerNumber string       `json:"customer_number,omitempty"` // Assigned on create, never changes
+	Type           CustomerType `json:"type"`
 
 	// Personal Fields
 	FirstName   string    `json:"first_name,omitempty"`
@@ -40,14 +42,25 @@ type Customer struct {
 	IndustryCode     string    `json:"industry_code,omitempty"`
 
 	// Business Profile
-	Status              CustomerStatus `json:"status"`
-	MembershipTier      string         `json:"membership_tier"`
-	PointsBalance       float64        `json:"points_balance"`
-	CLV                 float64        `json:"clv"`
-	PortfolioSize       float64        `json:"portfolio_size"`
-	LastTransactionDate *time.Time     `json:"last_transaction_date"`
-	PreferredChannel    string         `json:"preferred_channel"`
-	IsHighValue         bool           `json:"is_high_value"`
+	Status              CustomerStatus  `json:"status"`
+	MembershipTier      string          `json:"membership_tier"`
+	PointsBalance       decimal.Decimal `json:"points_balance"`
+	CLV                 decimal.Decimal `json:"clv"`
+	PortfolioSize       decimal.Decimal `json:"portfolio_size"` // from active holdings (or PortfolioValues without them)
+	PortfolioValues     []Money         `json:"portfolio_values,omitempty"`
+	LastTransactionDate *time.Time      `json:"last_transaction_date"`
+	PreferredChannel    string          `json:"preferred_channel"`
+	IsHighValue         bool            `json:"is_high_value"`
+
+	// Currency is the currency of CLV and PortfolioSize. Stored amounts are always in
+	// BaseCurrency; a CLV or PortfolioSize written in another currency is converted on save.
+	Currency string `json:"currency,omitempty"`
+
+	// ExternalRefs are the customer's keys in other systems. Omitted on update to leave them unchanged.
+	ExternalRefs []ExternalRef `json:"external_refs,omitempty"`
+
+	// SearchScore is how closely the name matched a fuzzy search, from 0 to 1. Set only on fuzzy results.
+	SearchScore float64 `json:"search_score,omitempty"`
 
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -69,8 +82,9 @@ type Address struct {
 	ZipCode      string `json:"zip_code"`
 	Country      string `json:"country"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"` // nil for records added before it was kept
 }
 
 type Identity struct {
@@ -81,8 +95,9 @@ type Identity struct {
 	IssuanceCountry string    `json:"issuance_country"`
 	ExpiryDate      time.Time `json:"expiry_date"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
 }
 
 type Relationship struct {
@@ -91,7 +106,8 @@ type Relationship struct {
 	ToCustomerID   uuid.UUID `json:"to_customer_id"`
 	Role           string    `json:"role"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
 }
 
 type Consent struct {
@@ -102,5 +118,6 @@ type Consent struct {
 	IsGranted  bool      `json:"is_granted"`
 	Timestamp  time.Time `json:"timestamp"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
 }
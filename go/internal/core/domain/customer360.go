@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sections of the customer 360 view that can be requested with include=.
+const (
+	Section360Addresses     = "addresses"
+	Section360Identities    = "identities"
+	Section360Relationships = "relationships"
+	Section360Consents      = "consents"
+	Section360Audit         = "audit"
+)
+
+var Sections360 = []string{
+	Section360Addresses, Section360Identities, Section360Relationships, Section360Consents, Section360Audit,
+}
+
+// ParseSections360 validates an include= list. An empty list selects every section.
+func ParseSections360(include []string) (map[string]bool, error) {
+	if len(include) == 0 {
+		include = Sections360
+	}
+	sections := map[string]bool{}
+	for _, s := range include {
+		known := false
+		for _, k := range Sections360 {
+			known = known || s == k
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown section %q", s)
+		}
+		sections[s] = true
+	}
+	return sections, nil
+}
+
+// Customer360 is a customer with its related records in one document. Sections that were not
+// requested are omitted; sections that failed to load are reported in Errors instead.
+type Customer360 struct {
+	Customer      *Customer           `json:"customer"`
+	Addresses     []*Address          `json:"addresses,omitempty"`
+	Identities    []*Identity         `json:"identities,omitempty"`
+	Relationships []*RelationshipView `json:"relationships,omitempty"`
+	Consents      []*Consent          `json:"consents,omitempty"`
+	Audit         []*AuditEntry       `json:"audit,omitempty"`
+	Errors        map[string]string   `json:"errors,omitempty"`
+}
+
+// RelationshipView is a relationship edge with a summary of the customer on the other end.
+type RelationshipView struct {
+	*Relationship
+	Related *CustomerSummary `json:"related,omitempty"`
+}
+
+// CustomerSummary is the minimal view of a related customer. Names of protected (high-value)
+// customers are withheld; reading them requires the usual access grant.
+type CustomerSummary struct {
+	ID             uuid.UUID      `json:"id"`
+	Type           CustomerType   `json:"type"`
+	DisplayName    string         `json:"display_name,omitempty"`
+	Status         CustomerStatus `json:"status"`
+	MembershipTier string         `json:"membership_tier,omitempty"`
+	Protected      bool           `json:"protected,omitempty"`
+}
+
+func NewCustomerSummary(c *Customer) *CustomerSummary {
+	s := &CustomerSummary{
+		ID:             c.ID,
+		Type:           c.Type,
+		Status:         c.Status,
+		MembershipTier: c.MembershipTier,
+		Protected:      c.IsHighValue,
+	}
+	if !c.IsHighValue {
+		s.DisplayName = c.DisplayName()
+	}
+	return s
+}
+
+// Withheld is what a list or search may show of a protected customer to a user who cannot read
+// it: enough to recognise the record and request an access grant, but no personal data.
+func (c *Customer) Withheld() *Customer {
+	return &Customer{
+		ID:             c.ID,
+		Type:           c.Type,
+		Status:         c.Status,
+		MembershipTier: c.MembershipTier,
+		IsHighValue:    c.IsHighValue,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+// DisplayName is the company name for juristic customers and the full name otherwise.
+func (c *Customer) DisplayName() string {
+	if c.Type == TypeJuristic {
+		return c.CompanyName
+	}
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
+}
+
+// AuditEntry is an audit log line as shown in the customer 360 view.
+type AuditEntry struct {
+	ID          uuid.UUID `json:"id"`
+	Action      string    `json:"action"`
+	PerformedBy string    `json:"performed_by"`
+	Changes     string    `json:"changes,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CheckDigitScheme is how the last digit of a customer number is computed.
+type CheckDigitScheme string
+
+const (
+	CheckLuhn  CheckDigitScheme = "LUHN"
+	CheckMod11 CheckDigitScheme = "MOD11" // Thai national ID style: weights 2, 3, 4... from the right, (11 - sum mod 11) mod 10
+	CheckNone  CheckDigitScheme = "NONE"
+)
+
+var numberPrefix = regexp.MustCompile(`^[0-9]{0,6}$`)
+
+// CustomerNumberFormat lays out customer numbers as Prefix, then the sequence zero-padded to
+// Digits, then a check digit, e.g. 001 00001234 7.
+type CustomerNumberFormat struct {
+	Prefix string           // Branch or entity code, digits only
+	Digits int              // Width of the sequence part
+	Check  CheckDigitScheme // LUHN, MOD11 or NONE
+}
+
+func (f CustomerNumberFormat) Validate() error {
+	if !numberPrefix.MatchString(f.Prefix) {
+		return fmt.Errorf("customer number prefix must be up to 6 digits, got %q", f.Prefix)
+	}
+	if f.Digits < 4 || f.Digits > 12 {
+		return fmt.Errorf("customer number digits must be 4 to 12, got %d", f.Digits)
+	}
+	switch f.Check {
+	case CheckLuhn, CheckMod11, CheckNone:
+		return nil
+	}
+	return fmt.Errorf("unknown check digit scheme %q, want LUHN, MOD11 or NONE", f.Check)
+}
+
+// Format renders sequence value seq as a customer number.
+func (f CustomerNumberFormat) Format(seq int64) (string, error) {
+	body := fmt.Sprintf("%s%0*d", f.Prefix, f.Digits, seq)
+	if seq < 1 || len(body) != len(f.Prefix)+f.Digits {
+		return "", fmt.Errorf("sequence %d does not fit %d digits", seq, f.Digits)
+	}
+	return body + f.checkDigit(body), nil
+}
+
+// Parse normalizes number (dropping spaces and dashes staff may read out) and checks its
+// length, prefix and check digit.
+func (f CustomerNumberFormat) Parse(number string) (string, error) {
+	n := strings.NewReplacer(" ", "", "-", "").Replace(number)
+	want := len(f.Prefix) + f.Digits
+	if f.Check != CheckNone {
+		want++
+	}
+	if len(n) != want || strings.Trim(n, "0123456789") != "" || !strings.HasPrefix(n, f.Prefix) {
+		return "", fmt.Errorf("invalid customer number %q", number)
+	}
+	if f.Check != CheckNone && f.checkDigit(n[:want-1]) != n[want-1:] {
+		return "", errors.New("customer number check digit does not match")
+	}
+	return n, nil
+}
+
+func (f CustomerNumberFormat) checkDigit(body string) string {
+	switch f.Check {
+	case CheckLuhn:
+		sum := 0
+		for i := 0; i < len(body); i++ {
+			d := int(body[len(body)-1-i] - '0')
+			if i%2 == 0 { // Doubling starts at the digit next to the check digit
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		return fmt.Sprint((10 - sum%10) % 10)
+	case CheckMod11:
+		sum := 0
+		for i := 0; i < len(body); i++ {
+			sum += int(body[len(body)-1-i]-'0') * (i + 2)
+		}
+		return fmt.Sprint((11 - sum%11) % 10)
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCustomerPageSize = 50
+	MaxCustomerPageSize     = 200
+)
+
+// CustomerSortFields are the fields customer lists can be sorted by.
+var CustomerSortFields = []string{
+	"created_at", "updated_at", "last_transaction_date", "first_name", "last_name", "company_name",
+	"customer_number", "status", "membership_tier", "clv", "portfolio_size",
+}
+
+// SortByScore orders fuzzy results by how well the name matched. It is only valid with
+// CustomerFilter.Fuzzy, where it is also the default.
+const SortByScore = "score"
+
+// CustomerFilter selects customers. Empty fields do not filter; list fields match any value
+// given. Ranges are inclusive of From and exclusive of To.
+type CustomerFilter struct {
+	Text          string           `json:"text,omitempty"`  // Name or customer number
+	Fuzzy         bool             `json:"fuzzy,omitempty"` // Match Text against normalized names, tolerating spelling and script
+	Types         []CustomerType   `json:"types,omitempty"`
+	Statuses      []CustomerStatus `json:"statuses,omitempty"`
+	Tiers         []string         `json:"tiers,omitempty"`
+	Nationalities []string         `json:"nationalities,omitempty"`
+	HighValue     *bool            `json:"high_value,omitempty"`
+
+	// Attribute matches against the customer's identities, contact points and addresses.
+	// Address fields must all match the same address.
+	IdentityNumber string `json:"identity_number,omitempty"` // Exact, ignoring spaces and dashes
+	Phone          string `json:"phone,omitempty"`           // Mobile or landline, any common format
+	Email          string `json:"email,omitempty"`
+	ZipCode        string `json:"zip_code,omitempty"`
+	Province       string `json:"province,omitempty"` // Province or city, case-insensitive
+	District       string `json:"district,omitempty"`
+
+	CreatedFrom         *time.Time `json:"created_from,omitempty"`
+	CreatedTo           *time.Time `json:"created_to,omitempty"`
+	UpdatedFrom         *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo           *time.Time `json:"updated_to,omitempty"`
+	LastTransactionFrom *time.Time `json:"last_transaction_from,omitempty"`
+	LastTransactionTo   *time.Time `json:"last_transaction_to,omitempty"`
+}
+
+// IsEmpty reports whether the filter would match every customer.
+func (f CustomerFilter) IsEmpty() bool {
+	return f.Text == "" && len(f.Types) == 0 && len(f.Statuses) == 0 && len(f.Tiers) == 0 &&
+		len(f.Nationalities) == 0 && f.HighValue == nil &&
+		f.IdentityNumber == "" && f.Phone == "" && f.Email == "" &&
+		f.ZipCode == "" && f.Province == "" && f.District == "" &&
+		f.CreatedFrom == nil && f.CreatedTo == nil && f.UpdatedFrom == nil && f.UpdatedTo == nil &&
+		f.LastTransactionFrom == nil && f.LastTransactionTo == nil
+}
+
+// Normalize upper-cases enum values and rejects unknown ones.
+func (f *CustomerFilter) Normalize() error {
+	f.Text = strings.TrimSpace(f.Text)
+	if f.Text == "" {
+		f.Fuzzy = false
+	}
+	f.IdentityNumber = strings.ToUpper(identityStripper.Replace(f.IdentityNumber))
+	f.Phone = strings.TrimSpace(f.Phone)
+	f.Email = strings.ToLower(strings.TrimSpace(f.Email))
+	f.ZipCode = strings.ReplaceAll(f.ZipCode, " ", "")
+	f.Province = strings.TrimSpace(f.Province)
+	f.District = strings.TrimSpace(f.District)
+	for i, t := range f.Types {
+		f.Types[i] = CustomerType(strings.ToUpper(string(t)))
+		if f.Types[i] != TypePersonal && f.Types[i] != TypeJuristic {
+			return fmt.Errorf("unknown customer type %q", t)
+		}
+	}
+	for i, s := range f.Statuses {
+		f.Statuses[i] = CustomerStatus(strings.ToUpper(string(s)))
+		switch f.Statuses[i] {
+		case StatusActive, StatusInactive, StatusSuspended, StatusDeceased, StatusBlacklist:
+		default:
+			return fmt.Errorf("unknown customer status %q", s)
+		}
+	}
+	for i := range f.Tiers {
+		f.Tiers[i] = strings.ToUpper(strings.TrimSpace(f.Tiers[i]))
+	}
+	return nil
+}
+
+// SortKey orders a customer list by Field, descending when Desc.
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// ParseSort reads a comma-separated sort such as "-created_at,last_name", where a leading
+// "-" means descending.
+func ParseSort(s string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !isCustomerSortField(k.Field) {
+			return nil, fmt.Errorf("cannot sort by %q, want one of %s", k.Field, strings.Join(CustomerSortFields, ", "))
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func isCustomerSortField(field string) bool {
+	if field == SortByScore {
+		return true
+	}
+	for _, f := range CustomerSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomerQuery is a filtered, sorted page of customers. Rows with equal sort keys are
+// ordered by ID so pages are stable. Cursor paging is available with the default
+// newest-first sort only; Offset is ignored when Cursor is set.
+type CustomerQuery struct {
+	Filter CustomerFilter
+	Sort   []SortKey // Newest first when empty
+	Limit  int
+	Offset int
+	Cursor *PageCursor
+	Fields FieldSet // Customer fields to load; nil loads all
+}
+
+var defaultCustomerSort = SortKey{Field: "created_at", Desc: true}
+
+// Pageable reports whether the query is in the newest-first order that page cursors follow.
+func (q CustomerQuery) Pageable() bool {
+	return len(q.Sort) == 1 && q.Sort[0] == defaultCustomerSort
+}
+
+// Normalize applies defaults and enforces the page size limit.
+func (q *CustomerQuery) Normalize() error {
+	if err := q.Filter.Normalize(); err != nil {
+		return err
+	}
+	for _, k := range q.Sort {
+		if !isCustomerSortField(k.Field) {
+			return fmt.Errorf("cannot sort by %q", k.Field)
+		}
+		if k.Field == SortByScore && !q.Filter.Fuzzy {
+			return fmt.Errorf("sorting by %s needs a fuzzy search", SortByScore)
+		}
+	}
+	if len(q.Sort) == 0 && q.Filter.Fuzzy {
+		q.Sort = []SortKey{{Field: SortByScore, Desc: true}}
+	}
+	if len(q.Sort) == 0 {
+		q.Sort = []SortKey{defaultCustomerSort}
+	}
+	if q.Cursor != nil {
+		if !q.Pageable() {
+			return fmt.Errorf("cursor paging needs the default sort (-created_at)")
+		}
+		q.Offset = 0
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultCustomerPageSize
+	}
+	if q.Limit > MaxCustomerPageSize {
+		return fmt.Errorf("limit %d exceeds the maximum page size of %d", q.Limit, MaxCustomerPageSize)
+	}
+	if q.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	return nil
+}
+
+// CustomerPage is one page of a CustomerQuery and how many customers match in total. It is
+// the standard list envelope plus the count; cursors are set only for pageable queries.
+type CustomerPage struct {
+	Customers  []*Customer `json:"items"`
+	TotalCount int         `json:"total_count"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var identityStripper = strings.NewReplacer(" ", "", "-", "")
+
+// CustomerSearchFields are the prefixes understood by ParseCustomerSearch.
+var CustomerSearchFields = []string{"name", "id", "phone", "email", "zip", "province", "district"}
+
+// ParseCustomerSearch reads a typed search such as `id:1234567890123 zip:10110 name:somchai`
+// into f. Values containing spaces are quoted: province:"Chiang Mai". Words without a field
+// prefix are name or customer-number text, as in an untyped search. Every term must match.
+func ParseCustomerSearch(s string, f *CustomerFilter) error {
+	var text []string
+	for _, term := range splitSearchTerms(s) {
+		field, value, typed := strings.Cut(term, ":")
+		if !typed || !isSearchFieldName(field) {
+			text = append(text, unquote(term))
+			continue
+		}
+		value = unquote(value)
+		if value == "" {
+			return fmt.Errorf("%w: %s: needs a value", ErrInvalidSearch, field)
+		}
+
+		var dst *string
+		switch strings.ToLower(field) {
+		case "name":
+			text = append(text, value)
+			continue
+		case "id":
+			dst = &f.IdentityNumber
+		case "phone":
+			dst = &f.Phone
+		case "email":
+			dst = &f.Email
+		case "zip":
+			dst = &f.ZipCode
+		case "province":
+			dst = &f.Province
+		case "district":
+			dst = &f.District
+		default:
+			return fmt.Errorf("%w: unknown field %q, want one of %s", ErrInvalidSearch, field, strings.Join(CustomerSearchFields, ", "))
+		}
+		if *dst != "" {
+			return fmt.Errorf("%w: %s: given more than once", ErrInvalidSearch, field)
+		}
+		*dst = value
+	}
+	f.Text = strings.Join(text, " ")
+	return nil
+}
+
+// splitSearchTerms splits on spaces outside double quotes, keeping the quotes.
+func splitSearchTerms(s string) []string {
+	var terms []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if cur.Len() > 0 {
+				terms = append(terms, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		terms = append(terms, cur.String())
+	}
+	return terms
+}
+
+// isSearchFieldName reports whether s looks like a field prefix (ASCII letters only), so that
+// text such as "10:30" or a Thai word before a colon stays plain text.
+func isSearchFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func unquote(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, `"`, ""))
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when the addressed record does not exist; messages name the record,
+	// e.g. "customer not found".
+	ErrNotFound = errors.New("not found")
+
+	// ErrForbidden is returned when the caller is authenticated but not allowed to perform the operation.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrAccessGrantRequired is returned when a protected (high-value) customer is read without an active access grant.
+	ErrAccessGrantRequired = errors.New("access grant required for protected customer")
+
+	// ErrConflict is returned when the operation clashes with the current state, e.g. a job already running.
+	ErrConflict = errors.New("conflict")
+
+	// ErrIdentityConflict is returned when an identity document is already held by another
+	// customer. It wraps ErrConflict.
+	ErrIdentityConflict = fmt.Errorf("%w: identity already belongs to another customer", ErrConflict)
+
+	// ErrInsufficientPoints is returned when a debit exceeds the customer's usable points.
+	ErrInsufficientPoints = errors.New("insufficient points")
+
+	// ErrVerificationFailed is returned when a one-time code is wrong, expired or out of attempts.
+	ErrVerificationFailed = errors.New("verification failed")
+
+	// ErrVerificationUnavailable is returned when no sender is configured to deliver one-time codes.
+	ErrVerificationUnavailable = errors.New("verification codes cannot be sent")
+
+	// ErrInvalidSearch is returned when a customer search cannot be parsed, e.g. an unknown field.
+	ErrInvalidSearch = errors.New("invalid search")
+)
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Source systems customers are known in.
+const (
+	ExternalSystemCAS         = "CAS"          // Legacy policy administration, keyed by cli_num
+	ExternalSystemCoreBanking = "CORE_BANKING" // Core banking CIF number
+	ExternalSystemCRM         = "CRM"          // CRM contact ID
+)
+
+// ExternalSystems lists the systems a reference may point into.
+var ExternalSystems = []string{ExternalSystemCAS, ExternalSystemCoreBanking, ExternalSystemCRM}
+
+// ExternalRef records that a customer is known as Key in another system.
+// A (System, Key) pair belongs to at most one customer.
+type ExternalRef struct {
+	ID         uuid.UUID `json:"id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	System     string    `json:"system"`
+	Key        string    `json:"key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Normalize upper-cases System and trims Key, rejecting unknown systems and empty or overlong keys.
+func (r *ExternalRef) Normalize() error {
+	r.System = strings.ToUpper(strings.TrimSpace(r.System))
+	r.Key = strings.TrimSpace(r.Key)
+	known := false
+	for _, s := range ExternalSystems {
+		if s == r.System {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown external system %q, want one of %s", r.System, strings.Join(ExternalSystems, ", "))
+	}
+	if r.Key == "" || len(r.Key) > 100 {
+		return fmt.Errorf("%s key must be 1 to 100 characters", r.System)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldSet is a sparse fieldset: the JSON fields a client asked for, as in
+// ?fields=id,first_name,addresses.zip_code. A field mapped to nil is wanted whole; a field
+// mapped to a set wants only those nested fields. A nil FieldSet selects everything.
+type FieldSet map[string]FieldSet
+
+// CustomerSubResources can be embedded in a single customer by naming them, or their nested
+// fields, in a customer fieldset.
+var CustomerSubResources = map[string]interface{}{
+	"addresses":     Address{},
+	"identities":    Identity{},
+	"relationships": Relationship{},
+	"consents":      Consent{},
+}
+
+// WithheldCustomerFields are the fields Customer.Withheld keeps.
+var WithheldCustomerFields = []string{"id", "type", "status", "membership_tier", "is_high_value", "created_at", "updated_at"}
+
+// ParseFieldSet reads a comma-separated field list for resources shaped like v. Dotted names
+// select fields of the sub-resources in subs. An empty list selects everything.
+func ParseFieldSet(s string, v interface{}, subs map[string]interface{}) (FieldSet, error) {
+	var fs FieldSet
+	known := JSONFields(v)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if fs == nil {
+			fs = FieldSet{}
+		}
+		name, nested, isNested := strings.Cut(part, ".")
+		if sub, ok := subs[name]; ok {
+			if !isNested {
+				fs[name] = nil
+				continue
+			}
+			if !contains(JSONFields(sub), nested) {
+				return nil, fmt.Errorf("unknown field %q", part)
+			}
+			if existing, ok := fs[name]; ok && existing == nil {
+				continue // already wanted whole
+			}
+			if fs[name] == nil {
+				fs[name] = FieldSet{}
+			}
+			fs[name][nested] = nil
+			continue
+		}
+		if isNested || !contains(known, name) {
+			return nil, fmt.Errorf("unknown field %q", part)
+		}
+		fs[name] = nil
+	}
+	return fs, nil
+}
+
+// Has reports whether the field is selected, whole or in part.
+func (f FieldSet) Has(name string) bool {
+	if f == nil {
+		return true
+	}
+	_, ok := f[name]
+	return ok
+}
+
+// Nested returns the selection within a field, nil when it is wanted whole.
+func (f FieldSet) Nested(name string) FieldSet {
+	return f[name]
+}
+
+// Restrict keeps only the allowed top-level fields. On a nil set it selects exactly allowed.
+func (f FieldSet) Restrict(allowed []string) FieldSet {
+	out := FieldSet{}
+	for _, name := range allowed {
+		if f.Has(name) {
+			out[name] = f.Nested(name)
+		}
+	}
+	return out
+}
+
+// JSONFields lists the JSON names of a struct's exported fields.
+func JSONFields(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ProductCategory string
+
+const (
+	ProductInsurance  ProductCategory = "INSURANCE"
+	ProductDeposit    ProductCategory = "DEPOSIT"
+	ProductInvestment ProductCategory = "INVESTMENT"
+	ProductLoan       ProductCategory = "LOAN"
+)
+
+// Product is an entry in the product catalog that holdings refer to by Code.
+type Product struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Category  ProductCategory `json:"category"`
+	IsActive  bool            `json:"is_active"` // Inactive products cannot be taken up by new holdings
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// HoldingRole is the customer's part in a holding; one policy may link several customers.
+type HoldingRole string
+
+const (
+	HoldingOwner       HoldingRole = "OWNER"
+	HoldingInsured     HoldingRole = "INSURED"
+	HoldingPayer       HoldingRole = "PAYER"
+	HoldingBeneficiary HoldingRole = "BENEFICIARY"
+)
+
+type HoldingStatus string
+
+const (
+	HoldingPending     HoldingStatus = "PENDING"
+	HoldingActive      HoldingStatus = "ACTIVE"
+	HoldingLapsed      HoldingStatus = "LAPSED"
+	HoldingMatured     HoldingStatus = "MATURED"
+	HoldingSurrendered HoldingStatus = "SURRENDERED"
+	HoldingClosed      HoldingStatus = "CLOSED"
+)
+
+// Holding links a customer to a policy or account (legacy tclient_policy_links). ExternalRef is the
+// policy or account number in the product system. SumInsured and Balance are in Currency.
+type Holding struct {
+	ID          uuid.UUID        `json:"id"`
+	CustomerID  uuid.UUID        `json:"customer_id"`
+	ProductCode string           `json:"product_code"`
+	ProductName string           `json:"product_name,omitempty"`
+	ExternalRef string           `json:"external_ref"`
+	Role        HoldingRole      `json:"role"`
+	Status      HoldingStatus    `json:"status"`
+	Currency    string           `json:"currency"`
+	SumInsured  *decimal.Decimal `json:"sum_insured,omitempty"`
+	Balance     *decimal.Decimal `json:"balance,omitempty"`
+	StartDate   time.Time        `json:"start_date"`
+	EndDate     *time.Time       `json:"end_date,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsActive reports whether the holding is in force at t: ACTIVE, started and not yet ended.
+func (h *Holding) IsActive(t time.Time) bool {
+	if h.Status != HoldingActive || h.StartDate.After(t) {
+		return false
+	}
+	return h.EndDate == nil || h.EndDate.After(t)
+}
+
+// CountsTowardPortfolio reports whether the holding's balance belongs in the customer's portfolio
+// size. Only owners count, so a policy linking several customers is not added up more than once.
+func (h *Holding) CountsTowardPortfolio(t time.Time) bool {
+	return h.Role == HoldingOwner && h.Balance != nil && h.IsActive(t)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InteractionChannel string
+
+const (
+	ChannelPhone  InteractionChannel = "PHONE"
+	ChannelEmail  InteractionChannel = "EMAIL"
+	ChannelBranch InteractionChannel = "BRANCH"
+	ChannelChat   InteractionChannel = "CHAT"
+	ChannelSMS    InteractionChannel = "SMS"
+	ChannelLetter InteractionChannel = "LETTER"
+	ChannelNote   InteractionChannel = "NOTE" // Internal note, no customer contact
+)
+
+type InteractionDirection string
+
+const (
+	DirectionInbound  InteractionDirection = "INBOUND"
+	DirectionOutbound InteractionDirection = "OUTBOUND"
+	DirectionInternal InteractionDirection = "INTERNAL"
+)
+
+// Interaction records a contact with the customer (a call, email, branch visit...) or an internal note.
+// StaffUserID is the author; only the author or an admin may change it.
+type Interaction struct {
+	ID          uuid.UUID            `json:"id"`
+	CustomerID  uuid.UUID            `json:"customer_id"`
+	Channel     InteractionChannel   `json:"channel"`
+	Direction   InteractionDirection `json:"direction"`
+	Subject     string               `json:"subject"`
+	Body        string               `json:"body,omitempty"`
+	StaffUserID uuid.UUID            `json:"staff_user_id"`
+	Pinned      bool                 `json:"pinned"`
+	Attachments []AttachmentRef      `json:"attachments,omitempty"`
+	OccurredAt  time.Time            `json:"occurred_at"` // When the contact happened; defaults to creation time
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AttachmentRef points at a file held in external document storage; CIC stores only the reference.
+type AttachmentRef struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// Validate normalises the enums and checks required fields. Notes are always INTERNAL.
+func (i *Interaction) Validate() error {
+	i.Channel = InteractionChannel(strings.ToUpper(string(i.Channel)))
+	i.Direction = InteractionDirection(strings.ToUpper(string(i.Direction)))
+
+	switch i.Channel {
+	case ChannelPhone, ChannelEmail, ChannelBranch, ChannelChat, ChannelSMS, ChannelLetter:
+		switch i.Direction {
+		case DirectionInbound, DirectionOutbound:
+		default:
+			return fmt.Errorf("direction must be %s or %s", DirectionInbound, DirectionOutbound)
+		}
+	case ChannelNote:
+		i.Direction = DirectionInternal
+	default:
+		return fmt.Errorf("unknown channel %q", i.Channel)
+	}
+
+	if strings.TrimSpace(i.Subject) == "" {
+		return errors.New("subject is required")
+	}
+	for _, a := range i.Attachments {
+		if strings.TrimSpace(a.Name) == "" || strings.TrimSpace(a.URL) == "" {
+			return errors.New("attachments need a name and url")
+		}
+	}
+	return nil
+}
+
+// InteractionFilter narrows a customer's interaction list. From is inclusive, To exclusive, both on OccurredAt.
+type InteractionFilter struct {
+	Channel    InteractionChannel
+	PinnedOnly bool
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
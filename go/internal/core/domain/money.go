@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BaseCurrency is the currency CLV, portfolio size and reports are expressed in.
+const BaseCurrency = "THB"
+
+// Money is an exact amount in a currency. Amounts are decimal.Decimal, which maps to
+// Postgres NUMERIC and marshals to JSON as a string (e.g. "1250.50") to avoid float rounding.
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// THB returns amount in the base currency.
+func THB(amount decimal.Decimal) Money {
+	return Money{Amount: amount, Currency: BaseCurrency}
+}
+
+// Add sums two amounts of the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", o.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+func (m Money) String() string {
+	return m.Amount.StringFixed(2) + " " + m.Currency
+}
+
+// FXRate is the local conversion rate from Currency to the base currency: 1 Currency = RateToBase THB.
+type FXRate struct {
+	Currency   string          `json:"currency"`
+	RateToBase decimal.Decimal `json:"rate_to_base"`
+	AsOf       time.Time       `json:"as_of"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Convert expresses m in the base currency, rounded to 2 decimal places.
+func (r *FXRate) Convert(m Money) (Money, error) {
+	if m.Currency == BaseCurrency {
+		return m, nil
+	}
+	if m.Currency != r.Currency {
+		return Money{}, fmt.Errorf("rate for %s cannot convert %s", r.Currency, m.Currency)
+	}
+	return THB(m.Amount.Mul(r.RateToBase).Round(2)), nil
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageCursor is a position in a newest-first list: the sort timestamp and ID of the row at a
+// page boundary. Before asks for the rows preceding (newer than) it rather than following it.
+// Unlike offsets, positions stay put when rows are added or removed while paging.
+type PageCursor struct {
+	At     time.Time
+	ID     uuid.UUID
+	Before bool
+}
+
+// Encode renders the cursor as an opaque URL-safe string.
+func (c PageCursor) Encode() string {
+	dir := "a"
+	if c.Before {
+		dir = "b"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(dir + "|" + c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()))
+}
+
+func DecodePageCursor(s string) (*PageCursor, error) {
+	invalid := errors.New("invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || (parts[0] != "a" && parts[0] != "b") {
+		return nil, invalid
+	}
+	at, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, invalid
+	}
+	return &PageCursor{At: at, ID: id, Before: parts[0] == "b"}, nil
+}
+
+// PageRequest selects a page of a list by cursor or, for older clients, by offset. Offset is
+// ignored when Cursor is set; with neither, the first page is returned.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Cursor *PageCursor
+}
+
+// Page is the standard list envelope. NextCursor and PrevCursor are omitted at either end.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// NewPage builds a page from rows fetched for p: up to Limit+1 rows in the direction of the
+// cursor, the extra row only showing that more exist. key gives a row's cursor position.
+func NewPage[T any](rows []T, p PageRequest, key func(T) PageCursor) *Page[T] {
+	more := len(rows) > p.Limit
+	if more {
+		rows = rows[:p.Limit]
+	}
+	backward := p.Cursor != nil && p.Cursor.Before
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page := &Page[T]{Items: rows}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(rows) == 0 {
+		return page
+	}
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	first.Before, last.Before = true, false
+	if more || backward {
+		page.NextCursor = last.Encode()
+	}
+	if (backward && more) || (!backward && (p.Cursor != nil || p.Offset > 0)) {
+		page.PrevCursor = first.Encode()
+	}
+	return page
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PointsTxType string
+
+const (
+	PointsEarn    PointsTxType = "EARN"
+	PointsBurn    PointsTxType = "BURN"
+	PointsAdjust  PointsTxType = "ADJUST"
+	PointsExpire  PointsTxType = "EXPIRE"
+	PointsReverse PointsTxType = "REVERSE"
+)
+
+// PointsTransaction is an immutable ledger entry. Points is signed: credits are positive, debits negative.
+// Credits from EARN and positive ADJUST open a lot whose Remaining is consumed oldest-first by debits.
+type PointsTransaction struct {
+	ID             uuid.UUID          `json:"id"`
+	CustomerID     uuid.UUID          `json:"customer_id"`
+	Type           PointsTxType       `json:"type"`
+	Points         int64              `json:"points"`
+	Remaining      int64              `json:"remaining"`
+	ExpiresAt      *time.Time         `json:"expires_at,omitempty"`
+	IdempotencyKey string             `json:"idempotency_key,omitempty"`
+	Reference      string             `json:"reference,omitempty"`
+	Reason         string             `json:"reason,omitempty"`
+	ReversesID     *uuid.UUID         `json:"reverses_id,omitempty"`
+	CreatedBy      *uuid.UUID         `json:"created_by,omitempty"`
+	Allocations    []PointsAllocation `json:"allocations,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointsAllocation is how much of a transaction was taken from (positive) or given back to (negative) a lot.
+type PointsAllocation struct {
+	LotID  uuid.UUID `json:"lot_id"`
+	Points int64     `json:"points"`
+}
+
+// IsLot reports whether the transaction opened a lot that later debits can draw on.
+func (t *PointsTransaction) IsLot() bool {
+	return t.Points > 0 && (t.Type == PointsEarn || t.Type == PointsAdjust)
+}
+
+// Usable reports whether the lot still has points that have not expired at the given time.
+func (t *PointsTransaction) Usable(now time.Time) bool {
+	return t.Remaining > 0 && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// PointsRequest is a client-submitted ledger posting. Points is a magnitude for EARN and BURN,
+// and signed for ADJUST; REVERSE takes its amount from the reversed transaction.
+type PointsRequest struct {
+	Type           PointsTxType `json:"type"`
+	Points         int64        `json:"points"`
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"`
+	IdempotencyKey string       `json:"idempotency_key,omitempty"`
+	Reference      string       `json:"reference,omitempty"`
+	Reason         string       `json:"reason,omitempty"`
+	ReversesID     *uuid.UUID   `json:"reverses_id,omitempty"`
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommPurpose is why the bank is contacting the customer.
+type CommPurpose string
+
+const (
+	PurposeStatement CommPurpose = "STATEMENT"
+	PurposePromotion CommPurpose = "PROMOTION"
+	PurposeService   CommPurpose = "SERVICE"  // Servicing and transactional notices
+	PurposeSecurity  CommPurpose = "SECURITY" // Fraud alerts and one-time codes
+)
+
+// CommChannel is how the customer is contacted.
+type CommChannel string
+
+const (
+	CommEmail CommChannel = "EMAIL"
+	CommSMS   CommChannel = "SMS"
+	CommPhone CommChannel = "PHONE"
+	CommLine  CommChannel = "LINE"
+	CommPost  CommChannel = "POST"
+)
+
+var (
+	commPurposes = []CommPurpose{PurposeStatement, PurposePromotion, PurposeService, PurposeSecurity}
+	commChannels = []CommChannel{CommEmail, CommSMS, CommPhone, CommLine, CommPost}
+	languageCode = regexp.MustCompile(`^[a-z]{2}$`)
+	weekdayNames = map[string]time.Weekday{
+		"SUN": time.Sunday, "MON": time.Monday, "TUE": time.Tuesday, "WED": time.Wednesday,
+		"THU": time.Thursday, "FRI": time.Friday, "SAT": time.Saturday,
+	}
+)
+
+// CommunicationPreferences is a customer's purpose × channel matrix plus preferred language.
+type CommunicationPreferences struct {
+	CustomerID uuid.UUID           `json:"customer_id"`
+	Language   string              `json:"language"` // ISO 639-1, e.g. "th", "en"
+	Channels   []ChannelPreference `json:"channels"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// ChannelPreference is one cell of the matrix. Lower Priority is preferred. Days limits contact to
+// the listed weekdays (MON..SUN; empty means every day). QuietStart/QuietEnd are HH:MM local
+// times during which the channel must not be used; a window may cross midnight, e.g. 20:00-08:00.
+type ChannelPreference struct {
+	Purpose    CommPurpose `json:"purpose"`
+	Channel    CommChannel `json:"channel"`
+	Allowed    bool        `json:"allowed"`
+	Priority   int         `json:"priority"`
+	Days       []string    `json:"days,omitempty"`
+	QuietStart string      `json:"quiet_start,omitempty"`
+	QuietEnd   string      `json:"quiet_end,omitempty"`
+}
+
+// Validate normalises case and rejects unknown purposes, channels, days, clock times and duplicate cells.
+func (p *CommunicationPreferences) Validate() error {
+	p.Language = strings.ToLower(strings.TrimSpace(p.Language))
+	if p.Language != "" && !languageCode.MatchString(p.Language) {
+		return fmt.Errorf("language must be a two-letter ISO 639-1 code, got %q", p.Language)
+	}
+
+	seen := map[string]bool{}
+	for i := range p.Channels {
+		c := &p.Channels[i]
+		c.Purpose = CommPurpose(strings.ToUpper(string(c.Purpose)))
+		c.Channel = CommChannel(strings.ToUpper(string(c.Channel)))
+		if !containsPurpose(c.Purpose) {
+			return fmt.Errorf("unknown purpose %q", c.Purpose)
+		}
+		if !containsChannel(c.Channel) {
+			return fmt.Errorf("unknown channel %q", c.Channel)
+		}
+		key := string(c.Purpose) + "/" + string(c.Channel)
+		if seen[key] {
+			return fmt.Errorf("duplicate preference for %s", key)
+		}
+		seen[key] = true
+
+		for j, d := range c.Days {
+			d = strings.ToUpper(strings.TrimSpace(d))
+			if _, ok := weekdayNames[d]; !ok {
+				return fmt.Errorf("%s: unknown day %q, want MON..SUN", key, d)
+			}
+			c.Days[j] = d
+		}
+		if (c.QuietStart == "") != (c.QuietEnd == "") {
+			return fmt.Errorf("%s: quiet_start and quiet_end go together", key)
+		}
+		for _, t := range []string{c.QuietStart, c.QuietEnd} {
+			if _, err := parseClock(t); t != "" && err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// For returns the allowed cells for purpose, most preferred first.
+func (p *CommunicationPreferences) For(purpose CommPurpose) []ChannelPreference {
+	var out []ChannelPreference
+	for _, c := range p.Channels {
+		if c.Purpose == purpose && c.Allowed {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Priority < out[j].Priority })
+	return out
+}
+
+// Unavailable explains why the channel cannot be used at t in loc, or returns "" if it can.
+func (c ChannelPreference) Unavailable(t time.Time, loc *time.Location) string {
+	local := t.In(loc)
+	if len(c.Days) > 0 {
+		ok := false
+		for _, d := range c.Days {
+			if weekdayNames[d] == local.Weekday() {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Sprintf("not on %s", strings.ToUpper(local.Weekday().String()[:3]))
+		}
+	}
+	if c.QuietStart != "" {
+		start, _ := parseClock(c.QuietStart)
+		end, _ := parseClock(c.QuietEnd)
+		now := local.Hour()*60 + local.Minute()
+		quiet := start <= now && now < end
+		if start > end { // Crosses midnight
+			quiet = now >= start || now < end
+		}
+		if quiet {
+			return fmt.Sprintf("quiet hours %s-%s", c.QuietStart, c.QuietEnd)
+		}
+	}
+	return ""
+}
+
+// ChannelDecision answers "how should we contact this customer for this purpose now".
+// Channel is empty when no channel is usable; Skipped says why each candidate was passed over.
+type ChannelDecision struct {
+	Purpose  CommPurpose      `json:"purpose"`
+	Channel  CommChannel      `json:"channel,omitempty"`
+	Contact  string           `json:"contact,omitempty"`
+	Language string           `json:"language,omitempty"`
+	At       time.Time        `json:"at"`
+	Skipped  []SkippedChannel `json:"skipped,omitempty"`
+}
+
+type SkippedChannel struct {
+	Channel CommChannel `json:"channel"`
+	Reason  string      `json:"reason"`
+}
+
+func ParseCommPurpose(s string) (CommPurpose, error) {
+	p := CommPurpose(strings.ToUpper(strings.TrimSpace(s)))
+	if !containsPurpose(p) {
+		return "", fmt.Errorf("unknown purpose %q", s)
+	}
+	return p, nil
+}
+
+func containsPurpose(p CommPurpose) bool {
+	for _, v := range commPurposes {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func containsChannel(c CommChannel) bool {
+	for _, v := range commChannels {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock returns minutes since midnight for an HH:MM time.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SegmentDaily segments are materialized by the daily segment job; others only on request.
+const SegmentDaily = "DAILY"
+
+// Membership changes between segment runs.
+const (
+	SegmentEntered = "ENTERED"
+	SegmentLeft    = "LEFT"
+)
+
+// Segment is a saved customer filter. The owner and admins manage it; users whose role is in
+// SharedWithRoles may view, evaluate and export it.
+type Segment struct {
+	ID              uuid.UUID      `json:"id"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description,omitempty"`
+	Filter          CustomerFilter `json:"filter"`
+	SharedWithRoles []string       `json:"shared_with_roles,omitempty"`
+	Schedule        string         `json:"schedule,omitempty"` // DAILY, or empty for on demand
+	OwnerID         uuid.UUID      `json:"owner_id"`
+	LastRun         *SegmentRun    `json:"last_run,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// SegmentUser is who is acting on a segment.
+type SegmentUser struct {
+	ID    uuid.UUID
+	Role  string
+	Admin bool
+}
+
+// Validate normalises the segment and its filter.
+func (s *Segment) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" || len(s.Name) > 100 {
+		return fmt.Errorf("name is required and at most 100 characters")
+	}
+	s.Schedule = strings.ToUpper(strings.TrimSpace(s.Schedule))
+	if s.Schedule != "" && s.Schedule != SegmentDaily {
+		return fmt.Errorf("unknown schedule %q, want %s or none", s.Schedule, SegmentDaily)
+	}
+	roles := []string{}
+	for _, r := range s.SharedWithRoles {
+		if r = strings.ToUpper(strings.TrimSpace(r)); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	s.SharedWithRoles = roles
+	return s.Filter.Normalize()
+}
+
+// CanView reports whether u may see, evaluate and export the segment.
+func (s *Segment) CanView(u SegmentUser) bool {
+	if s.CanManage(u) {
+		return true
+	}
+	for _, r := range s.SharedWithRoles {
+		if strings.EqualFold(r, u.Role) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManage reports whether u may change, share, delete or materialize the segment.
+func (s *Segment) CanManage(u SegmentUser) bool {
+	return u.Admin || s.OwnerID == u.ID
+}
+
+// SegmentRun is one materialization: the segment's size then and how many customers entered
+// or left it since the previous run.
+type SegmentRun struct {
+	ID          uuid.UUID `json:"id"`
+	SegmentID   uuid.UUID `json:"segment_id"`
+	RunAt       time.Time `json:"run_at"`
+	Size        int       `json:"size"`
+	Entered     int       `json:"entered"`
+	Left        int       `json:"left"`
+	TriggeredBy string    `json:"triggered_by"`
+}
+
+// SegmentChange is a customer entering or leaving a segment between two runs.
+type SegmentChange struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	Change     string    `json:"change"` // ENTERED or LEFT
+}
+
+// SegmentDiff compares membership at two runs. From is nil when comparing with the empty
+// segment before the first run.
+type SegmentDiff struct {
+	From    *SegmentRun     `json:"from,omitempty"`
+	To      *SegmentRun     `json:"to"`
+	Changes []SegmentChange `json:"changes"`
+}
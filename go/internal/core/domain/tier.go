@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TierChangeSource string
+
+const (
+	TierSourceCreate    TierChangeSource = "CREATE"
+	TierSourceUpdate    TierChangeSource = "UPDATE"
+	TierSourceRecalc    TierChangeSource = "RECALC"
+	TierSourcePoints    TierChangeSource = "POINTS"
+	TierSourcePortfolio TierChangeSource = "PORTFOLIO"
+	TierSourceImport    TierChangeSource = "IMPORT"
+)
+
+// TierChange records a derived tier or high-value flag moving, and the rule version that moved it.
+type TierChange struct {
+	ID           uuid.UUID        `json:"id"`
+	CustomerID   uuid.UUID        `json:"customer_id"`
+	OldTier      string           `json:"old_tier"`
+	NewTier      string           `json:"new_tier"`
+	OldHighValue bool             `json:"old_high_value"`
+	NewHighValue bool             `json:"new_high_value"`
+	RuleVersion  string           `json:"rule_version"`
+	Rule         string           `json:"rule"`
+	Source       TierChangeSource `json:"source"`
+	ChangedAt    time.Time        `json:"changed_at"`
+}
+
+// TierRecalcResult summarises a full recalculation run.
+type TierRecalcResult struct {
+	RuleVersion string    `json:"rule_version"`
+	Scanned     int       `json:"scanned"`
+	Changed     int       `json:"changed"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Timeline event types, usable in the type= filter.
+const (
+	TimelineCustomer     = "customer"
+	TimelineStatus       = "status"
+	TimelineConsent      = "consent"
+	TimelineAddress      = "address"
+	TimelineIdentity     = "identity"
+	TimelineRelationship = "relationship"
+	TimelineContact      = "contact"
+	TimelineBankAccount  = "bank_account"
+	TimelineHolding      = "holding"
+	TimelineTier         = "tier"
+	TimelinePoints       = "points"
+	TimelineAccess       = "access"
+	TimelineInteraction  = "interaction"
+)
+
+// TimelineEntry is one event in a customer's activity feed. ID is unique across sources
+// (e.g. "audit:<uuid>") and breaks ties between events with the same timestamp.
+type TimelineEntry struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Action     string    `json:"action"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor,omitempty"`
+	Summary    string    `json:"summary"`
+	Link       string    `json:"link,omitempty"`
+}
+
+// Before reports whether e sorts after (is older than) the cursor position in the newest-first feed.
+func (e *TimelineEntry) Before(c *TimelineCursor) bool {
+	if c == nil {
+		return true
+	}
+	if !e.OccurredAt.Equal(c.At) {
+		return e.OccurredAt.Before(c.At)
+	}
+	return e.ID < c.ID
+}
+
+// TimelineCursor marks the last entry of a page.
+type TimelineCursor struct {
+	At time.Time
+	ID string
+}
+
+func (c TimelineCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+func DecodeTimelineCursor(s string) (*TimelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &TimelineCursor{At: t, ID: id}, nil
+}
+
+// TimelineQuery selects a page of the feed. Empty Types means all types; From/To bound OccurredAt.
+type TimelineQuery struct {
+	Types  []string
+	From   *time.Time
+	To     *time.Time
+	Cursor *TimelineCursor
+	Limit  int
+}
+
+// Matches applies the type and date-range filters.
+func (q TimelineQuery) Matches(e *TimelineEntry) bool {
+	if q.From != nil && e.OccurredAt.Before(*q.From) {
+		return false
+	}
+	if q.To != nil && !e.OccurredAt.Before(*q.To) {
+		return false
+	}
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range q.Types {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+type TimelinePage struct {
+	Entries    []*TimelineEntry `json:"entries"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
 	"github.com/amnuaym/cic/go/internal/models"
@@ -11,23 +12,38 @@ import (
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *domain.Customer) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
+	// GetFields is GetByID reading only the columns behind fields; the rest are left zero.
+	GetFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error)
+	// GetByIDs loads the live customers among ids, in no particular order; missing ids are skipped.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error)
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
 	Update(ctx context.Context, customer *domain.Customer) error
+	// UpdateStatus moves a live customer from one status to another in one write, failing with
+	// ErrConflict when the customer is no longer in from.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.CustomerStatus) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error // Soft delete
 	Restore(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
-	ListDeleted(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
+	List(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error)
+	ListDeleted(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error)
+	CountDeleted(ctx context.Context) (int, error)
 	Search(ctx context.Context, query string) ([]*domain.Customer, error)
+	// Query returns one page of customers matching a normalized query and the total match count.
+	Query(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error)
+	// MatchingIDs returns the ids of every customer matching a normalized filter.
+	MatchingIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error)
 }
 
 type AddressRepository interface {
 	Create(ctx context.Context, address *domain.Address) error
 	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Address, error)
+	ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Address, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type IdentityRepository interface {
 	Create(ctx context.Context, identity *domain.Identity) error
 	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Identity, error)
+	ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Identity, error)
 	GetByNumber(ctx context.Context, number string) (*domain.Identity, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -35,14 +51,229 @@ type IdentityRepository interface {
 type RelationshipRepository interface {
 	Create(ctx context.Context, rel *domain.Relationship) error
 	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Relationship, error)
+	ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Relationship, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type ConsentRepository interface {
 	Create(ctx context.Context, consent *domain.Consent) error
 	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Consent, error)
+	ListByCustomerIDs(ctx context.Context, customerIDs []uuid.UUID) ([]*domain.Consent, error)
 }
 
 type UserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 }
+
+type AccessGrantRepository interface {
+	Create(ctx context.Context, grant *domain.AccessGrant) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error)
+	// Update moves a grant out of the from status; it fails with ErrConflict when the grant
+	// is no longer in it.
+	Update(ctx context.Context, grant *domain.AccessGrant, from domain.AccessGrantStatus) error
+	FindActive(ctx context.Context, customerID, userID uuid.UUID) (*domain.AccessGrant, error)
+	RecordUse(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error)
+}
+
+type ChangeRequestRepository interface {
+	Create(ctx context.Context, cr *domain.ChangeRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error)
+	Update(ctx context.Context, cr *domain.ChangeRequest, from domain.ChangeRequestStatus) error
+	List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error)
+	ExpirePending(ctx context.Context) (int64, error)
+}
+
+type OrgRepository interface {
+	CreateBranch(ctx context.Context, b *models.Branch) error
+	ListBranches(ctx context.Context) ([]*models.Branch, error)
+	CreateTeam(ctx context.Context, t *models.Team) error
+	ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*models.Team, error)
+	GetTeam(ctx context.Context, id uuid.UUID) (*models.Team, error)
+	AssignUser(ctx context.Context, userID uuid.UUID, teamID, branchID *uuid.UUID) error
+}
+
+type DelegationRepository interface {
+	Create(ctx context.Context, d *models.Delegation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Delegation, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error)
+	ListActiveForDelegate(ctx context.Context, delegateID uuid.UUID) ([]*models.Delegation, error)
+}
+
+type TierRepository interface {
+	// ListForRecalc pages through live customers in id order, starting after afterID.
+	ListForRecalc(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.Customer, error)
+	// GetForRecalc loads one live customer with the same fields as ListForRecalc.
+	GetForRecalc(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error)
+	UpdateTier(ctx context.Context, customerID uuid.UUID, tier string, isHighValue bool) error
+	CreateHistory(ctx context.Context, change *domain.TierChange) error
+	ListHistory(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.TierChange, error)
+}
+
+type PointsRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PointsTransaction, error)
+	// FindByIdempotencyKey returns nil, nil when no transaction uses the key.
+	FindByIdempotencyKey(ctx context.Context, customerID uuid.UUID, key string) (*domain.PointsTransaction, error)
+	List(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.PointsTransaction, error)
+	// Post locks the customer's ledger, passes build the lots that still have points (oldest first),
+	// and atomically writes the transaction it returns together with its lot allocations and the
+	// refreshed balance. build may return nil to write nothing.
+	Post(ctx context.Context, customerID uuid.UUID, build func(lots []*domain.PointsTransaction) (*domain.PointsTransaction, error)) error
+	// CustomersWithExpiredLots lists live customers holding lots past expiry that still have points.
+	CustomersWithExpiredLots(ctx context.Context, limit int) ([]uuid.UUID, error)
+}
+
+type FXRateRepository interface {
+	List(ctx context.Context) ([]*domain.FXRate, error)
+	// Get returns nil, nil when no rate is configured for the currency.
+	Get(ctx context.Context, currency string) (*domain.FXRate, error)
+	Upsert(ctx context.Context, rate *domain.FXRate) error
+}
+
+type PortfolioValueRepository interface {
+	List(ctx context.Context, customerID uuid.UUID) ([]domain.Money, error)
+	Replace(ctx context.Context, customerID uuid.UUID, values []domain.Money) error
+}
+
+type InteractionRepository interface {
+	Create(ctx context.Context, i *domain.Interaction) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Interaction, error)
+	Update(ctx context.Context, i *domain.Interaction) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// List returns pinned interactions first, then the rest newest first.
+	List(ctx context.Context, customerID uuid.UUID, f domain.InteractionFilter) ([]*domain.Interaction, error)
+	// ListForTimeline returns interactions strictly newest first, at or before before and within [from, to).
+	ListForTimeline(ctx context.Context, customerID uuid.UUID, from, to, before *time.Time, limit int) ([]*domain.Interaction, error)
+}
+
+type ContactPointRepository interface {
+	// Create and Update clear the primary flag on the customer's other contact points of the same type
+	// when cp.IsPrimary is set.
+	Create(ctx context.Context, cp *domain.ContactPoint) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ContactPoint, error)
+	Update(ctx context.Context, cp *domain.ContactPoint) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error)
+	// FindByValue returns contact points of any of the given types whose normalized value matches exactly.
+	FindByValue(ctx context.Context, types []domain.ContactType, value string) ([]*domain.ContactPoint, error)
+
+	SaveVerification(ctx context.Context, v *domain.ContactVerification) error
+	GetVerification(ctx context.Context, contactPointID uuid.UUID) (*domain.ContactVerification, error)
+	// UseVerificationAttempt counts an attempt at the outstanding code in one conditional update,
+	// reporting false when maxAttempts were already used, so concurrent guesses cannot exceed it.
+	UseVerificationAttempt(ctx context.Context, contactPointID uuid.UUID, maxAttempts int) (bool, error)
+	DeleteVerification(ctx context.Context, contactPointID uuid.UUID) error
+}
+
+type PreferenceRepository interface {
+	// Get returns nil, nil when the customer has no stored preferences.
+	Get(ctx context.Context, customerID uuid.UUID) (*domain.CommunicationPreferences, error)
+	// Save replaces the customer's whole matrix.
+	Save(ctx context.Context, p *domain.CommunicationPreferences) error
+}
+
+type BankAccountRepository interface {
+	// Create and Update clear the primary flag on the customer's other accounts with the same purpose
+	// when b.IsPrimary is set.
+	Create(ctx context.Context, b *domain.BankAccount) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.BankAccount, error)
+	Update(ctx context.Context, b *domain.BankAccount) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.BankAccount, error)
+}
+
+type ProductRepository interface {
+	List(ctx context.Context, activeOnly bool) ([]*domain.Product, error)
+	GetByCode(ctx context.Context, code string) (*domain.Product, error)
+	// Save inserts the product or updates the one with the same code.
+	Save(ctx context.Context, p *domain.Product) error
+}
+
+type HoldingRepository interface {
+	Create(ctx context.Context, h *domain.Holding) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Holding, error)
+	Update(ctx context.Context, h *domain.Holding) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Holding, error)
+	// UpdatePortfolio atomically replaces the customer's per-currency portfolio values and sets
+	// portfolio_size to total.
+	UpdatePortfolio(ctx context.Context, customerID uuid.UUID, total domain.Money, values []domain.Money) error
+	// CustomersWithDateChanges lists customers with a holding starting or ending in (from, to].
+	CustomersWithDateChanges(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// RefreshWatermark returns the end of the last completed portfolio refresh, or zero if none.
+	RefreshWatermark(ctx context.Context) (time.Time, error)
+	SetRefreshWatermark(ctx context.Context, at time.Time) error
+}
+
+// CustomerNumberRepository draws customer numbers and looks customers up by them.
+type CustomerNumberRepository interface {
+	NextNumberSequence(ctx context.Context) (int64, error)
+	FindIDByNumber(ctx context.Context, number string) (uuid.UUID, error)
+	ListUnnumbered(ctx context.Context, limit int) ([]uuid.UUID, error)
+	AssignNumber(ctx context.Context, id uuid.UUID, number string) error
+}
+
+type ExternalRefRepository interface {
+	// Find returns nil, nil when no customer has the reference.
+	Find(ctx context.Context, system, key string) (*domain.ExternalRef, error)
+	ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]domain.ExternalRef, error)
+	// Replace makes refs the customer's complete set. A reference held by another customer
+	// fails with ErrConflict and nothing is changed.
+	Replace(ctx context.Context, customerID uuid.UUID, refs []domain.ExternalRef) error
+	// Merge moves every reference of one customer to another and soft-deletes the first, in one
+	// transaction, returning how many references moved.
+	Merge(ctx context.Context, fromCustomerID, toCustomerID, deletedBy uuid.UUID) (int64, error)
+}
+
+// CASClientSource reads clients from the legacy CAS database in cli_num order.
+type CASClientSource interface {
+	// Clients returns up to limit clients with cli_num greater than after, with their addresses.
+	Clients(ctx context.Context, after string, limit int) ([]*domain.CASClient, error)
+}
+
+// JobLock serializes a background job across every instance sharing the database.
+type JobLock interface {
+	// Lock takes the lock for job, failing with domain.ErrConflict while another process holds
+	// it. unlock releases it.
+	Lock(ctx context.Context, job string) (unlock func(), err error)
+}
+
+type ImportRepository interface {
+	CreateRun(ctx context.Context, run *domain.ImportRun) error
+	// UpdateRun saves the run's cursor, counters, status and error.
+	UpdateRun(ctx context.Context, run *domain.ImportRun) error
+	GetRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]*domain.ImportRun, error)
+	// AddReject records or replaces the reason a source record was rejected in the run.
+	AddReject(ctx context.Context, reject *domain.ImportReject) error
+	ListRejects(ctx context.Context, runID uuid.UUID) ([]*domain.ImportReject, error)
+	// SaveClient writes the customer, addresses, identities and external reference in one
+	// transaction. An identity held by another customer fails with domain.ErrIdentityConflict,
+	// any other clash with domain.ErrConflict.
+	SaveClient(ctx context.Context, c *domain.ImportedClient) error
+	// Lock takes the database-wide lock for imports from source, failing with domain.ErrConflict
+	// while another process holds it. unlock releases it.
+	Lock(ctx context.Context, source string) (unlock func(), err error)
+}
+
+type SegmentRepository interface {
+	Create(ctx context.Context, s *domain.Segment) error
+	// GetByID loads the segment with its latest run.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Segment, error)
+	Update(ctx context.Context, s *domain.Segment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// List returns every segment by name, with its latest run; schedule narrows it when not empty.
+	List(ctx context.Context, schedule string) ([]*domain.Segment, error)
+	// SaveRun records a materialization whose members are exactly memberIDs. It stamps the run,
+	// closes the membership of customers no longer present, opens it for new ones and fills in
+	// the run's size and entered/left counts.
+	SaveRun(ctx context.Context, run *domain.SegmentRun, memberIDs []uuid.UUID) error
+	GetRun(ctx context.Context, id uuid.UUID) (*domain.SegmentRun, error)
+	// PreviousRun returns the run before the given one, or nil, nil for the first run.
+	PreviousRun(ctx context.Context, run *domain.SegmentRun) (*domain.SegmentRun, error)
+	ListRuns(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]*domain.SegmentRun, error)
+	// Changes lists customers whose membership differs between two runs; from may be nil for
+	// the empty segment before the first run.
+	Changes(ctx context.Context, segmentID uuid.UUID, from, to *domain.SegmentRun) ([]domain.SegmentChange, error)
+}
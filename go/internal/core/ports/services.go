@@ -2,34 +2,260 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/amnuaym/cic/go/internal/models"
 	"github.com/google/uuid"
 )
 
 type CustomerService interface {
-	CreateCustomer(ctx context.Context, customer *domain.Customer) error
+	CreateCustomer(ctx context.Context, customer *domain.Customer, userID uuid.UUID) error
 	GetCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
-	UpdateCustomer(ctx context.Context, customer *domain.Customer) error
+	// GetCustomerFields is GetCustomer loading only the selected fields.
+	GetCustomerFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error)
+	UpdateCustomer(ctx context.Context, customer *domain.Customer, userID uuid.UUID) error
 	DeleteCustomer(ctx context.Context, id, userID uuid.UUID) error
 	RestoreCustomer(ctx context.Context, id, userID uuid.UUID) error
+	OverrideRestoreCustomer(ctx context.Context, id, userID uuid.UUID, justification string) error
 	SearchCustomers(ctx context.Context, query string) ([]*domain.Customer, error)
-	ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
-	ListDeletedCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
-	AnonymizeCustomer(ctx context.Context, id uuid.UUID) error
+	// QueryCustomers filters, sorts and pages customers; the limit may not exceed MaxCustomerPageSize.
+	QueryCustomers(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error)
+	// MatchingCustomerIDs returns every customer matching the filter, unpaged.
+	MatchingCustomerIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error)
+	ListCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error)
+	ListDeletedCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error)
+	// CountDeletedCustomers returns how many customers are soft-deleted in total.
+	CountDeletedCustomers(ctx context.Context) (int, error)
+	AnonymizeCustomer(ctx context.Context, id, userID uuid.UUID) error
+	// ChangeCustomerStatus applies a status transition alone, leaving every other field as stored.
+	ChangeCustomerStatus(ctx context.Context, id uuid.UUID, change domain.StatusChange, userID uuid.UUID) error
+	// MergeCustomer retires the duplicate id into intoID: its external references move to
+	// intoID and it is soft-deleted.
+	MergeCustomer(ctx context.Context, id, intoID, userID uuid.UUID) error
+	// CheckChange reports why a sensitive operation by userID could not be applied now, or nil.
+	CheckChange(ctx context.Context, op domain.ChangeOperation, id, userID uuid.UUID, justification string) error
+	// CheckMerge reports why id could not be merged into intoID now, or nil.
+	CheckMerge(ctx context.Context, id, intoID uuid.UUID) error
 
-	AddAddress(ctx context.Context, address *domain.Address) error
+	AddAddress(ctx context.Context, address *domain.Address, userID uuid.UUID) error
 	GetAddresses(ctx context.Context, customerID uuid.UUID) ([]*domain.Address, error)
-	RemoveAddress(ctx context.Context, addressID uuid.UUID) error
+	RemoveAddress(ctx context.Context, customerID, addressID, userID uuid.UUID) error
 
-	AddIdentity(ctx context.Context, identity *domain.Identity) error
+	AddIdentity(ctx context.Context, identity *domain.Identity, userID uuid.UUID) error
 	GetIdentities(ctx context.Context, customerID uuid.UUID) ([]*domain.Identity, error)
-	RemoveIdentity(ctx context.Context, identityID uuid.UUID) error
+	RemoveIdentity(ctx context.Context, customerID, identityID, userID uuid.UUID) error
 
-	AddRelationship(ctx context.Context, rel *domain.Relationship) error
+	AddRelationship(ctx context.Context, rel *domain.Relationship, userID uuid.UUID) error
 	GetRelationships(ctx context.Context, customerID uuid.UUID) ([]*domain.Relationship, error)
-	RemoveRelationship(ctx context.Context, relID uuid.UUID) error
+	RemoveRelationship(ctx context.Context, customerID, relID, userID uuid.UUID) error
 
-	ManageConsent(ctx context.Context, consent *domain.Consent) error
+	ManageConsent(ctx context.Context, consent *domain.Consent, userID uuid.UUID) error
 	GetConsents(ctx context.Context, customerID uuid.UUID) ([]*domain.Consent, error)
+
+	// Batch reads for callers resolving many customers at once, such as the GraphQL loaders.
+	// Sub-resources are grouped by customer; a relationship is listed under both of its ends.
+	GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error)
+	GetAddressesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Address, error)
+	GetIdentitiesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Identity, error)
+	GetRelationshipsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Relationship, error)
+	GetConsentsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Consent, error)
+}
+
+type AccessGrantService interface {
+	RequestAccess(ctx context.Context, customerID, userID uuid.UUID, reason string) (*domain.AccessGrant, error)
+	ApproveAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error)
+	RejectAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error)
+	CheckAccess(ctx context.Context, customerID, userID uuid.UUID, role string) error
+	CanRead(ctx context.Context, c *domain.Customer, userID uuid.UUID, role string) (bool, error)
+	ListGrants(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error)
+}
+
+type ChangeRequestService interface {
+	Submit(ctx context.Context, op domain.ChangeOperation, customerID, makerID uuid.UUID, payload interface{}, reason string) (*domain.ChangeRequest, error)
+	Approve(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error)
+	Reject(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error)
+	List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error)
+}
+
+type HierarchyService interface {
+	// Supervisors returns the user's supervisor chain, nearest first, up to the configured depth.
+	Supervisors(ctx context.Context, userID uuid.UUID) ([]*models.User, error)
+	// CanActFor reports whether actor may exercise supervisor rights over subject for the scope:
+	// actor is subject, a transitive supervisor, the lead of subject's team in subject's branch,
+	// or holds an active delegation from one of those. Delegations issued by subject themselves
+	// confer nothing.
+	CanActFor(ctx context.Context, actorID, subjectID uuid.UUID, scope string) (bool, error)
+
+	Delegate(ctx context.Context, d *models.Delegation) error
+	RevokeDelegation(ctx context.Context, id, userID uuid.UUID, isAdmin bool) error
+	ListDelegations(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error)
+
+	CreateBranch(ctx context.Context, b *models.Branch) error
+	ListBranches(ctx context.Context) ([]*models.Branch, error)
+	CreateTeam(ctx context.Context, t *models.Team) error
+	ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*models.Team, error)
+	AssignUser(ctx context.Context, userID uuid.UUID, teamID, branchID *uuid.UUID) error
+}
+
+type TierService interface {
+	// Assign derives tier and high-value flag onto c. It returns the change relative to prev
+	// (nil for a new customer), or nil if nothing moved.
+	Assign(ctx context.Context, c, prev *domain.Customer, source domain.TierChangeSource) (*domain.TierChange, error)
+	RecordChange(ctx context.Context, change *domain.TierChange) error
+	// Reevaluate re-derives one stored customer's tier after an input the rules read changed
+	// outside UpdateCustomer, such as the points balance. It returns the recorded change, or nil.
+	Reevaluate(ctx context.Context, customerID uuid.UUID, source domain.TierChangeSource) (*domain.TierChange, error)
+	RecalculateAll(ctx context.Context) (*domain.TierRecalcResult, error)
+	History(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.TierChange, error)
+	Rules() tiering.Config
+}
+
+type PointsService interface {
+	// Post records a ledger transaction. When the idempotency key was already used, the original
+	// transaction is returned with created=false.
+	Post(ctx context.Context, customerID, userID uuid.UUID, req domain.PointsRequest) (tx *domain.PointsTransaction, created bool, err error)
+	List(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.PointsTransaction, error)
+	// ExpireDue writes EXPIRE transactions for every lot past its expiry date.
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+type FXService interface {
+	Rates(ctx context.Context) ([]*domain.FXRate, error)
+	SetRate(ctx context.Context, rate *domain.FXRate, userID uuid.UUID) error
+	// ToBase converts an amount to the base currency (THB) using the local rate table.
+	ToBase(ctx context.Context, m domain.Money) (domain.Money, error)
+	// SumToBase converts each amount to THB and adds them up.
+	SumToBase(ctx context.Context, values []domain.Money) (domain.Money, error)
+}
+
+type Customer360Service interface {
+	// Get360 loads the customer and the requested sections concurrently (all sections when
+	// include is empty). Only a missing customer fails the call; section errors are reported inline.
+	Get360(ctx context.Context, id uuid.UUID, include []string) (*domain.Customer360, error)
+}
+
+type TimelineService interface {
+	// Timeline merges every event source for the customer into one newest-first page.
+	Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) (*domain.TimelinePage, error)
+}
+
+type InteractionService interface {
+	Create(ctx context.Context, i *domain.Interaction) error
+	Get(ctx context.Context, customerID, id uuid.UUID) (*domain.Interaction, error)
+	List(ctx context.Context, customerID uuid.UUID, f domain.InteractionFilter) ([]*domain.Interaction, error)
+	// Update and Delete are limited to the author unless isAdmin.
+	Update(ctx context.Context, i *domain.Interaction, userID uuid.UUID, isAdmin bool) error
+	Delete(ctx context.Context, customerID, id, userID uuid.UUID, isAdmin bool) error
+}
+
+// OTPSender delivers a one-time code to a contact point. Implementations range from a development
+// log writer to an SMS or email gateway.
+type OTPSender interface {
+	Send(ctx context.Context, cp *domain.ContactPoint, code string) error
+}
+
+type ContactPointService interface {
+	Add(ctx context.Context, cp *domain.ContactPoint, userID uuid.UUID) error
+	List(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error)
+	// Update changes the value or primary flag; a new value must be verified again.
+	Update(ctx context.Context, cp *domain.ContactPoint, userID uuid.UUID) error
+	Remove(ctx context.Context, customerID, id, userID uuid.UUID) error
+	StartVerification(ctx context.Context, customerID, id, userID uuid.UUID) (*domain.ContactPoint, error)
+	ConfirmVerification(ctx context.Context, customerID, id uuid.UUID, code string, userID uuid.UUID) (*domain.ContactPoint, error)
+	// FindCustomers returns the customers holding the given phone number or email address.
+	FindCustomers(ctx context.Context, phone, email string) ([]*domain.Customer, error)
+}
+
+type PreferenceService interface {
+	Get(ctx context.Context, customerID uuid.UUID) (*domain.CommunicationPreferences, error)
+	Save(ctx context.Context, p *domain.CommunicationPreferences, userID uuid.UUID) error
+	// BestChannel picks the most preferred channel usable for purpose at the given time, honouring
+	// quiet hours, allowed days, consent and whether the customer has a contact for the channel.
+	BestChannel(ctx context.Context, customerID uuid.UUID, purpose domain.CommPurpose, at time.Time) (*domain.ChannelDecision, error)
+}
+
+// BankAccountService returns account numbers masked except from Reveal.
+type BankAccountService interface {
+	Add(ctx context.Context, b *domain.BankAccount, userID uuid.UUID) error
+	List(ctx context.Context, customerID uuid.UUID) ([]*domain.BankAccount, error)
+	Update(ctx context.Context, b *domain.BankAccount, userID uuid.UUID) error
+	Remove(ctx context.Context, customerID, id, userID uuid.UUID) error
+	// Reveal returns the account with its full number and records who asked.
+	Reveal(ctx context.Context, customerID, id uuid.UUID, performedBy string) (*domain.BankAccount, error)
+}
+
+// HoldingService keeps the customer's portfolio size in step with their active holdings.
+type HoldingService interface {
+	ListProducts(ctx context.Context, activeOnly bool) ([]*domain.Product, error)
+	SaveProduct(ctx context.Context, p *domain.Product, userID uuid.UUID) error
+	Add(ctx context.Context, h *domain.Holding, userID uuid.UUID) error
+	List(ctx context.Context, customerID uuid.UUID) ([]*domain.Holding, error)
+	Update(ctx context.Context, h *domain.Holding, userID uuid.UUID) error
+	Remove(ctx context.Context, customerID, id, userID uuid.UUID) error
+	// RefreshPortfolio recomputes portfolio size and values from the customer's active holdings
+	// and re-evaluates the tier.
+	RefreshPortfolio(ctx context.Context, customerID uuid.UUID) (domain.Money, error)
+	// RefreshDue refreshes customers whose holdings started or ended since the last completed run,
+	// returning how many were refreshed.
+	RefreshDue(ctx context.Context) (int, error)
+}
+
+type ImportOptions struct {
+	DryRun bool
+	// ResumeRunID continues a failed or interrupted run from its cursor instead of starting over.
+	ResumeRunID *uuid.UUID
+	// Limit stops the run after this many source records; 0 means no limit.
+	Limit     int
+	StartedBy string
+}
+
+type CustomerNumberService interface {
+	// Next draws and formats a new customer number.
+	Next(ctx context.Context) (string, error)
+	// FindCustomerID looks a customer up by number, rejecting numbers with a wrong check digit.
+	FindCustomerID(ctx context.Context, number string) (uuid.UUID, error)
+	// Backfill numbers customers created before numbering was enabled and returns how many.
+	Backfill(ctx context.Context) (int, error)
+}
+
+type ExternalRefService interface {
+	// FindCustomerID resolves a reference in another system to the CIC customer.
+	FindCustomerID(ctx context.Context, system, key string) (uuid.UUID, error)
+	// Merge moves a customer's references to the customer it is merged into and deletes it.
+	Merge(ctx context.Context, fromCustomerID, toCustomerID, userID uuid.UUID) (int64, error)
+}
+
+type CASImportService interface {
+	// Run imports synchronously and returns the finished run.
+	Run(ctx context.Context, opts ImportOptions) (*domain.ImportRun, error)
+	// Start creates the run and imports in the background, returning the run as started.
+	Start(ctx context.Context, opts ImportOptions) (*domain.ImportRun, error)
+	GetRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]*domain.ImportRun, error)
+	Rejects(ctx context.Context, runID uuid.UUID) ([]*domain.ImportReject, error)
+}
+
+// SegmentService manages saved customer filters. Owners and admins manage a segment; roles it
+// is shared with may view, evaluate and export it.
+type SegmentService interface {
+	Create(ctx context.Context, s *domain.Segment, u domain.SegmentUser) error
+	Get(ctx context.Context, id uuid.UUID, u domain.SegmentUser) (*domain.Segment, error)
+	// List returns the segments visible to u.
+	List(ctx context.Context, u domain.SegmentUser) ([]*domain.Segment, error)
+	Update(ctx context.Context, s *domain.Segment, u domain.SegmentUser) error
+	Delete(ctx context.Context, id uuid.UUID, u domain.SegmentUser) error
+	// Evaluate runs the segment's filter now with q's sort and paging.
+	Evaluate(ctx context.Context, id uuid.UUID, u domain.SegmentUser, q domain.CustomerQuery) (*domain.CustomerPage, error)
+	// Export returns every customer currently in the segment.
+	Export(ctx context.Context, id uuid.UUID, u domain.SegmentUser) ([]*domain.Customer, error)
+	// Materialize snapshots the segment's current members as a new run.
+	Materialize(ctx context.Context, id uuid.UUID, u domain.SegmentUser) (*domain.SegmentRun, error)
+	// MaterializeDue materializes every DAILY segment and returns how many ran.
+	MaterializeDue(ctx context.Context) (int, error)
+	Runs(ctx context.Context, id uuid.UUID, u domain.SegmentUser, limit, offset int) ([]*domain.SegmentRun, error)
+	// Changes compares membership at runID with fromRunID, or with the previous run when nil.
+	Changes(ctx context.Context, id, runID uuid.UUID, fromRunID *uuid.UUID, u domain.SegmentUser) (*domain.SegmentDiff, error)
 }
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+)
+
+// AccessGrantConfig controls how break-the-glass grants for protected customers are issued.
+type AccessGrantConfig struct {
+	// TTL is how long a grant stays valid once approved.
+	TTL time.Duration
+	// RequireApproval keeps new grants PENDING until the requester's supervisor approves them.
+	RequireApproval bool
+	// BypassRoles may read protected customers without a grant (their reads are still audited).
+	BypassRoles []string
+	// ApproverRoles may approve any pending grant, in addition to the requester's supervisor.
+	ApproverRoles []string
+}
+
+type accessGrantService struct {
+	grantRepo    ports.AccessGrantRepository
+	customerRepo ports.CustomerRepository
+	hierarchy    ports.HierarchyService
+	auditService AuditService
+	cfg          AccessGrantConfig
+	now          func() time.Time
+}
+
+func NewAccessGrantService(
+	gRepo ports.AccessGrantRepository,
+	cRepo ports.CustomerRepository,
+	hierarchy ports.HierarchyService,
+	audit AuditService,
+	cfg AccessGrantConfig,
+) *accessGrantService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Minute
+	}
+	return &accessGrantService{
+		grantRepo:    gRepo,
+		customerRepo: cRepo,
+		hierarchy:    hierarchy,
+		auditService: audit,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+func (s *accessGrantService) RequestAccess(ctx context.Context, customerID, userID uuid.UUID, reason string) (*domain.AccessGrant, error) {
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	c, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsHighValue {
+		return nil, fmt.Errorf("%w: customer is not protected, no access grant is needed", domain.ErrConflict)
+	}
+
+	g := &domain.AccessGrant{
+		CustomerID:  customerID,
+		RequestedBy: userID,
+		Reason:      reason,
+		Status:      domain.GrantPending,
+	}
+	if !s.cfg.RequireApproval {
+		s.approve(g, nil)
+	}
+
+	if err := s.grantRepo.Create(ctx, g); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "ACCESS_GRANT_REQUEST", userID.String(),
+		fmt.Sprintf("grant=%s status=%s reason=%s", g.ID, g.Status, reason), "")
+	return g, nil
+}
+
+func (s *accessGrantService) ApproveAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error) {
+	g, err := s.pendingGrantFor(ctx, grantID, approverID, approverRole)
+	if err != nil {
+		return nil, err
+	}
+
+	s.approve(g, &approverID)
+	if err := s.grantRepo.Update(ctx, g, domain.GrantPending); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, g.CustomerID, "CUSTOMER", "ACCESS_GRANT_APPROVE", approverID.String(),
+		fmt.Sprintf("grant=%s requested_by=%s expires_at=%s", g.ID, g.RequestedBy, g.ExpiresAt.Format(time.RFC3339)), "")
+	return g, nil
+}
+
+func (s *accessGrantService) RejectAccess(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error) {
+	g, err := s.pendingGrantFor(ctx, grantID, approverID, approverRole)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	g.Status = domain.GrantRejected
+	g.ApprovedBy = &approverID
+	g.ApprovedAt = &now
+	if err := s.grantRepo.Update(ctx, g, domain.GrantPending); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, g.CustomerID, "CUSTOMER", "ACCESS_GRANT_REJECT", approverID.String(),
+		fmt.Sprintf("grant=%s requested_by=%s", g.ID, g.RequestedBy), "")
+	return g, nil
+}
+
+// CheckAccess allows reads of ordinary customers unconditionally. Reads of high-value customers
+// require a bypass role or an active grant, and every such read is audited.
+func (s *accessGrantService) CheckAccess(ctx context.Context, customerID, userID uuid.UUID, role string) error {
+	c, err := s.customerRepo.GetByID(ctx, customerID)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Let the downstream handler report the missing customer.
+		return nil
+	}
+	if err != nil {
+		// Anything else fails closed: a protected customer must not be readable unchecked.
+		return err
+	}
+	if !c.IsHighValue {
+		return nil
+	}
+
+	if hasRole(s.cfg.BypassRoles, role) {
+		s.auditService.Log(ctx, customerID, "CUSTOMER", "PROTECTED_READ", userID.String(), "role="+role, "")
+		return nil
+	}
+
+	g, err := s.grantRepo.FindActive(ctx, customerID, userID)
+	if err != nil {
+		return err
+	}
+	if g == nil || !g.IsActive(s.now()) {
+		s.auditService.Log(ctx, customerID, "CUSTOMER", "ACCESS_GRANT_DENIED", userID.String(), "no active grant", "")
+		return domain.ErrAccessGrantRequired
+	}
+
+	if err := s.grantRepo.RecordUse(ctx, g.ID); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "ACCESS_GRANT_USE", userID.String(), "grant="+g.ID.String(), "")
+	return nil
+}
+
+// CanRead reports whether the user may see c in full, without auditing or using up a grant.
+// Lists and searches use it to withhold protected customers instead of refusing the page.
+func (s *accessGrantService) CanRead(ctx context.Context, c *domain.Customer, userID uuid.UUID, role string) (bool, error) {
+	if !c.IsHighValue || hasRole(s.cfg.BypassRoles, role) {
+		return true, nil
+	}
+	g, err := s.grantRepo.FindActive(ctx, c.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return g != nil && g.IsActive(s.now()), nil
+}
+
+func (s *accessGrantService) ListGrants(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error) {
+	return s.grantRepo.List(ctx, customerID, userID, limit, offset)
+}
+
+func (s *accessGrantService) approve(g *domain.AccessGrant, approverID *uuid.UUID) {
+	now := s.now()
+	expires := now.Add(s.cfg.TTL)
+	g.Status = domain.GrantApproved
+	g.ApprovedBy = approverID
+	g.ApprovedAt = &now
+	g.ExpiresAt = &expires
+}
+
+// pendingGrantFor loads a pending grant and verifies the approver is one of the requester's
+// supervisors (or their delegate) or holds an approver role. Nobody may decide on their own request.
+func (s *accessGrantService) pendingGrantFor(ctx context.Context, grantID, approverID uuid.UUID, approverRole string) (*domain.AccessGrant, error) {
+	g, err := s.grantRepo.GetByID(ctx, grantID)
+	if err != nil {
+		return nil, err
+	}
+	if g.Status != domain.GrantPending {
+		return nil, fmt.Errorf("access grant is already %s", g.Status)
+	}
+	if g.RequestedBy == approverID {
+		return nil, fmt.Errorf("%w: cannot decide on your own access request", domain.ErrForbidden)
+	}
+	if hasRole(s.cfg.ApproverRoles, approverRole) {
+		return g, nil
+	}
+
+	allowed, err := s.hierarchy.CanActFor(ctx, approverID, g.RequestedBy, models.DelegationScopeAccessGrant)
+	if err != nil {
+		return nil, errors.New("failed to verify requester identity")
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: only the requester's supervisor can decide on this request", domain.ErrForbidden)
+	}
+	return g, nil
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+)
+
+// Mock AccessGrantRepository
+type mockAccessGrantRepo struct {
+	grants map[uuid.UUID]*domain.AccessGrant
+	uses   int
+}
+
+func newMockAccessGrantRepo() *mockAccessGrantRepo {
+	return &mockAccessGrantRepo{grants: map[uuid.UUID]*domain.AccessGrant{}}
+}
+
+// The mock stores copies, so a decision only lands through Update, as with the database.
+func (m *mockAccessGrantRepo) Create(ctx context.Context, g *domain.AccessGrant) error {
+	g.ID = uuid.New()
+	stored := *g
+	m.grants[g.ID] = &stored
+	return nil
+}
+func (m *mockAccessGrantRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error) {
+	g, ok := m.grants[id]
+	if !ok {
+		return nil, errors.New("access grant not found")
+	}
+	loaded := *g
+	return &loaded, nil
+}
+func (m *mockAccessGrantRepo) Update(ctx context.Context, g *domain.AccessGrant, from domain.AccessGrantStatus) error {
+	if m.grants[g.ID].Status != from {
+		return domain.ErrConflict
+	}
+	stored := *g
+	m.grants[g.ID] = &stored
+	return nil
+}
+func (m *mockAccessGrantRepo) FindActive(ctx context.Context, customerID, userID uuid.UUID) (*domain.AccessGrant, error) {
+	for _, g := range m.grants {
+		if g.CustomerID == customerID && g.RequestedBy == userID && g.IsActive(time.Now()) {
+			return g, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockAccessGrantRepo) RecordUse(ctx context.Context, id uuid.UUID) error {
+	m.uses++
+	return nil
+}
+func (m *mockAccessGrantRepo) List(ctx context.Context, customerID, userID *uuid.UUID, limit, offset int) ([]*domain.AccessGrant, error) {
+	return nil, nil
+}
+
+func highValueRepo(highValue bool) *mockCustomerRepo {
+	return &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id, IsHighValue: highValue}, nil
+		},
+	}
+}
+
+func TestCheckAccess_OrdinaryCustomer(t *testing.T) {
+	grants := newMockAccessGrantRepo()
+	svc := NewAccessGrantService(grants, highValueRepo(false), nil, &mockAuditService{}, AccessGrantConfig{})
+
+	if err := svc.CheckAccess(context.Background(), uuid.New(), uuid.New(), "OPERATOR"); err != nil {
+		t.Errorf("Expected access to ordinary customer, got %v", err)
+	}
+	if _, err := svc.RequestAccess(context.Background(), uuid.New(), uuid.New(), "curious"); !errors.Is(err, domain.ErrConflict) || len(grants.grants) != 0 {
+		t.Errorf("Expected a request for an ordinary customer to be refused, got %v", err)
+	}
+}
+
+func TestCheckAccess_ProtectedCustomerRequiresGrant(t *testing.T) {
+	grants := newMockAccessGrantRepo()
+	svc := NewAccessGrantService(grants, highValueRepo(true), nil, &mockAuditService{}, AccessGrantConfig{})
+	cid, uid := uuid.New(), uuid.New()
+
+	err := svc.CheckAccess(context.Background(), cid, uid, "OPERATOR")
+	if !errors.Is(err, domain.ErrAccessGrantRequired) {
+		t.Fatalf("Expected ErrAccessGrantRequired, got %v", err)
+	}
+
+	if _, err := svc.RequestAccess(context.Background(), cid, uid, "customer called about claim"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := svc.CheckAccess(context.Background(), cid, uid, "OPERATOR"); err != nil {
+		t.Errorf("Expected access with active grant, got %v", err)
+	}
+	if grants.uses != 1 {
+		t.Errorf("Expected grant use to be recorded, got %d", grants.uses)
+	}
+}
+
+func TestCheckAccess_BypassRole(t *testing.T) {
+	var actions []string
+	audit := &mockAuditService{
+		logFunc: func(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ip string) {
+			actions = append(actions, action)
+		},
+	}
+	svc := NewAccessGrantService(newMockAccessGrantRepo(), highValueRepo(true), nil, audit, AccessGrantConfig{BypassRoles: []string{"ADMIN"}})
+
+	if err := svc.CheckAccess(context.Background(), uuid.New(), uuid.New(), "ADMIN"); err != nil {
+		t.Errorf("Expected ADMIN to bypass, got %v", err)
+	}
+	if len(actions) != 1 || actions[0] != "PROTECTED_READ" {
+		t.Errorf("Expected bypass read to be audited, got %v", actions)
+	}
+}
+
+func TestApproveAccess_SupervisorOnly(t *testing.T) {
+	grants := newMockAccessGrantRepo()
+	requester, supervisor, other := uuid.New(), uuid.New(), uuid.New()
+	users := &mockUserRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, SupervisorID: &supervisor, IsActive: true}, nil
+		},
+	}
+	hierarchy := NewHierarchyService(users, nil, &mockDelegationRepo{}, &mockAuditService{}, 1)
+	svc := NewAccessGrantService(grants, highValueRepo(true), hierarchy, &mockAuditService{}, AccessGrantConfig{RequireApproval: true})
+
+	g, err := svc.RequestAccess(context.Background(), uuid.New(), requester, "complaint follow-up")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.Status != domain.GrantPending {
+		t.Fatalf("Expected PENDING grant, got %s", g.Status)
+	}
+
+	if _, err := svc.ApproveAccess(context.Background(), g.ID, requester, "OPERATOR"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected requester self-approval to be forbidden, got %v", err)
+	}
+	if _, err := svc.ApproveAccess(context.Background(), g.ID, other, "OPERATOR"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected non-supervisor approval to be forbidden, got %v", err)
+	}
+
+	g, err = svc.ApproveAccess(context.Background(), g.ID, supervisor, "OPERATOR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !g.IsActive(time.Now()) {
+		t.Errorf("Expected approved grant to be active")
+	}
+}
+
+func TestCanRead_DoesNotAuditOrUseGrant(t *testing.T) {
+	var actions []string
+	audit := &mockAuditService{
+		logFunc: func(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ip string) {
+			actions = append(actions, action)
+		},
+	}
+	grants := newMockAccessGrantRepo()
+	svc := NewAccessGrantService(grants, highValueRepo(true), nil, audit, AccessGrantConfig{BypassRoles: []string{"ADMIN"}})
+	ctx := context.Background()
+	protected := &domain.Customer{ID: uuid.New(), IsHighValue: true}
+	uid := uuid.New()
+
+	if ok, err := svc.CanRead(ctx, &domain.Customer{ID: uuid.New()}, uid, "OPERATOR"); !ok || err != nil {
+		t.Errorf("Expected ordinary customers to be readable, got %v, %v", ok, err)
+	}
+	if ok, _ := svc.CanRead(ctx, protected, uid, "OPERATOR"); ok {
+		t.Error("Expected a protected customer to be withheld without a grant")
+	}
+	if ok, _ := svc.CanRead(ctx, protected, uid, "ADMIN"); !ok {
+		t.Error("Expected ADMIN to bypass")
+	}
+
+	if _, err := svc.RequestAccess(ctx, protected.ID, uid, "customer called about claim"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	actions = nil
+	if ok, _ := svc.CanRead(ctx, protected, uid, "OPERATOR"); !ok {
+		t.Error("Expected an active grant to allow reading")
+	}
+	if grants.uses != 0 || len(actions) != 0 {
+		t.Errorf("Expected no grant use or audit, got %d uses, %v", grants.uses, actions)
+	}
+}
+
+func TestCheckAccess_FailsClosedOnLookupError(t *testing.T) {
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	svc := NewAccessGrantService(newMockAccessGrantRepo(), customers, nil, &mockAuditService{}, AccessGrantConfig{})
+
+	if err := svc.CheckAccess(context.Background(), uuid.New(), uuid.New(), "OPERATOR"); err == nil {
+		t.Error("Expected a lookup failure to deny access")
+	}
+
+	customers.getByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+		return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	if err := svc.CheckAccess(context.Background(), uuid.New(), uuid.New(), "OPERATOR"); err != nil {
+		t.Errorf("Expected a missing customer to be left to the handler, got %v", err)
+	}
+}
+
+// staleGrantRepo reads every grant as still pending, as a second approver racing the first would.
+type staleGrantRepo struct {
+	*mockAccessGrantRepo
+}
+
+func (m staleGrantRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error) {
+	g, err := m.mockAccessGrantRepo.GetByID(ctx, id)
+	if err == nil {
+		g.Status = domain.GrantPending
+	}
+	return g, err
+}
+
+func TestDecideAccess_OnlyOneDecisionWins(t *testing.T) {
+	grants := newMockAccessGrantRepo()
+	svc := NewAccessGrantService(staleGrantRepo{grants}, highValueRepo(true), nil, &mockAuditService{},
+		AccessGrantConfig{RequireApproval: true, ApproverRoles: []string{"ADMIN"}})
+
+	g, err := svc.RequestAccess(context.Background(), uuid.New(), uuid.New(), "complaint follow-up")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := svc.ApproveAccess(context.Background(), g.ID, uuid.New(), "ADMIN"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := svc.RejectAccess(context.Background(), g.ID, uuid.New(), "ADMIN"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected the second decision to conflict, got %v", err)
+	}
+	if grants.grants[g.ID].Status != domain.GrantApproved {
+		t.Errorf("Expected the first decision to stand, got %s", grants.grants[g.ID].Status)
+	}
+}
+
+func TestApproveAccess_SelfIssuedDelegationCannotApprove(t *testing.T) {
+	grants := newMockAccessGrantRepo()
+	requester, supervisor, peer := uuid.New(), uuid.New(), uuid.New()
+	users := orgChart(map[uuid.UUID]uuid.UUID{requester: supervisor, peer: supervisor})
+	delegations := &mockDelegationRepo{}
+	hierarchy := NewHierarchyService(users, nil, delegations, &mockAuditService{}, 1)
+	svc := NewAccessGrantService(grants, highValueRepo(true), hierarchy, &mockAuditService{}, AccessGrantConfig{RequireApproval: true})
+
+	// The requester hands a peer their own access-grant rights...
+	if err := hierarchy.Delegate(context.Background(), &models.Delegation{
+		DelegatorID: requester,
+		DelegateID:  peer,
+		Scope:       models.DelegationScopeAccessGrant,
+		EndsAt:      time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	g, err := svc.RequestAccess(context.Background(), uuid.New(), requester, "complaint follow-up")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// ...which must not let the peer approve the requester's own request.
+	if _, err := svc.ApproveAccess(context.Background(), g.ID, peer, "OPERATOR"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected approval through a self-issued delegation to be forbidden, got %v", err)
+	}
+
+	// A delegation from the supervisor still works.
+	if err := hierarchy.Delegate(context.Background(), &models.Delegation{
+		DelegatorID: supervisor,
+		DelegateID:  peer,
+		Scope:       models.DelegationScopeAccessGrant,
+		EndsAt:      time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := svc.ApproveAccess(context.Background(), g.ID, peer, "OPERATOR"); err != nil {
+		t.Errorf("Expected the supervisor's delegate to approve, got %v", err)
+	}
+}
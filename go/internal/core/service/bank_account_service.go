@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/utils/validation"
+	"github.com/google/uuid"
+)
+
+type bankAccountService struct {
+	repo         ports.BankAccountRepository
+	customerRepo ports.CustomerRepository
+	auditService AuditService
+}
+
+func NewBankAccountService(repo ports.BankAccountRepository, customerRepo ports.CustomerRepository, audit AuditService) *bankAccountService {
+	return &bankAccountService{repo: repo, customerRepo: customerRepo, auditService: audit}
+}
+
+func (s *bankAccountService) Add(ctx context.Context, b *domain.BankAccount, userID uuid.UUID) error {
+	if err := normalizeBankAccount(b); err != nil {
+		return err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, b.CustomerID); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, b); err != nil {
+		return err
+	}
+	b.Mask()
+	s.auditService.Log(ctx, b.CustomerID, "CUSTOMER", "BANK_ACCOUNT_ADD", userID.String(), bankAccountChanges(b), "")
+	return nil
+}
+
+func (s *bankAccountService) List(ctx context.Context, customerID uuid.UUID) ([]*domain.BankAccount, error) {
+	accounts, err := s.repo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range accounts {
+		b.Mask()
+	}
+	return accounts, nil
+}
+
+// Update replaces the account's details. An empty or masked account number keeps the stored one,
+// so a client can send back what it read.
+func (s *bankAccountService) Update(ctx context.Context, b *domain.BankAccount, userID uuid.UUID) error {
+	existing, err := s.get(ctx, b.CustomerID, b.ID)
+	if err != nil {
+		return err
+	}
+	if b.AccountNumber == "" || strings.Contains(strings.ToUpper(b.AccountNumber), "X") {
+		b.AccountNumber = existing.AccountNumber
+	}
+	if err := normalizeBankAccount(b); err != nil {
+		return err
+	}
+	b.CreatedAt = existing.CreatedAt
+
+	if err := s.repo.Update(ctx, b); err != nil {
+		return err
+	}
+	b.Mask()
+	s.auditService.Log(ctx, b.CustomerID, "CUSTOMER", "BANK_ACCOUNT_UPDATE", userID.String(), bankAccountChanges(b), "")
+	return nil
+}
+
+func (s *bankAccountService) Remove(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	b, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	b.Mask()
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "BANK_ACCOUNT_REMOVE", userID.String(), bankAccountChanges(b), "")
+	return nil
+}
+
+func (s *bankAccountService) Reveal(ctx context.Context, customerID, id uuid.UUID, performedBy string) (*domain.BankAccount, error) {
+	b, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "BANK_ACCOUNT_REVEAL", performedBy, fmt.Sprintf("bank_account=%s", b.ID), "")
+	return b, nil
+}
+
+func (s *bankAccountService) get(ctx context.Context, customerID, id uuid.UUID) (*domain.BankAccount, error) {
+	b, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.CustomerID != customerID {
+		return nil, fmt.Errorf("bank account %w", domain.ErrNotFound)
+	}
+	return b, nil
+}
+
+// normalizeBankAccount upper-cases the type and purpose, fills BankName and validates the account number for the bank.
+func normalizeBankAccount(b *domain.BankAccount) error {
+	b.BankCode = strings.TrimSpace(b.BankCode)
+	name, ok := domain.ThaiBanks[b.BankCode]
+	if !ok {
+		return fmt.Errorf("unknown bank code %q", b.BankCode)
+	}
+	b.BankName = name
+
+	number, err := validation.NormalizeBankAccountNumber(b.BankCode, b.AccountNumber)
+	if err != nil {
+		return err
+	}
+	b.AccountNumber = number
+
+	b.AccountType = domain.BankAccountType(strings.ToUpper(strings.TrimSpace(string(b.AccountType))))
+	switch b.AccountType {
+	case domain.BankAccountSavings, domain.BankAccountCurrent, domain.BankAccountFixedDeposit:
+	default:
+		return fmt.Errorf("unknown account type %q", b.AccountType)
+	}
+	b.Purpose = domain.BankAccountPurpose(strings.ToUpper(strings.TrimSpace(string(b.Purpose))))
+	switch b.Purpose {
+	case domain.BankAccountPayout, domain.BankAccountDirectDebit:
+	default:
+		return fmt.Errorf("unknown purpose %q", b.Purpose)
+	}
+	b.Branch = strings.TrimSpace(b.Branch)
+	b.AccountName = strings.TrimSpace(b.AccountName)
+	return nil
+}
+
+// bankAccountChanges describes an account for the audit log; b must already be masked.
+func bankAccountChanges(b *domain.BankAccount) string {
+	return fmt.Sprintf("bank_account=%s bank=%s account=%s purpose=%s", b.ID, b.BankName, b.AccountNumber, b.Purpose)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockBankAccountRepo struct {
+	rows map[uuid.UUID]*domain.BankAccount
+}
+
+func (m *mockBankAccountRepo) Create(ctx context.Context, b *domain.BankAccount) error {
+	b.ID = uuid.New()
+	b2 := *b
+	m.rows[b.ID] = &b2
+	return nil
+}
+func (m *mockBankAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.BankAccount, error) {
+	b, ok := m.rows[id]
+	if !ok {
+		return nil, fmt.Errorf("bank account %w", domain.ErrNotFound)
+	}
+	b2 := *b
+	return &b2, nil
+}
+func (m *mockBankAccountRepo) Update(ctx context.Context, b *domain.BankAccount) error {
+	b2 := *b
+	m.rows[b.ID] = &b2
+	return nil
+}
+func (m *mockBankAccountRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.rows, id)
+	return nil
+}
+func (m *mockBankAccountRepo) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.BankAccount, error) {
+	var out []*domain.BankAccount
+	for _, b := range m.rows {
+		if b.CustomerID == customerID {
+			b2 := *b
+			out = append(out, &b2)
+		}
+	}
+	return out, nil
+}
+
+func TestBankAccountService_ValidatesAndMasks(t *testing.T) {
+	customerID := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	repo := &mockBankAccountRepo{rows: map[uuid.UUID]*domain.BankAccount{}}
+	var logged []string
+	audit := &mockAuditService{logFunc: func(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ip string) {
+		logged = append(logged, action+" "+performedBy+" "+changes)
+	}}
+	svc := NewBankAccountService(repo, customers, audit)
+	ctx := context.Background()
+
+	invalid := []domain.BankAccount{
+		{BankCode: "999", AccountNumber: "1234567890", AccountType: "savings", Purpose: "payout"},
+		{BankCode: "014", AccountNumber: "123-4-5678", AccountType: "savings", Purpose: "payout"},
+		{BankCode: "030", AccountNumber: "1234567890", AccountType: "savings", Purpose: "payout"},
+		{BankCode: "014", AccountNumber: "12345678AB", AccountType: "savings", Purpose: "payout"},
+		{BankCode: "014", AccountNumber: "1234567890", AccountType: "loan", Purpose: "payout"},
+		{BankCode: "014", AccountNumber: "1234567890", AccountType: "savings", Purpose: "refund"},
+	}
+	for _, b := range invalid {
+		b.CustomerID = customerID
+		if err := svc.Add(ctx, &b, uuid.New()); err == nil {
+			t.Errorf("Add(%s %s %s %s): expected error", b.BankCode, b.AccountNumber, b.AccountType, b.Purpose)
+		}
+	}
+
+	b := &domain.BankAccount{CustomerID: customerID, BankCode: "014", AccountNumber: "123-4-56789-0", AccountType: "savings", Purpose: "payout", IsPrimary: true}
+	if err := svc.Add(ctx, b, uuid.New()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if b.BankName != "SCB" || b.AccountNumber != "XXXXXX7890" || repo.rows[b.ID].AccountNumber != "1234567890" {
+		t.Errorf("Expected SCB, masked response and stored digits, got %s %s/%s", b.BankName, b.AccountNumber, repo.rows[b.ID].AccountNumber)
+	}
+
+	list, _ := svc.List(ctx, customerID)
+	if len(list) != 1 || list[0].AccountNumber != "XXXXXX7890" {
+		t.Errorf("Expected masked account in list, got %+v", list)
+	}
+
+	// Sending the masked number back keeps the stored one.
+	update := *list[0]
+	update.Branch = "Siam Square"
+	if err := svc.Update(ctx, &update, uuid.New()); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if repo.rows[b.ID].AccountNumber != "1234567890" || repo.rows[b.ID].Branch != "Siam Square" {
+		t.Errorf("Expected number kept and branch updated, got %+v", repo.rows[b.ID])
+	}
+
+	if _, err := svc.Reveal(ctx, uuid.New(), b.ID, "someone"); err == nil {
+		t.Error("Expected account to be hidden under another customer")
+	}
+	revealed, err := svc.Reveal(ctx, customerID, b.ID, "officer-1")
+	if err != nil || revealed.AccountNumber != "1234567890" {
+		t.Fatalf("Expected full number from Reveal, got %+v, %v", revealed, err)
+	}
+
+	for _, l := range logged {
+		if strings.Contains(l, "1234567890") {
+			t.Errorf("Audit entry leaks the account number: %s", l)
+		}
+	}
+	if last := logged[len(logged)-1]; !strings.HasPrefix(last, "BANK_ACCOUNT_REVEAL officer-1") {
+		t.Errorf("Expected reveal to be audited against the caller, got %q", last)
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/utils/validation"
+	"github.com/google/uuid"
+)
+
+const casImportBatchSize = 500
+
+// CASIDTypes maps CAS id_typ codes to CIC identity types. Clients with an id_num under any
+// other code are rejected rather than guessed at.
+var CASIDTypes = map[string]string{
+	"I": "National ID",
+	"P": "Passport",
+	"T": "Tax ID",
+}
+
+// Name prefixes CAS sometimes stores in cli_nm instead of cli_title.
+var casNameTitles = []string{"นางสาว", "นาง", "นาย", "ด.ช.", "ด.ญ.", "MR.", "MRS.", "MS.", "MISS", "DR."}
+
+// Markers of a company rather than a person in cli_nm.
+var casJuristicMarkers = []string{"บริษัท", "ห้างหุ้นส่วน", "หจก.", "บจก.", "CO.,LTD", "CO., LTD", "COMPANY", "LIMITED", "CORPORATION", "PCL"}
+
+type casImportService struct {
+	source       ports.CASClientSource
+	repo         ports.ImportRepository
+	refs         ports.ExternalRefRepository
+	numbers      ports.CustomerNumberService
+	tiers        ports.TierService
+	auditService AuditService
+	batchSize    int
+	now          func() time.Time
+}
+
+// NewCASImportService imports from source, which may be nil when no CAS database is configured.
+// Imported customers are numbered by numbers and tiered by tiers unless they are nil.
+func NewCASImportService(source ports.CASClientSource, repo ports.ImportRepository, refs ports.ExternalRefRepository, numbers ports.CustomerNumberService, tiers ports.TierService, audit AuditService) *casImportService {
+	return &casImportService{
+		source:       source,
+		repo:         repo,
+		refs:         refs,
+		numbers:      numbers,
+		tiers:        tiers,
+		auditService: audit,
+		batchSize:    casImportBatchSize,
+		now:          time.Now,
+	}
+}
+
+// Run imports clients in cli_num order, checkpointing the run after every batch. Clients that
+// already have a CAS reference are skipped, so re-running or resuming never duplicates a
+// customer. A dry run maps and validates everything and records rejects, but writes no customers.
+func (s *casImportService) Run(ctx context.Context, opts ports.ImportOptions) (*domain.ImportRun, error) {
+	run, unlock, err := s.begin(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return run, s.process(ctx, run, opts.Limit, unlock)
+}
+
+func (s *casImportService) Start(ctx context.Context, opts ports.ImportOptions) (*domain.ImportRun, error) {
+	run, unlock, err := s.begin(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
+	go s.process(context.Background(), run, opts.Limit, unlock)
+	return &started, nil
+}
+
+func (s *casImportService) GetRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	return s.repo.GetRun(ctx, id)
+}
+
+func (s *casImportService) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ImportRun, error) {
+	return s.repo.ListRuns(ctx, limit, offset)
+}
+
+func (s *casImportService) Rejects(ctx context.Context, runID uuid.UUID) ([]*domain.ImportReject, error) {
+	if _, err := s.repo.GetRun(ctx, runID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRejects(ctx, runID)
+}
+
+// begin takes the import lock and creates or reopens the run. The lock is held in the database,
+// so only one CAS run executes at a time across every API instance and cas-import command; the
+// returned unlock releases it.
+func (s *casImportService) begin(ctx context.Context, opts ports.ImportOptions) (run *domain.ImportRun, unlock func(), err error) {
+	if s.source == nil {
+		return nil, nil, errors.New("CAS import is not configured")
+	}
+	release, err := s.repo.Lock(ctx, domain.ExternalSystemCAS)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	if opts.ResumeRunID != nil {
+		if run, err = s.repo.GetRun(ctx, *opts.ResumeRunID); err != nil {
+			return nil, nil, err
+		}
+		if run.Source != domain.ExternalSystemCAS || run.Status == domain.ImportCompleted {
+			return nil, nil, fmt.Errorf("%w: run %s cannot be resumed", domain.ErrConflict, run.ID)
+		}
+		run.Status, run.Error, run.FinishedAt = domain.ImportRunning, "", nil
+		if err := s.repo.UpdateRun(ctx, run); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		run = &domain.ImportRun{
+			Source:    domain.ExternalSystemCAS,
+			DryRun:    opts.DryRun,
+			Status:    domain.ImportRunning,
+			StartedBy: opts.StartedBy,
+		}
+		if err := s.repo.CreateRun(ctx, run); err != nil {
+			return nil, nil, err
+		}
+	}
+	return run, release, nil
+}
+
+func (s *casImportService) process(ctx context.Context, run *domain.ImportRun, limit int, unlock func()) (err error) {
+	defer unlock()
+	defer func() {
+		now := s.now()
+		run.FinishedAt = &now
+		run.Status = domain.ImportCompleted
+		if err != nil {
+			run.Status, run.Error = domain.ImportFailed, err.Error()
+		}
+		// The caller's context may be what stopped the run; record the outcome regardless.
+		if uerr := s.repo.UpdateRun(context.Background(), run); uerr != nil && err == nil {
+			err = uerr
+		}
+	}()
+
+	for done := 0; limit <= 0 || done < limit; {
+		n := s.batchSize
+		if limit > 0 && limit-done < n {
+			n = limit - done
+		}
+		clients, err := s.source.Clients(ctx, run.Cursor, n)
+		if err != nil {
+			return err
+		}
+		if len(clients) == 0 {
+			return nil
+		}
+		for _, c := range clients {
+			if err := s.importClient(ctx, run, c); err != nil {
+				return fmt.Errorf("cli_num %s: %w", c.CliNum, err)
+			}
+			run.Cursor = c.CliNum
+			run.Processed++
+			done++
+		}
+		if err := s.repo.UpdateRun(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importClient handles one client. Only infrastructure failures are returned; bad data is rejected.
+func (s *casImportService) importClient(ctx context.Context, run *domain.ImportRun, c *domain.CASClient) error {
+	if ref, err := s.refs.Find(ctx, domain.ExternalSystemCAS, c.CliNum); err != nil {
+		return err
+	} else if ref != nil {
+		run.Skipped++
+		return nil
+	}
+
+	mapped, reasons := mapCASClient(c, s.now())
+	if len(reasons) > 0 {
+		return s.reject(ctx, run, c.CliNum, strings.Join(reasons, "; "))
+	}
+	var change *domain.TierChange
+	if s.tiers != nil {
+		var err error
+		if change, err = s.tiers.Assign(ctx, mapped.Customer, nil, domain.TierSourceImport); err != nil {
+			return err
+		}
+	}
+	if run.DryRun {
+		run.Imported++
+		return nil
+	}
+
+	if s.numbers != nil {
+		number, err := s.numbers.Next(ctx)
+		if err != nil {
+			return err
+		}
+		mapped.Customer.CustomerNumber = number
+	}
+	err := s.repo.SaveClient(ctx, mapped)
+	if errors.Is(err, domain.ErrIdentityConflict) {
+		return s.reject(ctx, run, c.CliNum, err.Error())
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		// Another run may have imported the client since the check above.
+		if ref, ferr := s.refs.Find(ctx, domain.ExternalSystemCAS, c.CliNum); ferr == nil && ref != nil {
+			run.Skipped++
+			return nil
+		}
+		return s.reject(ctx, run, c.CliNum, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	run.Imported++
+	s.auditService.Log(ctx, mapped.Customer.ID, "CUSTOMER", "IMPORT", run.StartedBy,
+		fmt.Sprintf("CAS cli_num=%s run=%s", c.CliNum, run.ID), "")
+	if change != nil {
+		// The customer row is the source of truth; history is logged rather than failing the run.
+		change.CustomerID = mapped.Customer.ID
+		if err := s.tiers.RecordChange(ctx, change); err != nil {
+			log.Printf("tier history for customer %s: %v", mapped.Customer.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *casImportService) reject(ctx context.Context, run *domain.ImportRun, key, reason string) error {
+	run.Rejected++
+	return s.repo.AddReject(ctx, &domain.ImportReject{RunID: run.ID, SourceKey: key, Reason: reason})
+}
+
+// mapCASClient maps a CAS client onto CIC, returning every reason it cannot be imported.
+func mapCASClient(c *domain.CASClient, now time.Time) (*domain.ImportedClient, []string) {
+	var reasons []string
+	name := strings.Join(strings.Fields(c.Name), " ")
+	if name == "" {
+		reasons = append(reasons, "cli_nm is empty")
+	}
+
+	cust := &domain.Customer{Type: domain.TypePersonal, Status: domain.StatusActive, Title: c.Title, Nationality: c.Nationality}
+	if isJuristicName(name) {
+		cust.Type = domain.TypeJuristic
+		cust.CompanyName = name
+	} else {
+		cust.FirstName, cust.LastName, cust.Title = splitCASName(name, c.Title)
+	}
+	if c.BirthDate != nil {
+		if c.BirthDate.After(now) {
+			reasons = append(reasons, fmt.Sprintf("birth_dt %s is in the future", c.BirthDate.Format("2006-01-02")))
+		} else if cust.Type == domain.TypeJuristic {
+			cust.RegistrationDate = *c.BirthDate
+		} else {
+			cust.DateOfBirth = *c.BirthDate
+		}
+	}
+
+	var identities []*domain.Identity
+	if c.IDNumber != "" {
+		idType, ok := CASIDTypes[strings.ToUpper(c.IDType)]
+		number := casIDNumber(c.IDNumber)
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("unknown id_typ %q", c.IDType))
+		case idType == "National ID" || idType == "Tax ID":
+			if err := validation.ValidateThaiID(number); err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s %s: %v", strings.ToLower(idType), c.IDNumber, err))
+			}
+			identities = append(identities, &domain.Identity{Type: idType, Number: number, IssuanceCountry: "Thailand"})
+		default:
+			// A passport is issued by the holder's country of nationality.
+			identities = append(identities, &domain.Identity{Type: idType, Number: number, IssuanceCountry: c.Nationality})
+		}
+	}
+	identities = append(identities, casPreviousIDs(c)...)
+
+	var addresses []*domain.Address
+	for _, a := range c.Addresses {
+		if a.Invalid || a.Lines[0] == "" {
+			continue
+		}
+		addresses = append(addresses, &domain.Address{
+			Type:         casAddressType(c, a.AddrType, cust.Type),
+			AddressLine1: a.Lines[0],
+			AddressLine2: strings.Join(nonEmpty(a.Lines[1], a.Lines[2], a.Lines[3]), ", "),
+			ZipCode:      a.ZipCode,
+			Country:      "Thailand",
+		})
+	}
+
+	if len(reasons) > 0 {
+		return nil, reasons
+	}
+	return &domain.ImportedClient{
+		Customer:   cust,
+		Addresses:  addresses,
+		Identities: identities,
+		Ref:        &domain.ExternalRef{System: domain.ExternalSystemCAS, Key: c.CliNum},
+	}, nil
+}
+
+// casPreviousIDs maps the numbers a client held before the current one to expired identities.
+// CAS logs no id_typ for them, so only numbers that pass the Thai ID check are imported, as a
+// Tax ID when the current id is one and as a National ID otherwise; anything else stays in CAS.
+func casPreviousIDs(c *domain.CASClient) []*domain.Identity {
+	idType := "National ID"
+	if strings.ToUpper(c.IDType) == "T" {
+		idType = "Tax ID"
+	}
+	seen := map[string]bool{casIDNumber(c.IDNumber): true}
+	var identities []*domain.Identity
+	for _, prev := range c.PreviousIDs {
+		number := casIDNumber(prev.Number)
+		if seen[number] || validation.ValidateThaiID(number) != nil {
+			continue
+		}
+		seen[number] = true
+		id := &domain.Identity{Type: idType, Number: number, IssuanceCountry: "Thailand"}
+		if prev.ExpiredAt != nil {
+			id.ExpiryDate = *prev.ExpiredAt
+		}
+		identities = append(identities, id)
+	}
+	return identities
+}
+
+func casIDNumber(n string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(n))
+}
+
+func isJuristicName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, m := range casJuristicMarkers {
+		if strings.Contains(upper, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCASName splits "first [middle] last" or "LAST, FIRST", moving a leading title into title
+// when CAS has none.
+func splitCASName(name, title string) (first, last, outTitle string) {
+	if strings.Contains(name, ",") {
+		parts := strings.SplitN(name, ",", 2)
+		last, name = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+	words := strings.Fields(name)
+	if len(words) > 1 {
+		for _, t := range casNameTitles {
+			if strings.EqualFold(words[0], t) {
+				if title == "" {
+					title = words[0]
+				}
+				words = words[1:]
+				break
+			}
+		}
+	}
+	if last == "" && len(words) > 1 {
+		last, words = words[len(words)-1], words[:len(words)-1]
+	}
+	return strings.Join(words, " "), last, title
+}
+
+// casAddressType names an address by which of the client's address pointers refers to it.
+func casAddressType(c *domain.CASClient, addrType int, customerType domain.CustomerType) string {
+	is := func(p *int) bool { return p != nil && *p == addrType }
+	switch {
+	case is(c.RegAddrType):
+		return "Registered"
+	case is(c.BillingAddrType):
+		return "Mailing"
+	case is(c.OfficeAddrType) && customerType == domain.TypeJuristic:
+		return "HQ"
+	case is(c.OfficeAddrType):
+		return "Office"
+	case is(c.ResAddrType):
+		return "Residential"
+	default:
+		return "Other"
+	}
+}
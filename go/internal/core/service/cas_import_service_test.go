@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type stubCASSource struct {
+	clients []*domain.CASClient // In cli_num order
+	failAt  string              // Fail a batch read starting after this cli_num, once
+}
+
+func (s *stubCASSource) Clients(ctx context.Context, after string, limit int) ([]*domain.CASClient, error) {
+	if s.failAt != "" && after == s.failAt {
+		s.failAt = ""
+		return nil, errors.New("connection reset")
+	}
+	var out []*domain.CASClient
+	for _, c := range s.clients {
+		if c.CliNum > after && len(out) < limit {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+type mockImportRepo struct {
+	runs    map[uuid.UUID]*domain.ImportRun
+	rejects map[uuid.UUID][]*domain.ImportReject
+	refs    *mockExternalRefRepo
+	saved   []*domain.ImportedClient
+	held    map[string]bool // Identity numbers belonging to other customers
+	locked  bool
+}
+
+func newMockImportRepo() *mockImportRepo {
+	return &mockImportRepo{runs: map[uuid.UUID]*domain.ImportRun{}, rejects: map[uuid.UUID][]*domain.ImportReject{}, refs: newMockExternalRefRepo()}
+}
+
+func (m *mockImportRepo) CreateRun(ctx context.Context, run *domain.ImportRun) error {
+	run.ID = uuid.New()
+	return m.UpdateRun(ctx, run)
+}
+func (m *mockImportRepo) UpdateRun(ctx context.Context, run *domain.ImportRun) error {
+	r := *run
+	m.runs[run.ID] = &r
+	return nil
+}
+func (m *mockImportRepo) GetRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	r, ok := m.runs[id]
+	if !ok {
+		return nil, errors.New("import run not found")
+	}
+	r2 := *r
+	return &r2, nil
+}
+func (m *mockImportRepo) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ImportRun, error) {
+	return nil, nil
+}
+func (m *mockImportRepo) AddReject(ctx context.Context, r *domain.ImportReject) error {
+	m.rejects[r.RunID] = append(m.rejects[r.RunID], r)
+	return nil
+}
+func (m *mockImportRepo) ListRejects(ctx context.Context, runID uuid.UUID) ([]*domain.ImportReject, error) {
+	return m.rejects[runID], nil
+}
+func (m *mockImportRepo) SaveClient(ctx context.Context, c *domain.ImportedClient) error {
+	for _, i := range c.Identities {
+		if m.held[i.Number] {
+			return fmt.Errorf("%w: %s %s", domain.ErrIdentityConflict, i.Type, i.Number)
+		}
+	}
+	c.Customer.ID = uuid.New()
+	c.Ref.CustomerID = c.Customer.ID
+	m.refs.rows[c.Ref.System+"/"+c.Ref.Key] = c.Ref
+	m.saved = append(m.saved, c)
+	return nil
+}
+func (m *mockImportRepo) Lock(ctx context.Context, source string) (func(), error) {
+	if m.locked {
+		return nil, fmt.Errorf("%w: an import from %s is already running", domain.ErrConflict, source)
+	}
+	m.locked = true
+	return func() { m.locked = false }, nil
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestMapCASClient(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := now.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name      string
+		client    domain.CASClient
+		wantType  domain.CustomerType
+		wantFirst string
+		wantLast  string
+		wantTitle string
+		wantErr   bool
+	}{
+		{"thai title in name", domain.CASClient{Name: "นาย สมชาย  ใจดี", IDType: "I", IDNumber: "1-1017-00207-03-0"},
+			domain.TypePersonal, "สมชาย", "ใจดี", "นาย", false},
+		{"surname first", domain.CASClient{Name: "SMITH, JOHN PAUL", Title: "MR.", IDType: "P", IDNumber: "aa123456"},
+			domain.TypePersonal, "JOHN PAUL", "SMITH", "MR.", false},
+		{"company", domain.CASClient{Name: "บริษัท ตัวอย่าง จำกัด", IDType: "T", IDNumber: "0105555123450"},
+			domain.TypeJuristic, "", "", "", false},
+		{"empty name", domain.CASClient{Name: "  "}, "", "", "", "", true},
+		{"bad national id", domain.CASClient{Name: "สมหญิง ใจดี", IDType: "I", IDNumber: "1101700207031"}, "", "", "", "", true},
+		{"unknown id type", domain.CASClient{Name: "สมหญิง ใจดี", IDType: "X", IDNumber: "123"}, "", "", "", "", true},
+		{"future birth date", domain.CASClient{Name: "สมหญิง ใจดี", BirthDate: &future}, "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		got, reasons := mapCASClient(&tt.client, now)
+		if tt.wantErr {
+			if len(reasons) == 0 {
+				t.Errorf("%s: expected reject", tt.name)
+			}
+			continue
+		}
+		if len(reasons) > 0 {
+			t.Errorf("%s: unexpected reject %v", tt.name, reasons)
+			continue
+		}
+		c := got.Customer
+		if c.Type != tt.wantType || c.FirstName != tt.wantFirst || c.LastName != tt.wantLast || c.Title != tt.wantTitle {
+			t.Errorf("%s: got %s %q %q %q", tt.name, c.Type, c.Title, c.FirstName, c.LastName)
+		}
+		if tt.wantType == domain.TypeJuristic && c.CompanyName != tt.client.Name {
+			t.Errorf("%s: expected company name %q, got %q", tt.name, tt.client.Name, c.CompanyName)
+		}
+	}
+
+	got, _ := mapCASClient(&domain.CASClient{
+		CliNum: "0000000001", Name: "สมชาย ใจดี", IDType: "I", IDNumber: "1101700207030",
+		RegAddrType: intPtr(1), BillingAddrType: intPtr(2),
+		Addresses: []domain.CASAddress{
+			{AddrType: 1, Lines: [4]string{"99/1 ถนนสุขุมวิท", "แขวงคลองเตย", "", "เขตคลองเตย"}, ZipCode: "10110"},
+			{AddrType: 2, Lines: [4]string{"1 ถนนสีลม"}, Invalid: true},
+			{AddrType: 3, Lines: [4]string{"2 ถนนพระราม 4"}},
+		},
+	}, now)
+	if got.Ref.System != domain.ExternalSystemCAS || got.Ref.Key != "0000000001" {
+		t.Errorf("Expected CAS ref, got %+v", got.Ref)
+	}
+	if len(got.Identities) != 1 || got.Identities[0].Type != "National ID" || got.Identities[0].IssuanceCountry != "Thailand" {
+		t.Errorf("Expected a Thai national ID, got %+v", got.Identities)
+	}
+	if len(got.Addresses) != 2 || got.Addresses[0].Type != "Registered" || got.Addresses[0].AddressLine2 != "แขวงคลองเตย, เขตคลองเตย" || got.Addresses[1].Type != "Other" {
+		t.Errorf("Expected registered and other addresses without the invalid one, got %+v %+v", got.Addresses[0], got.Addresses[1])
+	}
+
+	expired := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	got, _ = mapCASClient(&domain.CASClient{
+		CliNum: "0000000002", Name: "JOHN SMITH", IDType: "P", IDNumber: "AA123456", Nationality: "United Kingdom",
+		PreviousIDs: []domain.CASPreviousID{{Number: "1-1017-00207-03-0", ExpiredAt: &expired}, {Number: "ZZ999"}},
+	}, now)
+	if got.Customer.Nationality != "United Kingdom" || len(got.Identities) != 2 ||
+		got.Identities[0].IssuanceCountry != "United Kingdom" ||
+		got.Identities[1].Type != "National ID" || got.Identities[1].Number != "1101700207030" || !got.Identities[1].ExpiryDate.Equal(expired) {
+		t.Errorf("Expected a UK passport and the expired Thai ID without the untyped number, got %+v %+v", got.Customer, got.Identities)
+	}
+}
+
+func TestCASImportService_DryRunAndResume(t *testing.T) {
+	source := &stubCASSource{clients: []*domain.CASClient{
+		{CliNum: "0000000001", Name: "สมชาย ใจดี", IDType: "I", IDNumber: "1101700207030"},
+		{CliNum: "0000000002", Name: ""},
+		{CliNum: "0000000003", Name: "บริษัท ตัวอย่าง จำกัด"},
+	}}
+	repo := newMockImportRepo()
+	var audited int
+	svc := NewCASImportService(source, repo, repo.refs, nil, nil, &mockAuditService{
+		logFunc: func(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ip string) {
+			if action == "IMPORT" {
+				audited++
+			}
+		},
+	})
+	svc.batchSize = 1
+	ctx := context.Background()
+
+	run, err := svc.Run(ctx, ports.ImportOptions{DryRun: true})
+	if err != nil || run.Status != domain.ImportCompleted || run.Imported != 2 || run.Rejected != 1 || len(repo.saved) != 0 {
+		t.Fatalf("Dry run: expected 2 importable and 1 rejected with nothing saved, got %+v, %v (saved %d)", run, err, len(repo.saved))
+	}
+	if rejects := repo.rejects[run.ID]; len(rejects) != 1 || rejects[0].SourceKey != "0000000002" {
+		t.Errorf("Expected a reject for the nameless client, got %+v", rejects)
+	}
+
+	source.failAt = "0000000001"
+	run, err = svc.Run(ctx, ports.ImportOptions{})
+	if err == nil || run.Status != domain.ImportFailed || run.Cursor != "0000000001" || repo.runs[run.ID].Status != domain.ImportFailed {
+		t.Fatalf("Expected the run to fail after the first client, got %+v, %v", run, err)
+	}
+
+	if _, err := svc.Run(ctx, ports.ImportOptions{ResumeRunID: &run.ID}); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	resumed := repo.runs[run.ID]
+	if resumed.Status != domain.ImportCompleted || resumed.Processed != 3 || resumed.Imported != 2 || len(repo.saved) != 2 || audited != 2 {
+		t.Errorf("Expected the resumed run to finish with 2 customers, got %+v (saved %d, audited %d)", resumed, len(repo.saved), audited)
+	}
+	if _, err := svc.Run(ctx, ports.ImportOptions{ResumeRunID: &run.ID}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected a completed run not to resume, got %v", err)
+	}
+
+	again, err := svc.Run(ctx, ports.ImportOptions{})
+	if err != nil || again.Skipped != 2 || again.Imported != 0 || len(repo.saved) != 2 {
+		t.Errorf("Expected a rerun to skip imported clients, got %+v, %v", again, err)
+	}
+}
+
+func TestCASImportService_TiersLocksAndIdentityConflicts(t *testing.T) {
+	source := &stubCASSource{clients: []*domain.CASClient{
+		{CliNum: "0000000001", Name: "สมชาย ใจดี", IDType: "I", IDNumber: "1101700207030"},
+		{CliNum: "0000000002", Name: "สมหญิง ใจดี"},
+	}}
+	repo := newMockImportRepo()
+	repo.held = map[string]bool{"1101700207030": true}
+	tierRepo := &mockTierRepo{}
+	svc := NewCASImportService(source, repo, repo.refs, nil, defaultTierService(t, tierRepo), &mockAuditService{})
+	ctx := context.Background()
+
+	repo.locked = true
+	if _, err := svc.Run(ctx, ports.ImportOptions{}); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Expected a conflict while another process holds the lock, got %v", err)
+	}
+	repo.locked = false
+
+	run, err := svc.Run(ctx, ports.ImportOptions{})
+	if err != nil || run.Imported != 1 || run.Rejected != 1 || repo.locked {
+		t.Fatalf("Expected one import, one reject and the lock released, got %+v, %v (locked %t)", run, err, repo.locked)
+	}
+	if rejects := repo.rejects[run.ID]; len(rejects) != 1 || rejects[0].SourceKey != "0000000001" ||
+		!strings.Contains(rejects[0].Reason, "identity already belongs to another customer") {
+		t.Errorf("Expected the held identity to reject the first client, got %+v", rejects)
+	}
+	imported := repo.saved[0].Customer
+	if imported.MembershipTier == "" || len(tierRepo.history) != 1 ||
+		tierRepo.history[0].CustomerID != imported.ID || tierRepo.history[0].Source != domain.TierSourceImport {
+		t.Errorf("Expected the imported customer tiered with IMPORT history, got %q %+v", imported.MembershipTier, tierRepo.history)
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type changeRequestService struct {
+	repo         ports.ChangeRequestRepository
+	customers    ports.CustomerService
+	auditService AuditService
+	ttl          time.Duration
+	now          func() time.Time
+}
+
+// NewChangeRequestService wires the four-eyes workflow. Approved requests are applied
+// through the given CustomerService, so the usual validation and audit still run.
+func NewChangeRequestService(repo ports.ChangeRequestRepository, customers ports.CustomerService, audit AuditService, ttl time.Duration) *changeRequestService {
+	if ttl <= 0 {
+		ttl = 72 * time.Hour
+	}
+	return &changeRequestService{
+		repo:         repo,
+		customers:    customers,
+		auditService: audit,
+		ttl:          ttl,
+		now:          time.Now,
+	}
+}
+
+func (s *changeRequestService) Submit(ctx context.Context, op domain.ChangeOperation, customerID, makerID uuid.UUID, payload interface{}, reason string) (*domain.ChangeRequest, error) {
+	switch op {
+	case domain.OpDeleteCustomer, domain.OpRestoreCustomer, domain.OpAnonymizeCustomer, domain.OpBlacklistCustomer, domain.OpMergeCustomer:
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op)
+	}
+	if err := s.check(ctx, op, customerID, makerID, payload); err != nil {
+		return nil, err
+	}
+
+	cr := &domain.ChangeRequest{
+		Operation:  op,
+		CustomerID: customerID,
+		Reason:     reason,
+		Status:     domain.ChangePending,
+		MakerID:    makerID,
+		ExpiresAt:  s.now().Add(s.ttl),
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		cr.Payload = raw
+	}
+
+	if err := s.repo.Create(ctx, cr); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "SUBMIT", makerID.String(),
+		fmt.Sprintf("operation=%s customer=%s reason=%s", op, customerID, reason), "")
+	return cr, nil
+}
+
+// Approve applies a pending request on behalf of its maker. The maker may not approve their own request.
+func (s *changeRequestService) Approve(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error) {
+	cr, err := s.pending(ctx, id, checkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cr.Status = domain.ChangeApproved
+	cr.CheckerID = &checkerID
+	cr.Comment = comment
+	cr.DecidedAt = &now
+	if err := s.repo.Update(ctx, cr, domain.ChangePending); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "APPROVE", checkerID.String(), comment, "")
+
+	applyErr := s.apply(ctx, cr)
+	if applyErr != nil {
+		cr.Status = domain.ChangeFailed
+		cr.Error = applyErr.Error()
+	} else {
+		cr.Status = domain.ChangeApplied
+	}
+	if err := s.repo.Update(ctx, cr, domain.ChangeApproved); err != nil {
+		return nil, err
+	}
+
+	if applyErr != nil {
+		s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "FAIL", checkerID.String(), applyErr.Error(), "")
+		return cr, applyErr
+	}
+	s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "APPLY", checkerID.String(), string(cr.Operation), "")
+	return cr, nil
+}
+
+func (s *changeRequestService) Reject(ctx context.Context, id, checkerID uuid.UUID, comment string) (*domain.ChangeRequest, error) {
+	cr, err := s.pending(ctx, id, checkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cr.Status = domain.ChangeRejected
+	cr.CheckerID = &checkerID
+	cr.Comment = comment
+	cr.DecidedAt = &now
+	if err := s.repo.Update(ctx, cr, domain.ChangePending); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "REJECT", checkerID.String(), comment, "")
+	return cr, nil
+}
+
+func (s *changeRequestService) Get(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List sweeps stale pending requests to EXPIRED before listing, so callers never see them as actionable.
+func (s *changeRequestService) List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error) {
+	if _, err := s.repo.ExpirePending(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+func (s *changeRequestService) pending(ctx context.Context, id, checkerID uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status != domain.ChangePending {
+		return nil, fmt.Errorf("change request is already %s", cr.Status)
+	}
+	if cr.MakerID == checkerID {
+		return nil, fmt.Errorf("%w: the maker cannot decide on their own change request", domain.ErrForbidden)
+	}
+	if !s.now().Before(cr.ExpiresAt) {
+		now := s.now()
+		cr.Status = domain.ChangeExpired
+		cr.DecidedAt = &now
+		if err := s.repo.Update(ctx, cr, domain.ChangePending); err == nil {
+			s.auditService.Log(ctx, cr.ID, "CHANGE_REQUEST", "EXPIRE", "SYSTEM", "", "")
+		}
+		return nil, errors.New("change request has expired")
+	}
+	return cr, nil
+}
+
+// check refuses a request that could not be applied as things stand, such as anonymizing a
+// customer who still holds products, instead of leaving it for a checker to find out.
+func (s *changeRequestService) check(ctx context.Context, op domain.ChangeOperation, customerID, makerID uuid.UUID, payload interface{}) error {
+	var justification string
+	switch p := payload.(type) {
+	case domain.RestoreOverride:
+		justification = p.Justification
+	case *domain.RestoreOverride:
+		justification = p.Justification
+	case domain.CustomerMerge:
+		return s.customers.CheckMerge(ctx, customerID, p.IntoID)
+	case *domain.CustomerMerge:
+		return s.customers.CheckMerge(ctx, customerID, p.IntoID)
+	}
+	if op == domain.OpMergeCustomer {
+		return errors.New("merge requires the customer to merge into")
+	}
+	return s.customers.CheckChange(ctx, op, customerID, makerID, justification)
+}
+
+func (s *changeRequestService) apply(ctx context.Context, cr *domain.ChangeRequest) error {
+	switch cr.Operation {
+	case domain.OpDeleteCustomer:
+		return s.customers.DeleteCustomer(ctx, cr.CustomerID, cr.MakerID)
+	case domain.OpRestoreCustomer:
+		var override domain.RestoreOverride
+		if len(cr.Payload) > 0 {
+			if err := json.Unmarshal(cr.Payload, &override); err != nil {
+				return fmt.Errorf("invalid payload: %w", err)
+			}
+		}
+		if override.Justification != "" {
+			return s.customers.OverrideRestoreCustomer(ctx, cr.CustomerID, cr.MakerID, override.Justification)
+		}
+		return s.customers.RestoreCustomer(ctx, cr.CustomerID, cr.MakerID)
+	case domain.OpAnonymizeCustomer:
+		return s.customers.AnonymizeCustomer(ctx, cr.CustomerID, cr.MakerID)
+	case domain.OpMergeCustomer:
+		var merge domain.CustomerMerge
+		if err := json.Unmarshal(cr.Payload, &merge); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return s.customers.MergeCustomer(ctx, cr.CustomerID, merge.IntoID, cr.MakerID)
+	case domain.OpBlacklistCustomer:
+		var change domain.StatusChange
+		if err := json.Unmarshal(cr.Payload, &change); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		// Only the status is written, and only while the customer is still in change.From.
+		change.To = domain.StatusBlacklist
+		return s.customers.ChangeCustomerStatus(ctx, cr.CustomerID, change, cr.MakerID)
+	}
+	return fmt.Errorf("unsupported operation %q", cr.Operation)
+}
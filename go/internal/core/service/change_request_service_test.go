@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock ChangeRequestRepository
+type mockChangeRequestRepo struct {
+	requests map[uuid.UUID]*domain.ChangeRequest
+}
+
+func newMockChangeRequestRepo() *mockChangeRequestRepo {
+	return &mockChangeRequestRepo{requests: map[uuid.UUID]*domain.ChangeRequest{}}
+}
+
+func (m *mockChangeRequestRepo) Create(ctx context.Context, cr *domain.ChangeRequest) error {
+	cr.ID = uuid.New()
+	copied := *cr
+	m.requests[cr.ID] = &copied
+	return nil
+}
+func (m *mockChangeRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, ok := m.requests[id]
+	if !ok {
+		return nil, errors.New("change request not found")
+	}
+	copied := *cr
+	return &copied, nil
+}
+func (m *mockChangeRequestRepo) Update(ctx context.Context, cr *domain.ChangeRequest, from domain.ChangeRequestStatus) error {
+	if m.requests[cr.ID].Status != from {
+		return fmt.Errorf("change request is no longer %s", from)
+	}
+	copied := *cr
+	m.requests[cr.ID] = &copied
+	return nil
+}
+func (m *mockChangeRequestRepo) List(ctx context.Context, status string, limit, offset int) ([]*domain.ChangeRequest, error) {
+	return nil, nil
+}
+func (m *mockChangeRequestRepo) ExpirePending(ctx context.Context) (int64, error) { return 0, nil }
+
+func liveCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	return &domain.Customer{ID: id, Status: domain.StatusActive}, nil
+}
+
+func TestChangeRequest_MakerCannotApprove(t *testing.T) {
+	customers := NewCustomerService(&mockCustomerRepo{getByIDFunc: liveCustomer}, nil, nil, nil, nil, nil, &mockAuditService{})
+	svc := NewChangeRequestService(newMockChangeRequestRepo(), customers, &mockAuditService{}, time.Hour)
+	maker := uuid.New()
+
+	cr, err := svc.Submit(context.Background(), domain.OpDeleteCustomer, uuid.New(), maker, nil, "duplicate record")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := svc.Approve(context.Background(), cr.ID, maker, ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for maker approval, got %v", err)
+	}
+}
+
+func TestChangeRequest_ApproveAppliesOperation(t *testing.T) {
+	cid, maker, checker := uuid.New(), uuid.New(), uuid.New()
+	var deletedBy uuid.UUID
+	repo := &mockCustomerRepo{
+		getByIDFunc: liveCustomer,
+		deleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
+			deletedBy = userID
+			return nil
+		},
+	}
+	customers := NewCustomerService(repo, nil, nil, nil, nil, nil, &mockAuditService{})
+	svc := NewChangeRequestService(newMockChangeRequestRepo(), customers, &mockAuditService{}, time.Hour)
+
+	cr, _ := svc.Submit(context.Background(), domain.OpDeleteCustomer, cid, maker, nil, "")
+	cr, err := svc.Approve(context.Background(), cr.ID, checker, "ok")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cr.Status != domain.ChangeApplied {
+		t.Errorf("Expected APPLIED, got %s", cr.Status)
+	}
+	if deletedBy != maker {
+		t.Errorf("Expected delete to be attributed to the maker")
+	}
+
+	if _, err := svc.Reject(context.Background(), cr.ID, checker, ""); err == nil {
+		t.Errorf("Expected error deciding on an applied request")
+	}
+}
+
+func TestChangeRequest_Expired(t *testing.T) {
+	customers := NewCustomerService(&mockCustomerRepo{getByIDFunc: liveCustomer}, nil, nil, nil, nil, nil, &mockAuditService{})
+	repo := newMockChangeRequestRepo()
+	svc := NewChangeRequestService(repo, customers, &mockAuditService{}, time.Hour)
+
+	cr, _ := svc.Submit(context.Background(), domain.OpAnonymizeCustomer, uuid.New(), uuid.New(), nil, "")
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	if _, err := svc.Approve(context.Background(), cr.ID, uuid.New(), ""); err == nil {
+		t.Fatalf("Expected error approving expired request")
+	}
+	if repo.requests[cr.ID].Status != domain.ChangeExpired {
+		t.Errorf("Expected EXPIRED, got %s", repo.requests[cr.ID].Status)
+	}
+}
+
+func TestChangeRequest_SubmitRefusesDoomedRequests(t *testing.T) {
+	cid, maker := uuid.New(), uuid.New()
+	repo := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			if id != cid {
+				return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+			}
+			return &domain.Customer{ID: id, Status: domain.StatusActive, PortfolioSize: decimal.NewFromInt(1000)}, nil
+		},
+		getDeletedByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil // deleter unknown
+		},
+	}
+	customers := NewCustomerService(repo, nil, nil, nil, nil, nil, &mockAuditService{})
+	crRepo := newMockChangeRequestRepo()
+	svc := NewChangeRequestService(crRepo, customers, &mockAuditService{}, time.Hour)
+	ctx := context.Background()
+
+	if _, err := svc.Submit(ctx, domain.OpAnonymizeCustomer, cid, maker, nil, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected ErrConflict anonymizing a customer with a portfolio, got %v", err)
+	}
+	if _, err := svc.Submit(ctx, domain.OpDeleteCustomer, uuid.New(), maker, nil, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a missing customer, got %v", err)
+	}
+	if _, err := svc.Submit(ctx, domain.OpRestoreCustomer, cid, maker, nil, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected ErrConflict restoring a record with unknown deleter, got %v", err)
+	}
+	if len(crRepo.requests) != 0 {
+		t.Errorf("Expected no request to be queued, got %d", len(crRepo.requests))
+	}
+
+	if _, err := svc.Submit(ctx, domain.OpRestoreCustomer, cid, maker, domain.RestoreOverride{Justification: "legacy record"}, ""); err != nil {
+		t.Errorf("Expected an override restore to be accepted, got %v", err)
+	}
+}
+
+func TestChangeRequest_BlacklistKeepsLaterEdits(t *testing.T) {
+	cid, maker, checker := uuid.New(), uuid.New(), uuid.New()
+	stored := &domain.Customer{ID: cid, FirstName: "Somchai", Status: domain.StatusActive}
+	repo := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, c *domain.Customer) error {
+			t.Error("Expected only the status to be written")
+			return nil
+		},
+		updateStatusFunc: func(ctx context.Context, id uuid.UUID, from, to domain.CustomerStatus) error {
+			if stored.Status != from {
+				return fmt.Errorf("%w: status is %s", domain.ErrConflict, stored.Status)
+			}
+			stored.Status = to
+			return nil
+		},
+	}
+	customers := NewCustomerService(repo, nil, nil, nil, nil, nil, &mockAuditService{})
+	svc := NewChangeRequestService(newMockChangeRequestRepo(), customers, &mockAuditService{}, time.Hour)
+	ctx := context.Background()
+
+	cr, err := svc.Submit(ctx, domain.OpBlacklistCustomer, cid, maker,
+		domain.StatusChange{From: domain.StatusActive, To: domain.StatusBlacklist}, "fraud")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Edited while the request waits.
+	stored.FirstName = "Somsak"
+	stored.CLV = decimal.NewFromInt(500)
+
+	if _, err := svc.Approve(ctx, cr.ID, checker, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored.Status != domain.StatusBlacklist {
+		t.Errorf("Expected BLACKLISTED, got %s", stored.Status)
+	}
+	if stored.FirstName != "Somsak" || !stored.CLV.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("Expected the later edit to survive approval, got %+v", stored)
+	}
+
+	// A request made from a status the customer has since left no longer applies.
+	stored.Status = domain.StatusInactive
+	cr, _ = svc.Submit(ctx, domain.OpBlacklistCustomer, cid, maker,
+		domain.StatusChange{From: domain.StatusActive, To: domain.StatusBlacklist}, "")
+	if _, err := svc.Approve(ctx, cr.ID, checker, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected ErrConflict after an intervening status change, got %v", err)
+	}
+}
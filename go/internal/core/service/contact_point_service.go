@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/utils/validation"
+	"github.com/google/uuid"
+)
+
+type ContactPointConfig struct {
+	// OTPTTL is how long a one-time code stays valid.
+	OTPTTL time.Duration
+	// OTPMaxAttempts is how many wrong codes are accepted before a new code must be requested.
+	OTPMaxAttempts int
+	// OTPResendAfter is the minimum gap between codes sent to the same contact point.
+	OTPResendAfter time.Duration
+	// DefaultCountry is the calling code used for phone numbers entered without one, e.g. "66".
+	DefaultCountry string
+}
+
+type contactPointService struct {
+	repo         ports.ContactPointRepository
+	customerRepo ports.CustomerRepository
+	sender       ports.OTPSender
+	auditService AuditService
+	cfg          ContactPointConfig
+	now          func() time.Time
+}
+
+func NewContactPointService(repo ports.ContactPointRepository, customerRepo ports.CustomerRepository, sender ports.OTPSender, audit AuditService, cfg ContactPointConfig) *contactPointService {
+	if cfg.OTPTTL <= 0 {
+		cfg.OTPTTL = 10 * time.Minute
+	}
+	if cfg.OTPMaxAttempts <= 0 {
+		cfg.OTPMaxAttempts = 5
+	}
+	if cfg.DefaultCountry == "" {
+		cfg.DefaultCountry = "66"
+	}
+	return &contactPointService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		sender:       sender,
+		auditService: audit,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+func (s *contactPointService) Add(ctx context.Context, cp *domain.ContactPoint, userID uuid.UUID) error {
+	if err := s.normalize(cp); err != nil {
+		return err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, cp.CustomerID); err != nil {
+		return err
+	}
+	cp.Status = domain.VerificationUnverified
+	cp.VerifiedAt = nil
+
+	if err := s.repo.Create(ctx, cp); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, cp.CustomerID, "CUSTOMER", "CONTACT_ADD", userID.String(), fmt.Sprintf("contact=%s type=%s", cp.ID, cp.Type), "")
+	return nil
+}
+
+func (s *contactPointService) List(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error) {
+	return s.repo.ListByCustomerID(ctx, customerID)
+}
+
+// Update applies a new value and primary flag. The type cannot change, and a changed value
+// drops the verification so the new value has to be confirmed again.
+func (s *contactPointService) Update(ctx context.Context, cp *domain.ContactPoint, userID uuid.UUID) error {
+	existing, err := s.get(ctx, cp.CustomerID, cp.ID)
+	if err != nil {
+		return err
+	}
+	cp.Type = existing.Type
+	if cp.Value == "" {
+		cp.Value = existing.Value
+	}
+	if err := s.normalize(cp); err != nil {
+		return err
+	}
+
+	cp.Status, cp.VerifiedAt = existing.Status, existing.VerifiedAt
+	if cp.Value != existing.Value {
+		cp.Status, cp.VerifiedAt = domain.VerificationUnverified, nil
+		if err := s.repo.DeleteVerification(ctx, cp.ID); err != nil {
+			return err
+		}
+	}
+	cp.CreatedAt = existing.CreatedAt
+
+	if err := s.repo.Update(ctx, cp); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, cp.CustomerID, "CUSTOMER", "CONTACT_UPDATE", userID.String(), fmt.Sprintf("contact=%s type=%s", cp.ID, cp.Type), "")
+	return nil
+}
+
+func (s *contactPointService) Remove(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	cp, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "CONTACT_REMOVE", userID.String(), fmt.Sprintf("contact=%s type=%s", cp.ID, cp.Type), "")
+	return nil
+}
+
+// StartVerification sends a fresh six-digit code, replacing any outstanding one.
+func (s *contactPointService) StartVerification(ctx context.Context, customerID, id, userID uuid.UUID) (*domain.ContactPoint, error) {
+	cp, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cp.Type.CanVerify() {
+		return nil, fmt.Errorf("%s contact points cannot be verified by code", cp.Type)
+	}
+
+	if prev, err := s.repo.GetVerification(ctx, id); err != nil {
+		return nil, err
+	} else if prev != nil && s.now().Sub(prev.CreatedAt) < s.cfg.OTPResendAfter {
+		return nil, fmt.Errorf("%w: a code was sent recently, try again later", domain.ErrConflict)
+	}
+
+	code, err := otpCode()
+	if err != nil {
+		return nil, err
+	}
+	v := &domain.ContactVerification{
+		ContactPointID: id,
+		CodeHash:       hashOTP(id, code),
+		ExpiresAt:      s.now().Add(s.cfg.OTPTTL),
+	}
+	if err := s.repo.SaveVerification(ctx, v); err != nil {
+		return nil, err
+	}
+	if err := s.sender.Send(ctx, cp, code); err != nil {
+		// No code went out, so the row must not hold off a retry.
+		if err := s.repo.DeleteVerification(ctx, id); err != nil {
+			log.Printf("removing unsent verification for contact point %s: %v", id, err)
+		}
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	if cp.Status != domain.VerificationVerified {
+		cp.Status = domain.VerificationPending
+		if err := s.repo.Update(ctx, cp); err != nil {
+			return nil, err
+		}
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "CONTACT_VERIFY_START", userID.String(), fmt.Sprintf("contact=%s type=%s", cp.ID, cp.Type), "")
+	return cp, nil
+}
+
+// ConfirmVerification checks code against the outstanding one. Wrong, expired and exhausted
+// codes all fail with ErrVerificationFailed.
+func (s *contactPointService) ConfirmVerification(ctx context.Context, customerID, id uuid.UUID, code string, userID uuid.UUID) (*domain.ContactPoint, error) {
+	cp, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.repo.GetVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%w: no code outstanding, request a new one", domain.ErrVerificationFailed)
+	}
+	if !s.now().Before(v.ExpiresAt) {
+		return nil, fmt.Errorf("%w: code expired, request a new one", domain.ErrVerificationFailed)
+	}
+	// The attempt is counted before the code is compared, so parallel guesses each use one up.
+	ok, err := s.repo.UseVerificationAttempt(ctx, id, s.cfg.OTPMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: too many attempts, request a new one", domain.ErrVerificationFailed)
+	}
+
+	want := []byte(v.CodeHash)
+	got := []byte(hashOTP(id, strings.TrimSpace(code)))
+	if subtle.ConstantTimeCompare(want, got) != 1 {
+		return nil, fmt.Errorf("%w: incorrect code", domain.ErrVerificationFailed)
+	}
+
+	now := s.now()
+	cp.Status = domain.VerificationVerified
+	cp.VerifiedAt = &now
+	if err := s.repo.Update(ctx, cp); err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteVerification(ctx, id); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "CONTACT_VERIFIED", userID.String(), fmt.Sprintf("contact=%s type=%s", cp.ID, cp.Type), "")
+	return cp, nil
+}
+
+// FindCustomers looks up customers by exact normalized phone (mobile or landline) or email.
+func (s *contactPointService) FindCustomers(ctx context.Context, phone, email string) ([]*domain.Customer, error) {
+	var types []domain.ContactType
+	var value string
+	var err error
+	switch {
+	case phone != "" && email != "":
+		return nil, fmt.Errorf("%w: search by phone or email, not both", domain.ErrInvalidSearch)
+	case phone != "":
+		types = []domain.ContactType{domain.ContactMobile, domain.ContactLandline}
+		value, err = validation.NormalizePhone(phone, s.cfg.DefaultCountry)
+	case email != "":
+		types = []domain.ContactType{domain.ContactEmail}
+		value, err = validation.NormalizeEmail(email)
+	default:
+		return nil, fmt.Errorf("%w: phone or email is required", domain.ErrInvalidSearch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSearch, err)
+	}
+
+	cps, err := s.repo.FindByValue(ctx, types, value)
+	if err != nil {
+		return nil, err
+	}
+	customers := []*domain.Customer{}
+	seen := map[uuid.UUID]bool{}
+	for _, cp := range cps {
+		if seen[cp.CustomerID] {
+			continue
+		}
+		seen[cp.CustomerID] = true
+		c, err := s.customerRepo.GetByID(ctx, cp.CustomerID)
+		if err != nil {
+			// Contact points of soft-deleted customers are not search results.
+			continue
+		}
+		customers = append(customers, c)
+	}
+	return customers, nil
+}
+
+func (s *contactPointService) get(ctx context.Context, customerID, id uuid.UUID) (*domain.ContactPoint, error) {
+	cp, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cp.CustomerID != customerID {
+		return nil, fmt.Errorf("contact point %w", domain.ErrNotFound)
+	}
+	return cp, nil
+}
+
+func (s *contactPointService) normalize(cp *domain.ContactPoint) error {
+	cp.Type = domain.ContactType(strings.ToUpper(string(cp.Type)))
+	var err error
+	switch {
+	case cp.Type.IsPhone():
+		cp.Value, err = validation.NormalizePhone(cp.Value, s.cfg.DefaultCountry)
+	case cp.Type == domain.ContactEmail:
+		cp.Value, err = validation.NormalizeEmail(cp.Value)
+	case cp.Type == domain.ContactLine:
+		cp.Value, err = validation.NormalizeLineID(cp.Value)
+	default:
+		return fmt.Errorf("unknown contact type %q", cp.Type)
+	}
+	return err
+}
+
+// otpCode returns a uniformly random six-digit code.
+func otpCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashOTP binds the code to its contact point so a stored hash is useless for any other one.
+func hashOTP(contactPointID uuid.UUID, code string) string {
+	sum := sha256.Sum256([]byte(contactPointID.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
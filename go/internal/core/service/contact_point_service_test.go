@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockContactPointRepo struct {
+	rows          map[uuid.UUID]*domain.ContactPoint
+	verifications map[uuid.UUID]*domain.ContactVerification
+}
+
+func newMockContactPointRepo() *mockContactPointRepo {
+	return &mockContactPointRepo{rows: map[uuid.UUID]*domain.ContactPoint{}, verifications: map[uuid.UUID]*domain.ContactVerification{}}
+}
+
+func (m *mockContactPointRepo) Create(ctx context.Context, cp *domain.ContactPoint) error {
+	cp.ID = uuid.New()
+	cp2 := *cp
+	m.rows[cp.ID] = &cp2
+	return nil
+}
+func (m *mockContactPointRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ContactPoint, error) {
+	cp, ok := m.rows[id]
+	if !ok {
+		return nil, errors.New("contact point not found")
+	}
+	cp2 := *cp
+	return &cp2, nil
+}
+func (m *mockContactPointRepo) Update(ctx context.Context, cp *domain.ContactPoint) error {
+	cp2 := *cp
+	m.rows[cp.ID] = &cp2
+	return nil
+}
+func (m *mockContactPointRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.rows, id)
+	return nil
+}
+func (m *mockContactPointRepo) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error) {
+	var out []*domain.ContactPoint
+	for _, cp := range m.rows {
+		if cp.CustomerID == customerID {
+			out = append(out, cp)
+		}
+	}
+	return out, nil
+}
+func (m *mockContactPointRepo) FindByValue(ctx context.Context, types []domain.ContactType, value string) ([]*domain.ContactPoint, error) {
+	var out []*domain.ContactPoint
+	for _, cp := range m.rows {
+		for _, t := range types {
+			if cp.Type == t && cp.Value == value {
+				out = append(out, cp)
+			}
+		}
+	}
+	return out, nil
+}
+func (m *mockContactPointRepo) SaveVerification(ctx context.Context, v *domain.ContactVerification) error {
+	v.CreatedAt = time.Now()
+	m.verifications[v.ContactPointID] = v
+	return nil
+}
+func (m *mockContactPointRepo) GetVerification(ctx context.Context, id uuid.UUID) (*domain.ContactVerification, error) {
+	return m.verifications[id], nil
+}
+func (m *mockContactPointRepo) UseVerificationAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) (bool, error) {
+	v := m.verifications[id]
+	if v == nil || v.Attempts >= maxAttempts {
+		return false, nil
+	}
+	v.Attempts++
+	return true, nil
+}
+func (m *mockContactPointRepo) DeleteVerification(ctx context.Context, id uuid.UUID) error {
+	delete(m.verifications, id)
+	return nil
+}
+
+type captureSender struct {
+	code string
+	err  error // Returned instead of sending when set
+}
+
+func (s *captureSender) Send(ctx context.Context, cp *domain.ContactPoint, code string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.code = code
+	return nil
+}
+
+func TestContactPointService_NormalizesValues(t *testing.T) {
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	svc := NewContactPointService(newMockContactPointRepo(), customers, &captureSender{}, &mockAuditService{}, ContactPointConfig{})
+
+	tests := []struct {
+		typ     domain.ContactType
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"mobile", "081-234-5678", "+66812345678", false},
+		{domain.ContactLandline, "02 123 4567", "+6621234567", false},
+		{domain.ContactMobile, "+65 9123 4567", "+6591234567", false},
+		{domain.ContactMobile, "0066812345678", "+66812345678", false},
+		{domain.ContactEmail, " Somchai@Example.CO.TH ", "somchai@example.co.th", false},
+		{domain.ContactLine, "Somchai.J", "somchai.j", false},
+		{domain.ContactMobile, "812345678", "", true},
+		{domain.ContactEmail, "not-an-email", "", true},
+		{"FAX", "021234567", "", true},
+	}
+	for _, tt := range tests {
+		cp := &domain.ContactPoint{CustomerID: uuid.New(), Type: tt.typ, Value: tt.value}
+		err := svc.Add(context.Background(), cp, uuid.New())
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Add(%s %q): expected error", tt.typ, tt.value)
+			}
+			continue
+		}
+		if err != nil || cp.Value != tt.want || cp.Status != domain.VerificationUnverified {
+			t.Errorf("Add(%s %q) = %q/%s, %v; want %q", tt.typ, tt.value, cp.Value, cp.Status, err, tt.want)
+		}
+	}
+}
+
+func TestContactPointService_Verification(t *testing.T) {
+	customerID := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	repo := newMockContactPointRepo()
+	sender := &captureSender{}
+	svc := NewContactPointService(repo, customers, sender, &mockAuditService{}, ContactPointConfig{OTPMaxAttempts: 2})
+	ctx := context.Background()
+
+	cp := &domain.ContactPoint{CustomerID: customerID, Type: domain.ContactMobile, Value: "0812345678"}
+	if err := svc.Add(ctx, cp, uuid.New()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	// A code that could not be sent does not count towards the resend throttle.
+	sender.err = domain.ErrVerificationUnavailable
+	if _, err := svc.StartVerification(ctx, customerID, cp.ID, uuid.New()); !errors.Is(err, domain.ErrVerificationUnavailable) {
+		t.Fatalf("Expected ErrVerificationUnavailable, got %v", err)
+	}
+	if repo.verifications[cp.ID] != nil {
+		t.Fatal("Expected no verification stored for an unsent code")
+	}
+	sender.err = nil
+	if _, err := svc.StartVerification(ctx, customerID, cp.ID, uuid.New()); err != nil {
+		t.Fatalf("StartVerification failed: %v", err)
+	}
+	if len(sender.code) != 6 || repo.rows[cp.ID].Status != domain.VerificationPending {
+		t.Fatalf("Expected a six-digit code and PENDING status, got %q/%s", sender.code, repo.rows[cp.ID].Status)
+	}
+
+	wrong := "000000"
+	if sender.code == wrong {
+		wrong = "111111"
+	}
+	if _, err := svc.ConfirmVerification(ctx, customerID, cp.ID, wrong, uuid.New()); !errors.Is(err, domain.ErrVerificationFailed) {
+		t.Errorf("Expected ErrVerificationFailed for wrong code, got %v", err)
+	}
+	if _, err := svc.ConfirmVerification(ctx, uuid.New(), cp.ID, sender.code, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Error("Expected contact point to be hidden under another customer")
+	}
+
+	got, err := svc.ConfirmVerification(ctx, customerID, cp.ID, sender.code, uuid.New())
+	if err != nil || got.Status != domain.VerificationVerified || got.VerifiedAt == nil {
+		t.Fatalf("Expected VERIFIED, got %+v, %v", got, err)
+	}
+	if _, err := svc.ConfirmVerification(ctx, customerID, cp.ID, sender.code, uuid.New()); !errors.Is(err, domain.ErrVerificationFailed) {
+		t.Errorf("Expected a used code to be rejected, got %v", err)
+	}
+
+	found, err := svc.FindCustomers(ctx, "+66 81 234 5678", "")
+	if err != nil || len(found) != 1 || found[0].ID != customerID {
+		t.Errorf("Expected to find the customer by phone, got %v, %v", found, err)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// customer360AuditLimit is how many of the latest audit entries the 360 view embeds.
+const customer360AuditLimit = 20
+
+// AuditLister is the read side of the audit log.
+type AuditLister interface {
+	List(ctx context.Context, p domain.PageRequest, action string, entityID string) (*domain.Page[*repository.AuditLog], error)
+}
+
+type customer360Service struct {
+	customers ports.CustomerService
+	audit     AuditLister
+}
+
+func NewCustomer360Service(customers ports.CustomerService, audit AuditLister) *customer360Service {
+	return &customer360Service{customers: customers, audit: audit}
+}
+
+func (s *customer360Service) Get360(ctx context.Context, id uuid.UUID, include []string) (*domain.Customer360, error) {
+	sections, err := domain.ParseSections360(include)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &domain.Customer360{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	fail := func(section string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if view.Errors == nil {
+			view.Errors = map[string]string{}
+		}
+		view.Errors[section] = err.Error()
+	}
+	load := func(section string, fn func() error) {
+		if !sections[section] {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				fail(section, err)
+			}
+		}()
+	}
+
+	var customerErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		view.Customer, customerErr = s.customers.GetCustomer(ctx, id)
+	}()
+	load(domain.Section360Addresses, func() (err error) {
+		view.Addresses, err = s.customers.GetAddresses(ctx, id)
+		return err
+	})
+	load(domain.Section360Identities, func() (err error) {
+		view.Identities, err = s.customers.GetIdentities(ctx, id)
+		return err
+	})
+	load(domain.Section360Relationships, func() (err error) {
+		view.Relationships, err = s.relationships(ctx, id)
+		return err
+	})
+	load(domain.Section360Consents, func() (err error) {
+		view.Consents, err = s.customers.GetConsents(ctx, id)
+		return err
+	})
+	load(domain.Section360Audit, func() (err error) {
+		view.Audit, err = s.auditTrail(ctx, id)
+		return err
+	})
+	wg.Wait()
+
+	if customerErr != nil {
+		return nil, customerErr
+	}
+	return view, nil
+}
+
+// relationships loads the edges and, concurrently, a summary of each customer on the other end.
+// A related customer that cannot be loaded leaves its summary empty and is reported as the error.
+func (s *customer360Service) relationships(ctx context.Context, id uuid.UUID) ([]*domain.RelationshipView, error) {
+	rels, err := s.customers.GetRelationships(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*domain.RelationshipView, len(rels))
+	summaries := map[uuid.UUID]*domain.CustomerSummary{}
+	var related []uuid.UUID
+	for i, rel := range rels {
+		views[i] = &domain.RelationshipView{Relationship: rel}
+		if other := otherEnd(rel, id); !containsID(related, other) {
+			related = append(related, other)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	for _, relatedID := range related {
+		wg.Add(1)
+		go func(relatedID uuid.UUID) {
+			defer wg.Done()
+			c, err := s.customers.GetCustomer(ctx, relatedID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("related customer %s: %w", relatedID, err)
+				}
+				return
+			}
+			summaries[relatedID] = domain.NewCustomerSummary(c)
+		}(relatedID)
+	}
+	wg.Wait()
+
+	for _, v := range views {
+		v.Related = summaries[otherEnd(v.Relationship, id)]
+	}
+	return views, firstErr
+}
+
+func (s *customer360Service) auditTrail(ctx context.Context, id uuid.UUID) ([]*domain.AuditEntry, error) {
+	logs, err := s.audit.List(ctx, domain.PageRequest{Limit: customer360AuditLimit}, "", id.String())
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.AuditEntry, 0, len(logs.Items))
+	for _, l := range logs.Items {
+		entries = append(entries, &domain.AuditEntry{
+			ID:          l.ID,
+			Action:      l.Action,
+			PerformedBy: l.PerformedBy,
+			Changes:     l.Changes,
+			Timestamp:   l.Timestamp,
+		})
+	}
+	return entries, nil
+}
+
+func otherEnd(rel *domain.Relationship, id uuid.UUID) uuid.UUID {
+	if rel.FromCustomerID == id {
+		return rel.ToCustomerID
+	}
+	return rel.FromCustomerID
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
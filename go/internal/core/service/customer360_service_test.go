@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// stubCustomers serves the reads the 360 view needs; other CustomerService methods are not used.
+type stubCustomers struct {
+	ports.CustomerService
+	customers     map[uuid.UUID]*domain.Customer
+	relationships []*domain.Relationship
+	consentsErr   error
+}
+
+func (s *stubCustomers) GetCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	if c, ok := s.customers[id]; ok {
+		return c, nil
+	}
+	return nil, errors.New("customer not found")
+}
+func (s *stubCustomers) GetAddresses(ctx context.Context, id uuid.UUID) ([]*domain.Address, error) {
+	return []*domain.Address{{CustomerID: id, Type: "Mailing"}}, nil
+}
+func (s *stubCustomers) GetIdentities(ctx context.Context, id uuid.UUID) ([]*domain.Identity, error) {
+	return nil, nil
+}
+func (s *stubCustomers) GetRelationships(ctx context.Context, id uuid.UUID) ([]*domain.Relationship, error) {
+	return s.relationships, nil
+}
+func (s *stubCustomers) GetConsents(ctx context.Context, id uuid.UUID) ([]*domain.Consent, error) {
+	return nil, s.consentsErr
+}
+
+type stubAuditLister struct{}
+
+func (stubAuditLister) List(ctx context.Context, p domain.PageRequest, action string, entityID string) (*domain.Page[*repository.AuditLog], error) {
+	return &domain.Page[*repository.AuditLog]{Items: []*repository.AuditLog{{Action: "CREATE", EntityID: uuid.MustParse(entityID)}}}, nil
+}
+
+func TestGet360(t *testing.T) {
+	id, spouse, company := uuid.New(), uuid.New(), uuid.New()
+	customers := &stubCustomers{
+		customers: map[uuid.UUID]*domain.Customer{
+			id:      {ID: id, Type: domain.TypePersonal, FirstName: "Somchai"},
+			spouse:  {ID: spouse, Type: domain.TypePersonal, FirstName: "Malee", LastName: "Jaidee"},
+			company: {ID: company, Type: domain.TypeJuristic, CompanyName: "Siam Holdings", IsHighValue: true},
+		},
+		relationships: []*domain.Relationship{
+			{FromCustomerID: id, ToCustomerID: spouse, Role: "SPOUSE"},
+			{FromCustomerID: company, ToCustomerID: id, Role: "DIRECTOR"},
+		},
+		consentsErr: errors.New("consent store unavailable"),
+	}
+	svc := NewCustomer360Service(customers, stubAuditLister{})
+
+	view, err := svc.Get360(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if view.Customer.ID != id || len(view.Addresses) != 1 || len(view.Audit) != 1 {
+		t.Errorf("Expected all sections loaded, got %+v", view)
+	}
+	if view.Errors["consents"] == "" || len(view.Errors) != 1 {
+		t.Errorf("Expected only the consents section to report an error, got %v", view.Errors)
+	}
+	if got := view.Relationships[0].Related; got == nil || got.DisplayName != "Malee Jaidee" {
+		t.Errorf("Expected spouse summary, got %+v", got)
+	}
+	if got := view.Relationships[1].Related; got == nil || !got.Protected || got.DisplayName != "" {
+		t.Errorf("Expected protected company summary without name, got %+v", got)
+	}
+}
+
+func TestGet360_Include(t *testing.T) {
+	id := uuid.New()
+	customers := &stubCustomers{customers: map[uuid.UUID]*domain.Customer{id: {ID: id}}}
+	svc := NewCustomer360Service(customers, stubAuditLister{})
+
+	view, err := svc.Get360(context.Background(), id, []string{domain.Section360Addresses})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(view.Addresses) != 1 || view.Audit != nil || view.Errors != nil {
+		t.Errorf("Expected only addresses, got %+v", view)
+	}
+
+	if _, err := svc.Get360(context.Background(), id, []string{"orders"}); err == nil {
+		t.Error("Expected error for unknown section")
+	}
+	if _, err := svc.Get360(context.Background(), uuid.New(), nil); err == nil {
+		t.Error("Expected error for missing customer")
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const customerNumberBackfillBatch = 500
+
+type customerNumberService struct {
+	repo         ports.CustomerNumberRepository
+	format       domain.CustomerNumberFormat
+	auditService AuditService
+}
+
+// NewCustomerNumberService formats numbers drawn from a database sequence, so numbers are
+// unique across every instance sharing the database. format must already be validated.
+func NewCustomerNumberService(repo ports.CustomerNumberRepository, format domain.CustomerNumberFormat, audit AuditService) *customerNumberService {
+	return &customerNumberService{repo: repo, format: format, auditService: audit}
+}
+
+func (s *customerNumberService) Next(ctx context.Context) (string, error) {
+	seq, err := s.repo.NextNumberSequence(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.format.Format(seq)
+}
+
+func (s *customerNumberService) FindCustomerID(ctx context.Context, number string) (uuid.UUID, error) {
+	n, err := s.format.Parse(number)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return s.repo.FindIDByNumber(ctx, n)
+}
+
+// Backfill numbers unnumbered customers oldest first, so older customers get lower numbers.
+func (s *customerNumberService) Backfill(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		ids, err := s.repo.ListUnnumbered(ctx, customerNumberBackfillBatch)
+		if err != nil || len(ids) == 0 {
+			return total, err
+		}
+		for _, id := range ids {
+			number, err := s.Next(ctx)
+			if err != nil {
+				return total, err
+			}
+			if err := s.repo.AssignNumber(ctx, id, number); err != nil {
+				return total, err
+			}
+			s.auditService.Log(ctx, id, "CUSTOMER", "NUMBER_ASSIGN", "SYSTEM", number, "")
+			total++
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockNumberRepo struct {
+	seq      int64
+	numbers  map[string]uuid.UUID
+	unnumber []uuid.UUID
+}
+
+func (m *mockNumberRepo) NextNumberSequence(ctx context.Context) (int64, error) {
+	m.seq++
+	return m.seq, nil
+}
+func (m *mockNumberRepo) FindIDByNumber(ctx context.Context, number string) (uuid.UUID, error) {
+	id, ok := m.numbers[number]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	return id, nil
+}
+func (m *mockNumberRepo) ListUnnumbered(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	if len(m.unnumber) > limit {
+		return m.unnumber[:limit], nil
+	}
+	return m.unnumber, nil
+}
+func (m *mockNumberRepo) AssignNumber(ctx context.Context, id uuid.UUID, number string) error {
+	m.numbers[number] = id
+	for i, u := range m.unnumber {
+		if u == id {
+			m.unnumber = append(m.unnumber[:i], m.unnumber[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestCustomerNumberFormat(t *testing.T) {
+	tests := []struct {
+		format domain.CustomerNumberFormat
+		seq    int64
+		want   string
+	}{
+		{domain.CustomerNumberFormat{Prefix: "799", Digits: 7, Check: domain.CheckLuhn}, 2739871, "79927398713"},
+		{domain.CustomerNumberFormat{Prefix: "110", Digits: 9, Check: domain.CheckMod11}, 170020703, "1101700207030"},
+		{domain.CustomerNumberFormat{Prefix: "001", Digits: 6, Check: domain.CheckNone}, 42, "001000042"},
+	}
+	for _, tt := range tests {
+		got, err := tt.format.Format(tt.seq)
+		if err != nil || got != tt.want {
+			t.Errorf("%s Format(%d) = %q, %v; want %q", tt.format.Check, tt.seq, got, err, tt.want)
+			continue
+		}
+		if n, err := tt.format.Parse(got[:3] + "-" + got[3:]); err != nil || n != got {
+			t.Errorf("%s Parse(%q) = %q, %v", tt.format.Check, got, n, err)
+		}
+	}
+
+	f := domain.CustomerNumberFormat{Prefix: "001", Digits: 4, Check: domain.CheckLuhn}
+	if _, err := f.Format(10000); err == nil {
+		t.Error("Expected a sequence wider than Digits to be refused")
+	}
+	good, _ := f.Format(1234)
+	typo := good[:4] + string('0'+(good[4]-'0'+1)%10) + good[5:]
+	for _, bad := range []string{typo, "002" + good[3:], good + "1", "abc"} {
+		if _, err := f.Parse(bad); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", bad)
+		}
+	}
+	if err := (domain.CustomerNumberFormat{Prefix: "B01", Digits: 8, Check: domain.CheckLuhn}).Validate(); err == nil {
+		t.Error("Expected a non-numeric prefix to be refused")
+	}
+}
+
+func TestCustomerNumberService(t *testing.T) {
+	format := domain.CustomerNumberFormat{Prefix: "001", Digits: 8, Check: domain.CheckMod11}
+	old := uuid.New()
+	repo := &mockNumberRepo{numbers: map[string]uuid.UUID{}, unnumber: []uuid.UUID{old}}
+	numbers := NewCustomerNumberService(repo, format, &mockAuditService{})
+	ctx := context.Background()
+
+	if n, err := numbers.Backfill(ctx); err != nil || n != 1 {
+		t.Fatalf("Backfill = %d, %v; want 1", n, err)
+	}
+
+	var created *domain.Customer
+	customers := NewCustomerService(&mockCustomerRepo{
+		createFunc: func(ctx context.Context, c *domain.Customer) error {
+			c.ID = uuid.New()
+			created = c
+			repo.numbers[c.CustomerNumber] = c.ID
+			return nil
+		},
+	}, nil, nil, nil, nil, nil, &mockAuditService{}, WithCustomerNumbers(numbers))
+
+	if err := customers.CreateCustomer(ctx, &domain.Customer{Type: domain.TypePersonal, CustomerNumber: "999"}, uuid.New()); err != nil {
+		t.Fatalf("CreateCustomer failed: %v", err)
+	}
+	if created.CustomerNumber == "999" || created.CustomerNumber[:3] != "001" || len(created.CustomerNumber) != 12 {
+		t.Fatalf("Expected an assigned 001 number, got %q", created.CustomerNumber)
+	}
+
+	if id, err := numbers.FindCustomerID(ctx, created.CustomerNumber); err != nil || id != created.ID {
+		t.Errorf("FindCustomerID = %s, %v; want %s", id, err, created.ID)
+	}
+	first, _ := format.Format(1)
+	if id, err := numbers.FindCustomerID(ctx, first); err != nil || id != old {
+		t.Errorf("Expected the backfilled customer to hold the first number, got %s, %v", id, err)
+	}
+}
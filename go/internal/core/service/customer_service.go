@@ -3,12 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
 	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/models"
 	"github.com/amnuaym/cic/go/internal/utils/validation"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type customerService struct {
@@ -17,8 +22,77 @@ type customerService struct {
 	identityRepo     ports.IdentityRepository
 	relationshipRepo ports.RelationshipRepository
 	consentRepo      ports.ConsentRepository
-	userRepo         ports.UserRepository
+	hierarchy        ports.HierarchyService
 	auditService     AuditService
+	tiers            ports.TierService
+	fx               ports.FXService
+	portfolioValues  ports.PortfolioValueRepository
+	holdings         ports.HoldingRepository
+	externalRefs     ports.ExternalRefRepository
+	refService       ports.ExternalRefService
+	numbers          ports.CustomerNumberService
+	phoneCountry     string
+}
+
+// CustomerServiceOption configures optional customerService behaviour
+type CustomerServiceOption func(*customerService)
+
+// WithTierRules derives membership tier and the high-value flag on every create and update,
+// overriding whatever the client sent.
+func WithTierRules(tiers ports.TierService) CustomerServiceOption {
+	return func(s *customerService) {
+		s.tiers = tiers
+	}
+}
+
+// WithPortfolioValues accepts per-currency portfolio values on create and update. When given,
+// PortfolioSize is derived as their sum in THB using the local FX rates.
+func WithPortfolioValues(fx ports.FXService, values ports.PortfolioValueRepository) CustomerServiceOption {
+	return func(s *customerService) {
+		s.fx = fx
+		s.portfolioValues = values
+	}
+}
+
+// WithHoldings derives the portfolio from the holdings subsystem: portfolio_size and
+// portfolio_values sent by clients are ignored, and anonymization is refused while any
+// holding is active.
+func WithHoldings(holdings ports.HoldingRepository) CustomerServiceOption {
+	return func(s *customerService) {
+		s.holdings = holdings
+	}
+}
+
+// WithExternalRefs accepts external_refs on create and update, replacing the customer's whole
+// set when present, and returns them with the customer.
+func WithExternalRefs(refs ports.ExternalRefRepository) CustomerServiceOption {
+	return func(s *customerService) {
+		s.externalRefs = refs
+	}
+}
+
+// WithMerging enables MergeCustomer, which hands the merged customer's external references to
+// the survivor through refs.
+func WithMerging(refs ports.ExternalRefService) CustomerServiceOption {
+	return func(s *customerService) {
+		s.refService = refs
+	}
+}
+
+// WithCustomerNumbers assigns every new customer a human-readable number. Numbers sent by
+// clients are ignored.
+func WithCustomerNumbers(numbers ports.CustomerNumberService) CustomerServiceOption {
+	return func(s *customerService) {
+		s.numbers = numbers
+	}
+}
+
+// WithPhoneCountry sets the calling code assumed for phone searches entered without one.
+// Defaults to "66".
+func WithPhoneCountry(code string) CustomerServiceOption {
+	return func(s *customerService) {
+		s.phoneCountry = code
+	}
 }
 
 func NewCustomerService(
@@ -27,43 +101,270 @@ func NewCustomerService(
 	iRepo ports.IdentityRepository,
 	rRepo ports.RelationshipRepository,
 	cnRepo ports.ConsentRepository,
-	uRepo ports.UserRepository,
+	hierarchy ports.HierarchyService,
 	audit AuditService,
+	opts ...CustomerServiceOption,
 ) *customerService {
-	return &customerService{
+	s := &customerService{
 		customerRepo:     cRepo,
 		addressRepo:      aRepo,
 		identityRepo:     iRepo,
 		relationshipRepo: rRepo,
 		consentRepo:      cnRepo,
-		userRepo:         uRepo,
+		hierarchy:        hierarchy,
 		auditService:     audit,
+		phoneCountry:     "66",
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // --- Customer Core ---
 
-func (s *customerService) CreateCustomer(ctx context.Context, c *domain.Customer) error {
+func (s *customerService) CreateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
 	// Add Validation Logic Here
+	c.PointsBalance = decimal.Zero // derived from the points ledger
+	if s.holdings != nil {
+		if c.PortfolioValues != nil {
+			return fmt.Errorf("%w: portfolio_values are derived from holdings", domain.ErrConflict)
+		}
+		c.PortfolioSize = decimal.Zero // derived from holdings
+	}
+	if err := s.toBaseCurrency(ctx, c); err != nil {
+		return err
+	}
+	if err := s.derivePortfolioSize(ctx, c); err != nil {
+		return err
+	}
+	if err := s.checkExternalRefs(ctx, c); err != nil {
+		return err
+	}
+
+	var change *domain.TierChange
+	if s.tiers != nil {
+		var err error
+		if change, err = s.tiers.Assign(ctx, c, nil, domain.TierSourceCreate); err != nil {
+			return err
+		}
+	}
+
+	c.CustomerNumber = ""
+	if s.numbers != nil {
+		var err error
+		if c.CustomerNumber, err = s.numbers.Next(ctx); err != nil {
+			return err
+		}
+	}
+
 	err := s.customerRepo.Create(ctx, c)
 	if err == nil {
-		s.auditService.Log(ctx, c.ID, "CUSTOMER", "CREATE", "SYSTEM", "Created Customer", "")
+		s.auditService.Log(ctx, c.ID, "CUSTOMER", "CREATE", userID.String(), "Created Customer", "")
+		s.recordTierChange(ctx, c.ID, change)
+		err = s.savePortfolioValues(ctx, c)
+	}
+	if err == nil {
+		err = s.saveExternalRefs(ctx, c, userID)
 	}
 	return err
 }
 
 func (s *customerService) GetCustomer(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
-	return s.customerRepo.GetByID(ctx, id)
+	return s.GetCustomerFields(ctx, id, nil)
 }
 
-func (s *customerService) UpdateCustomer(ctx context.Context, c *domain.Customer) error {
-	err := s.customerRepo.Update(ctx, c)
+// GetCustomerFields skips the portfolio value and external reference lookups unless selected.
+func (s *customerService) GetCustomerFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error) {
+	c, err := s.customerRepo.GetFields(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	if s.portfolioValues != nil && fields.Has("portfolio_values") {
+		if c.PortfolioValues, err = s.portfolioValues.List(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	if s.externalRefs != nil && fields.Has("external_refs") {
+		if c.ExternalRefs, err = s.externalRefs.ListByCustomerID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *customerService) UpdateCustomer(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
+	if s.holdings != nil && c.PortfolioValues != nil {
+		return fmt.Errorf("%w: portfolio_values are derived from holdings", domain.ErrConflict)
+	}
+	if err := s.toBaseCurrency(ctx, c); err != nil {
+		return err
+	}
+	if err := s.derivePortfolioSize(ctx, c); err != nil {
+		return err
+	}
+
+	prev, err := s.customerRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkExternalRefs(ctx, c); err != nil {
+		return err
+	}
+	c.CustomerNumber = prev.CustomerNumber
+	if s.holdings != nil {
+		c.PortfolioSize = prev.PortfolioSize
+	}
+
+	var change *domain.TierChange
+	if s.tiers != nil {
+		c.PointsBalance = prev.PointsBalance
+		if change, err = s.tiers.Assign(ctx, c, prev, domain.TierSourceUpdate); err != nil {
+			return err
+		}
+	}
+
+	err = s.customerRepo.Update(ctx, c)
 	if err == nil {
-		s.auditService.Log(ctx, c.ID, "CUSTOMER", "UPDATE", "SYSTEM", "Updated Customer", "")
+		s.auditService.Log(ctx, c.ID, "CUSTOMER", "UPDATE", userID.String(), "Updated Customer", "")
+		if c.Status != "" && c.Status != prev.Status {
+			s.auditService.Log(ctx, c.ID, "CUSTOMER", "STATUS_CHANGE", userID.String(),
+				fmt.Sprintf("%s -> %s", prev.Status, c.Status), "")
+		}
+		s.recordTierChange(ctx, c.ID, change)
+		err = s.savePortfolioValues(ctx, c)
+	}
+	if err == nil {
+		err = s.saveExternalRefs(ctx, c, userID)
 	}
 	return err
 }
 
+// toBaseCurrency converts CLV and PortfolioSize sent in another currency to the base currency
+// they are stored in, so a foreign amount is never taken for THB. No currency means THB.
+func (s *customerService) toBaseCurrency(ctx context.Context, c *domain.Customer) error {
+	currency := strings.ToUpper(c.Currency)
+	if currency != "" && currency != domain.BaseCurrency {
+		if s.fx == nil {
+			return fmt.Errorf("amounts in %s are not supported; send %s", currency, domain.BaseCurrency)
+		}
+		clv, err := s.fx.ToBase(ctx, domain.Money{Amount: c.CLV, Currency: currency})
+		if err != nil {
+			return err
+		}
+		portfolio, err := s.fx.ToBase(ctx, domain.Money{Amount: c.PortfolioSize, Currency: currency})
+		if err != nil {
+			return err
+		}
+		c.CLV, c.PortfolioSize = clv.Amount, portfolio.Amount
+	}
+	c.Currency = domain.BaseCurrency
+	return nil
+}
+
+// derivePortfolioSize sets PortfolioSize to the THB total of PortfolioValues. A nil slice leaves
+// PortfolioSize as sent; an empty one clears the values.
+func (s *customerService) derivePortfolioSize(ctx context.Context, c *domain.Customer) error {
+	if c.PortfolioValues == nil {
+		return nil
+	}
+	if s.fx == nil {
+		return errors.New("portfolio_values are not supported")
+	}
+	total, err := s.fx.SumToBase(ctx, c.PortfolioValues)
+	if err != nil {
+		return err
+	}
+	for i := range c.PortfolioValues {
+		c.PortfolioValues[i].Currency = strings.ToUpper(c.PortfolioValues[i].Currency)
+	}
+	c.PortfolioSize = total.Amount
+	return nil
+}
+
+func (s *customerService) savePortfolioValues(ctx context.Context, c *domain.Customer) error {
+	if c.PortfolioValues == nil || s.portfolioValues == nil {
+		return nil
+	}
+	return s.portfolioValues.Replace(ctx, c.ID, c.PortfolioValues)
+}
+
+// checkExternalRefs normalizes the references sent and refuses any held by another customer,
+// before the customer row is written. A nil slice leaves the references as they are.
+func (s *customerService) checkExternalRefs(ctx context.Context, c *domain.Customer) error {
+	if c.ExternalRefs == nil {
+		return nil
+	}
+	if s.externalRefs == nil {
+		return errors.New("external_refs are not supported")
+	}
+	seen := map[string]bool{}
+	for i := range c.ExternalRefs {
+		ref := &c.ExternalRefs[i]
+		if err := ref.Normalize(); err != nil {
+			return err
+		}
+		if seen[ref.System+"/"+ref.Key] {
+			return fmt.Errorf("duplicate external ref %s %s", ref.System, ref.Key)
+		}
+		seen[ref.System+"/"+ref.Key] = true
+
+		existing, err := s.externalRefs.Find(ctx, ref.System, ref.Key)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.CustomerID != c.ID {
+			return fmt.Errorf("%w: %s %s belongs to customer %s", domain.ErrConflict, ref.System, ref.Key, existing.CustomerID)
+		}
+	}
+	return nil
+}
+
+func (s *customerService) saveExternalRefs(ctx context.Context, c *domain.Customer, userID uuid.UUID) error {
+	if c.ExternalRefs == nil {
+		return nil
+	}
+	if err := s.externalRefs.Replace(ctx, c.ID, c.ExternalRefs); err != nil {
+		return err
+	}
+	keys := make([]string, len(c.ExternalRefs))
+	for i, ref := range c.ExternalRefs {
+		keys[i] = ref.System + "=" + ref.Key
+	}
+	s.auditService.Log(ctx, c.ID, "CUSTOMER", "EXTERNAL_REFS_UPDATE", userID.String(), strings.Join(keys, ", "), "")
+	return nil
+}
+
+// recordTierChange stores tier history after the customer row is written. The row is the
+// source of truth, so a failure here is logged rather than failing the request.
+func (s *customerService) recordTierChange(ctx context.Context, customerID uuid.UUID, change *domain.TierChange) {
+	if change == nil {
+		return
+	}
+	change.CustomerID = customerID
+	if err := s.tiers.RecordChange(ctx, change); err != nil {
+		log.Printf("tier history for customer %s: %v", customerID, err)
+	}
+}
+
+// ChangeCustomerStatus writes only the status, so an approved blacklist neither resends the
+// customer's derived fields through UpdateCustomer nor overwrites edits made meanwhile.
+func (s *customerService) ChangeCustomerStatus(ctx context.Context, id uuid.UUID, change domain.StatusChange, userID uuid.UUID) error {
+	if err := s.customerRepo.UpdateStatus(ctx, id, change.From, change.To); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, id, "CUSTOMER", "STATUS_CHANGE", userID.String(),
+		fmt.Sprintf("%s -> %s", change.From, change.To), "")
+	if s.tiers != nil {
+		// Status is a tier fact. The status is already stored, so a failure is logged; the
+		// nightly recalculation corrects it.
+		if _, err := s.tiers.Reevaluate(ctx, id, domain.TierSourceUpdate); err != nil {
+			log.Printf("tier re-evaluation for customer %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
 func (s *customerService) DeleteCustomer(ctx context.Context, id, userID uuid.UUID) error {
 	// Note: We should ideally update the DeletedBy field in the repo.
 	// The repo Delete method needs to accept userID or we do an update first?
@@ -79,70 +380,161 @@ func (s *customerService) DeleteCustomer(ctx context.Context, id, userID uuid.UU
 	return err
 }
 
+// MergeCustomer repoints the duplicate's external references first, so lookups from other
+// systems never resolve to a deleted customer; its other records stay with it and can still be
+// read among the deleted customers.
+func (s *customerService) MergeCustomer(ctx context.Context, id, intoID, userID uuid.UUID) error {
+	if err := s.CheckMerge(ctx, id, intoID); err != nil {
+		return err
+	}
+	// The references move and the duplicate is deleted in one transaction.
+	if _, err := s.refService.Merge(ctx, id, intoID, userID); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, id, "CUSTOMER", "MERGE", userID.String(), "Merged into "+intoID.String(), "")
+	s.auditService.Log(ctx, intoID, "CUSTOMER", "MERGE", userID.String(), "Merged "+id.String(), "")
+	return nil
+}
+
+func (s *customerService) CheckMerge(ctx context.Context, id, intoID uuid.UUID) error {
+	if s.refService == nil {
+		return errors.New("customer merge is not configured")
+	}
+	if id == intoID {
+		return fmt.Errorf("%w: cannot merge a customer into itself", domain.ErrConflict)
+	}
+	for _, cid := range []uuid.UUID{id, intoID} {
+		if _, err := s.customerRepo.GetByID(ctx, cid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchCustomers runs a typed search (see domain.ParseCustomerSearch) and returns the 20
+// newest matches.
 func (s *customerService) SearchCustomers(ctx context.Context, query string) ([]*domain.Customer, error) {
-	return s.customerRepo.Search(ctx, query)
+	q := domain.CustomerQuery{Limit: 20}
+	if err := domain.ParseCustomerSearch(query, &q.Filter); err != nil {
+		return nil, err
+	}
+	page, err := s.QueryCustomers(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return page.Customers, nil
 }
 
-func (s *customerService) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	return s.customerRepo.List(ctx, limit, offset)
+func (s *customerService) QueryCustomers(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	s.normalizePhone(&q.Filter)
+	return s.customerRepo.Query(ctx, q)
 }
 
-func (s *customerService) ListDeletedCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	return s.customerRepo.ListDeleted(ctx, limit, offset)
+func (s *customerService) MatchingCustomerIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error) {
+	if err := f.Normalize(); err != nil {
+		return nil, err
+	}
+	s.normalizePhone(&f)
+	return s.customerRepo.MatchingIDs(ctx, f)
+}
+
+// normalizePhone puts a phone filter in E.164 as contact points are stored; a number that does
+// not normalize simply matches nothing.
+func (s *customerService) normalizePhone(f *domain.CustomerFilter) {
+	if f.Phone == "" {
+		return
+	}
+	if phone, err := validation.NormalizePhone(f.Phone, s.phoneCountry); err == nil {
+		f.Phone = phone
+	}
+}
+
+func (s *customerService) ListCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	return s.customerRepo.List(ctx, p)
+}
+
+func (s *customerService) ListDeletedCustomers(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	return s.customerRepo.ListDeleted(ctx, p)
+}
+
+func (s *customerService) CountDeletedCustomers(ctx context.Context) (int, error) {
+	return s.customerRepo.CountDeleted(ctx)
 }
 
 func (s *customerService) RestoreCustomer(ctx context.Context, id, userID uuid.UUID) error {
+	if err := s.restorable(ctx, id, userID); err != nil {
+		return err
+	}
+
+	err := s.customerRepo.Restore(ctx, id)
+	if err == nil {
+		s.auditService.Log(ctx, id, "CUSTOMER", "RESTORE", userID.String(), "Restored Customer", "")
+	}
+	return err
+}
+
+// restorable checks that userID may restore the deleted customer.
+func (s *customerService) restorable(ctx context.Context, id, userID uuid.UUID) error {
 	// 1. Get Customer to see who deleted it
-	customer, err := s.customerRepo.GetByID(ctx, id)
+	customer, err := s.customerRepo.GetDeletedByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	
+
 	if customer.DeletedBy == nil {
-		// If no one tracked as deleter, maybe allow admin or anyone? 
-		// For strictness, let's say only if we know who deleted it, strict rules apply.
-		// If nil, maybe legacy data? Allow restore? 
-		// Let's assume allow for now if legacy, or restrict. 
-		// User requirement: "allowed only the user who delete the record to restore and their supervisor only"
-		// If DeletedBy is nil, we can't verify. Fail safe: allow Admin? 
-		// Handler doesn't check role for restore.
-		// Let's require DeletedBy to be present for this specific restricted logic.
-		// But for legacy support, if DeletedBy is nil, we might block.
-		return errors.New("cannot restore record with unknown deleter")
-	}
-
-	// 2. Check if Restorer is the Deleter
-	if *customer.DeletedBy == userID {
-		// Allowed
-	} else {
-		// 3. Check if Restorer is the Supervisor of the Deleter
-		// Need to get Deleter's profile to check supervisor_id
-		deleter, err := s.userRepo.GetByID(ctx, *customer.DeletedBy)
-		if err != nil {
-			return errors.New("failed to verify deleter identity")
-		}
-		
-		if deleter.SupervisorID == nil || *deleter.SupervisorID != userID {
-			return errors.New("forbidden: only the deleter or their supervisor can restore")
-		}
+		// Legacy rows predate deleted_by tracking; the deleter cannot be verified, so these
+		// go through OverrideRestoreCustomer with an admin justification instead.
+		return fmt.Errorf("%w: cannot restore record with unknown deleter: admin override with justification required", domain.ErrConflict)
+	}
+
+	// 2. Restorer must be the deleter, one of their supervisors (up the configured
+	// number of levels), or someone holding an active restore delegation from one of those supervisors.
+	allowed, err := s.hierarchy.CanActFor(ctx, userID, *customer.DeletedBy, models.DelegationScopeRestore)
+	if err != nil {
+		return errors.New("failed to verify deleter identity")
+	}
+	if !allowed {
+		return fmt.Errorf("%w: only the deleter or their supervisor can restore", domain.ErrForbidden)
+	}
+	return nil
+}
+
+// OverrideRestoreCustomer lets an admin restore a legacy record whose deleter was never
+// recorded. The justification is mandatory and kept in the audit log.
+func (s *customerService) OverrideRestoreCustomer(ctx context.Context, id, userID uuid.UUID, justification string) error {
+	if err := s.overrideRestorable(ctx, id, justification); err != nil {
+		return err
 	}
 
-	err = s.customerRepo.Restore(ctx, id)
+	err := s.customerRepo.Restore(ctx, id)
 	if err == nil {
-		s.auditService.Log(ctx, id, "CUSTOMER", "RESTORE", userID.String(), "Restored Customer", "")
+		s.auditService.Log(ctx, id, "CUSTOMER", "RESTORE_OVERRIDE", userID.String(), justification, "")
 	}
 	return err
 }
 
-func (s *customerService) AnonymizeCustomer(ctx context.Context, id uuid.UUID) error {
-	c, err := s.customerRepo.GetByID(ctx, id)
+func (s *customerService) overrideRestorable(ctx context.Context, id uuid.UUID, justification string) error {
+	if strings.TrimSpace(justification) == "" {
+		return errors.New("justification is required for restore override")
+	}
+
+	customer, err := s.customerRepo.GetDeletedByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if customer.DeletedBy != nil {
+		return fmt.Errorf("%w: restore override is only available for records with unknown deleter", domain.ErrConflict)
+	}
+	return nil
+}
 
-	// Check for active products (Condition 1)
-	if c.PortfolioSize > 0 {
-		return errors.New("cannot anonymize customer with active portfolio")
+func (s *customerService) AnonymizeCustomer(ctx context.Context, id, userID uuid.UUID) error {
+	c, err := s.anonymizable(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	// Anonymize PII (Condition 2)
@@ -157,7 +549,7 @@ func (s *customerService) AnonymizeCustomer(ctx context.Context, id uuid.UUID) e
 
 	err = s.customerRepo.Update(ctx, c)
 	if err == nil {
-		s.auditService.Log(ctx, id, "CUSTOMER", "ANONYMIZE", "SYSTEM", "Anonymized Customer", "")
+		s.auditService.Log(ctx, id, "CUSTOMER", "ANONYMIZE", userID.String(), "Anonymized Customer", "")
 	}
 	// Note: Identities and Addresses should also be deleted or anonymized!
 	// For now, let's delete them as they are sensitive
@@ -169,59 +561,264 @@ func (s *customerService) AnonymizeCustomer(ctx context.Context, id uuid.UUID) e
 	return err
 }
 
+// anonymizable loads the customer and checks that it holds no active products (Condition 1).
+func (s *customerService) anonymizable(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	c, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.hasActivePortfolio(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, fmt.Errorf("%w: cannot anonymize customer with active portfolio", domain.ErrConflict)
+	}
+	return c, nil
+}
+
+// CheckChange runs the checks of a sensitive operation without applying it, so a change
+// request that could never be applied is refused when it is submitted rather than when a
+// checker approves it. justification is that of a restore override, if any.
+func (s *customerService) CheckChange(ctx context.Context, op domain.ChangeOperation, id, userID uuid.UUID, justification string) error {
+	switch op {
+	case domain.OpDeleteCustomer:
+		_, err := s.customerRepo.GetByID(ctx, id)
+		return err
+	case domain.OpRestoreCustomer:
+		if justification != "" {
+			return s.overrideRestorable(ctx, id, justification)
+		}
+		return s.restorable(ctx, id, userID)
+	case domain.OpAnonymizeCustomer:
+		_, err := s.anonymizable(ctx, id)
+		return err
+	case domain.OpBlacklistCustomer:
+		c, err := s.customerRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c.Status == domain.StatusBlacklist {
+			return fmt.Errorf("%w: customer is already %s", domain.ErrConflict, domain.StatusBlacklist)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported operation %q", op)
+}
+
+// hasActivePortfolio reports whether the customer still holds anything. With holdings any active
+// holding counts, whatever the role; otherwise a positive PortfolioSize does.
+func (s *customerService) hasActivePortfolio(ctx context.Context, c *domain.Customer) (bool, error) {
+	if s.holdings == nil {
+		return c.PortfolioSize.IsPositive(), nil
+	}
+	holdings, err := s.holdings.ListByCustomerID(ctx, c.ID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, h := range holdings {
+		if h.IsActive(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // --- Addresses ---
 
-func (s *customerService) AddAddress(ctx context.Context, a *domain.Address) error {
-	return s.addressRepo.Create(ctx, a)
+func (s *customerService) AddAddress(ctx context.Context, a *domain.Address, userID uuid.UUID) error {
+	a.CreatedBy = &userID
+	err := s.addressRepo.Create(ctx, a)
+	if err == nil {
+		s.auditService.Log(ctx, a.CustomerID, "CUSTOMER", "ADDRESS_ADD", userID.String(), fmt.Sprintf("address=%s type=%s", a.ID, a.Type), "")
+	}
+	return err
 }
 
 func (s *customerService) GetAddresses(ctx context.Context, customerID uuid.UUID) ([]*domain.Address, error) {
 	return s.addressRepo.ListByCustomerID(ctx, customerID)
 }
 
-func (s *customerService) RemoveAddress(ctx context.Context, id uuid.UUID) error {
-	return s.addressRepo.Delete(ctx, id)
+// RemoveAddress deletes one of the customer's addresses. Sub-resource removals are audited
+// against the customer so they show up in the customer's timeline.
+func (s *customerService) RemoveAddress(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	addresses, err := s.addressRepo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	for _, a := range addresses {
+		if a.ID != id {
+			continue
+		}
+		if err := s.addressRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		s.auditService.Log(ctx, customerID, "CUSTOMER", "ADDRESS_REMOVE", userID.String(), fmt.Sprintf("address=%s type=%s", a.ID, a.Type), "")
+		return nil
+	}
+	return fmt.Errorf("address %w", domain.ErrNotFound)
 }
 
 // --- Identities ---
 
-func (s *customerService) AddIdentity(ctx context.Context, i *domain.Identity) error {
+func (s *customerService) AddIdentity(ctx context.Context, i *domain.Identity, userID uuid.UUID) error {
 	if i.Type == "National ID" {
 		if err := validation.ValidateThaiID(i.Number); err != nil {
 			return err
 		}
 	}
-	return s.identityRepo.Create(ctx, i)
+	i.CreatedBy = &userID
+	err := s.identityRepo.Create(ctx, i)
+	if err == nil {
+		s.auditService.Log(ctx, i.CustomerID, "CUSTOMER", "IDENTITY_ADD", userID.String(), fmt.Sprintf("identity=%s type=%s", i.ID, i.Type), "")
+	}
+	return err
 }
 
 func (s *customerService) GetIdentities(ctx context.Context, customerID uuid.UUID) ([]*domain.Identity, error) {
 	return s.identityRepo.ListByCustomerID(ctx, customerID)
 }
 
-func (s *customerService) RemoveIdentity(ctx context.Context, id uuid.UUID) error {
-	return s.identityRepo.Delete(ctx, id)
+func (s *customerService) RemoveIdentity(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	identities, err := s.identityRepo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	for _, i := range identities {
+		if i.ID != id {
+			continue
+		}
+		if err := s.identityRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		s.auditService.Log(ctx, customerID, "CUSTOMER", "IDENTITY_REMOVE", userID.String(), fmt.Sprintf("identity=%s type=%s", i.ID, i.Type), "")
+		return nil
+	}
+	return fmt.Errorf("identity %w", domain.ErrNotFound)
 }
 
 // --- Relationships ---
 
-func (s *customerService) AddRelationship(ctx context.Context, r *domain.Relationship) error {
-	return s.relationshipRepo.Create(ctx, r)
+func (s *customerService) AddRelationship(ctx context.Context, r *domain.Relationship, userID uuid.UUID) error {
+	r.CreatedBy = &userID
+	err := s.relationshipRepo.Create(ctx, r)
+	if err == nil {
+		s.auditService.Log(ctx, r.FromCustomerID, "CUSTOMER", "RELATIONSHIP_ADD", userID.String(), fmt.Sprintf("relationship=%s role=%s to=%s", r.ID, r.Role, r.ToCustomerID), "")
+	}
+	return err
 }
 
 func (s *customerService) GetRelationships(ctx context.Context, customerID uuid.UUID) ([]*domain.Relationship, error) {
 	return s.relationshipRepo.ListByCustomerID(ctx, customerID)
 }
 
-func (s *customerService) RemoveRelationship(ctx context.Context, id uuid.UUID) error {
-	return s.relationshipRepo.Delete(ctx, id)
+func (s *customerService) RemoveRelationship(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	rels, err := s.relationshipRepo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	for _, r := range rels {
+		if r.ID != id {
+			continue
+		}
+		if err := s.relationshipRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		s.auditService.Log(ctx, customerID, "CUSTOMER", "RELATIONSHIP_REMOVE", userID.String(), fmt.Sprintf("relationship=%s role=%s to=%s", r.ID, r.Role, r.ToCustomerID), "")
+		return nil
+	}
+	return fmt.Errorf("relationship %w", domain.ErrNotFound)
 }
 
 // --- Consents ---
 
-func (s *customerService) ManageConsent(ctx context.Context, c *domain.Consent) error {
+func (s *customerService) ManageConsent(ctx context.Context, c *domain.Consent, userID uuid.UUID) error {
+	c.CreatedBy = &userID
 	return s.consentRepo.Create(ctx, c)
 }
 
 func (s *customerService) GetConsents(ctx context.Context, customerID uuid.UUID) ([]*domain.Consent, error) {
 	return s.consentRepo.ListByCustomerID(ctx, customerID)
 }
+
+// --- Batch reads ---
+
+// GetCustomersByIDs loads many customers in one repository call. Portfolio values and external
+// references are not attached.
+func (s *customerService) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.customerRepo.GetByIDs(ctx, ids)
+}
+
+func (s *customerService) GetAddressesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Address, error) {
+	out := make(map[uuid.UUID][]*domain.Address, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	addresses, err := s.addressRepo.ListByCustomerIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addresses {
+		out[a.CustomerID] = append(out[a.CustomerID], a)
+	}
+	return out, nil
+}
+
+func (s *customerService) GetIdentitiesByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Identity, error) {
+	out := make(map[uuid.UUID][]*domain.Identity, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	identities, err := s.identityRepo.ListByCustomerIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range identities {
+		out[i.CustomerID] = append(out[i.CustomerID], i)
+	}
+	return out, nil
+}
+
+// GetRelationshipsByCustomerIDs groups each relationship under whichever of its ends were asked for.
+func (s *customerService) GetRelationshipsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Relationship, error) {
+	out := make(map[uuid.UUID][]*domain.Relationship, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rels, err := s.relationshipRepo.ListByCustomerIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for _, r := range rels {
+		if wanted[r.FromCustomerID] {
+			out[r.FromCustomerID] = append(out[r.FromCustomerID], r)
+		}
+		if wanted[r.ToCustomerID] && r.ToCustomerID != r.FromCustomerID {
+			out[r.ToCustomerID] = append(out[r.ToCustomerID], r)
+		}
+	}
+	return out, nil
+}
+
+func (s *customerService) GetConsentsByCustomerIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*domain.Consent, error) {
+	out := make(map[uuid.UUID][]*domain.Consent, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	consents, err := s.consentRepo.ListByCustomerIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range consents {
+		out[c.CustomerID] = append(out[c.CustomerID], c)
+	}
+	return out, nil
+}
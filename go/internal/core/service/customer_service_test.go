@@ -2,21 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/amnuaym/cic/go/internal/core/domain"
 	"github.com/amnuaym/cic/go/internal/models"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // Mock CustomerRepository
 type mockCustomerRepo struct {
-	createFunc  func(ctx context.Context, c *domain.Customer) error
-	getByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
-	updateFunc  func(ctx context.Context, c *domain.Customer) error
-	deleteFunc  func(ctx context.Context, id, userID uuid.UUID) error
-	restoreFunc func(ctx context.Context, id uuid.UUID) error
-	searchFunc  func(ctx context.Context, query string) ([]*domain.Customer, error)
+	createFunc         func(ctx context.Context, c *domain.Customer) error
+	getByIDFunc        func(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
+	getDeletedByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.Customer, error)
+	updateFunc         func(ctx context.Context, c *domain.Customer) error
+	updateStatusFunc   func(ctx context.Context, id uuid.UUID, from, to domain.CustomerStatus) error
+	deleteFunc         func(ctx context.Context, id, userID uuid.UUID) error
+	restoreFunc        func(ctx context.Context, id uuid.UUID) error
+	searchFunc         func(ctx context.Context, query string) ([]*domain.Customer, error)
+	queryFunc          func(ctx context.Context, q domain.CustomerQuery) ([]*domain.Customer, int, error)
+	matchingIDsFunc    func(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error)
 }
 
 func (m *mockCustomerRepo) Create(ctx context.Context, c *domain.Customer) error {
@@ -25,9 +32,30 @@ func (m *mockCustomerRepo) Create(ctx context.Context, c *domain.Customer) error
 func (m *mockCustomerRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
 	return m.getByIDFunc(ctx, id)
 }
+func (m *mockCustomerRepo) GetFields(ctx context.Context, id uuid.UUID, fields domain.FieldSet) (*domain.Customer, error) {
+	return m.getByIDFunc(ctx, id)
+}
+func (m *mockCustomerRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Customer, error) {
+	var out []*domain.Customer
+	for _, id := range ids {
+		if c, err := m.getByIDFunc(ctx, id); err == nil {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (m *mockCustomerRepo) GetDeletedByID(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+	if m.getDeletedByIDFunc != nil {
+		return m.getDeletedByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
 func (m *mockCustomerRepo) Update(ctx context.Context, c *domain.Customer) error {
 	return m.updateFunc(ctx, c)
 }
+func (m *mockCustomerRepo) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.CustomerStatus) error {
+	return m.updateStatusFunc(ctx, id, from, to)
+}
 func (m *mockCustomerRepo) Delete(ctx context.Context, id, userID uuid.UUID) error {
 	return m.deleteFunc(ctx, id, userID)
 }
@@ -40,12 +68,28 @@ func (m *mockCustomerRepo) Restore(ctx context.Context, id uuid.UUID) error {
 func (m *mockCustomerRepo) Search(ctx context.Context, query string) ([]*domain.Customer, error) {
 	return m.searchFunc(ctx, query)
 }
-func (m *mockCustomerRepo) List(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	return nil, nil
+func (m *mockCustomerRepo) Query(ctx context.Context, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	customers, total, err := m.queryFunc(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CustomerPage{Customers: customers, TotalCount: total}, nil
 }
-func (m *mockCustomerRepo) ListDeleted(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
+func (m *mockCustomerRepo) MatchingIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error) {
+	if m.matchingIDsFunc != nil {
+		return m.matchingIDsFunc(ctx, f)
+	}
 	return nil, nil
 }
+func (m *mockCustomerRepo) List(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	return &domain.Page[*domain.Customer]{Items: []*domain.Customer{}}, nil
+}
+func (m *mockCustomerRepo) ListDeleted(ctx context.Context, p domain.PageRequest) (*domain.Page[*domain.Customer], error) {
+	return &domain.Page[*domain.Customer]{Items: []*domain.Customer{}}, nil
+}
+func (m *mockCustomerRepo) CountDeleted(ctx context.Context) (int, error) {
+	return 0, nil
+}
 
 // Mock AuditService
 type mockAuditService struct {
@@ -67,6 +111,9 @@ func (m *mockAddressRepo) Create(ctx context.Context, a *domain.Address) error {
 func (m *mockAddressRepo) ListByCustomerID(ctx context.Context, id uuid.UUID) ([]*domain.Address, error) {
 	return nil, nil
 }
+func (m *mockAddressRepo) ListByCustomerIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Address, error) {
+	return nil, nil
+}
 func (m *mockAddressRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return m.deleteFunc(ctx, id)
 }
@@ -78,18 +125,26 @@ func (m *mockIdentityRepo) Create(ctx context.Context, i *domain.Identity) error
 func (m *mockIdentityRepo) ListByCustomerID(ctx context.Context, id uuid.UUID) ([]*domain.Identity, error) {
 	return nil, nil
 }
+func (m *mockIdentityRepo) ListByCustomerIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Identity, error) {
+	return nil, nil
+}
 func (m *mockIdentityRepo) GetByNumber(ctx context.Context, number string) (*domain.Identity, error) {
 	return nil, nil
 }
 func (m *mockIdentityRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
 
 // Mock RelationshipRepo
-type mockRelationshipRepo struct{}
+type mockRelationshipRepo struct {
+	rels []*domain.Relationship
+}
 
 func (m *mockRelationshipRepo) Create(ctx context.Context, r *domain.Relationship) error { return nil }
 func (m *mockRelationshipRepo) ListByCustomerID(ctx context.Context, id uuid.UUID) ([]*domain.Relationship, error) {
 	return nil, nil
 }
+func (m *mockRelationshipRepo) ListByCustomerIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Relationship, error) {
+	return m.rels, nil
+}
 func (m *mockRelationshipRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
 
 // Mock ConsentRepo
@@ -99,6 +154,9 @@ func (m *mockConsentRepo) Create(ctx context.Context, c *domain.Consent) error {
 func (m *mockConsentRepo) ListByCustomerID(ctx context.Context, id uuid.UUID) ([]*domain.Consent, error) {
 	return nil, nil
 }
+func (m *mockConsentRepo) ListByCustomerIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Consent, error) {
+	return nil, nil
+}
 
 // Mock UserRepo
 type mockUserRepo struct {
@@ -132,7 +190,7 @@ func TestCreateCustomer(t *testing.T) {
 	svc := NewCustomerService(mockRepo, nil, nil, nil, nil, nil, mockAudit)
 
 	c := &domain.Customer{FirstName: "John", LastName: "Doe", Type: domain.TypePersonal}
-	err := svc.CreateCustomer(context.Background(), c)
+	err := svc.CreateCustomer(context.Background(), c, uuid.New())
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -145,7 +203,7 @@ func TestAnonymizeCustomer(t *testing.T) {
 	cid := uuid.New()
 	mockRepo := &mockCustomerRepo{
 		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
-			return &domain.Customer{ID: cid, FirstName: "John", LastName: "Doe", PortfolioSize: 0}, nil
+			return &domain.Customer{ID: cid, FirstName: "John", LastName: "Doe", PortfolioSize: decimal.Zero}, nil
 		},
 		updateFunc: func(ctx context.Context, c *domain.Customer) error {
 			if c.FirstName == "John" {
@@ -169,7 +227,7 @@ func TestAnonymizeCustomer(t *testing.T) {
 
 	svc := NewCustomerService(mockRepo, mockAddress, nil, nil, nil, nil, mockAudit)
 
-	err := svc.AnonymizeCustomer(context.Background(), cid)
+	err := svc.AnonymizeCustomer(context.Background(), cid, uuid.New())
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -179,17 +237,126 @@ func TestAnonymizeCustomer_ActivePortfolio(t *testing.T) {
 	cid := uuid.New()
 	mockRepo := &mockCustomerRepo{
 		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
-			return &domain.Customer{ID: cid, PortfolioSize: 5}, nil
+			return &domain.Customer{ID: cid, PortfolioSize: decimal.NewFromInt(5)}, nil
 		},
 	}
 
 	svc := NewCustomerService(mockRepo, nil, nil, nil, nil, nil, nil)
 
-	err := svc.AnonymizeCustomer(context.Background(), cid)
+	err := svc.AnonymizeCustomer(context.Background(), cid, uuid.New())
 	if err == nil {
 		t.Errorf("Expected error for active portfolio")
 	}
-	if err.Error() != "cannot anonymize customer with active portfolio" {
+	if err.Error() != "conflict: cannot anonymize customer with active portfolio" {
 		t.Errorf("Expected specific error message, got %s", err.Error())
 	}
 }
+
+func TestRestoreCustomer_DelegateOfSupervisor(t *testing.T) {
+	cid, deleter, supervisor, delegate := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	restored := false
+	mockRepo := &mockCustomerRepo{
+		getDeletedByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: cid, DeletedBy: &deleter}, nil
+		},
+		restoreFunc: func(ctx context.Context, id uuid.UUID) error {
+			restored = true
+			return nil
+		},
+	}
+	delegations := &mockDelegationRepo{delegations: []*models.Delegation{{
+		DelegatorID: supervisor,
+		DelegateID:  delegate,
+		Scope:       models.DelegationScopeRestore,
+		StartsAt:    time.Now().Add(-time.Hour),
+		EndsAt:      time.Now().Add(time.Hour),
+	}}}
+	hierarchy := NewHierarchyService(orgChart(map[uuid.UUID]uuid.UUID{deleter: supervisor}), nil, delegations, &mockAuditService{}, 1)
+
+	svc := NewCustomerService(mockRepo, nil, nil, nil, nil, hierarchy, &mockAuditService{})
+
+	if err := svc.RestoreCustomer(context.Background(), cid, uuid.New()); err == nil {
+		t.Errorf("Expected unrelated user to be refused")
+	}
+	if err := svc.RestoreCustomer(context.Background(), cid, delegate); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !restored {
+		t.Errorf("Expected customer to be restored by delegate")
+	}
+}
+
+func TestOverrideRestoreCustomer_RequiresJustification(t *testing.T) {
+	cid := uuid.New()
+	mockRepo := &mockCustomerRepo{
+		getDeletedByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: cid}, nil
+		},
+	}
+	var logged string
+	mockAudit := &mockAuditService{
+		logFunc: func(ctx context.Context, entityID uuid.UUID, entityType, action, performedBy, changes, ip string) {
+			logged = action + ": " + changes
+		},
+	}
+
+	svc := NewCustomerService(mockRepo, nil, nil, nil, nil, nil, mockAudit)
+
+	if err := svc.RestoreCustomer(context.Background(), cid, uuid.New()); err == nil {
+		t.Errorf("Expected legacy row to need an override")
+	}
+	if err := svc.OverrideRestoreCustomer(context.Background(), cid, uuid.New(), " "); err == nil {
+		t.Errorf("Expected blank justification to be rejected")
+	}
+	if err := svc.OverrideRestoreCustomer(context.Background(), cid, uuid.New(), "ticket INC-42: deleted in 2019 migration"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if logged != "RESTORE_OVERRIDE: ticket INC-42: deleted in 2019 migration" {
+		t.Errorf("Expected justification in audit log, got %q", logged)
+	}
+}
+
+func TestSearchCustomers_TypedTerms(t *testing.T) {
+	var got domain.CustomerFilter
+	repo := &mockCustomerRepo{
+		queryFunc: func(ctx context.Context, q domain.CustomerQuery) ([]*domain.Customer, int, error) {
+			got = q.Filter
+			return []*domain.Customer{}, 0, nil
+		},
+	}
+	svc := NewCustomerService(repo, nil, nil, nil, nil, nil, &mockAuditService{})
+
+	_, err := svc.SearchCustomers(context.Background(),
+		`id:1-2345-67890-12-3 phone:081-234-5678 province:"Chiang Mai" name:somchai jaidee`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.IdentityNumber != "1234567890123" || got.Phone != "+66812345678" || got.Province != "Chiang Mai" || got.Text != "somchai jaidee" {
+		t.Errorf("Terms not parsed: %+v", got)
+	}
+
+	for _, bad := range []string{"passport:AA123", "zip:", "zip:10110 zip:10120"} {
+		if _, err := svc.SearchCustomers(context.Background(), bad); !errors.Is(err, domain.ErrInvalidSearch) {
+			t.Errorf("%s: expected ErrInvalidSearch, got %v", bad, err)
+		}
+	}
+}
+
+func TestGetRelationshipsByCustomerIDs_GroupsUnderBothEnds(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	ab := &domain.Relationship{ID: uuid.New(), FromCustomerID: a, ToCustomerID: b, Role: "SPOUSE"}
+	bc := &domain.Relationship{ID: uuid.New(), FromCustomerID: b, ToCustomerID: c, Role: "DIRECTOR"}
+	rels := &mockRelationshipRepo{rels: []*domain.Relationship{ab, bc}}
+	svc := NewCustomerService(&mockCustomerRepo{}, nil, nil, rels, nil, nil, &mockAuditService{})
+
+	got, err := svc.GetRelationshipsByCustomerIDs(context.Background(), []uuid.UUID{a, b})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got[a]) != 1 || len(got[b]) != 2 {
+		t.Errorf("Expected a under one relationship and b under two, got %d and %d", len(got[a]), len(got[b]))
+	}
+	if _, ok := got[c]; ok {
+		t.Error("Expected c to be left out as it was not asked for")
+	}
+}
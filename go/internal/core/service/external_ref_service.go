@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type externalRefService struct {
+	repo         ports.ExternalRefRepository
+	customerRepo ports.CustomerRepository
+	auditService AuditService
+}
+
+func NewExternalRefService(repo ports.ExternalRefRepository, customerRepo ports.CustomerRepository, audit AuditService) *externalRefService {
+	return &externalRefService{repo: repo, customerRepo: customerRepo, auditService: audit}
+}
+
+func (s *externalRefService) FindCustomerID(ctx context.Context, system, key string) (uuid.UUID, error) {
+	ref := domain.ExternalRef{System: system, Key: key}
+	if err := ref.Normalize(); err != nil {
+		return uuid.Nil, err
+	}
+	found, err := s.repo.Find(ctx, ref.System, ref.Key)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if found == nil {
+		return uuid.Nil, fmt.Errorf("external reference %w", domain.ErrNotFound)
+	}
+	return found.CustomerID, nil
+}
+
+// Merge deletes the merged customer while the surviving one takes over every key it had, so
+// lookups from other systems keep resolving. Both customers must exist.
+func (s *externalRefService) Merge(ctx context.Context, fromCustomerID, toCustomerID, userID uuid.UUID) (int64, error) {
+	if fromCustomerID == toCustomerID {
+		return 0, errors.New("cannot merge a customer into itself")
+	}
+	for _, id := range []uuid.UUID{fromCustomerID, toCustomerID} {
+		if _, err := s.customerRepo.GetByID(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.repo.Merge(ctx, fromCustomerID, toCustomerID, userID)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	performedBy := userID.String()
+	s.auditService.Log(ctx, fromCustomerID, "CUSTOMER", "EXTERNAL_REFS_MOVE", performedBy,
+		fmt.Sprintf("%d moved to %s", n, toCustomerID), "")
+	s.auditService.Log(ctx, toCustomerID, "CUSTOMER", "EXTERNAL_REFS_MOVE", performedBy,
+		fmt.Sprintf("%d moved from %s", n, fromCustomerID), "")
+	return n, nil
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockExternalRefRepo struct {
+	rows    map[string]*domain.ExternalRef // By system/key
+	deleted map[uuid.UUID]bool
+}
+
+func newMockExternalRefRepo() *mockExternalRefRepo {
+	return &mockExternalRefRepo{rows: map[string]*domain.ExternalRef{}, deleted: map[uuid.UUID]bool{}}
+}
+
+func (m *mockExternalRefRepo) Find(ctx context.Context, system, key string) (*domain.ExternalRef, error) {
+	return m.rows[system+"/"+key], nil
+}
+func (m *mockExternalRefRepo) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]domain.ExternalRef, error) {
+	refs := []domain.ExternalRef{}
+	for _, ref := range m.rows {
+		if ref.CustomerID == customerID {
+			refs = append(refs, *ref)
+		}
+	}
+	return refs, nil
+}
+func (m *mockExternalRefRepo) Replace(ctx context.Context, customerID uuid.UUID, refs []domain.ExternalRef) error {
+	for k, ref := range m.rows {
+		if ref.CustomerID == customerID {
+			delete(m.rows, k)
+		}
+	}
+	for i := range refs {
+		ref := refs[i]
+		ref.CustomerID = customerID
+		m.rows[ref.System+"/"+ref.Key] = &ref
+	}
+	return nil
+}
+func (m *mockExternalRefRepo) Merge(ctx context.Context, fromCustomerID, toCustomerID, deletedBy uuid.UUID) (int64, error) {
+	if m.deleted[fromCustomerID] {
+		return 0, domain.ErrNotFound
+	}
+	m.deleted[fromCustomerID] = true
+	var n int64
+	for _, ref := range m.rows {
+		if ref.CustomerID == fromCustomerID {
+			ref.CustomerID = toCustomerID
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestCustomerService_ExternalRefs(t *testing.T) {
+	refs := newMockExternalRefRepo()
+	stored := map[uuid.UUID]*domain.Customer{}
+	repo := &mockCustomerRepo{
+		createFunc: func(ctx context.Context, c *domain.Customer) error {
+			c.ID = uuid.New()
+			stored[c.ID] = c
+			return nil
+		},
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			c, ok := stored[id]
+			if !ok {
+				return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+			}
+			c2 := *c
+			c2.ExternalRefs = nil
+			return &c2, nil
+		},
+		updateFunc: func(ctx context.Context, c *domain.Customer) error { return nil },
+	}
+	svc := NewCustomerService(repo, nil, nil, nil, nil, nil, &mockAuditService{}, WithExternalRefs(refs))
+	ctx := context.Background()
+
+	a := &domain.Customer{Type: domain.TypePersonal, FirstName: "สมชาย", ExternalRefs: []domain.ExternalRef{
+		{System: "cas", Key: " 0000012345 "}, {System: "CRM", Key: "C-1"},
+	}}
+	if err := svc.CreateCustomer(ctx, a, uuid.New()); err != nil {
+		t.Fatalf("CreateCustomer failed: %v", err)
+	}
+	if ref := refs.rows["CAS/0000012345"]; ref == nil || ref.CustomerID != a.ID {
+		t.Fatalf("Expected normalized CAS ref for the new customer, got %+v", refs.rows)
+	}
+
+	b := &domain.Customer{Type: domain.TypePersonal, FirstName: "สมหญิง", ExternalRefs: []domain.ExternalRef{{System: "CAS", Key: "0000012345"}}}
+	if err := svc.CreateCustomer(ctx, b, uuid.New()); !errors.Is(err, domain.ErrConflict) || len(stored) != 1 {
+		t.Errorf("Expected a taken ref to be refused before the customer is created, got %v", err)
+	}
+	for _, bad := range []domain.ExternalRef{{System: "SAP", Key: "1"}, {System: "CAS", Key: " "}} {
+		c := &domain.Customer{Type: domain.TypePersonal, ExternalRefs: []domain.ExternalRef{bad}}
+		if err := svc.CreateCustomer(ctx, c, uuid.New()); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+
+	// Omitting external_refs leaves them alone; sending a list replaces them.
+	if err := svc.UpdateCustomer(ctx, &domain.Customer{ID: a.ID, FirstName: "สมชาย"}, uuid.New()); err != nil || len(refs.rows) != 2 {
+		t.Errorf("Expected refs untouched by an update without them, got %v, %d refs", err, len(refs.rows))
+	}
+	if err := svc.UpdateCustomer(ctx, &domain.Customer{ID: a.ID, ExternalRefs: []domain.ExternalRef{{System: "CAS", Key: "0000012345"}}}, uuid.New()); err != nil {
+		t.Fatalf("UpdateCustomer failed: %v", err)
+	}
+	got, err := svc.GetCustomer(ctx, a.ID)
+	if err != nil || len(got.ExternalRefs) != 1 || got.ExternalRefs[0].System != "CAS" {
+		t.Errorf("Expected only the CAS ref to remain, got %+v, %v", got, err)
+	}
+}
+
+func TestExternalRefService_FindAndMerge(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+	refs := newMockExternalRefRepo()
+	refs.Replace(context.Background(), from, []domain.ExternalRef{{System: "CAS", Key: "0000012345"}, {System: "CORE_BANKING", Key: "88001"}})
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			if id != from && id != to {
+				return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+			}
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	svc := NewExternalRefService(refs, customers, &mockAuditService{})
+	ctx := context.Background()
+
+	if id, err := svc.FindCustomerID(ctx, "cas", "0000012345"); err != nil || id != from {
+		t.Errorf("FindCustomerID = %s, %v; want %s", id, err, from)
+	}
+	if _, err := svc.FindCustomerID(ctx, "CAS", "missing"); err == nil {
+		t.Error("Expected unknown key to be not found")
+	}
+
+	if n, err := svc.Merge(ctx, from, to, uuid.New()); err != nil || n != 2 || !refs.deleted[from] {
+		t.Fatalf("Merge = %d, %v; want 2 and the merged customer deleted", n, err)
+	}
+	if id, _ := svc.FindCustomerID(ctx, "CORE_BANKING", "88001"); id != to {
+		t.Errorf("Expected ref to follow the merge, got %s", id)
+	}
+	if _, err := svc.Merge(ctx, to, uuid.New(), uuid.New()); err == nil || refs.deleted[to] {
+		t.Error("Expected a merge into an unknown customer to fail")
+	}
+}
+
+func TestCustomerService_MergeRepointsRefs(t *testing.T) {
+	dup, survivor, maker := uuid.New(), uuid.New(), uuid.New()
+	refs := newMockExternalRefRepo()
+	refs.Replace(context.Background(), dup, []domain.ExternalRef{{System: "CAS", Key: "0000012345"}})
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			if id != dup && id != survivor {
+				return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+			}
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{},
+		WithMerging(NewExternalRefService(refs, customers, &mockAuditService{})))
+	approvals := NewChangeRequestService(newMockChangeRequestRepo(), svc, &mockAuditService{}, time.Hour)
+	ctx := context.Background()
+
+	if _, err := approvals.Submit(ctx, domain.OpMergeCustomer, dup, maker, domain.CustomerMerge{IntoID: dup}, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected a merge into itself to be refused, got %v", err)
+	}
+	if _, err := approvals.Submit(ctx, domain.OpMergeCustomer, dup, maker, domain.CustomerMerge{IntoID: uuid.New()}, ""); err == nil {
+		t.Error("Expected a merge into an unknown customer to be refused")
+	}
+
+	cr, err := approvals.Submit(ctx, domain.OpMergeCustomer, dup, maker, domain.CustomerMerge{IntoID: survivor}, "duplicate")
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if cr, err = approvals.Approve(ctx, cr.ID, uuid.New(), ""); err != nil || cr.Status != domain.ChangeApplied {
+		t.Fatalf("Expected the merge to apply, got %+v, %v", cr, err)
+	}
+	if ref := refs.rows["CAS/0000012345"]; ref.CustomerID != survivor || !refs.deleted[dup] {
+		t.Errorf("Expected the ref on the survivor and the duplicate deleted, got %s, deleted %v", ref.CustomerID, refs.deleted)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type fxService struct {
+	repo         ports.FXRateRepository
+	auditService AuditService
+}
+
+func NewFXService(repo ports.FXRateRepository, audit AuditService) *fxService {
+	return &fxService{repo: repo, auditService: audit}
+}
+
+func (s *fxService) Rates(ctx context.Context) ([]*domain.FXRate, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *fxService) SetRate(ctx context.Context, rate *domain.FXRate, userID uuid.UUID) error {
+	rate.Currency = strings.ToUpper(rate.Currency)
+	if !currencyCode.MatchString(rate.Currency) {
+		return fmt.Errorf("invalid currency code %q", rate.Currency)
+	}
+	if rate.Currency == domain.BaseCurrency {
+		return errors.New("the base currency has no rate")
+	}
+	if !rate.RateToBase.IsPositive() {
+		return errors.New("rate_to_base must be positive")
+	}
+	if rate.AsOf.IsZero() {
+		return errors.New("as_of is required")
+	}
+
+	if err := s.repo.Upsert(ctx, rate); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, uuid.Nil, "FX_RATE", "SET", userID.String(),
+		fmt.Sprintf("%s=%s as_of=%s", rate.Currency, rate.RateToBase, rate.AsOf.Format("2006-01-02")), "")
+	return nil
+}
+
+func (s *fxService) ToBase(ctx context.Context, m domain.Money) (domain.Money, error) {
+	m.Currency = strings.ToUpper(m.Currency)
+	if m.Currency == domain.BaseCurrency {
+		return m, nil
+	}
+	rate, err := s.repo.Get(ctx, m.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if rate == nil {
+		return domain.Money{}, fmt.Errorf("no FX rate configured for %s", m.Currency)
+	}
+	return rate.Convert(m)
+}
+
+func (s *fxService) SumToBase(ctx context.Context, values []domain.Money) (domain.Money, error) {
+	total := domain.THB(decimal.Zero)
+	seen := map[string]bool{}
+	for _, v := range values {
+		cur := strings.ToUpper(v.Currency)
+		if !currencyCode.MatchString(cur) {
+			return domain.Money{}, fmt.Errorf("invalid currency code %q", v.Currency)
+		}
+		if seen[cur] {
+			return domain.Money{}, fmt.Errorf("duplicate currency %s", cur)
+		}
+		seen[cur] = true
+
+		converted, err := s.ToBase(ctx, v)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		if total, err = total.Add(converted); err != nil {
+			return domain.Money{}, err
+		}
+	}
+	return total, nil
+}
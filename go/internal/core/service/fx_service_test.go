@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockFXRateRepo struct {
+	rates map[string]*domain.FXRate
+}
+
+func (m *mockFXRateRepo) List(ctx context.Context) ([]*domain.FXRate, error) {
+	var out []*domain.FXRate
+	for _, r := range m.rates {
+		out = append(out, r)
+	}
+	return out, nil
+}
+func (m *mockFXRateRepo) Get(ctx context.Context, currency string) (*domain.FXRate, error) {
+	return m.rates[currency], nil
+}
+func (m *mockFXRateRepo) Upsert(ctx context.Context, rate *domain.FXRate) error {
+	m.rates[rate.Currency] = rate
+	return nil
+}
+
+type mockPortfolioValueRepo struct {
+	saved map[uuid.UUID][]domain.Money
+}
+
+func (m *mockPortfolioValueRepo) List(ctx context.Context, customerID uuid.UUID) ([]domain.Money, error) {
+	return m.saved[customerID], nil
+}
+func (m *mockPortfolioValueRepo) Replace(ctx context.Context, customerID uuid.UUID, values []domain.Money) error {
+	m.saved[customerID] = values
+	return nil
+}
+
+func newTestFXService() *fxService {
+	return NewFXService(&mockFXRateRepo{rates: map[string]*domain.FXRate{
+		"USD": {Currency: "USD", RateToBase: decimal.RequireFromString("36.1234")},
+	}}, &mockAuditService{})
+}
+
+func TestSumToBase(t *testing.T) {
+	svc := newTestFXService()
+	_, err := svc.SumToBase(context.Background(), []domain.Money{
+		{Amount: decimal.RequireFromString("0.10"), Currency: "THB"},
+		{Amount: decimal.RequireFromString("0.20"), Currency: "thb"},
+	})
+	if err == nil {
+		t.Fatal("Expected duplicate currency error")
+	}
+
+	total, err := svc.SumToBase(context.Background(), []domain.Money{
+		{Amount: decimal.RequireFromString("0.10"), Currency: "THB"},
+		{Amount: decimal.RequireFromString("1000.05"), Currency: "usd"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 1000.05 * 36.1234 = 36125.206317 -> 36125.21, plus 0.10
+	if want := decimal.RequireFromString("36125.31"); !total.Amount.Equal(want) || total.Currency != "THB" {
+		t.Errorf("Expected %s THB, got %s", want, total)
+	}
+
+	if _, err := svc.SumToBase(context.Background(), []domain.Money{{Amount: decimal.NewFromInt(1), Currency: "EUR"}}); err == nil {
+		t.Error("Expected error for currency without a rate")
+	}
+}
+
+func TestSetRate_Validation(t *testing.T) {
+	svc := newTestFXService()
+	asOf := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	for _, rate := range []*domain.FXRate{
+		{Currency: "US", RateToBase: decimal.NewFromInt(1), AsOf: asOf},
+		{Currency: "THB", RateToBase: decimal.NewFromInt(1), AsOf: asOf},
+		{Currency: "EUR", RateToBase: decimal.Zero, AsOf: asOf},
+	} {
+		if err := svc.SetRate(context.Background(), rate, uuid.New()); err == nil {
+			t.Errorf("Expected error for %+v", rate)
+		}
+	}
+	if err := svc.SetRate(context.Background(), &domain.FXRate{Currency: "eur", RateToBase: decimal.RequireFromString("39.5"), AsOf: asOf}, uuid.New()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCreateCustomer_PortfolioValuesInTHB(t *testing.T) {
+	values := &mockPortfolioValueRepo{saved: map[uuid.UUID][]domain.Money{}}
+	customers := &mockCustomerRepo{
+		createFunc: func(ctx context.Context, c *domain.Customer) error {
+			c.ID = uuid.New()
+			return nil
+		},
+	}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{},
+		WithPortfolioValues(newTestFXService(), values))
+
+	c := &domain.Customer{
+		Type:          domain.TypePersonal,
+		PortfolioSize: decimal.NewFromInt(1),
+		PortfolioValues: []domain.Money{
+			{Amount: decimal.RequireFromString("100"), Currency: "usd"},
+			{Amount: decimal.RequireFromString("500.50"), Currency: "THB"},
+		},
+	}
+	if err := svc.CreateCustomer(context.Background(), c, uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := decimal.RequireFromString("4112.84"); !c.PortfolioSize.Equal(want) {
+		t.Errorf("Expected portfolio size %s, got %s", want, c.PortfolioSize)
+	}
+	if len(values.saved[c.ID]) != 2 || values.saved[c.ID][0].Currency != "USD" {
+		t.Errorf("Expected normalised values to be saved, got %+v", values.saved[c.ID])
+	}
+
+	raw, _ := json.Marshal(c)
+	if !strings.Contains(string(raw), `"portfolio_size":"4112.84"`) {
+		t.Errorf("Expected money serialised as a JSON string, got %s", raw)
+	}
+}
+
+func TestCreateCustomer_ConvertsForeignAmounts(t *testing.T) {
+	customers := &mockCustomerRepo{
+		createFunc: func(ctx context.Context, c *domain.Customer) error {
+			c.ID = uuid.New()
+			return nil
+		},
+	}
+	values := &mockPortfolioValueRepo{saved: map[uuid.UUID][]domain.Money{}}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{},
+		WithPortfolioValues(newTestFXService(), values))
+
+	c := &domain.Customer{Type: domain.TypePersonal, CLV: decimal.NewFromInt(1000), Currency: "usd"}
+	if err := svc.CreateCustomer(context.Background(), c, uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := decimal.RequireFromString("36123.40"); !c.CLV.Equal(want) || c.Currency != "THB" {
+		t.Errorf("Expected CLV %s THB, got %s %s", want, c.CLV, c.Currency)
+	}
+
+	c = &domain.Customer{Type: domain.TypePersonal, CLV: decimal.NewFromInt(1000), Currency: "EUR"}
+	if err := svc.CreateCustomer(context.Background(), c, uuid.New()); err == nil {
+		t.Error("Expected error for a currency without a rate")
+	}
+
+	plain := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{})
+	c = &domain.Customer{Type: domain.TypePersonal, CLV: decimal.NewFromInt(1000), Currency: "USD"}
+	if err := plain.CreateCustomer(context.Background(), c, uuid.New()); err == nil {
+		t.Error("Expected foreign amounts to be refused without FX rates")
+	}
+}
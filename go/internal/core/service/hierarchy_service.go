@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+)
+
+type hierarchyService struct {
+	userRepo       ports.UserRepository
+	orgRepo        ports.OrgRepository
+	delegationRepo ports.DelegationRepository
+	auditService   AuditService
+	maxLevels      int
+	now            func() time.Time
+}
+
+// NewHierarchyService builds the org hierarchy service. maxLevels bounds how far up the
+// supervisor chain rights are inherited (1 = direct supervisor only).
+func NewHierarchyService(
+	uRepo ports.UserRepository,
+	oRepo ports.OrgRepository,
+	dRepo ports.DelegationRepository,
+	audit AuditService,
+	maxLevels int,
+) *hierarchyService {
+	if maxLevels <= 0 {
+		maxLevels = 1
+	}
+	return &hierarchyService{
+		userRepo:       uRepo,
+		orgRepo:        oRepo,
+		delegationRepo: dRepo,
+		auditService:   audit,
+		maxLevels:      maxLevels,
+		now:            time.Now,
+	}
+}
+
+func (s *hierarchyService) Supervisors(ctx context.Context, userID uuid.UUID) ([]*models.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[uuid.UUID]bool{userID: true}
+	var chain []*models.User
+	for len(chain) < s.maxLevels && u.SupervisorID != nil && !seen[*u.SupervisorID] {
+		seen[*u.SupervisorID] = true
+		sup, err := s.userRepo.GetByID(ctx, *u.SupervisorID)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, sup)
+		u = sup
+	}
+	return chain, nil
+}
+
+func (s *hierarchyService) CanActFor(ctx context.Context, actorID, subjectID uuid.UUID, scope string) (bool, error) {
+	if actorID == subjectID {
+		return true, nil
+	}
+
+	chain, err := s.Supervisors(ctx, subjectID)
+	if err != nil {
+		return false, err
+	}
+	lead, err := s.teamLead(ctx, subjectID)
+	if err != nil {
+		return false, err
+	}
+	if lead != nil {
+		chain = append(chain, lead)
+	}
+	// Only a supervisor's rights can be delegated. A delegation from the subject would let them
+	// hand a peer the power to approve the subject's own requests.
+	principals := map[uuid.UUID]bool{}
+	for _, sup := range chain {
+		if !sup.IsActive {
+			// Someone who has left cannot act, but their delegates still may.
+			principals[sup.ID] = true
+			continue
+		}
+		if sup.ID == actorID {
+			return true, nil
+		}
+		principals[sup.ID] = true
+	}
+
+	delegations, err := s.delegationRepo.ListActiveForDelegate(ctx, actorID)
+	if err != nil {
+		return false, err
+	}
+	now := s.now()
+	for _, d := range delegations {
+		if principals[d.DelegatorID] && d.IsActive(scope, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// teamLead returns the lead of the subject's team, who supervises its members alongside their
+// supervisor chain, or nil. A lead of a team in another branch than the subject, or who has
+// moved to another branch than the team, is not the subject's lead.
+func (s *hierarchyService) teamLead(ctx context.Context, subjectID uuid.UUID) (*models.User, error) {
+	subject, err := s.userRepo.GetByID(ctx, subjectID)
+	if err != nil || subject.TeamID == nil {
+		return nil, err
+	}
+	team, err := s.orgRepo.GetTeam(ctx, *subject.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.LeadID == nil || *team.LeadID == subjectID || !sameBranch(subject.BranchID, team.BranchID) {
+		return nil, nil
+	}
+	lead, err := s.userRepo.GetByID(ctx, *team.LeadID)
+	if err != nil || !sameBranch(lead.BranchID, team.BranchID) {
+		return nil, err
+	}
+	return lead, nil
+}
+
+// sameBranch treats an unset branch as matching any.
+func sameBranch(a, b *uuid.UUID) bool {
+	return a == nil || b == nil || *a == *b
+}
+
+func (s *hierarchyService) Delegate(ctx context.Context, d *models.Delegation) error {
+	if d.DelegateID == uuid.Nil || d.DelegateID == d.DelegatorID {
+		return errors.New("a different delegate is required")
+	}
+	if d.Scope == "" {
+		d.Scope = models.DelegationScopeAll
+	}
+	if d.StartsAt.IsZero() {
+		d.StartsAt = s.now()
+	}
+	if !d.EndsAt.After(d.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if _, err := s.userRepo.GetByID(ctx, d.DelegateID); err != nil {
+		return errors.New("delegate not found")
+	}
+
+	if err := s.delegationRepo.Create(ctx, d); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, d.ID, "DELEGATION", "CREATE", d.DelegatorID.String(),
+		fmt.Sprintf("delegate=%s scope=%s until=%s", d.DelegateID, d.Scope, d.EndsAt.Format(time.RFC3339)), "")
+	return nil
+}
+
+func (s *hierarchyService) RevokeDelegation(ctx context.Context, id, userID uuid.UUID, isAdmin bool) error {
+	d, err := s.delegationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if d.DelegatorID != userID && !isAdmin {
+		return fmt.Errorf("%w: only the delegator can revoke a delegation", domain.ErrForbidden)
+	}
+	if err := s.delegationRepo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, id, "DELEGATION", "REVOKE", userID.String(), "", "")
+	return nil
+}
+
+func (s *hierarchyService) ListDelegations(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error) {
+	return s.delegationRepo.ListByUser(ctx, userID)
+}
+
+func (s *hierarchyService) CreateBranch(ctx context.Context, b *models.Branch) error {
+	if b.Code == "" || b.Name == "" {
+		return errors.New("branch code and name are required")
+	}
+	return s.orgRepo.CreateBranch(ctx, b)
+}
+
+func (s *hierarchyService) ListBranches(ctx context.Context) ([]*models.Branch, error) {
+	return s.orgRepo.ListBranches(ctx)
+}
+
+func (s *hierarchyService) CreateTeam(ctx context.Context, t *models.Team) error {
+	if t.Name == "" {
+		return errors.New("team name is required")
+	}
+	return s.orgRepo.CreateTeam(ctx, t)
+}
+
+func (s *hierarchyService) ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*models.Team, error) {
+	return s.orgRepo.ListTeams(ctx, branchID)
+}
+
+func (s *hierarchyService) AssignUser(ctx context.Context, userID uuid.UUID, teamID, branchID *uuid.UUID) error {
+	return s.orgRepo.AssignUser(ctx, userID, teamID, branchID)
+}
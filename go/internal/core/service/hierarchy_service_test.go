@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/models"
+	"github.com/google/uuid"
+)
+
+// Mock DelegationRepository
+type mockDelegationRepo struct {
+	delegations []*models.Delegation
+}
+
+func (m *mockDelegationRepo) Create(ctx context.Context, d *models.Delegation) error {
+	d.ID = uuid.New()
+	m.delegations = append(m.delegations, d)
+	return nil
+}
+func (m *mockDelegationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Delegation, error) {
+	for _, d := range m.delegations {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errors.New("delegation not found")
+}
+func (m *mockDelegationRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	d, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	d.RevokedAt = &now
+	return nil
+}
+func (m *mockDelegationRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error) {
+	return m.delegations, nil
+}
+func (m *mockDelegationRepo) ListActiveForDelegate(ctx context.Context, delegateID uuid.UUID) ([]*models.Delegation, error) {
+	var active []*models.Delegation
+	for _, d := range m.delegations {
+		if d.DelegateID == delegateID && d.RevokedAt == nil {
+			active = append(active, d)
+		}
+	}
+	return active, nil
+}
+
+// Mock OrgRepository
+type mockOrgRepo struct {
+	teams map[uuid.UUID]*models.Team
+}
+
+func (m *mockOrgRepo) CreateBranch(ctx context.Context, b *models.Branch) error { return nil }
+func (m *mockOrgRepo) ListBranches(ctx context.Context) ([]*models.Branch, error) {
+	return nil, nil
+}
+func (m *mockOrgRepo) CreateTeam(ctx context.Context, t *models.Team) error { return nil }
+func (m *mockOrgRepo) ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*models.Team, error) {
+	return nil, nil
+}
+func (m *mockOrgRepo) GetTeam(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	if t, ok := m.teams[id]; ok {
+		return t, nil
+	}
+	return nil, errors.New("team not found")
+}
+func (m *mockOrgRepo) AssignUser(ctx context.Context, userID uuid.UUID, teamID, branchID *uuid.UUID) error {
+	return nil
+}
+
+// orgChart builds a user repo from a user -> supervisor map
+func orgChart(supervisors map[uuid.UUID]uuid.UUID) *mockUserRepo {
+	return &mockUserRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			u := &models.User{ID: id, IsActive: true}
+			if sup, ok := supervisors[id]; ok {
+				u.SupervisorID = &sup
+			}
+			return u, nil
+		},
+	}
+}
+
+func TestCanActFor_TransitiveSupervisors(t *testing.T) {
+	staff, lead, manager, director := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	users := orgChart(map[uuid.UUID]uuid.UUID{staff: lead, lead: manager, manager: director})
+
+	svc := NewHierarchyService(users, nil, &mockDelegationRepo{}, &mockAuditService{}, 2)
+
+	for _, tc := range []struct {
+		actor uuid.UUID
+		want  bool
+	}{
+		{staff, true},
+		{lead, true},
+		{manager, true},
+		{director, false}, // three levels up, beyond the configured depth
+	} {
+		got, err := svc.CanActFor(context.Background(), tc.actor, staff, models.DelegationScopeRestore)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("CanActFor(%s) = %v, want %v", tc.actor, got, tc.want)
+		}
+	}
+}
+
+func TestCanActFor_Delegation(t *testing.T) {
+	staff, somchai, malee := uuid.New(), uuid.New(), uuid.New()
+	users := orgChart(map[uuid.UUID]uuid.UUID{staff: somchai})
+	delegations := &mockDelegationRepo{}
+	svc := NewHierarchyService(users, nil, delegations, &mockAuditService{}, 1)
+
+	if ok, _ := svc.CanActFor(context.Background(), malee, staff, models.DelegationScopeRestore); ok {
+		t.Fatalf("Expected Malee to have no rights before delegation")
+	}
+
+	d := &models.Delegation{
+		DelegatorID: somchai,
+		DelegateID:  malee,
+		Scope:       models.DelegationScopeRestore,
+		EndsAt:      time.Now().Add(72 * time.Hour),
+	}
+	if err := svc.Delegate(context.Background(), d); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ok, _ := svc.CanActFor(context.Background(), malee, staff, models.DelegationScopeRestore); !ok {
+		t.Errorf("Expected Malee to act for Somchai while delegated")
+	}
+	if ok, _ := svc.CanActFor(context.Background(), malee, staff, models.DelegationScopeAccessGrant); ok {
+		t.Errorf("Expected delegation to be limited to its scope")
+	}
+
+	if err := svc.RevokeDelegation(context.Background(), d.ID, malee, false); err == nil {
+		t.Errorf("Expected delegate to be unable to revoke")
+	}
+	if err := svc.RevokeDelegation(context.Background(), d.ID, somchai, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ok, _ := svc.CanActFor(context.Background(), malee, staff, models.DelegationScopeRestore); ok {
+		t.Errorf("Expected revoked delegation to grant nothing")
+	}
+}
+
+func TestCanActFor_TeamLead(t *testing.T) {
+	silom, asok := uuid.New(), uuid.New()
+	staff, lead, transferred, malee := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	team, otherTeam := uuid.New(), uuid.New()
+	branches := map[uuid.UUID]uuid.UUID{staff: silom, lead: silom, transferred: asok}
+	teams := map[uuid.UUID]uuid.UUID{staff: team}
+	users := &mockUserRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.User, error) {
+			u := &models.User{ID: id, IsActive: true}
+			if b, ok := branches[id]; ok {
+				u.BranchID = &b
+			}
+			if tm, ok := teams[id]; ok {
+				u.TeamID = &tm
+			}
+			return u, nil
+		},
+	}
+	org := &mockOrgRepo{teams: map[uuid.UUID]*models.Team{
+		team:      {ID: team, BranchID: &silom, LeadID: &lead},
+		otherTeam: {ID: otherTeam, BranchID: &silom, LeadID: &transferred},
+	}}
+	delegations := &mockDelegationRepo{}
+	svc := NewHierarchyService(users, org, delegations, &mockAuditService{}, 1)
+	ctx := context.Background()
+
+	if ok, err := svc.CanActFor(ctx, lead, staff, models.DelegationScopeRestore); err != nil || !ok {
+		t.Errorf("Expected the team lead to act for their team, got %v, %v", ok, err)
+	}
+	if ok, _ := svc.CanActFor(ctx, transferred, staff, models.DelegationScopeRestore); ok {
+		t.Error("Expected the lead of another team to have no rights")
+	}
+
+	// The lead's rights can be delegated like a supervisor's.
+	if err := svc.Delegate(ctx, &models.Delegation{DelegatorID: lead, DelegateID: malee, EndsAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ok, _ := svc.CanActFor(ctx, malee, staff, models.DelegationScopeRestore); !ok {
+		t.Error("Expected Malee to act for the team lead while delegated")
+	}
+
+	// A lead posted to another branch no longer leads the team.
+	branches[lead] = asok
+	if ok, _ := svc.CanActFor(ctx, lead, staff, models.DelegationScopeRestore); ok {
+		t.Error("Expected a lead in another branch than the team to have no rights")
+	}
+}
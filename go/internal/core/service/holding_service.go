@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type holdingService struct {
+	repo         ports.HoldingRepository
+	products     ports.ProductRepository
+	customerRepo ports.CustomerRepository
+	fx           ports.FXService
+	tiers        ports.TierService
+	auditService AuditService
+	now          func() time.Time
+}
+
+// NewHoldingService re-evaluates the customer's tier after each portfolio refresh when tiers is set.
+func NewHoldingService(repo ports.HoldingRepository, products ports.ProductRepository, customerRepo ports.CustomerRepository, fx ports.FXService, tiers ports.TierService, audit AuditService) *holdingService {
+	return &holdingService{
+		repo:         repo,
+		products:     products,
+		customerRepo: customerRepo,
+		fx:           fx,
+		tiers:        tiers,
+		auditService: audit,
+		now:          time.Now,
+	}
+}
+
+// --- Product catalog ---
+
+func (s *holdingService) ListProducts(ctx context.Context, activeOnly bool) ([]*domain.Product, error) {
+	return s.products.List(ctx, activeOnly)
+}
+
+func (s *holdingService) SaveProduct(ctx context.Context, p *domain.Product, userID uuid.UUID) error {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.Name = strings.TrimSpace(p.Name)
+	p.Category = domain.ProductCategory(strings.ToUpper(string(p.Category)))
+	if p.Code == "" || p.Name == "" {
+		return errors.New("code and name are required")
+	}
+	switch p.Category {
+	case domain.ProductInsurance, domain.ProductDeposit, domain.ProductInvestment, domain.ProductLoan:
+	default:
+		return fmt.Errorf("unknown product category %q", p.Category)
+	}
+
+	if err := s.products.Save(ctx, p); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, uuid.Nil, "PRODUCT", "SAVE", userID.String(),
+		fmt.Sprintf("%s category=%s active=%t", p.Code, p.Category, p.IsActive), "")
+	return nil
+}
+
+// --- Holdings ---
+
+func (s *holdingService) Add(ctx context.Context, h *domain.Holding, userID uuid.UUID) error {
+	if _, err := s.customerRepo.GetByID(ctx, h.CustomerID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, h, true); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, h); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, h.CustomerID, "CUSTOMER", "HOLDING_ADD", userID.String(), holdingChanges(h), "")
+	_, err := s.RefreshPortfolio(ctx, h.CustomerID)
+	return err
+}
+
+func (s *holdingService) List(ctx context.Context, customerID uuid.UUID) ([]*domain.Holding, error) {
+	return s.repo.ListByCustomerID(ctx, customerID)
+}
+
+// Update replaces the holding's details. A holding may stay on a product that has since been
+// withdrawn from sale, but cannot be moved onto one.
+func (s *holdingService) Update(ctx context.Context, h *domain.Holding, userID uuid.UUID) error {
+	existing, err := s.get(ctx, h.CustomerID, h.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, h, !strings.EqualFold(h.ProductCode, existing.ProductCode)); err != nil {
+		return err
+	}
+	h.CreatedAt = existing.CreatedAt
+
+	if err := s.repo.Update(ctx, h); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, h.CustomerID, "CUSTOMER", "HOLDING_UPDATE", userID.String(), holdingChanges(h), "")
+	_, err = s.RefreshPortfolio(ctx, h.CustomerID)
+	return err
+}
+
+func (s *holdingService) Remove(ctx context.Context, customerID, id, userID uuid.UUID) error {
+	h, err := s.get(ctx, customerID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "HOLDING_REMOVE", userID.String(), holdingChanges(h), "")
+	_, err = s.RefreshPortfolio(ctx, customerID)
+	return err
+}
+
+// RefreshPortfolio sums the balances of the customer's active owned holdings per currency,
+// converts the total to THB and stores both on the customer, then re-evaluates the tier and
+// high-value flag the rules derive from it.
+func (s *holdingService) RefreshPortfolio(ctx context.Context, customerID uuid.UUID) (domain.Money, error) {
+	holdings, err := s.repo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	now := s.now()
+	byCurrency := map[string]decimal.Decimal{}
+	for _, h := range holdings {
+		if h.CountsTowardPortfolio(now) {
+			byCurrency[h.Currency] = byCurrency[h.Currency].Add(*h.Balance)
+		}
+	}
+	values := []domain.Money{}
+	for cur, amount := range byCurrency {
+		values = append(values, domain.Money{Amount: amount, Currency: cur})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Currency < values[j].Currency })
+
+	total, err := s.fx.SumToBase(ctx, values)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if err := s.repo.UpdatePortfolio(ctx, customerID, total, values); err != nil {
+		return domain.Money{}, err
+	}
+	s.reevaluateTier(ctx, customerID)
+	return total, nil
+}
+
+// reevaluateTier moves the tier after the portfolio changed. The portfolio is already stored, so
+// a failure is logged; the nightly recalculation corrects it.
+func (s *holdingService) reevaluateTier(ctx context.Context, customerID uuid.UUID) {
+	if s.tiers == nil {
+		return
+	}
+	if _, err := s.tiers.Reevaluate(ctx, customerID, domain.TierSourcePortfolio); err != nil {
+		log.Printf("tier re-evaluation for customer %s: %v", customerID, err)
+	}
+}
+
+// firstRefreshWindow is how far back RefreshDue looks when it has never completed a run.
+const firstRefreshWindow = 48 * time.Hour
+
+// RefreshDue refreshes customers with a holding that started or ended since the last completed
+// run. The watermark only moves once every customer is refreshed, so a failed or missed run is
+// caught up by the next one.
+func (s *holdingService) RefreshDue(ctx context.Context) (int, error) {
+	now := s.now()
+	from, err := s.repo.RefreshWatermark(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if from.IsZero() {
+		from = now.Add(-firstRefreshWindow)
+	}
+	ids, err := s.repo.CustomersWithDateChanges(ctx, from, now)
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if _, err := s.RefreshPortfolio(ctx, id); err != nil {
+			return i, fmt.Errorf("customer %s: %w", id, err)
+		}
+	}
+	return len(ids), s.repo.SetRefreshWatermark(ctx, now)
+}
+
+func (s *holdingService) get(ctx context.Context, customerID, id uuid.UUID) (*domain.Holding, error) {
+	h, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if h.CustomerID != customerID {
+		return nil, fmt.Errorf("holding %w", domain.ErrNotFound)
+	}
+	return h, nil
+}
+
+// validate normalises the holding and checks its product, role, status, dates and amounts.
+// newProduct requires the product to still be on sale.
+func (s *holdingService) validate(ctx context.Context, h *domain.Holding, newProduct bool) error {
+	h.ProductCode = strings.ToUpper(strings.TrimSpace(h.ProductCode))
+	h.ExternalRef = strings.TrimSpace(h.ExternalRef)
+	h.Role = domain.HoldingRole(strings.ToUpper(string(h.Role)))
+	h.Status = domain.HoldingStatus(strings.ToUpper(string(h.Status)))
+	h.Currency = strings.ToUpper(strings.TrimSpace(h.Currency))
+	if h.Currency == "" {
+		h.Currency = domain.BaseCurrency
+	}
+
+	if !currencyCode.MatchString(h.Currency) {
+		return fmt.Errorf("invalid currency code %q", h.Currency)
+	}
+	if h.ExternalRef == "" {
+		return errors.New("external_ref is required")
+	}
+	p, err := s.products.GetByCode(ctx, h.ProductCode)
+	if errors.Is(err, domain.ErrNotFound) {
+		// An unknown product is a bad request, not a missing holding.
+		return fmt.Errorf("unknown product %q", h.ProductCode)
+	}
+	if err != nil {
+		return err
+	}
+	if newProduct && !p.IsActive {
+		return fmt.Errorf("product %s is no longer offered", p.Code)
+	}
+	h.ProductName = p.Name
+
+	switch h.Role {
+	case domain.HoldingOwner, domain.HoldingInsured, domain.HoldingPayer, domain.HoldingBeneficiary:
+	default:
+		return fmt.Errorf("unknown holding role %q", h.Role)
+	}
+	switch h.Status {
+	case domain.HoldingPending, domain.HoldingActive, domain.HoldingLapsed, domain.HoldingMatured,
+		domain.HoldingSurrendered, domain.HoldingClosed:
+	default:
+		return fmt.Errorf("unknown holding status %q", h.Status)
+	}
+	if h.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+	if h.EndDate != nil && h.EndDate.Before(h.StartDate) {
+		return errors.New("end_date is before start_date")
+	}
+	for _, v := range []*decimal.Decimal{h.SumInsured, h.Balance} {
+		if v != nil && v.IsNegative() {
+			return errors.New("sum_insured and balance cannot be negative")
+		}
+	}
+	// Fail now rather than after the write if the balance cannot be counted in THB.
+	if h.Balance != nil {
+		if _, err := s.fx.ToBase(ctx, domain.Money{Amount: *h.Balance, Currency: h.Currency}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func holdingChanges(h *domain.Holding) string {
+	return fmt.Sprintf("holding=%s product=%s ref=%s role=%s status=%s", h.ID, h.ProductCode, h.ExternalRef, h.Role, h.Status)
+}
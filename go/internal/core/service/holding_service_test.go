@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockProductRepo struct {
+	products map[string]*domain.Product
+}
+
+func (m *mockProductRepo) List(ctx context.Context, activeOnly bool) ([]*domain.Product, error) {
+	var out []*domain.Product
+	for _, p := range m.products {
+		if p.IsActive || !activeOnly {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+func (m *mockProductRepo) GetByCode(ctx context.Context, code string) (*domain.Product, error) {
+	p, ok := m.products[code]
+	if !ok {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	return p, nil
+}
+func (m *mockProductRepo) Save(ctx context.Context, p *domain.Product) error {
+	m.products[p.Code] = p
+	return nil
+}
+
+type mockHoldingRepo struct {
+	rows      map[uuid.UUID]*domain.Holding
+	portfolio map[uuid.UUID]domain.Money
+	values    map[uuid.UUID][]domain.Money
+	due       []uuid.UUID
+	dueFrom   []time.Time
+	watermark time.Time
+}
+
+func newMockHoldingRepo() *mockHoldingRepo {
+	return &mockHoldingRepo{
+		rows:      map[uuid.UUID]*domain.Holding{},
+		portfolio: map[uuid.UUID]domain.Money{},
+		values:    map[uuid.UUID][]domain.Money{},
+	}
+}
+
+func (m *mockHoldingRepo) Create(ctx context.Context, h *domain.Holding) error {
+	h.ID = uuid.New()
+	h2 := *h
+	m.rows[h.ID] = &h2
+	return nil
+}
+func (m *mockHoldingRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Holding, error) {
+	h, ok := m.rows[id]
+	if !ok {
+		return nil, fmt.Errorf("holding %w", domain.ErrNotFound)
+	}
+	h2 := *h
+	return &h2, nil
+}
+func (m *mockHoldingRepo) Update(ctx context.Context, h *domain.Holding) error {
+	h2 := *h
+	m.rows[h.ID] = &h2
+	return nil
+}
+func (m *mockHoldingRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.rows, id)
+	return nil
+}
+func (m *mockHoldingRepo) ListByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*domain.Holding, error) {
+	var out []*domain.Holding
+	for _, h := range m.rows {
+		if h.CustomerID == customerID {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+func (m *mockHoldingRepo) UpdatePortfolio(ctx context.Context, customerID uuid.UUID, total domain.Money, values []domain.Money) error {
+	m.portfolio[customerID] = total
+	m.values[customerID] = values
+	return nil
+}
+func (m *mockHoldingRepo) CustomersWithDateChanges(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	m.dueFrom = append(m.dueFrom, from)
+	return m.due, nil
+}
+func (m *mockHoldingRepo) RefreshWatermark(ctx context.Context) (time.Time, error) {
+	return m.watermark, nil
+}
+func (m *mockHoldingRepo) SetRefreshWatermark(ctx context.Context, at time.Time) error {
+	m.watermark = at
+	return nil
+}
+
+func amount(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func TestHoldingService_PortfolioFromActiveOwnedHoldings(t *testing.T) {
+	customerID := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	products := &mockProductRepo{products: map[string]*domain.Product{
+		"LIFE20":  {Code: "LIFE20", Name: "20-Pay Life", Category: domain.ProductInsurance, IsActive: true},
+		"USDFUND": {Code: "USDFUND", Name: "USD Bond Fund", Category: domain.ProductInvestment, IsActive: true},
+		"OLDSAV":  {Code: "OLDSAV", Name: "Legacy Savings", Category: domain.ProductDeposit},
+	}}
+	repo := newMockHoldingRepo()
+	svc := NewHoldingService(repo, products, customers, newTestFXService(), nil, &mockAuditService{})
+	ctx := context.Background()
+	start := time.Now().AddDate(-1, 0, 0)
+	ended := time.Now().AddDate(0, 0, -1)
+
+	holdings := []*domain.Holding{
+		{ProductCode: "life20", ExternalRef: "P-1", Role: "owner", Status: "active", Balance: amount("100000.00"), SumInsured: amount("1000000")},
+		{ProductCode: "LIFE20", ExternalRef: "P-1", Role: "insured", Status: "active", Balance: amount("100000.00")},
+		{ProductCode: "USDFUND", ExternalRef: "F-9", Role: "owner", Status: "active", Currency: "usd", Balance: amount("1000")},
+		{ProductCode: "LIFE20", ExternalRef: "P-2", Role: "owner", Status: "lapsed", Balance: amount("5000")},
+		{ProductCode: "LIFE20", ExternalRef: "P-3", Role: "owner", Status: "active", Balance: amount("7000"), EndDate: &ended},
+	}
+	for _, h := range holdings {
+		h.CustomerID = customerID
+		h.StartDate = start
+		if err := svc.Add(ctx, h, uuid.New()); err != nil {
+			t.Fatalf("Add(%s %s) failed: %v", h.ExternalRef, h.Role, err)
+		}
+	}
+
+	// 100,000 THB + 1,000 USD at 36.1234; the insured link, lapsed and ended policies do not count.
+	if want := decimal.RequireFromString("136123.40"); !repo.portfolio[customerID].Amount.Equal(want) {
+		t.Errorf("Expected portfolio %s, got %s", want, repo.portfolio[customerID].Amount)
+	}
+	if len(repo.values[customerID]) != 2 || repo.values[customerID][0].Currency != "THB" {
+		t.Errorf("Expected THB and USD values, got %v", repo.values[customerID])
+	}
+
+	invalid := []*domain.Holding{
+		{ProductCode: "OLDSAV", ExternalRef: "S-1", Role: "OWNER", Status: "ACTIVE", StartDate: start},
+		{ProductCode: "NOPE", ExternalRef: "S-1", Role: "OWNER", Status: "ACTIVE", StartDate: start},
+		{ProductCode: "LIFE20", ExternalRef: "P-4", Role: "TRUSTEE", Status: "ACTIVE", StartDate: start},
+		{ProductCode: "LIFE20", ExternalRef: "P-4", Role: "OWNER", Status: "ACTIVE"},
+		{ProductCode: "LIFE20", ExternalRef: "P-4", Role: "OWNER", Status: "ACTIVE", StartDate: start, Currency: "EUR", Balance: amount("1")},
+	}
+	for _, h := range invalid {
+		h.CustomerID = customerID
+		if err := svc.Add(ctx, h, uuid.New()); err == nil {
+			t.Errorf("Add(%s %s %s): expected error", h.ProductCode, h.Role, h.Currency)
+		}
+	}
+
+	// Surrendering the fund drops it from the portfolio.
+	for _, h := range repo.rows {
+		if h.ExternalRef == "F-9" {
+			update := *h
+			update.Status = domain.HoldingSurrendered
+			if err := svc.Update(ctx, &update, uuid.New()); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+		}
+	}
+	if want := decimal.RequireFromString("100000"); !repo.portfolio[customerID].Amount.Equal(want) {
+		t.Errorf("Expected portfolio %s after surrender, got %s", want, repo.portfolio[customerID].Amount)
+	}
+}
+
+func TestAnonymizeCustomer_ActiveHolding(t *testing.T) {
+	cid := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: cid, PortfolioSize: decimal.Zero}, nil
+		},
+	}
+	holdings := newMockHoldingRepo()
+	holdings.rows[uuid.New()] = &domain.Holding{CustomerID: cid, Role: domain.HoldingBeneficiary, Status: domain.HoldingActive,
+		StartDate: time.Now().AddDate(0, -1, 0)}
+
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, nil, WithHoldings(holdings))
+	err := svc.AnonymizeCustomer(context.Background(), cid, uuid.New())
+	if err == nil || err.Error() != "conflict: cannot anonymize customer with active portfolio" {
+		t.Errorf("Expected active portfolio error for a zero-balance active holding, got %v", err)
+	}
+}
+
+// portfolioTierRepo reads the portfolio size the holding service stored, as the column would hold it.
+type portfolioTierRepo struct {
+	*mockTierRepo
+	holdings *mockHoldingRepo
+}
+
+func (r *portfolioTierRepo) GetForRecalc(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error) {
+	c, err := r.mockTierRepo.GetForRecalc(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	c.PortfolioSize = r.holdings.portfolio[customerID].Amount
+	return c, nil
+}
+
+func TestHoldingService_RefreshReevaluatesTier(t *testing.T) {
+	cid := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	products := &mockProductRepo{products: map[string]*domain.Product{
+		"LIFE20": {Code: "LIFE20", Name: "20-Pay Life", Category: domain.ProductInsurance, IsActive: true},
+	}}
+	repo := newMockHoldingRepo()
+	tierRepo := &portfolioTierRepo{
+		mockTierRepo: &mockTierRepo{customers: []*domain.Customer{{ID: cid, Type: domain.TypePersonal, MembershipTier: "STANDARD"}}},
+		holdings:     repo,
+	}
+	svc := NewHoldingService(repo, products, customers, newTestFXService(), defaultTierService(t, tierRepo), &mockAuditService{})
+
+	h := &domain.Holding{CustomerID: cid, ProductCode: "LIFE20", ExternalRef: "P-1", Role: "OWNER", Status: "ACTIVE",
+		StartDate: time.Now().AddDate(-1, 0, 0), Balance: amount("5000000")}
+	if err := svc.Add(context.Background(), h, uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tierRepo.history) != 1 || !tierRepo.history[0].NewHighValue || tierRepo.history[0].Source != domain.TierSourcePortfolio {
+		t.Errorf("Expected the holding to make the customer high value, got %+v", tierRepo.history)
+	}
+}
+
+func TestHoldingService_RefreshDueFromWatermark(t *testing.T) {
+	repo := newMockHoldingRepo()
+	svc := NewHoldingService(repo, &mockProductRepo{}, &mockCustomerRepo{}, newTestFXService(), nil, &mockAuditService{})
+	first := time.Date(2024, 5, 1, 1, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return first }
+
+	if _, err := svc.RefreshDue(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !repo.dueFrom[0].Equal(first.Add(-firstRefreshWindow)) || !repo.watermark.Equal(first) {
+		t.Fatalf("Expected the first run to look back %s and record its end, got from %s watermark %s",
+			firstRefreshWindow, repo.dueFrom[0], repo.watermark)
+	}
+
+	// Runs missed for a week are caught up from the last completed run.
+	later := first.AddDate(0, 0, 7)
+	svc.now = func() time.Time { return later }
+	if _, err := svc.RefreshDue(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !repo.dueFrom[1].Equal(first) || !repo.watermark.Equal(later) {
+		t.Errorf("Expected the next run to start at %s, got from %s watermark %s", first, repo.dueFrom[1], repo.watermark)
+	}
+}
+
+func TestUpdateCustomer_RejectsPortfolioValuesWithHoldings(t *testing.T) {
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+		updateFunc: func(ctx context.Context, c *domain.Customer) error {
+			t.Fatal("Customer should not be written")
+			return nil
+		},
+	}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{}, WithHoldings(newMockHoldingRepo()))
+
+	c := &domain.Customer{ID: uuid.New(), PortfolioValues: []domain.Money{{Amount: decimal.NewFromInt(1), Currency: "THB"}}}
+	if err := svc.UpdateCustomer(context.Background(), c, uuid.New()); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected a conflict for portfolio_values sent with holdings, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type interactionService struct {
+	repo         ports.InteractionRepository
+	customerRepo ports.CustomerRepository
+	auditService AuditService
+}
+
+func NewInteractionService(repo ports.InteractionRepository, customerRepo ports.CustomerRepository, audit AuditService) *interactionService {
+	return &interactionService{repo: repo, customerRepo: customerRepo, auditService: audit}
+}
+
+// Create records an interaction authored by i.StaffUserID against an existing customer.
+func (s *interactionService) Create(ctx context.Context, i *domain.Interaction) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, i.CustomerID); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, i); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, i.ID, "INTERACTION", "CREATE", i.StaffUserID.String(),
+		fmt.Sprintf("customer=%s channel=%s", i.CustomerID, i.Channel), "")
+	return nil
+}
+
+// Get returns the interaction only if it belongs to customerID, so a URL cannot reach another customer's notes.
+func (s *interactionService) Get(ctx context.Context, customerID, id uuid.UUID) (*domain.Interaction, error) {
+	i, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if i.CustomerID != customerID {
+		return nil, fmt.Errorf("interaction %w", domain.ErrNotFound)
+	}
+	return i, nil
+}
+
+func (s *interactionService) List(ctx context.Context, customerID uuid.UUID, f domain.InteractionFilter) ([]*domain.Interaction, error) {
+	if f.Limit <= 0 {
+		f.Limit = 50
+	}
+	return s.repo.List(ctx, customerID, f)
+}
+
+// Update replaces the editable fields of an existing interaction. The customer and author are kept
+// from the stored record; a zero OccurredAt keeps the original time.
+func (s *interactionService) Update(ctx context.Context, i *domain.Interaction, userID uuid.UUID, isAdmin bool) error {
+	existing, err := s.authorized(ctx, i.CustomerID, i.ID, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if err := i.Validate(); err != nil {
+		return err
+	}
+	i.StaffUserID = existing.StaffUserID
+	i.CreatedAt = existing.CreatedAt
+	if i.OccurredAt.IsZero() {
+		i.OccurredAt = existing.OccurredAt
+	}
+
+	if err := s.repo.Update(ctx, i); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, i.ID, "INTERACTION", "UPDATE", userID.String(), fmt.Sprintf("customer=%s", i.CustomerID), "")
+	return nil
+}
+
+func (s *interactionService) Delete(ctx context.Context, customerID, id, userID uuid.UUID, isAdmin bool) error {
+	if _, err := s.authorized(ctx, customerID, id, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, id, "INTERACTION", "DELETE", userID.String(), fmt.Sprintf("customer=%s", customerID), "")
+	return nil
+}
+
+func (s *interactionService) authorized(ctx context.Context, customerID, id, userID uuid.UUID, isAdmin bool) (*domain.Interaction, error) {
+	existing, err := s.Get(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.StaffUserID != userID && !isAdmin {
+		return nil, fmt.Errorf("%w: only the author or an admin can change an interaction", domain.ErrForbidden)
+	}
+	return existing, nil
+}
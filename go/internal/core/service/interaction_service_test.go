@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockInteractionRepo struct {
+	rows map[uuid.UUID]*domain.Interaction
+}
+
+func (m *mockInteractionRepo) Create(ctx context.Context, i *domain.Interaction) error {
+	i.ID = uuid.New()
+	cp := *i
+	m.rows[i.ID] = &cp
+	return nil
+}
+func (m *mockInteractionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Interaction, error) {
+	i, ok := m.rows[id]
+	if !ok {
+		return nil, fmt.Errorf("interaction %w", domain.ErrNotFound)
+	}
+	cp := *i
+	return &cp, nil
+}
+func (m *mockInteractionRepo) Update(ctx context.Context, i *domain.Interaction) error {
+	cp := *i
+	m.rows[i.ID] = &cp
+	return nil
+}
+func (m *mockInteractionRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.rows, id)
+	return nil
+}
+func (m *mockInteractionRepo) List(ctx context.Context, customerID uuid.UUID, f domain.InteractionFilter) ([]*domain.Interaction, error) {
+	return nil, nil
+}
+func (m *mockInteractionRepo) ListForTimeline(ctx context.Context, customerID uuid.UUID, from, to, before *time.Time, limit int) ([]*domain.Interaction, error) {
+	return nil, nil
+}
+
+func TestInteractionService_AuthorOrAdminOnly(t *testing.T) {
+	customerID, author, other := uuid.New(), uuid.New(), uuid.New()
+	repo := &mockInteractionRepo{rows: map[uuid.UUID]*domain.Interaction{}}
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	svc := NewInteractionService(repo, customers, &mockAuditService{})
+	ctx := context.Background()
+
+	note := &domain.Interaction{CustomerID: customerID, Channel: "note", Direction: "OUTBOUND", Subject: "Prefers Thai", StaffUserID: author}
+	if err := svc.Create(ctx, note); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if note.Direction != domain.DirectionInternal {
+		t.Errorf("Expected notes to be INTERNAL, got %s", note.Direction)
+	}
+
+	edit := &domain.Interaction{ID: note.ID, CustomerID: customerID, Channel: domain.ChannelNote, Subject: "Edited", Pinned: true}
+	if err := svc.Update(ctx, edit, other, false); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for non-author, got %v", err)
+	}
+	if err := svc.Update(ctx, edit, other, true); err != nil {
+		t.Errorf("Expected admin edit to succeed, got %v", err)
+	}
+	if got := repo.rows[note.ID]; got.StaffUserID != author || !got.Pinned {
+		t.Errorf("Expected author kept and note pinned, got %+v", got)
+	}
+
+	if _, err := svc.Get(ctx, uuid.New(), note.ID); err == nil {
+		t.Error("Expected interaction to be hidden under another customer")
+	}
+	if err := svc.Delete(ctx, customerID, note.ID, author, false); err != nil {
+		t.Errorf("Expected author delete to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const pointsExpiryBatchSize = 200
+
+type pointsService struct {
+	repo         ports.PointsRepository
+	customerRepo ports.CustomerRepository
+	tiers        ports.TierService
+	auditService AuditService
+	// expiryMonths is the default lifetime of earned points; EARN requests may set their own expires_at.
+	expiryMonths int
+	now          func() time.Time
+}
+
+// NewPointsService builds the ledger service. When tiers is not nil every posting re-evaluates
+// the customer's tier, since the rules may read the points balance.
+func NewPointsService(repo ports.PointsRepository, cRepo ports.CustomerRepository, tiers ports.TierService, audit AuditService, expiryMonths int) *pointsService {
+	return &pointsService{
+		repo:         repo,
+		customerRepo: cRepo,
+		tiers:        tiers,
+		auditService: audit,
+		expiryMonths: expiryMonths,
+		now:          time.Now,
+	}
+}
+
+func (s *pointsService) Post(ctx context.Context, customerID, userID uuid.UUID, req domain.PointsRequest) (*domain.PointsTransaction, bool, error) {
+	if err := validatePointsRequest(req); err != nil {
+		return nil, false, err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, customerID); err != nil {
+		return nil, false, err
+	}
+	if req.IdempotencyKey != "" {
+		existing, err := s.repo.FindByIdempotencyKey(ctx, customerID, req.IdempotencyKey)
+		if err != nil || existing != nil {
+			return existing, false, err
+		}
+	}
+
+	var reversed *domain.PointsTransaction
+	if req.Type == domain.PointsReverse {
+		orig, err := s.repo.GetByID(ctx, *req.ReversesID)
+		if err != nil {
+			return nil, false, err
+		}
+		if orig.CustomerID != customerID {
+			return nil, false, errors.New("points transaction not found")
+		}
+		if orig.Type == domain.PointsReverse || orig.Type == domain.PointsExpire {
+			return nil, false, fmt.Errorf("%s transactions cannot be reversed", orig.Type)
+		}
+		reversed = orig
+	}
+
+	var posted *domain.PointsTransaction
+	err := s.repo.Post(ctx, customerID, func(lots []*domain.PointsTransaction) (*domain.PointsTransaction, error) {
+		t := &domain.PointsTransaction{
+			Type:           req.Type,
+			IdempotencyKey: req.IdempotencyKey,
+			Reference:      req.Reference,
+			Reason:         req.Reason,
+			CreatedBy:      &userID,
+		}
+		now := s.now()
+
+		switch {
+		case req.Type == domain.PointsEarn:
+			t.Points = req.Points
+			t.ExpiresAt = req.ExpiresAt
+			if t.ExpiresAt == nil && s.expiryMonths > 0 {
+				exp := now.AddDate(0, s.expiryMonths, 0)
+				t.ExpiresAt = &exp
+			}
+		case req.Type == domain.PointsAdjust && req.Points > 0:
+			t.Points = req.Points
+			t.ExpiresAt = req.ExpiresAt
+		case req.Type == domain.PointsBurn, req.Type == domain.PointsAdjust:
+			n := req.Points
+			if n < 0 {
+				n = -n
+			}
+			allocs, err := allocateFIFO(lots, n, now)
+			if err != nil {
+				return nil, err
+			}
+			t.Points = -n
+			t.Allocations = allocs
+		case req.Type == domain.PointsReverse:
+			allocs, err := reverseAllocations(reversed, lots)
+			if err != nil {
+				return nil, err
+			}
+			t.Points = -reversed.Points
+			t.ReversesID = &reversed.ID
+			t.Allocations = allocs
+		}
+		posted = t
+		return t, nil
+	})
+	if errors.Is(err, domain.ErrConflict) && req.IdempotencyKey != "" {
+		// Lost a race with a concurrent request carrying the same key.
+		existing, findErr := s.repo.FindByIdempotencyKey(ctx, customerID, req.IdempotencyKey)
+		if findErr == nil && existing != nil {
+			return existing, false, nil
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.auditService.Log(ctx, customerID, "CUSTOMER", "POINTS_"+string(posted.Type), userID.String(),
+		fmt.Sprintf("points=%d reference=%s reason=%s", posted.Points, posted.Reference, posted.Reason), "")
+	s.reevaluateTier(ctx, customerID)
+	return posted, true, nil
+}
+
+// reevaluateTier re-derives the tier from the new balance. The posting is already committed, so
+// a failure is logged and left for the nightly recalculation rather than failing the request.
+func (s *pointsService) reevaluateTier(ctx context.Context, customerID uuid.UUID) {
+	if s.tiers == nil {
+		return
+	}
+	if _, err := s.tiers.Reevaluate(ctx, customerID, domain.TierSourcePoints); err != nil {
+		log.Printf("tier re-evaluation for customer %s: %v", customerID, err)
+	}
+}
+
+func (s *pointsService) List(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.PointsTransaction, error) {
+	return s.repo.List(ctx, customerID, limit, offset)
+}
+
+func (s *pointsService) ExpireDue(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		ids, err := s.repo.CustomersWithExpiredLots(ctx, pointsExpiryBatchSize)
+		if err != nil || len(ids) == 0 {
+			return expired, err
+		}
+		for _, id := range ids {
+			var posted *domain.PointsTransaction
+			err := s.repo.Post(ctx, id, func(lots []*domain.PointsTransaction) (*domain.PointsTransaction, error) {
+				now := s.now()
+				t := &domain.PointsTransaction{Type: domain.PointsExpire, Reason: "Points expired"}
+				for _, lot := range lots {
+					if lot.Remaining > 0 && !lot.Usable(now) {
+						t.Allocations = append(t.Allocations, domain.PointsAllocation{LotID: lot.ID, Points: lot.Remaining})
+						t.Points -= lot.Remaining
+					}
+				}
+				if len(t.Allocations) == 0 {
+					return nil, nil
+				}
+				posted = t
+				return t, nil
+			})
+			if errors.Is(err, domain.ErrNotFound) {
+				// Deleted since it was listed; it will not be listed again.
+				log.Printf("points expiry for customer %s: %v", id, err)
+				continue
+			}
+			if err != nil {
+				return expired, fmt.Errorf("customer %s: %w", id, err)
+			}
+			if posted != nil {
+				expired++
+				s.auditService.Log(ctx, id, "CUSTOMER", "POINTS_EXPIRE", "SYSTEM", fmt.Sprintf("points=%d", posted.Points), "")
+				s.reevaluateTier(ctx, id)
+			}
+		}
+		if len(ids) < pointsExpiryBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+func validatePointsRequest(req domain.PointsRequest) error {
+	switch req.Type {
+	case domain.PointsEarn, domain.PointsBurn:
+		if req.Points <= 0 {
+			return errors.New("points must be positive")
+		}
+	case domain.PointsAdjust:
+		if req.Points == 0 {
+			return errors.New("points must not be zero")
+		}
+		if req.Reason == "" {
+			return errors.New("reason is required for adjustments")
+		}
+	case domain.PointsReverse:
+		if req.ReversesID == nil {
+			return errors.New("reverses_id is required")
+		}
+		if req.Reason == "" {
+			return errors.New("reason is required for reversals")
+		}
+	case domain.PointsExpire:
+		return errors.New("EXPIRE transactions are created by the expiry job")
+	default:
+		return fmt.Errorf("unknown transaction type %q", req.Type)
+	}
+	return nil
+}
+
+// allocateFIFO takes n points from the oldest usable lots first. lots must be ordered oldest first.
+func allocateFIFO(lots []*domain.PointsTransaction, n int64, now time.Time) ([]domain.PointsAllocation, error) {
+	var allocs []domain.PointsAllocation
+	for _, lot := range lots {
+		if n == 0 {
+			break
+		}
+		if !lot.Usable(now) {
+			continue
+		}
+		take := lot.Remaining
+		if take > n {
+			take = n
+		}
+		allocs = append(allocs, domain.PointsAllocation{LotID: lot.ID, Points: take})
+		n -= take
+	}
+	if n > 0 {
+		return nil, domain.ErrInsufficientPoints
+	}
+	return allocs, nil
+}
+
+// reverseAllocations undoes orig. A debit hands its points back to the lots it drew from;
+// a credit can only be reversed while its lot is untouched.
+func reverseAllocations(orig *domain.PointsTransaction, lots []*domain.PointsTransaction) ([]domain.PointsAllocation, error) {
+	if !orig.IsLot() {
+		allocs := make([]domain.PointsAllocation, 0, len(orig.Allocations))
+		for _, a := range orig.Allocations {
+			allocs = append(allocs, domain.PointsAllocation{LotID: a.LotID, Points: -a.Points})
+		}
+		return allocs, nil
+	}
+	for _, lot := range lots {
+		if lot.ID == orig.ID && lot.Remaining == orig.Points {
+			return []domain.PointsAllocation{{LotID: orig.ID, Points: orig.Points}}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: points from this transaction have already been used or expired", domain.ErrInsufficientPoints)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// mockPointsRepo is an in-memory ledger that applies allocations the way the SQL repository does.
+type mockPointsRepo struct {
+	txs     []*domain.PointsTransaction
+	deleted map[uuid.UUID]bool
+}
+
+func (m *mockPointsRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.PointsTransaction, error) {
+	for _, t := range m.txs {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, errors.New("points transaction not found")
+}
+func (m *mockPointsRepo) FindByIdempotencyKey(ctx context.Context, customerID uuid.UUID, key string) (*domain.PointsTransaction, error) {
+	for _, t := range m.txs {
+		if t.CustomerID == customerID && t.IdempotencyKey == key {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockPointsRepo) List(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.PointsTransaction, error) {
+	return m.txs, nil
+}
+func (m *mockPointsRepo) Post(ctx context.Context, customerID uuid.UUID, build func([]*domain.PointsTransaction) (*domain.PointsTransaction, error)) error {
+	if m.deleted[customerID] {
+		return fmt.Errorf("customer %w", domain.ErrNotFound)
+	}
+	var lots []*domain.PointsTransaction
+	for _, t := range m.txs {
+		if t.CustomerID == customerID && t.Remaining > 0 {
+			lots = append(lots, t)
+		}
+	}
+	t, err := build(lots)
+	if err != nil || t == nil {
+		return err
+	}
+	t.ID = uuid.New()
+	t.CustomerID = customerID
+	if t.IsLot() {
+		t.Remaining = t.Points
+	}
+	for _, a := range t.Allocations {
+		lot, _ := m.GetByID(ctx, a.LotID)
+		lot.Remaining -= a.Points
+	}
+	m.txs = append(m.txs, t)
+	return nil
+}
+func (m *mockPointsRepo) CustomersWithExpiredLots(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, t := range m.txs {
+		if t.Remaining > 0 && t.ExpiresAt != nil && !time.Now().Before(*t.ExpiresAt) && !seen[t.CustomerID] {
+			seen[t.CustomerID] = true
+			ids = append(ids, t.CustomerID)
+		}
+	}
+	return ids, nil
+}
+
+func newTestPointsService(repo *mockPointsRepo) *pointsService {
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	return NewPointsService(repo, customers, nil, &mockAuditService{}, 24)
+}
+
+func earnLot(customerID uuid.UUID, points int64, expiresAt *time.Time) *domain.PointsTransaction {
+	return &domain.PointsTransaction{ID: uuid.New(), CustomerID: customerID, Type: domain.PointsEarn,
+		Points: points, Remaining: points, ExpiresAt: expiresAt}
+}
+
+func TestPointsBurn_FIFOSkipsExpiredLots(t *testing.T) {
+	cid := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	expired := earnLot(cid, 100, &past)
+	oldest := earnLot(cid, 30, nil)
+	newer := earnLot(cid, 50, nil)
+	repo := &mockPointsRepo{txs: []*domain.PointsTransaction{expired, oldest, newer}}
+	svc := newTestPointsService(repo)
+
+	tx, created, err := svc.Post(context.Background(), cid, uuid.New(), domain.PointsRequest{Type: domain.PointsBurn, Points: 40})
+	if err != nil || !created {
+		t.Fatalf("Unexpected result: created=%v err=%v", created, err)
+	}
+	if tx.Points != -40 || len(tx.Allocations) != 2 {
+		t.Fatalf("Expected -40 across two lots, got %d across %d", tx.Points, len(tx.Allocations))
+	}
+	if oldest.Remaining != 0 || newer.Remaining != 40 || expired.Remaining != 100 {
+		t.Errorf("Expected oldest lot drained first and expired lot untouched, got %d/%d/%d",
+			oldest.Remaining, newer.Remaining, expired.Remaining)
+	}
+
+	_, _, err = svc.Post(context.Background(), cid, uuid.New(), domain.PointsRequest{Type: domain.PointsBurn, Points: 41})
+	if !errors.Is(err, domain.ErrInsufficientPoints) {
+		t.Errorf("Expected ErrInsufficientPoints, got %v", err)
+	}
+}
+
+func TestPointsEarn_Idempotent(t *testing.T) {
+	cid := uuid.New()
+	repo := &mockPointsRepo{}
+	svc := newTestPointsService(repo)
+	req := domain.PointsRequest{Type: domain.PointsEarn, Points: 100, IdempotencyKey: "order-1"}
+
+	first, created, err := svc.Post(context.Background(), cid, uuid.New(), req)
+	if err != nil || !created {
+		t.Fatalf("Unexpected result: created=%v err=%v", created, err)
+	}
+	if first.ExpiresAt == nil {
+		t.Error("Expected default expiry on earned points")
+	}
+
+	again, created, err := svc.Post(context.Background(), cid, uuid.New(), req)
+	if err != nil || created || again.ID != first.ID {
+		t.Errorf("Expected replay to return original transaction, got created=%v err=%v", created, err)
+	}
+	if len(repo.txs) != 1 {
+		t.Errorf("Expected one ledger entry, got %d", len(repo.txs))
+	}
+}
+
+func TestPointsReverse(t *testing.T) {
+	cid := uuid.New()
+	lot := earnLot(cid, 100, nil)
+	repo := &mockPointsRepo{txs: []*domain.PointsTransaction{lot}}
+	svc := newTestPointsService(repo)
+	ctx := context.Background()
+
+	burn, _, _ := svc.Post(ctx, cid, uuid.New(), domain.PointsRequest{Type: domain.PointsBurn, Points: 60})
+
+	_, _, err := svc.Post(ctx, cid, uuid.New(), domain.PointsRequest{Type: domain.PointsReverse, ReversesID: &lot.ID, Reason: "refund"})
+	if err == nil {
+		t.Error("Expected reversing a partly used earn to fail")
+	}
+
+	rev, _, err := svc.Post(ctx, cid, uuid.New(), domain.PointsRequest{Type: domain.PointsReverse, ReversesID: &burn.ID, Reason: "cancelled"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rev.Points != 60 || lot.Remaining != 100 {
+		t.Errorf("Expected burn reversal to give 60 back to the lot, got %d (lot %d)", rev.Points, lot.Remaining)
+	}
+}
+
+func TestPointsExpireDue(t *testing.T) {
+	cid := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	lot := earnLot(cid, 70, &past)
+	repo := &mockPointsRepo{txs: []*domain.PointsTransaction{lot, earnLot(cid, 10, nil)}}
+
+	n, err := newTestPointsService(repo).ExpireDue(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Expected one expiry posting, got %d (err %v)", n, err)
+	}
+	last := repo.txs[len(repo.txs)-1]
+	if last.Type != domain.PointsExpire || last.Points != -70 || lot.Remaining != 0 {
+		t.Errorf("Expected EXPIRE of 70, got %s %d (lot %d)", last.Type, last.Points, lot.Remaining)
+	}
+}
+
+func TestPointsExpireDue_SkipsDeletedCustomers(t *testing.T) {
+	gone, live := uuid.New(), uuid.New()
+	past := time.Now().Add(-time.Hour)
+	lot := earnLot(live, 30, &past)
+	repo := &mockPointsRepo{
+		txs:     []*domain.PointsTransaction{earnLot(gone, 50, &past), lot},
+		deleted: map[uuid.UUID]bool{gone: true},
+	}
+
+	n, err := newTestPointsService(repo).ExpireDue(context.Background())
+	if err != nil || n != 1 || lot.Remaining != 0 {
+		t.Errorf("Expected the live customer's lot expired past the deleted one, got %d (err %v, remaining %d)", n, err, lot.Remaining)
+	}
+}
+
+// ledgerTierRepo reads the points balance from the ledger, as the cached column would hold it.
+type ledgerTierRepo struct {
+	*mockTierRepo
+	ledger *mockPointsRepo
+}
+
+func (r *ledgerTierRepo) GetForRecalc(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error) {
+	c, err := r.mockTierRepo.GetForRecalc(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	var balance int64
+	for _, t := range r.ledger.txs {
+		if t.CustomerID == customerID {
+			balance += t.Remaining
+		}
+	}
+	c.PointsBalance = decimal.NewFromInt(balance)
+	return c, nil
+}
+
+func TestPointsPost_ReevaluatesTier(t *testing.T) {
+	cid := uuid.New()
+	ledger := &mockPointsRepo{}
+	tierRepo := &ledgerTierRepo{
+		mockTierRepo: &mockTierRepo{customers: []*domain.Customer{{ID: cid, Type: domain.TypePersonal, MembershipTier: "STANDARD"}}},
+		ledger:       ledger,
+	}
+	svc := newTestPointsService(ledger)
+	svc.tiers = defaultTierService(t, tierRepo)
+
+	if _, _, err := svc.Post(context.Background(), cid, uuid.New(), domain.PointsRequest{Type: domain.PointsEarn, Points: 10000}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tierRepo.updated[cid] != "SILVER" {
+		t.Fatalf("Expected the earn to lift the customer to SILVER, got %q", tierRepo.updated[cid])
+	}
+	if len(tierRepo.history) != 1 || tierRepo.history[0].Source != domain.TierSourcePoints {
+		t.Errorf("Expected one POINTS tier change, got %+v", tierRepo.history)
+	}
+}
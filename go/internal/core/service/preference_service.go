@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// DefaultConsentTopics maps purposes to the consent topic that must be granted before using them.
+var DefaultConsentTopics = map[domain.CommPurpose]string{
+	domain.PurposePromotion: "MARKETING",
+}
+
+const defaultLanguage = "th"
+
+type preferenceService struct {
+	repo          ports.PreferenceRepository
+	customerRepo  ports.CustomerRepository
+	consentRepo   ports.ConsentRepository
+	contactRepo   ports.ContactPointRepository
+	addressRepo   ports.AddressRepository
+	auditService  AuditService
+	loc           *time.Location
+	consentTopics map[domain.CommPurpose]string
+}
+
+// NewPreferenceService evaluates quiet hours and allowed days in loc. consentTopics defaults
+// to DefaultConsentTopics when nil.
+func NewPreferenceService(
+	repo ports.PreferenceRepository,
+	customerRepo ports.CustomerRepository,
+	consentRepo ports.ConsentRepository,
+	contactRepo ports.ContactPointRepository,
+	addressRepo ports.AddressRepository,
+	audit AuditService,
+	loc *time.Location,
+	consentTopics map[domain.CommPurpose]string,
+) *preferenceService {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if consentTopics == nil {
+		consentTopics = DefaultConsentTopics
+	}
+	return &preferenceService{
+		repo:          repo,
+		customerRepo:  customerRepo,
+		consentRepo:   consentRepo,
+		contactRepo:   contactRepo,
+		addressRepo:   addressRepo,
+		auditService:  audit,
+		loc:           loc,
+		consentTopics: consentTopics,
+	}
+}
+
+// Get returns the stored preferences, or an empty matrix when none have been saved.
+func (s *preferenceService) Get(ctx context.Context, customerID uuid.UUID) (*domain.CommunicationPreferences, error) {
+	p, err := s.repo.Get(ctx, customerID)
+	if err != nil || p != nil {
+		return p, err
+	}
+	return &domain.CommunicationPreferences{CustomerID: customerID, Channels: []domain.ChannelPreference{}}, nil
+}
+
+func (s *preferenceService) Save(ctx context.Context, p *domain.CommunicationPreferences, userID uuid.UUID) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, p.CustomerID); err != nil {
+		return err
+	}
+	if err := s.repo.Save(ctx, p); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, p.CustomerID, "CUSTOMER", "PREFERENCES_UPDATE", userID.String(),
+		fmt.Sprintf("language=%s channels=%d", p.Language, len(p.Channels)), "")
+	return nil
+}
+
+// BestChannel walks the customer's allowed channels for purpose in priority order and returns the
+// first that is outside quiet hours, on an allowed day and has somewhere to send to. Without any
+// preferences for the purpose it falls back to the customer's PreferredChannel. A purpose needing
+// consent yields no channel unless the latest consent for its topic is a grant.
+func (s *preferenceService) BestChannel(ctx context.Context, customerID uuid.UUID, purpose domain.CommPurpose, at time.Time) (*domain.ChannelDecision, error) {
+	c, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.Get(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &domain.ChannelDecision{Purpose: purpose, Language: prefs.Language, At: at.In(s.loc)}
+	if d.Language == "" {
+		d.Language = defaultLanguage
+	}
+
+	candidates := prefs.For(purpose)
+	if len(candidates) == 0 && c.PreferredChannel != "" {
+		candidates = []domain.ChannelPreference{{Purpose: purpose, Channel: domain.CommChannel(strings.ToUpper(c.PreferredChannel)), Allowed: true}}
+	}
+
+	if topic, ok := s.consentTopics[purpose]; ok {
+		granted, err := s.consentGranted(ctx, customerID, topic)
+		if err != nil {
+			return nil, err
+		}
+		if !granted {
+			for _, cand := range candidates {
+				d.Skipped = append(d.Skipped, domain.SkippedChannel{Channel: cand.Channel, Reason: "no " + topic + " consent"})
+			}
+			return d, nil
+		}
+	}
+
+	contacts, err := s.contacts(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cand := range candidates {
+		if reason := cand.Unavailable(at, s.loc); reason != "" {
+			d.Skipped = append(d.Skipped, domain.SkippedChannel{Channel: cand.Channel, Reason: reason})
+			continue
+		}
+		contact, err := s.contactFor(ctx, customerID, cand.Channel, contacts)
+		if err != nil {
+			return nil, err
+		}
+		if contact == "" {
+			d.Skipped = append(d.Skipped, domain.SkippedChannel{Channel: cand.Channel, Reason: "no contact for channel"})
+			continue
+		}
+		d.Channel = cand.Channel
+		d.Contact = contact
+		return d, nil
+	}
+	return d, nil
+}
+
+// consentGranted reports whether the most recent consent record for topic is a grant.
+func (s *preferenceService) consentGranted(ctx context.Context, customerID uuid.UUID, topic string) (bool, error) {
+	consents, err := s.consentRepo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+	var latest *domain.Consent
+	for _, c := range consents {
+		if !strings.EqualFold(c.Topic, topic) {
+			continue
+		}
+		if latest == nil || c.Timestamp.After(latest.Timestamp) {
+			latest = c
+		}
+	}
+	return latest != nil && latest.IsGranted, nil
+}
+
+// contacts returns the customer's contact points, primary and verified ones first.
+func (s *preferenceService) contacts(ctx context.Context, customerID uuid.UUID) ([]*domain.ContactPoint, error) {
+	cps, err := s.contactRepo.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	rank := func(cp *domain.ContactPoint) int {
+		r := 0
+		if !cp.IsPrimary {
+			r += 2
+		}
+		if cp.Status != domain.VerificationVerified {
+			r++
+		}
+		return r
+	}
+	sort.SliceStable(cps, func(i, j int) bool { return rank(cps[i]) < rank(cps[j]) })
+	return cps, nil
+}
+
+// contactFor picks where to send on channel: a contact point of the matching type, or an address for POST.
+func (s *preferenceService) contactFor(ctx context.Context, customerID uuid.UUID, channel domain.CommChannel, contacts []*domain.ContactPoint) (string, error) {
+	var types []domain.ContactType
+	switch channel {
+	case domain.CommEmail:
+		types = []domain.ContactType{domain.ContactEmail}
+	case domain.CommSMS:
+		types = []domain.ContactType{domain.ContactMobile}
+	case domain.CommPhone:
+		types = []domain.ContactType{domain.ContactMobile, domain.ContactLandline}
+	case domain.CommLine:
+		types = []domain.ContactType{domain.ContactLine}
+	case domain.CommPost:
+		addresses, err := s.addressRepo.ListByCustomerID(ctx, customerID)
+		if err != nil || len(addresses) == 0 {
+			return "", err
+		}
+		a := addresses[0]
+		for _, candidate := range addresses {
+			if strings.EqualFold(candidate.Type, "Mailing") {
+				a = candidate
+				break
+			}
+		}
+		return strings.Join(nonEmpty(a.AddressLine1, a.AddressLine2, a.SubDistrict, a.District, a.City, a.State, a.ZipCode, a.Country), ", "), nil
+	default:
+		return "", nil
+	}
+
+	for _, t := range types {
+		for _, cp := range contacts {
+			if cp.Type == t {
+				return cp.Value, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func nonEmpty(parts ...string) []string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type mockPreferenceRepo struct {
+	prefs map[uuid.UUID]*domain.CommunicationPreferences
+}
+
+func (m *mockPreferenceRepo) Get(ctx context.Context, customerID uuid.UUID) (*domain.CommunicationPreferences, error) {
+	return m.prefs[customerID], nil
+}
+func (m *mockPreferenceRepo) Save(ctx context.Context, p *domain.CommunicationPreferences) error {
+	m.prefs[p.CustomerID] = p
+	return nil
+}
+
+func TestPreferenceService_BestChannel(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*3600)
+	customerID := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id}, nil
+		},
+	}
+	contacts := newMockContactPointRepo()
+	contacts.rows[uuid.New()] = &domain.ContactPoint{CustomerID: customerID, Type: domain.ContactMobile, Value: "+66812345678"}
+	contacts.rows[uuid.New()] = &domain.ContactPoint{CustomerID: customerID, Type: domain.ContactEmail, Value: "somchai@example.com"}
+	consents := &stubConsents{}
+	svc := NewPreferenceService(&mockPreferenceRepo{prefs: map[uuid.UUID]*domain.CommunicationPreferences{}},
+		customers, consents, contacts, &mockAddressRepo{}, &mockAuditService{}, bangkok, nil)
+	ctx := context.Background()
+
+	err := svc.Save(ctx, &domain.CommunicationPreferences{
+		CustomerID: customerID,
+		Language:   "EN",
+		Channels: []domain.ChannelPreference{
+			{Purpose: "promotion", Channel: "sms", Allowed: true, Priority: 1, Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+			{Purpose: "promotion", Channel: "email", Allowed: true, Priority: 2},
+			{Purpose: "service", Channel: "phone", Allowed: true, Priority: 1, QuietStart: "20:00", QuietEnd: "08:00"},
+			{Purpose: "service", Channel: "email", Allowed: true, Priority: 2},
+			{Purpose: "service", Channel: "line", Allowed: false, Priority: 0},
+		},
+	}, uuid.New())
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	monday10 := time.Date(2024, 6, 3, 10, 0, 0, 0, bangkok)
+	saturday10 := time.Date(2024, 6, 8, 10, 0, 0, 0, bangkok)
+	monday21 := time.Date(2024, 6, 3, 21, 0, 0, 0, bangkok)
+
+	tests := []struct {
+		name    string
+		purpose domain.CommPurpose
+		at      time.Time
+		granted bool
+		want    domain.CommChannel
+	}{
+		{"promotion without consent", domain.PurposePromotion, monday10, false, ""},
+		{"promotion on a weekday", domain.PurposePromotion, monday10, true, domain.CommSMS},
+		{"promotion at the weekend", domain.PurposePromotion, saturday10, true, domain.CommEmail},
+		{"service in office hours", domain.PurposeService, monday10, false, domain.CommPhone},
+		{"service in quiet hours", domain.PurposeService, monday21.UTC(), false, domain.CommEmail},
+		{"no preferences for purpose", domain.PurposeStatement, monday10, false, ""},
+	}
+	for _, tt := range tests {
+		consents.consents = []*domain.Consent{
+			{Topic: "MARKETING", IsGranted: !tt.granted, Timestamp: monday10.Add(-48 * time.Hour)},
+			{Topic: "MARKETING", IsGranted: tt.granted, Timestamp: monday10.Add(-24 * time.Hour)},
+		}
+		d, err := svc.BestChannel(ctx, customerID, tt.purpose, tt.at)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if d.Channel != tt.want {
+			t.Errorf("%s: got %q (skipped %+v), want %q", tt.name, d.Channel, d.Skipped, tt.want)
+		}
+		if d.Language != "en" {
+			t.Errorf("%s: expected language en, got %q", tt.name, d.Language)
+		}
+	}
+}
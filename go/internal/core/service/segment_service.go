@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type segmentService struct {
+	repo         ports.SegmentRepository
+	customers    ports.CustomerService
+	auditService AuditService
+}
+
+func NewSegmentService(repo ports.SegmentRepository, customers ports.CustomerService, audit AuditService) *segmentService {
+	return &segmentService{repo: repo, customers: customers, auditService: audit}
+}
+
+// Create saves a segment owned by u.
+func (s *segmentService) Create(ctx context.Context, seg *domain.Segment, u domain.SegmentUser) error {
+	seg.OwnerID = u.ID
+	if err := seg.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, seg); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, seg.ID, "SEGMENT", "CREATE", u.ID.String(), fmt.Sprintf("name=%s", seg.Name), "")
+	return nil
+}
+
+func (s *segmentService) Get(ctx context.Context, id uuid.UUID, u domain.SegmentUser) (*domain.Segment, error) {
+	seg, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !seg.CanView(u) {
+		return nil, fmt.Errorf("%w: segment is not shared with you", domain.ErrForbidden)
+	}
+	return seg, nil
+}
+
+func (s *segmentService) List(ctx context.Context, u domain.SegmentUser) ([]*domain.Segment, error) {
+	all, err := s.repo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	visible := []*domain.Segment{}
+	for _, seg := range all {
+		if seg.CanView(u) {
+			visible = append(visible, seg)
+		}
+	}
+	return visible, nil
+}
+
+// Update replaces the definition and sharing. The owner and creation time are kept.
+func (s *segmentService) Update(ctx context.Context, seg *domain.Segment, u domain.SegmentUser) error {
+	existing, err := s.managed(ctx, seg.ID, u)
+	if err != nil {
+		return err
+	}
+	if err := seg.Validate(); err != nil {
+		return err
+	}
+	seg.OwnerID = existing.OwnerID
+	seg.CreatedAt = existing.CreatedAt
+	seg.LastRun = existing.LastRun
+
+	if err := s.repo.Update(ctx, seg); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, seg.ID, "SEGMENT", "UPDATE", u.ID.String(), fmt.Sprintf("name=%s", seg.Name), "")
+	return nil
+}
+
+func (s *segmentService) Delete(ctx context.Context, id uuid.UUID, u domain.SegmentUser) error {
+	seg, err := s.managed(ctx, id, u)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, id, "SEGMENT", "DELETE", u.ID.String(), fmt.Sprintf("name=%s", seg.Name), "")
+	return nil
+}
+
+func (s *segmentService) Evaluate(ctx context.Context, id uuid.UUID, u domain.SegmentUser, q domain.CustomerQuery) (*domain.CustomerPage, error) {
+	seg, err := s.Get(ctx, id, u)
+	if err != nil {
+		return nil, err
+	}
+	q.Filter = seg.Filter
+	return s.customers.QueryCustomers(ctx, q)
+}
+
+// Export walks the segment newest first, a full page at a time.
+func (s *segmentService) Export(ctx context.Context, id uuid.UUID, u domain.SegmentUser) ([]*domain.Customer, error) {
+	seg, err := s.Get(ctx, id, u)
+	if err != nil {
+		return nil, err
+	}
+	var out []*domain.Customer
+	q := domain.CustomerQuery{Filter: seg.Filter, Limit: domain.MaxCustomerPageSize}
+	for {
+		page, err := s.customers.QueryCustomers(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page.Customers...)
+		if page.NextCursor == "" {
+			break
+		}
+		if q.Cursor, err = domain.DecodePageCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+	s.auditService.Log(ctx, id, "SEGMENT", "EXPORT", u.ID.String(), fmt.Sprintf("customers=%d", len(out)), "")
+	return out, nil
+}
+
+func (s *segmentService) Materialize(ctx context.Context, id uuid.UUID, u domain.SegmentUser) (*domain.SegmentRun, error) {
+	seg, err := s.managed(ctx, id, u)
+	if err != nil {
+		return nil, err
+	}
+	return s.materialize(ctx, seg, u.ID.String())
+}
+
+// MaterializeDue runs every DAILY segment. One failing segment does not stop the others; the
+// first error is returned after all have been tried.
+func (s *segmentService) MaterializeDue(ctx context.Context) (int, error) {
+	segments, err := s.repo.List(ctx, domain.SegmentDaily)
+	if err != nil {
+		return 0, err
+	}
+	var firstErr error
+	n := 0
+	for _, seg := range segments {
+		if _, err := s.materialize(ctx, seg, "SYSTEM"); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("segment %s: %w", seg.ID, err)
+			}
+			continue
+		}
+		n++
+	}
+	return n, firstErr
+}
+
+func (s *segmentService) materialize(ctx context.Context, seg *domain.Segment, triggeredBy string) (*domain.SegmentRun, error) {
+	ids, err := s.customers.MatchingCustomerIDs(ctx, seg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	run := &domain.SegmentRun{SegmentID: seg.ID, TriggeredBy: triggeredBy}
+	if err := s.repo.SaveRun(ctx, run, ids); err != nil {
+		return nil, err
+	}
+	s.auditService.Log(ctx, seg.ID, "SEGMENT", "MATERIALIZE", triggeredBy,
+		fmt.Sprintf("size=%d entered=%d left=%d", run.Size, run.Entered, run.Left), "")
+	return run, nil
+}
+
+func (s *segmentService) Runs(ctx context.Context, id uuid.UUID, u domain.SegmentUser, limit, offset int) ([]*domain.SegmentRun, error) {
+	if _, err := s.Get(ctx, id, u); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.repo.ListRuns(ctx, id, limit, offset)
+}
+
+func (s *segmentService) Changes(ctx context.Context, id, runID uuid.UUID, fromRunID *uuid.UUID, u domain.SegmentUser) (*domain.SegmentDiff, error) {
+	if _, err := s.Get(ctx, id, u); err != nil {
+		return nil, err
+	}
+	to, err := s.run(ctx, id, runID)
+	if err != nil {
+		return nil, err
+	}
+	var from *domain.SegmentRun
+	if fromRunID != nil {
+		if from, err = s.run(ctx, id, *fromRunID); err != nil {
+			return nil, err
+		}
+	} else if from, err = s.repo.PreviousRun(ctx, to); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.Changes(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.SegmentDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// run loads a run only if it belongs to segmentID, so a URL cannot reach another segment's runs.
+func (s *segmentService) run(ctx context.Context, segmentID, runID uuid.UUID) (*domain.SegmentRun, error) {
+	run, err := s.repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.SegmentID != segmentID {
+		return nil, fmt.Errorf("segment run %w", domain.ErrNotFound)
+	}
+	return run, nil
+}
+
+func (s *segmentService) managed(ctx context.Context, id uuid.UUID, u domain.SegmentUser) (*domain.Segment, error) {
+	seg, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !seg.CanManage(u) {
+		return nil, fmt.Errorf("%w: only the owner or an admin can change a segment", domain.ErrForbidden)
+	}
+	return seg, nil
+}
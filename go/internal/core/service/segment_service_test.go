@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// memSegments keeps segments and the current members of each in memory.
+type memSegments struct {
+	segments map[uuid.UUID]*domain.Segment
+	runs     []*domain.SegmentRun
+	members  map[uuid.UUID]map[uuid.UUID]bool
+	changes  func(from, to *domain.SegmentRun) []domain.SegmentChange
+}
+
+func newMemSegments() *memSegments {
+	return &memSegments{segments: map[uuid.UUID]*domain.Segment{}, members: map[uuid.UUID]map[uuid.UUID]bool{}}
+}
+
+func (m *memSegments) Create(ctx context.Context, s *domain.Segment) error {
+	s.ID = uuid.New()
+	s2 := *s
+	m.segments[s.ID] = &s2
+	return nil
+}
+func (m *memSegments) GetByID(ctx context.Context, id uuid.UUID) (*domain.Segment, error) {
+	s, ok := m.segments[id]
+	if !ok {
+		return nil, fmt.Errorf("segment %w", domain.ErrNotFound)
+	}
+	s2 := *s
+	return &s2, nil
+}
+func (m *memSegments) Update(ctx context.Context, s *domain.Segment) error {
+	s2 := *s
+	m.segments[s.ID] = &s2
+	return nil
+}
+func (m *memSegments) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.segments, id)
+	return nil
+}
+func (m *memSegments) List(ctx context.Context, schedule string) ([]*domain.Segment, error) {
+	var out []*domain.Segment
+	for _, s := range m.segments {
+		if schedule == "" || s.Schedule == schedule {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+func (m *memSegments) SaveRun(ctx context.Context, run *domain.SegmentRun, memberIDs []uuid.UUID) error {
+	run.ID = uuid.New()
+	run.RunAt = time.Now()
+	current := m.members[run.SegmentID]
+	next := map[uuid.UUID]bool{}
+	for _, id := range memberIDs {
+		next[id] = true
+		if !current[id] {
+			run.Entered++
+		}
+	}
+	for id := range current {
+		if !next[id] {
+			run.Left++
+		}
+	}
+	run.Size = len(memberIDs)
+	m.members[run.SegmentID] = next
+	m.runs = append(m.runs, run)
+	return nil
+}
+func (m *memSegments) GetRun(ctx context.Context, id uuid.UUID) (*domain.SegmentRun, error) {
+	for _, r := range m.runs {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("segment run %w", domain.ErrNotFound)
+}
+func (m *memSegments) PreviousRun(ctx context.Context, run *domain.SegmentRun) (*domain.SegmentRun, error) {
+	var prev *domain.SegmentRun
+	for _, r := range m.runs {
+		if r.ID == run.ID {
+			return prev, nil
+		}
+		if r.SegmentID == run.SegmentID {
+			prev = r
+		}
+	}
+	return nil, nil
+}
+func (m *memSegments) ListRuns(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]*domain.SegmentRun, error) {
+	return m.runs, nil
+}
+func (m *memSegments) Changes(ctx context.Context, segmentID uuid.UUID, from, to *domain.SegmentRun) ([]domain.SegmentChange, error) {
+	return m.changes(from, to), nil
+}
+
+// segmentCustomers answers the segment's filter with a fixed set of matching ids.
+type segmentCustomers struct {
+	ports.CustomerService
+	ids []uuid.UUID
+}
+
+func (s *segmentCustomers) MatchingCustomerIDs(ctx context.Context, f domain.CustomerFilter) ([]uuid.UUID, error) {
+	return s.ids, nil
+}
+
+func TestSegmentService_Sharing(t *testing.T) {
+	svc := NewSegmentService(newMemSegments(), &segmentCustomers{}, &mockAuditService{})
+	ctx := context.Background()
+	owner := domain.SegmentUser{ID: uuid.New(), Role: "OPERATOR"}
+	viewer := domain.SegmentUser{ID: uuid.New(), Role: "VIEWER"}
+	other := domain.SegmentUser{ID: uuid.New(), Role: "OPERATOR"}
+	admin := domain.SegmentUser{ID: uuid.New(), Role: "ADMIN", Admin: true}
+
+	seg := &domain.Segment{
+		Name:            " Bangkok VIPs ",
+		Filter:          domain.CustomerFilter{Tiers: []string{"gold"}, Province: "Bangkok"},
+		SharedWithRoles: []string{"viewer"},
+		Schedule:        "daily",
+	}
+	if err := svc.Create(ctx, seg, owner); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if seg.OwnerID != owner.ID || seg.Name != "Bangkok VIPs" || seg.Schedule != domain.SegmentDaily ||
+		seg.SharedWithRoles[0] != "VIEWER" || seg.Filter.Tiers[0] != "GOLD" {
+		t.Errorf("Expected normalized segment owned by creator, got %+v", seg)
+	}
+	if err := svc.Create(ctx, &domain.Segment{Name: "x", Schedule: "HOURLY"}, owner); err == nil {
+		t.Error("Expected unknown schedule to be rejected")
+	}
+
+	if _, err := svc.Get(ctx, seg.ID, viewer); err != nil {
+		t.Errorf("Expected shared role to view, got %v", err)
+	}
+	if _, err := svc.Get(ctx, seg.ID, other); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for unshared role, got %v", err)
+	}
+	if list, _ := svc.List(ctx, other); len(list) != 0 {
+		t.Errorf("Expected no visible segments, got %d", len(list))
+	}
+
+	update := &domain.Segment{ID: seg.ID, Name: "Renamed", OwnerID: viewer.ID}
+	if err := svc.Update(ctx, update, viewer); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected viewer update to be forbidden, got %v", err)
+	}
+	if err := svc.Update(ctx, update, admin); err != nil || update.OwnerID != owner.ID {
+		t.Errorf("Expected admin update to keep the owner, got %v, owner %s", err, update.OwnerID)
+	}
+	if _, err := svc.Materialize(ctx, seg.ID, viewer); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected viewer materialize to be forbidden, got %v", err)
+	}
+}
+
+func TestSegmentService_MaterializeTracksMembership(t *testing.T) {
+	repo := newMemSegments()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	customers := &segmentCustomers{ids: []uuid.UUID{a, b}}
+	svc := NewSegmentService(repo, customers, &mockAuditService{})
+	ctx := context.Background()
+	owner := domain.SegmentUser{ID: uuid.New(), Role: "OPERATOR"}
+
+	seg := &domain.Segment{Name: "Daily", Schedule: domain.SegmentDaily}
+	if err := svc.Create(ctx, seg, owner); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := svc.Create(ctx, &domain.Segment{Name: "On demand"}, owner); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	n, err := svc.MaterializeDue(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Expected only the daily segment to run, got %d, %v", n, err)
+	}
+	first := repo.runs[0]
+	if first.Size != 2 || first.Entered != 2 || first.Left != 0 || first.TriggeredBy != "SYSTEM" {
+		t.Errorf("Unexpected first run %+v", first)
+	}
+
+	customers.ids = []uuid.UUID{b, c}
+	second, err := svc.Materialize(ctx, seg.ID, owner)
+	if err != nil {
+		t.Fatalf("Materialize failed: %v", err)
+	}
+	if second.Size != 2 || second.Entered != 1 || second.Left != 1 || second.TriggeredBy != owner.ID.String() {
+		t.Errorf("Unexpected second run %+v", second)
+	}
+
+	var gotFrom *domain.SegmentRun
+	repo.changes = func(from, to *domain.SegmentRun) []domain.SegmentChange {
+		gotFrom = from
+		return []domain.SegmentChange{{CustomerID: c, Change: domain.SegmentEntered}, {CustomerID: a, Change: domain.SegmentLeft}}
+	}
+	diff, err := svc.Changes(ctx, seg.ID, second.ID, nil, owner)
+	if err != nil || gotFrom != first || diff.To != second || len(diff.Changes) != 2 {
+		t.Errorf("Expected changes since the previous run, got %+v, %v", diff, err)
+	}
+	if _, err := svc.Changes(ctx, uuid.New(), second.ID, nil, owner); err == nil {
+		t.Error("Expected a run to be hidden under another segment")
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/google/uuid"
+)
+
+const tierRecalcBatchSize = 500
+
+type tierService struct {
+	repo         ports.TierRepository
+	engine       *tiering.Engine
+	auditService AuditService
+	now          func() time.Time
+
+	recalcMu   sync.Mutex
+	recalcLock ports.JobLock
+}
+
+// TierServiceOption configures optional tierService behaviour
+type TierServiceOption func(*tierService)
+
+// WithRecalcLock makes RecalculateAll hold lock, so only one run is in progress across every
+// instance sharing it.
+func WithRecalcLock(lock ports.JobLock) TierServiceOption {
+	return func(s *tierService) {
+		s.recalcLock = lock
+	}
+}
+
+func NewTierService(repo ports.TierRepository, engine *tiering.Engine, audit AuditService, opts ...TierServiceOption) *tierService {
+	s := &tierService{
+		repo:         repo,
+		engine:       engine,
+		auditService: audit,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *tierService) Assign(ctx context.Context, c, prev *domain.Customer, source domain.TierChangeSource) (*domain.TierChange, error) {
+	d, err := s.engine.Evaluate(c, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("tier rules %s: %w", d.Version, err)
+	}
+	if d.Tier != "" {
+		c.MembershipTier = d.Tier
+	}
+	c.IsHighValue = d.IsHighValue
+
+	change := &domain.TierChange{
+		CustomerID:   c.ID,
+		NewTier:      c.MembershipTier,
+		NewHighValue: c.IsHighValue,
+		RuleVersion:  d.Version,
+		Rule:         d.Rule,
+		Source:       source,
+	}
+	if prev != nil {
+		change.OldTier = prev.MembershipTier
+		change.OldHighValue = prev.IsHighValue
+	}
+	if change.OldTier == change.NewTier && change.OldHighValue == change.NewHighValue {
+		return nil, nil
+	}
+	return change, nil
+}
+
+func (s *tierService) RecordChange(ctx context.Context, change *domain.TierChange) error {
+	if err := s.repo.CreateHistory(ctx, change); err != nil {
+		return err
+	}
+	s.auditService.Log(ctx, change.CustomerID, "CUSTOMER", "TIER_CHANGE", "SYSTEM",
+		fmt.Sprintf("%s -> %s high_value=%t rules=%s source=%s",
+			change.OldTier, change.NewTier, change.NewHighValue, change.RuleVersion, change.Source), "")
+	return nil
+}
+
+func (s *tierService) Reevaluate(ctx context.Context, customerID uuid.UUID, source domain.TierChangeSource) (*domain.TierChange, error) {
+	c, err := s.repo.GetForRecalc(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	prev := *c
+	change, err := s.Assign(ctx, c, &prev, source)
+	if err != nil || change == nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTier(ctx, c.ID, c.MembershipTier, c.IsHighValue); err != nil {
+		return nil, err
+	}
+	if err := s.RecordChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// RecalculateAll re-derives every live customer's tier with the current rules.
+// Only one run may be in progress at a time.
+func (s *tierService) RecalculateAll(ctx context.Context) (*domain.TierRecalcResult, error) {
+	if !s.recalcMu.TryLock() {
+		return nil, fmt.Errorf("%w: tier recalculation already running", domain.ErrConflict)
+	}
+	defer s.recalcMu.Unlock()
+	if s.recalcLock != nil {
+		unlock, err := s.recalcLock.Lock(ctx, "tier recalculation")
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, fmt.Errorf("%w: tier recalculation already running", domain.ErrConflict)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	res := &domain.TierRecalcResult{RuleVersion: s.engine.Version(), StartedAt: s.now()}
+	after := uuid.Nil
+	for {
+		batch, err := s.repo.ListForRecalc(ctx, after, tierRecalcBatchSize)
+		if err != nil {
+			return res, err
+		}
+		for _, c := range batch {
+			prev := *c
+			change, err := s.Assign(ctx, c, &prev, domain.TierSourceRecalc)
+			if err != nil {
+				return res, fmt.Errorf("customer %s: %w", c.ID, err)
+			}
+			res.Scanned++
+			if change == nil {
+				continue
+			}
+			if err := s.repo.UpdateTier(ctx, c.ID, c.MembershipTier, c.IsHighValue); err != nil {
+				return res, err
+			}
+			if err := s.RecordChange(ctx, change); err != nil {
+				return res, err
+			}
+			res.Changed++
+		}
+		if len(batch) < tierRecalcBatchSize {
+			break
+		}
+		after = batch[len(batch)-1].ID
+	}
+
+	res.FinishedAt = s.now()
+	s.auditService.Log(ctx, uuid.Nil, "TIER_RULES", "RECALCULATE", "SYSTEM",
+		fmt.Sprintf("rules=%s scanned=%d changed=%d", res.RuleVersion, res.Scanned, res.Changed), "")
+	return res, nil
+}
+
+func (s *tierService) History(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.TierChange, error) {
+	return s.repo.ListHistory(ctx, customerID, limit, offset)
+}
+
+func (s *tierService) Rules() tiering.Config {
+	return s.engine.Config()
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockTierRepo struct {
+	customers []*domain.Customer
+	updated   map[uuid.UUID]string
+	history   []*domain.TierChange
+}
+
+func (m *mockTierRepo) ListForRecalc(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.Customer, error) {
+	var out []*domain.Customer
+	started := afterID == uuid.Nil
+	for _, c := range m.customers {
+		if started && len(out) < limit {
+			out = append(out, c)
+		}
+		if c.ID == afterID {
+			started = true
+		}
+	}
+	return out, nil
+}
+func (m *mockTierRepo) GetForRecalc(ctx context.Context, customerID uuid.UUID) (*domain.Customer, error) {
+	for _, c := range m.customers {
+		if c.ID == customerID {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("customer %w", domain.ErrNotFound)
+}
+func (m *mockTierRepo) UpdateTier(ctx context.Context, customerID uuid.UUID, tier string, isHighValue bool) error {
+	if m.updated == nil {
+		m.updated = map[uuid.UUID]string{}
+	}
+	m.updated[customerID] = tier
+	return nil
+}
+func (m *mockTierRepo) CreateHistory(ctx context.Context, c *domain.TierChange) error {
+	c.ID = uuid.New()
+	m.history = append(m.history, c)
+	return nil
+}
+func (m *mockTierRepo) ListHistory(ctx context.Context, customerID uuid.UUID, limit, offset int) ([]*domain.TierChange, error) {
+	return m.history, nil
+}
+
+func defaultTierService(t *testing.T, repo ports.TierRepository) *tierService {
+	t.Helper()
+	engine, err := tiering.Load("")
+	if err != nil {
+		t.Fatalf("Load default rules: %v", err)
+	}
+	return NewTierService(repo, engine, &mockAuditService{})
+}
+
+func TestCreateCustomer_DerivesTier(t *testing.T) {
+	repo := &mockTierRepo{}
+	tiers := defaultTierService(t, repo)
+	customers := &mockCustomerRepo{
+		createFunc: func(ctx context.Context, c *domain.Customer) error {
+			c.ID = uuid.New()
+			return nil
+		},
+	}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{}, WithTierRules(tiers))
+
+	c := &domain.Customer{Type: domain.TypePersonal, CLV: decimal.NewFromInt(2000000), MembershipTier: "PLATINUM", IsHighValue: false}
+	if err := svc.CreateCustomer(context.Background(), c, uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.MembershipTier != "GOLD" || !c.IsHighValue {
+		t.Errorf("Expected client-supplied tier to be replaced by GOLD/high-value, got %s/%v", c.MembershipTier, c.IsHighValue)
+	}
+	if len(repo.history) != 1 || repo.history[0].CustomerID != c.ID || repo.history[0].RuleVersion != tiers.engine.Version() {
+		t.Fatalf("Expected one history entry for the new customer with the rule version, got %+v", repo.history)
+	}
+}
+
+func TestUpdateCustomer_UnchangedTierNotRecorded(t *testing.T) {
+	repo := &mockTierRepo{}
+	tiers := defaultTierService(t, repo)
+	id := uuid.New()
+	customers := &mockCustomerRepo{
+		getByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Customer, error) {
+			return &domain.Customer{ID: id, MembershipTier: "STANDARD"}, nil
+		},
+		updateFunc: func(ctx context.Context, c *domain.Customer) error { return nil },
+	}
+	svc := NewCustomerService(customers, nil, nil, nil, nil, nil, &mockAuditService{}, WithTierRules(tiers))
+
+	if err := svc.UpdateCustomer(context.Background(), &domain.Customer{ID: id, CLV: decimal.NewFromInt(50)}, uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(repo.history) != 0 {
+		t.Errorf("Expected no history when tier is unchanged, got %d entries", len(repo.history))
+	}
+}
+
+func TestRecalculateAll(t *testing.T) {
+	repo := &mockTierRepo{}
+	for i := 0; i < tierRecalcBatchSize+2; i++ {
+		repo.customers = append(repo.customers, &domain.Customer{ID: uuid.New(), MembershipTier: "STANDARD"})
+	}
+	promoted := repo.customers[tierRecalcBatchSize+1]
+	promoted.CLV = decimal.NewFromInt(6000000)
+
+	res, err := defaultTierService(t, repo).RecalculateAll(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Scanned != len(repo.customers) || res.Changed != 1 {
+		t.Errorf("Expected %d scanned / 1 changed, got %d / %d", len(repo.customers), res.Scanned, res.Changed)
+	}
+	if repo.updated[promoted.ID] != "PLATINUM" {
+		t.Errorf("Expected promoted customer to be PLATINUM, got %q", repo.updated[promoted.ID])
+	}
+	if len(repo.history) != 1 || repo.history[0].Source != domain.TierSourceRecalc || repo.history[0].OldTier != "STANDARD" {
+		t.Errorf("Expected one RECALC history entry from STANDARD, got %+v", repo.history)
+	}
+}
+
+type heldJobLock struct{ jobs []string }
+
+func (l *heldJobLock) Lock(ctx context.Context, job string) (func(), error) {
+	l.jobs = append(l.jobs, job)
+	return nil, fmt.Errorf("%w: job:%s is locked", domain.ErrConflict, job)
+}
+
+func TestRecalculateAll_LockedElsewhere(t *testing.T) {
+	repo := &mockTierRepo{customers: []*domain.Customer{{ID: uuid.New(), CLV: decimal.NewFromInt(6000000)}}}
+	engine, err := tiering.Load("")
+	if err != nil {
+		t.Fatalf("Load default rules: %v", err)
+	}
+	lock := &heldJobLock{}
+	svc := NewTierService(repo, engine, &mockAuditService{}, WithRecalcLock(lock))
+
+	if _, err := svc.RecalculateAll(context.Background()); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Expected ErrConflict while another instance recalculates, got %v", err)
+	}
+	if len(lock.jobs) != 1 || len(repo.updated) != 0 {
+		t.Errorf("Expected one lock attempt and no updates, got %v / %v", lock.jobs, repo.updated)
+	}
+}
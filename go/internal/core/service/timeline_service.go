@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTimelineLimit = 50
+	maxTimelineLimit     = 200
+)
+
+// TimelineSource feeds one kind of event into the customer timeline. Implementations return
+// the newest entries matching q that sort after q.Cursor, at most q.Limit of them, newest first.
+type TimelineSource interface {
+	Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) ([]*domain.TimelineEntry, error)
+}
+
+type timelineService struct {
+	sources []TimelineSource
+}
+
+// NewTimelineService merges the given sources into one feed. Adding a kind of event to the
+// timeline means adding a source here.
+func NewTimelineService(sources ...TimelineSource) *timelineService {
+	return &timelineService{sources: sources}
+}
+
+// Timeline asks every source for up to Limit entries after the cursor, merges them newest first
+// and keeps the first Limit. Each source returning its own top Limit makes the merge exact.
+func (s *timelineService) Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) (*domain.TimelinePage, error) {
+	if q.Limit <= 0 {
+		q.Limit = defaultTimelineLimit
+	}
+	if q.Limit > maxTimelineLimit {
+		q.Limit = maxTimelineLimit
+	}
+
+	results := make([][]*domain.TimelineEntry, len(s.sources))
+	errs := make([]error, len(s.sources))
+	var wg sync.WaitGroup
+	for i, src := range s.sources {
+		wg.Add(1)
+		go func(i int, src TimelineSource) {
+			defer wg.Done()
+			results[i], errs[i] = src.Timeline(ctx, customerID, q)
+		}(i, src)
+	}
+	wg.Wait()
+
+	var entries []*domain.TimelineEntry
+	for i := range s.sources {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, e := range results[i] {
+			if e.Before(q.Cursor) && q.Matches(e) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	sortTimeline(entries)
+
+	page := &domain.TimelinePage{Entries: entries}
+	if len(entries) > q.Limit {
+		page.Entries = entries[:q.Limit]
+		last := page.Entries[q.Limit-1]
+		page.NextCursor = domain.TimelineCursor{At: last.OccurredAt, ID: last.ID}.Encode()
+	}
+	if page.Entries == nil {
+		page.Entries = []*domain.TimelineEntry{}
+	}
+	return page, nil
+}
+
+// sortTimeline orders entries newest first, breaking ties by descending ID to match TimelineEntry.Before.
+func sortTimeline(entries []*domain.TimelineEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].OccurredAt.Equal(entries[j].OccurredAt) {
+			return entries[i].OccurredAt.After(entries[j].OccurredAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+}
+
+// topTimeline filters, sorts and trims entries a source built in memory.
+func topTimeline(entries []*domain.TimelineEntry, q domain.TimelineQuery) []*domain.TimelineEntry {
+	var out []*domain.TimelineEntry
+	for _, e := range entries {
+		if e.Before(q.Cursor) && q.Matches(e) {
+			out = append(out, e)
+		}
+	}
+	sortTimeline(out)
+	// One extra so the service can tell whether another page exists.
+	if len(out) > q.Limit+1 {
+		out = out[:q.Limit+1]
+	}
+	return out
+}
+
+// timelineFilled reports whether a paged source holds its top Limit+1 entries: it has more
+// than that many and has read past the timestamp of the one that would end the page.
+func timelineFilled(out []*domain.TimelineEntry, limit int, oldest time.Time) bool {
+	if len(out) <= limit {
+		return false
+	}
+	sortTimeline(out)
+	return oldest.Before(out[limit].OccurredAt)
+}
+
+func wantsType(q domain.TimelineQuery, types ...string) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, want := range q.Types {
+		for _, t := range types {
+			if want == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func customerLink(customerID uuid.UUID, sub string) string {
+	link := "/api/v1/customers/" + customerID.String()
+	if sub != "" {
+		link += "/" + sub
+	}
+	return link
+}
+
+// --- Audit ---
+
+// AuditTimelineLister reads one entity's audit trail page by page, newest first.
+type AuditTimelineLister interface {
+	ListForEntity(ctx context.Context, entityID uuid.UUID, from, to, before *time.Time, limit int) ([]*repository.AuditLog, error)
+}
+
+type auditTimelineSource struct {
+	audit AuditTimelineLister
+}
+
+// NewAuditTimelineSource turns the customer's audit trail into timeline entries: lifecycle events,
+// status changes, sub-resource removals, contact point changes, tier and points movements and protected access.
+// Sub-resource additions are left to the sub-resource source, which also covers rows older than the audit.
+func NewAuditTimelineSource(audit AuditTimelineLister) *auditTimelineSource {
+	return &auditTimelineSource{audit: audit}
+}
+
+func (s *auditTimelineSource) Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) ([]*domain.TimelineEntry, error) {
+	var before *time.Time
+	if q.Cursor != nil {
+		at := q.Cursor.At
+		before = &at
+	}
+	batch := q.Limit + 1
+
+	var out []*domain.TimelineEntry
+	seen := map[string]bool{}
+	for {
+		logs, err := s.audit.ListForEntity(ctx, customerID, q.From, q.To, before, batch)
+		if err != nil {
+			return nil, err
+		}
+		fresh := 0
+		for _, l := range logs {
+			id := "audit:" + l.ID.String()
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			fresh++
+			e := auditTimelineEntry(customerID, l)
+			if e != nil && e.Before(q.Cursor) && q.Matches(e) {
+				out = append(out, e)
+			}
+		}
+		// A short page is the end of the trail. Otherwise stop once every row at the page
+		// boundary's timestamp has been read, since ties are ordered by ID and not by row order.
+		if len(logs) < batch {
+			break
+		}
+		last := logs[len(logs)-1].Timestamp
+		if timelineFilled(out, q.Limit, last) {
+			break
+		}
+		// A full page with nothing new is all one timestamp, already seen: read more of it.
+		if fresh == 0 {
+			batch *= 2
+			continue
+		}
+		before = &last
+	}
+	return topTimeline(out, q), nil
+}
+
+// auditTimelineEntry maps an audit row to a timeline entry, or nil for rows the timeline skips.
+func auditTimelineEntry(customerID uuid.UUID, l *repository.AuditLog) *domain.TimelineEntry {
+	e := &domain.TimelineEntry{
+		ID:         "audit:" + l.ID.String(),
+		Action:     l.Action,
+		OccurredAt: l.Timestamp,
+		Actor:      l.PerformedBy,
+		Summary:    l.Changes,
+		Link:       "/api/v1/audit-logs/" + l.ID.String(),
+	}
+
+	switch {
+	case l.Action == "STATUS_CHANGE":
+		e.Type = domain.TimelineStatus
+		e.Summary = "Status changed " + l.Changes
+	case l.Action == "ADDRESS_ADD" || l.Action == "IDENTITY_ADD" || l.Action == "RELATIONSHIP_ADD":
+		return nil
+	case strings.HasPrefix(l.Action, "ADDRESS_"):
+		e.Type = domain.TimelineAddress
+		e.Summary = "Address removed: " + l.Changes
+	case strings.HasPrefix(l.Action, "IDENTITY_"):
+		e.Type = domain.TimelineIdentity
+		e.Summary = "Identity removed: " + l.Changes
+	case strings.HasPrefix(l.Action, "RELATIONSHIP_"):
+		e.Type = domain.TimelineRelationship
+		e.Summary = "Relationship removed: " + l.Changes
+	case strings.HasPrefix(l.Action, "CONTACT_"):
+		e.Type = domain.TimelineContact
+		e.Link = customerLink(customerID, "contact-points")
+	case strings.HasPrefix(l.Action, "BANK_ACCOUNT_"):
+		e.Type = domain.TimelineBankAccount
+		e.Link = customerLink(customerID, "bank-accounts")
+	case strings.HasPrefix(l.Action, "HOLDING_"):
+		e.Type = domain.TimelineHolding
+		e.Link = customerLink(customerID, "holdings")
+	case l.Action == "TIER_CHANGE":
+		e.Type = domain.TimelineTier
+		e.Link = customerLink(customerID, "tier-history")
+	case strings.HasPrefix(l.Action, "POINTS_"):
+		e.Type = domain.TimelinePoints
+		e.Link = customerLink(customerID, "points/transactions")
+	case l.Action == "PROTECTED_READ" || strings.HasPrefix(l.Action, "ACCESS_GRANT_"):
+		e.Type = domain.TimelineAccess
+	default:
+		e.Type = domain.TimelineCustomer
+	}
+	if e.Summary == "" {
+		e.Summary = l.Action
+	}
+	return e
+}
+
+// --- Consents ---
+
+type consentTimelineSource struct {
+	consents ports.ConsentRepository
+}
+
+// NewConsentTimelineSource reports each consent record as a grant or a withdrawal.
+func NewConsentTimelineSource(consents ports.ConsentRepository) *consentTimelineSource {
+	return &consentTimelineSource{consents: consents}
+}
+
+func (s *consentTimelineSource) Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) ([]*domain.TimelineEntry, error) {
+	if !wantsType(q, domain.TimelineConsent) {
+		return nil, nil
+	}
+	consents, err := s.consents.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.TimelineEntry, 0, len(consents))
+	for _, c := range consents {
+		action, verb := "CONSENT_WITHDRAWN", "withdrawn"
+		if c.IsGranted {
+			action, verb = "CONSENT_GRANTED", "granted"
+		}
+		at := c.Timestamp
+		if at.IsZero() {
+			at = c.CreatedAt
+		}
+		entries = append(entries, &domain.TimelineEntry{
+			ID:         "consent:" + c.ID.String(),
+			Type:       domain.TimelineConsent,
+			Action:     action,
+			OccurredAt: at,
+			Actor:      actorOf(c.CreatedBy),
+			Summary:    fmt.Sprintf("Consent %s v%s %s", c.Topic, c.Version, verb),
+			Link:       "/api/v1/consents/" + c.ID.String(),
+		})
+	}
+	return topTimeline(entries, q), nil
+}
+
+// --- Sub-resources ---
+
+type subResourceTimelineSource struct {
+	addresses     ports.AddressRepository
+	identities    ports.IdentityRepository
+	relationships ports.RelationshipRepository
+}
+
+// NewSubResourceTimelineSource reports when each current address, identity and relationship was added.
+func NewSubResourceTimelineSource(addresses ports.AddressRepository, identities ports.IdentityRepository, relationships ports.RelationshipRepository) *subResourceTimelineSource {
+	return &subResourceTimelineSource{addresses: addresses, identities: identities, relationships: relationships}
+}
+
+func (s *subResourceTimelineSource) Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) ([]*domain.TimelineEntry, error) {
+	var entries []*domain.TimelineEntry
+
+	if wantsType(q, domain.TimelineAddress) {
+		addresses, err := s.addresses.ListByCustomerID(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addresses {
+			entries = append(entries, &domain.TimelineEntry{
+				ID:         "address:" + a.ID.String(),
+				Type:       domain.TimelineAddress,
+				Action:     "ADDRESS_ADD",
+				OccurredAt: a.CreatedAt,
+				Actor:      actorOf(a.CreatedBy),
+				Summary:    fmt.Sprintf("%s address added: %s, %s", a.Type, a.City, a.Country),
+				Link:       customerLink(customerID, "addresses"),
+			})
+		}
+	}
+
+	if wantsType(q, domain.TimelineIdentity) {
+		identities, err := s.identities.ListByCustomerID(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range identities {
+			entries = append(entries, &domain.TimelineEntry{
+				ID:         "identity:" + i.ID.String(),
+				Type:       domain.TimelineIdentity,
+				Action:     "IDENTITY_ADD",
+				OccurredAt: i.CreatedAt,
+				Actor:      actorOf(i.CreatedBy),
+				Summary:    fmt.Sprintf("%s added ending %s", i.Type, lastDigits(i.Number, 4)),
+				Link:       customerLink(customerID, "identities"),
+			})
+		}
+	}
+
+	if wantsType(q, domain.TimelineRelationship) {
+		rels, err := s.relationships.ListByCustomerID(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rels {
+			entries = append(entries, &domain.TimelineEntry{
+				ID:         "relationship:" + r.ID.String(),
+				Type:       domain.TimelineRelationship,
+				Action:     "RELATIONSHIP_ADD",
+				OccurredAt: r.CreatedAt,
+				Actor:      actorOf(r.CreatedBy),
+				Summary:    fmt.Sprintf("%s relationship added with %s", r.Role, otherEnd(r, customerID)),
+				Link:       customerLink(customerID, "relationships"),
+			})
+		}
+	}
+	return topTimeline(entries, q), nil
+}
+
+// actorOf names who added a record. Records from before the adder was kept were audited as SYSTEM.
+func actorOf(createdBy *uuid.UUID) string {
+	if createdBy == nil {
+		return "SYSTEM"
+	}
+	return createdBy.String()
+}
+
+// lastDigits keeps only the tail of an identity number so the feed never shows it in full.
+func lastDigits(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
+
+// --- Interactions ---
+
+// InteractionTimelineLister reads a customer's interactions page by page, newest first.
+type InteractionTimelineLister interface {
+	ListForTimeline(ctx context.Context, customerID uuid.UUID, from, to, before *time.Time, limit int) ([]*domain.Interaction, error)
+}
+
+type interactionTimelineSource struct {
+	interactions InteractionTimelineLister
+}
+
+// NewInteractionTimelineSource reports calls, emails, visits and notes at the time they happened.
+func NewInteractionTimelineSource(interactions InteractionTimelineLister) *interactionTimelineSource {
+	return &interactionTimelineSource{interactions: interactions}
+}
+
+func (s *interactionTimelineSource) Timeline(ctx context.Context, customerID uuid.UUID, q domain.TimelineQuery) ([]*domain.TimelineEntry, error) {
+	if !wantsType(q, domain.TimelineInteraction) {
+		return nil, nil
+	}
+	var before *time.Time
+	if q.Cursor != nil {
+		at := q.Cursor.At
+		before = &at
+	}
+	batch := q.Limit + 1
+
+	// Paged like the audit source: rows at a tied timestamp come back again and are skipped.
+	var out []*domain.TimelineEntry
+	seen := map[string]bool{}
+	for {
+		interactions, err := s.interactions.ListForTimeline(ctx, customerID, q.From, q.To, before, batch)
+		if err != nil {
+			return nil, err
+		}
+		fresh := 0
+		for _, i := range interactions {
+			e := interactionTimelineEntry(customerID, i)
+			if seen[e.ID] {
+				continue
+			}
+			seen[e.ID] = true
+			fresh++
+			if e.Before(q.Cursor) && q.Matches(e) {
+				out = append(out, e)
+			}
+		}
+		if len(interactions) < batch {
+			break
+		}
+		last := interactions[len(interactions)-1].OccurredAt
+		if timelineFilled(out, q.Limit, last) {
+			break
+		}
+		if fresh == 0 {
+			batch *= 2
+			continue
+		}
+		before = &last
+	}
+	return topTimeline(out, q), nil
+}
+
+func interactionTimelineEntry(customerID uuid.UUID, i *domain.Interaction) *domain.TimelineEntry {
+	summary := fmt.Sprintf("%s %s: %s", i.Channel, strings.ToLower(string(i.Direction)), i.Subject)
+	if i.Channel == domain.ChannelNote {
+		summary = "Note: " + i.Subject
+	}
+	return &domain.TimelineEntry{
+		ID:         "interaction:" + i.ID.String(),
+		Type:       domain.TimelineInteraction,
+		Action:     string(i.Channel),
+		OccurredAt: i.OccurredAt,
+		Actor:      i.StaffUserID.String(),
+		Summary:    summary,
+		Link:       customerLink(customerID, "interactions/"+i.ID.String()),
+	}
+}
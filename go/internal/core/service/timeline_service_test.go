@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/adapter/repository"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// stubTimelineAudit serves ListForEntity from memory; logs must be newest first.
+type stubTimelineAudit struct {
+	logs []*repository.AuditLog
+}
+
+func (s *stubTimelineAudit) ListForEntity(ctx context.Context, entityID uuid.UUID, from, to, before *time.Time, limit int) ([]*repository.AuditLog, error) {
+	var out []*repository.AuditLog
+	for _, l := range s.logs {
+		if l.EntityID != entityID || (before != nil && l.Timestamp.After(*before)) {
+			continue
+		}
+		if (from != nil && l.Timestamp.Before(*from)) || (to != nil && !l.Timestamp.Before(*to)) {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, l)
+	}
+	return out, nil
+}
+
+type stubConsents struct {
+	mockConsentRepo
+	consents []*domain.Consent
+}
+
+func (s *stubConsents) ListByCustomerID(ctx context.Context, id uuid.UUID) ([]*domain.Consent, error) {
+	return s.consents, nil
+}
+
+func TestTimeline_MergesAndPaginates(t *testing.T) {
+	id := uuid.New()
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	audit := &stubTimelineAudit{logs: []*repository.AuditLog{
+		{ID: uuid.New(), EntityID: id, Action: "STATUS_CHANGE", Changes: "ACTIVE -> SUSPENDED", PerformedBy: "SYSTEM", Timestamp: base.Add(4 * time.Hour)},
+		{ID: uuid.New(), EntityID: id, Action: "ADDRESS_ADD", Timestamp: base.Add(3 * time.Hour)},
+		{ID: uuid.New(), EntityID: id, Action: "UPDATE", Timestamp: base.Add(2 * time.Hour)},
+		{ID: uuid.New(), EntityID: id, Action: "CREATE", Timestamp: base},
+	}}
+	consents := &stubConsents{consents: []*domain.Consent{
+		{ID: uuid.New(), Topic: "MARKETING", Version: "1", IsGranted: true, Timestamp: base.Add(time.Hour)},
+		{ID: uuid.New(), Topic: "MARKETING", Version: "1", IsGranted: false, Timestamp: base.Add(3 * time.Hour)},
+	}}
+	svc := NewTimelineService(NewAuditTimelineSource(audit), NewConsentTimelineSource(consents))
+
+	var actions []string
+	q := domain.TimelineQuery{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Pagination did not terminate")
+		}
+		page, err := svc.Timeline(context.Background(), id, q)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, e := range page.Entries {
+			actions = append(actions, e.Action)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if q.Cursor, err = domain.DecodeTimelineCursor(page.NextCursor); err != nil {
+			t.Fatalf("Bad cursor: %v", err)
+		}
+	}
+
+	want := []string{"STATUS_CHANGE", "CONSENT_WITHDRAWN", "UPDATE", "CONSENT_GRANTED", "CREATE"}
+	if len(actions) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, actions)
+		}
+	}
+}
+
+func TestTimeline_Filters(t *testing.T) {
+	id := uuid.New()
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	audit := &stubTimelineAudit{logs: []*repository.AuditLog{
+		{ID: uuid.New(), EntityID: id, Action: "STATUS_CHANGE", Changes: "ACTIVE -> SUSPENDED", Timestamp: base.Add(48 * time.Hour)},
+		{ID: uuid.New(), EntityID: id, Action: "POINTS_EARN", Timestamp: base.Add(24 * time.Hour)},
+		{ID: uuid.New(), EntityID: id, Action: "STATUS_CHANGE", Changes: "INACTIVE -> ACTIVE", Timestamp: base},
+	}}
+	svc := NewTimelineService(NewAuditTimelineSource(audit), NewConsentTimelineSource(&stubConsents{}))
+
+	to := base.Add(36 * time.Hour)
+	page, err := svc.Timeline(context.Background(), id, domain.TimelineQuery{Types: []string{domain.TimelineStatus}, To: &to})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Summary != "Status changed INACTIVE -> ACTIVE" {
+		t.Errorf("Expected only the first status change, got %+v", page.Entries)
+	}
+}
+
+// stubTimelineInteractions serves ListForTimeline from memory; interactions must be newest first.
+type stubTimelineInteractions struct {
+	interactions []*domain.Interaction
+}
+
+func (s *stubTimelineInteractions) ListForTimeline(ctx context.Context, customerID uuid.UUID, from, to, before *time.Time, limit int) ([]*domain.Interaction, error) {
+	var out []*domain.Interaction
+	for _, i := range s.interactions {
+		if before != nil && i.OccurredAt.After(*before) {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
+func TestTimeline_PagesPastTimestampTies(t *testing.T) {
+	id := uuid.New()
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	stub := &stubTimelineInteractions{}
+	for n := 0; n < 15; n++ {
+		stub.interactions = append(stub.interactions, &domain.Interaction{
+			ID: uuid.New(), CustomerID: id, StaffUserID: uuid.New(), Channel: domain.ChannelNote, OccurredAt: at,
+		})
+	}
+	svc := NewTimelineService(NewInteractionTimelineSource(stub))
+
+	seen := map[string]bool{}
+	q := domain.TimelineQuery{Limit: 5}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Pagination did not terminate")
+		}
+		page, err := svc.Timeline(context.Background(), id, q)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, e := range page.Entries {
+			if seen[e.ID] {
+				t.Fatalf("Entry %s returned twice", e.ID)
+			}
+			seen[e.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if q.Cursor, err = domain.DecodeTimelineCursor(page.NextCursor); err != nil {
+			t.Fatalf("Bad cursor: %v", err)
+		}
+	}
+	if len(seen) != 15 {
+		t.Errorf("Expected all 15 interactions, got %d", len(seen))
+	}
+}
+
+func TestTimeline_ConsentActor(t *testing.T) {
+	id, staff := uuid.New(), uuid.New()
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	consents := &stubConsents{consents: []*domain.Consent{
+		{ID: uuid.New(), Topic: "MARKETING", Version: "2", IsGranted: true, Timestamp: base.Add(time.Hour), CreatedBy: &staff},
+		{ID: uuid.New(), Topic: "MARKETING", Version: "1", IsGranted: true, Timestamp: base},
+	}}
+	svc := NewTimelineService(NewConsentTimelineSource(consents))
+
+	page, err := svc.Timeline(context.Background(), id, domain.TimelineQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Entries) != 2 || page.Entries[0].Actor != staff.String() || page.Entries[1].Actor != "SYSTEM" {
+		t.Errorf("Expected the recording user, then SYSTEM for a legacy row, got %+v", page.Entries)
+	}
+}
@@ -0,0 +1,142 @@
+// Package tiering derives membership tier and the high-value flag from customer metrics
+// using rules loaded from a versioned JSON config.
+package tiering
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+//go:embed default_rules.json
+var defaultRules []byte
+
+// TierRule assigns Name to customers matching When. Rules are evaluated in order; first match wins.
+type TierRule struct {
+	Name string `json:"name"`
+	When string `json:"when"`
+}
+
+// Config is the versioned rule set.
+type Config struct {
+	Version    string                     `json:"version"`
+	Thresholds map[string]decimal.Decimal `json:"thresholds"`
+	Tiers      []TierRule                 `json:"tiers"`
+	HighValue  string                     `json:"high_value"`
+}
+
+// Decision is the outcome of evaluating the rules for one customer.
+type Decision struct {
+	Tier        string
+	Rule        string
+	IsHighValue bool
+	Version     string
+}
+
+type Engine struct {
+	cfg       Config
+	tiers     []Expr
+	highValue Expr
+}
+
+// New compiles every expression in cfg up front so a bad config fails at startup, including
+// one naming a fact CustomerFacts does not provide or a threshold cfg does not define.
+func New(cfg Config) (*Engine, error) {
+	if cfg.Version == "" {
+		return nil, errors.New("tier rules: version is required")
+	}
+	if len(cfg.Tiers) == 0 {
+		return nil, errors.New("tier rules: at least one tier is required")
+	}
+
+	known := CustomerFacts(&domain.Customer{}, time.Time{})
+	e := &Engine{cfg: cfg}
+	for _, t := range cfg.Tiers {
+		expr, err := compile(t.When, cfg.Thresholds, known)
+		if err != nil {
+			return nil, fmt.Errorf("tier rules: tier %s: %w", t.Name, err)
+		}
+		e.tiers = append(e.tiers, expr)
+	}
+
+	hv := cfg.HighValue
+	if hv == "" {
+		hv = "false"
+	}
+	expr, err := compile(hv, cfg.Thresholds, known)
+	if err != nil {
+		return nil, fmt.Errorf("tier rules: high_value: %w", err)
+	}
+	e.highValue = expr
+	return e, nil
+}
+
+// Load reads the rule set from path, or the built-in default when path is empty.
+func Load(path string) (*Engine, error) {
+	raw := defaultRules
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("tier rules: %w", err)
+		}
+		raw = b
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("tier rules: %w", err)
+	}
+	return New(cfg)
+}
+
+func (e *Engine) Version() string { return e.cfg.Version }
+func (e *Engine) Config() Config  { return e.cfg }
+
+// Evaluate derives the tier and high-value flag for c. Customers matching no rule keep an empty tier.
+func (e *Engine) Evaluate(c *domain.Customer, now time.Time) (Decision, error) {
+	facts := CustomerFacts(c, now)
+	d := Decision{Version: e.cfg.Version}
+
+	for i, expr := range e.tiers {
+		ok, err := expr.Eval(facts)
+		if err != nil {
+			return d, fmt.Errorf("tier %s: %w", e.cfg.Tiers[i].Name, err)
+		}
+		if ok {
+			d.Tier = e.cfg.Tiers[i].Name
+			d.Rule = e.cfg.Tiers[i].When
+			break
+		}
+	}
+
+	hv, err := e.highValue.Eval(facts)
+	if err != nil {
+		return d, fmt.Errorf("high_value: %w", err)
+	}
+	d.IsHighValue = hv
+	return d, nil
+}
+
+// CustomerFacts exposes the customer attributes rules may refer to.
+// days_since_last_transaction is -1 when the customer has never transacted.
+func CustomerFacts(c *domain.Customer, now time.Time) Facts {
+	days := int64(-1)
+	if c.LastTransactionDate != nil {
+		days = int64(now.Sub(*c.LastTransactionDate).Hours() / 24)
+	}
+	return Facts{
+		"clv":                         Number(c.CLV),
+		"points_balance":              Number(c.PointsBalance),
+		"portfolio_size":              Number(c.PortfolioSize),
+		"days_since_last_transaction": Number(decimal.NewFromInt(days)),
+		"type":                        String(string(c.Type)),
+		"status":                      String(string(c.Status)),
+		"nationality":                 String(c.Nationality),
+	}
+}
@@ -0,0 +1,103 @@
+package tiering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestCompile(t *testing.T) {
+	facts := Facts{
+		"clv":  Number(decimal.NewFromInt(1500000)),
+		"type": String("JURISTIC"),
+	}
+	constants := map[string]decimal.Decimal{"gold_clv": decimal.NewFromInt(1000000)}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"clv >= gold_clv", true},
+		{"clv < 1_000_000", false},
+		{`type == "JURISTIC" AND clv > 0`, true},
+		{`NOT (type == "PERSONAL") AND (clv < 0 OR clv != 1)`, true},
+		{"false OR clv == 1500000", true},
+		{"true", true},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.expr, constants)
+		if err != nil {
+			t.Fatalf("Compile(%q) error: %v", tt.expr, err)
+		}
+		got, err := e.Eval(facts)
+		if err != nil {
+			t.Fatalf("Eval(%q) error: %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expr := range []string{"clv >=", "clv = 1", "(clv > 1", "clv", `type > "A"`} {
+		e, err := Compile(expr, nil)
+		if err != nil {
+			continue
+		}
+		if _, err := e.Eval(Facts{"clv": Number(decimal.NewFromInt(1)), "type": String("B")}); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	e, err := Load("")
+	if err != nil {
+		t.Fatalf("Load default rules: %v", err)
+	}
+
+	tests := []struct {
+		c         domain.Customer
+		tier      string
+		highValue bool
+	}{
+		{domain.Customer{CLV: decimal.NewFromInt(6000000)}, "PLATINUM", true},
+		{domain.Customer{CLV: decimal.RequireFromString("1000000.00")}, "GOLD", true},
+		{domain.Customer{CLV: decimal.RequireFromString("999999.99")}, "SILVER", false},
+		{domain.Customer{PointsBalance: decimal.NewFromInt(20000)}, "SILVER", false},
+		{domain.Customer{}, "STANDARD", false},
+	}
+	for _, tt := range tests {
+		d, err := e.Evaluate(&tt.c, time.Now())
+		if err != nil {
+			t.Fatalf("Evaluate error: %v", err)
+		}
+		if d.Tier != tt.tier || d.IsHighValue != tt.highValue {
+			t.Errorf("Evaluate(%+v) = %s/%v, want %s/%v", tt.c, d.Tier, d.IsHighValue, tt.tier, tt.highValue)
+		}
+		if d.Version != e.Version() {
+			t.Errorf("Expected decision to carry rule version %s", e.Version())
+		}
+	}
+}
+
+func TestNew_RejectsUnknownIdentifiers(t *testing.T) {
+	thresholds := map[string]decimal.Decimal{"gold_clv": decimal.NewFromInt(1000000)}
+	for _, cfg := range []Config{
+		{Version: "t", Thresholds: thresholds, Tiers: []TierRule{{Name: "GOLD", When: "cvl >= gold_clv"}}},
+		{Version: "t", Thresholds: thresholds, Tiers: []TierRule{{Name: "GOLD", When: "clv >= gld_clv"}}},
+		{Version: "t", Thresholds: thresholds, Tiers: []TierRule{{Name: "STANDARD", When: "true"}}, HighValue: "portfolio > 0"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected New to reject %+v", cfg)
+		}
+	}
+
+	cfg := Config{Version: "t", Thresholds: thresholds, Tiers: []TierRule{{Name: "GOLD", When: `clv >= gold_clv AND status != "BLACKLISTED"`}}}
+	if _, err := New(cfg); err != nil {
+		t.Errorf("Expected known facts and thresholds to compile, got %v", err)
+	}
+}
@@ -0,0 +1,363 @@
+package tiering
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Value is a fact or literal in a rule expression: either an exact decimal number or a string.
+type Value struct {
+	Num   decimal.Decimal
+	Str   string
+	IsStr bool
+}
+
+func Number(n decimal.Decimal) Value { return Value{Num: n} }
+func String(s string) Value          { return Value{Str: s, IsStr: true} }
+
+// Facts are the named values an expression is evaluated against.
+type Facts map[string]Value
+
+// Expr is a compiled rule expression.
+type Expr interface {
+	Eval(f Facts) (bool, error)
+}
+
+// Compile parses an expression such as
+//
+//	clv >= gold_clv AND (portfolio_size > 0 OR type == "JURISTIC")
+//
+// Identifiers resolve first against constants (config thresholds), then against facts
+// at evaluation time. Supported operators: AND, OR, NOT, ==, !=, <, <=, >, >=, parentheses.
+func Compile(src string, constants map[string]decimal.Decimal) (Expr, error) {
+	return compile(src, constants, nil)
+}
+
+// compile is Compile that, when known is not nil, also rejects identifiers that are neither
+// constants nor facts in known.
+func compile(src string, constants map[string]decimal.Decimal, known Facts) (Expr, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, constants: constants, known: known}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q at end of expression", p.toks[p.pos].text)
+	}
+	return e, nil
+}
+
+// --- Lexer ---
+
+type tokKind int
+
+const (
+	tokIdent tokKind = iota
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	text string
+}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			toks = append(toks, token{tokString, src[i+1 : i+1+end]})
+			i += end + 2
+		case strings.ContainsRune("<>=!", c):
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("invalid operator %q at %d", op, i)
+			}
+			toks = append(toks, token{tokOp, op})
+			i += len(op)
+		case unicode.IsDigit(c) || c == '.' || c == '-':
+			j := i + 1
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.' || src[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, strings.ReplaceAll(src[i:j], "_", "")})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_') {
+				j++
+			}
+			word := src[i:j]
+			switch strings.ToUpper(word) {
+			case "AND", "OR", "NOT":
+				toks = append(toks, token{tokOp, strings.ToUpper(word)})
+			default:
+				toks = append(toks, token{tokIdent, word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+	return toks, nil
+}
+
+// --- Parser ---
+
+type parser struct {
+	toks      []token
+	pos       int
+	constants map[string]decimal.Decimal
+	known     Facts
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.toks) {
+		return &p.toks[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) acceptOp(op string) bool {
+	if t := p.peek(); t != nil && t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.acceptOp("NOT") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	if t := p.peek(); t != nil && t.kind == tokLParen {
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t == nil || t.kind != tokOp || t.text == "AND" || t.text == "OR" || t.text == "NOT" {
+		// A bare operand is only valid for the boolean literals.
+		if lit, ok := left.(literal); ok && lit.boolean {
+			return boolExpr(lit.v.Num.Equal(decimal.NewFromInt(1))), nil
+		}
+		return nil, fmt.Errorf("expected comparison operator")
+	}
+	p.pos++
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return compareExpr{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	switch t.kind {
+	case tokNumber:
+		n, err := decimal.NewFromString(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literal{v: Number(n)}, nil
+	case tokString:
+		return literal{v: String(t.text)}, nil
+	case tokIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return literal{v: Number(decimal.NewFromInt(1)), boolean: true}, nil
+		case "false":
+			return literal{v: Number(decimal.Zero), boolean: true}, nil
+		}
+		if n, ok := p.constants[t.text]; ok {
+			return literal{v: Number(n)}, nil
+		}
+		if _, ok := p.known[t.text]; p.known != nil && !ok {
+			return nil, fmt.Errorf("unknown fact or threshold %q", t.text)
+		}
+		return fact(t.text), nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// --- AST ---
+
+type operand interface {
+	value(f Facts) (Value, error)
+}
+
+type literal struct {
+	v       Value
+	boolean bool
+}
+
+func (l literal) value(Facts) (Value, error) { return l.v, nil }
+
+type fact string
+
+func (n fact) value(f Facts) (Value, error) {
+	v, ok := f[string(n)]
+	if !ok {
+		return Value{}, fmt.Errorf("unknown fact %q", string(n))
+	}
+	return v, nil
+}
+
+type boolExpr bool
+
+func (b boolExpr) Eval(Facts) (bool, error) { return bool(b), nil }
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Eval(f Facts) (bool, error) {
+	l, err := e.left.Eval(f)
+	if err != nil || !l {
+		return false, err
+	}
+	return e.right.Eval(f)
+}
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Eval(f Facts) (bool, error) {
+	l, err := e.left.Eval(f)
+	if err != nil || l {
+		return l, err
+	}
+	return e.right.Eval(f)
+}
+
+type notExpr struct{ e Expr }
+
+func (e notExpr) Eval(f Facts) (bool, error) {
+	v, err := e.e.Eval(f)
+	return !v, err
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e compareExpr) Eval(f Facts) (bool, error) {
+	l, err := e.left.value(f)
+	if err != nil {
+		return false, err
+	}
+	r, err := e.right.value(f)
+	if err != nil {
+		return false, err
+	}
+
+	if l.IsStr || r.IsStr {
+		if l.IsStr != r.IsStr {
+			return false, fmt.Errorf("cannot compare string with number")
+		}
+		switch e.op {
+		case "==":
+			return l.Str == r.Str, nil
+		case "!=":
+			return l.Str != r.Str, nil
+		}
+		return false, fmt.Errorf("operator %s not supported for strings", e.op)
+	}
+
+	cmp := l.Num.Cmp(r.Num)
+	switch e.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %s", e.op)
+}
@@ -22,7 +22,12 @@ func NewDB() (*sql.DB, error) {
 		)
 	}
 
-	db, err := sql.Open("postgres", dbURL)
+	return Open(dbURL)
+}
+
+// Open connects to the Postgres database at url and checks it is reachable.
+func Open(url string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
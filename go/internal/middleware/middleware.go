@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -41,6 +42,7 @@ func JWTAuth(next http.Handler) http.Handler {
 
 // APIKeyAuth middleware validates API keys
 func APIKeyAuth(db *sql.DB) func(http.Handler) http.Handler {
+	lookup := APIKeyLookup(db)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			apiKey := r.Header.Get("X-API-Key")
@@ -49,18 +51,8 @@ func APIKeyAuth(db *sql.DB) func(http.Handler) http.Handler {
 				return
 			}
 
-			keyHash := auth.HashAPIKey(apiKey)
-
-			var userID string
-			var isActive bool
-			query := `
-				SELECT user_id, is_active 
-				FROM api_keys 
-				WHERE key_hash = $1 
-				AND (expires_at IS NULL OR expires_at > NOW())
-			`
-			err := db.QueryRow(query, keyHash).Scan(&userID, &isActive)
-			if err != nil || !isActive {
+			userID, err := lookup(r.Context(), apiKey)
+			if err != nil {
 				http.Error(w, "Invalid API key", http.StatusUnauthorized)
 				return
 			}
@@ -71,6 +63,30 @@ func APIKeyAuth(db *sql.DB) func(http.Handler) http.Handler {
 	}
 }
 
+// APIKeyLookup returns a function that resolves an API key to the ID of the user who owns it.
+// It fails for unknown, inactive and expired keys.
+func APIKeyLookup(db *sql.DB) func(ctx context.Context, apiKey string) (string, error) {
+	return func(ctx context.Context, apiKey string) (string, error) {
+		keyHash := auth.HashAPIKey(apiKey)
+
+		var userID string
+		var isActive bool
+		query := `
+			SELECT user_id, is_active 
+			FROM api_keys 
+			WHERE key_hash = $1 
+			AND (expires_at IS NULL OR expires_at > NOW())
+		`
+		if err := db.QueryRowContext(ctx, query, keyHash).Scan(&userID, &isActive); err != nil {
+			return "", err
+		}
+		if !isActive {
+			return "", errors.New("api key is inactive")
+		}
+		return userID, nil
+	}
+}
+
 // CORSMiddleware adds CORS headers
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
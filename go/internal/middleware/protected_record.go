@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CustomerAccessChecker decides whether a user may read a given customer.
+type CustomerAccessChecker interface {
+	CheckAccess(ctx context.Context, customerID, userID uuid.UUID, role string) error
+}
+
+// RequireCustomerAccess returns middleware that guards reads of protected (high-value) customers
+// addressed by the {id} route variable. Must be applied AFTER JWTAuth middleware.
+func RequireCustomerAccess(checker CustomerAccessChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			customerID, err := uuid.Parse(mux.Vars(r)["id"])
+			if err != nil {
+				// Let the handler report the malformed ID.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := r.Context().Value(UserContextKey).(*auth.JWTClaims)
+			if !ok || claims == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			userID, err := uuid.Parse(claims.UserID)
+			if err != nil {
+				http.Error(w, "Invalid user ID in token", http.StatusUnauthorized)
+				return
+			}
+
+			if err := checker.CheckAccess(r.Context(), customerID, userID, claims.Role); err != nil {
+				if errors.Is(err, domain.ErrAccessGrantRequired) {
+					http.Error(w, "Forbidden: protected customer, request access via /access-requests", http.StatusForbidden)
+					return
+				}
+				log.Printf("ERROR checking access to customer %s: %v", customerID, err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -14,6 +14,8 @@ type User struct {
 	PasswordHash  *string    `json:"-"`
 	Role          string     `json:"role"`
 	SupervisorID  *uuid.UUID `json:"supervisor_id,omitempty"`
+	TeamID        *uuid.UUID `json:"team_id,omitempty"`
+	BranchID      *uuid.UUID `json:"branch_id,omitempty"`
 	OAuthProvider *string    `json:"oauth_provider,omitempty"`
 	OAuthID       *string    `json:"oauth_id,omitempty"`
 	IsActive      bool       `json:"is_active"`
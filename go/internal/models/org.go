@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Branch represents a physical or virtual branch office
+type Branch struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Team represents a group of staff within a branch
+type Team struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	BranchID  *uuid.UUID `json:"branch_id,omitempty"`
+	LeadID    *uuid.UUID `json:"lead_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Delegation scopes
+const (
+	DelegationScopeAll         = "*"
+	DelegationScopeRestore     = "RESTORE"
+	DelegationScopeAccessGrant = "ACCESS_GRANT"
+)
+
+// Delegation temporarily hands a user's supervisor rights to another user
+type Delegation struct {
+	ID          uuid.UUID  `json:"id"`
+	DelegatorID uuid.UUID  `json:"delegator_id"`
+	DelegateID  uuid.UUID  `json:"delegate_id"`
+	Scope       string     `json:"scope"`
+	Reason      string     `json:"reason,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the delegation covers the given scope at the given time
+func (d *Delegation) IsActive(scope string, at time.Time) bool {
+	if d.RevokedAt != nil || at.Before(d.StartsAt) || !at.Before(d.EndsAt) {
+		return false
+	}
+	return d.Scope == DelegationScopeAll || d.Scope == scope
+}
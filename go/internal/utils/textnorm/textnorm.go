@@ -0,0 +1,295 @@
+// Package textnorm turns Thai and English names into comparable keys, so that "นายสมชาย",
+// "สมชาย", "Somchai" and "Mr. Somchay" all normalize to the same string. Keys are for matching
+// only; they are not a correct romanization and must never be shown to users.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Honorifics and legal-form words dropped from names, longest first so that "นางสาว" wins
+// over "นาง". Thai titles are often written without a space before the name.
+var honorifics = []string{
+	"เด็กชาย", "เด็กหญิง", "นางสาว", "ว่าที่", "น.ส.", "ด.ช.", "ด.ญ.", "นาย", "นาง", "คุณ", "ดร.",
+}
+
+var englishStopWords = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mstr": true, "dr": true, "khun": true,
+	"co": true, "ltd": true, "limited": true, "company": true, "plc": true, "pcl": true, "inc": true,
+}
+
+var thaiStopWords = map[string]bool{"บริษัท": true, "จำกัด": true, "(มหาชน)": true, "มหาชน": true, "ห้างหุ้นส่วน": true}
+
+// Key is the full pipeline: honorifics stripped, tone marks removed, Thai transliterated,
+// lower-cased and romanization variants folded.
+func Key(name string) string {
+	words := strings.Fields(Transliterate(RemoveToneMarks(StripHonorifics(name))))
+	for i, w := range words {
+		words[i] = foldLatin(w)
+	}
+	return strings.Join(nonEmpty(words), " ")
+}
+
+// StripHonorifics removes titles and legal-form words, keeping the name itself.
+func StripHonorifics(name string) string {
+	var out []string
+	for _, w := range strings.Fields(name) {
+		lw := strings.Trim(strings.ToLower(w), ".,")
+		if englishStopWords[lw] || thaiStopWords[w] {
+			continue
+		}
+		for _, h := range honorifics {
+			if strings.HasPrefix(w, h) {
+				w = strings.TrimPrefix(w, h)
+				break
+			}
+		}
+		if w != "" {
+			out = append(out, w)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// RemoveToneMarks drops the Thai tone marks and the marks most often typed inconsistently
+// (mai taikhu), and deletes silent letters marked with thanthakhat together with the mark.
+func RemoveToneMarks(s string) string {
+	rs := []rune(s)
+	out := make([]rune, 0, len(rs))
+	for _, r := range rs {
+		switch {
+		case r >= '่' && r <= '๋', r == '็':
+			continue
+		case r == '์': // Thanthakhat silences the letter (and its vowel sign) before it
+			for len(out) > 0 && isAboveBelowVowel(out[len(out)-1]) {
+				out = out[:len(out)-1]
+			}
+			if len(out) > 0 && isConsonant(out[len(out)-1]) {
+				out = out[:len(out)-1]
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// Initial and final sounds of Thai consonants, after RTGS.
+var consonants = map[rune][2]string{
+	'ก': {"k", "k"}, 'ข': {"kh", "k"}, 'ฃ': {"kh", "k"}, 'ค': {"kh", "k"}, 'ฅ': {"kh", "k"}, 'ฆ': {"kh", "k"},
+	'ง': {"ng", "ng"}, 'จ': {"ch", "t"}, 'ฉ': {"ch", "t"}, 'ช': {"ch", "t"}, 'ซ': {"s", "t"}, 'ฌ': {"ch", "t"},
+	'ญ': {"y", "n"}, 'ฎ': {"d", "t"}, 'ฏ': {"t", "t"}, 'ฐ': {"th", "t"}, 'ฑ': {"th", "t"}, 'ฒ': {"th", "t"},
+	'ณ': {"n", "n"}, 'ด': {"d", "t"}, 'ต': {"t", "t"}, 'ถ': {"th", "t"}, 'ท': {"th", "t"}, 'ธ': {"th", "t"},
+	'น': {"n", "n"}, 'บ': {"b", "p"}, 'ป': {"p", "p"}, 'ผ': {"ph", "p"}, 'ฝ': {"f", "p"}, 'พ': {"ph", "p"},
+	'ฟ': {"f", "p"}, 'ภ': {"ph", "p"}, 'ม': {"m", "m"}, 'ย': {"y", "i"}, 'ร': {"r", "n"}, 'ล': {"l", "n"},
+	'ว': {"w", "o"}, 'ศ': {"s", "t"}, 'ษ': {"s", "t"}, 'ส': {"s", "t"}, 'ห': {"h", ""}, 'ฬ': {"l", "n"},
+	'อ': {"", ""}, 'ฮ': {"h", ""},
+}
+
+func isConsonant(r rune) bool { _, ok := consonants[r]; return ok }
+
+func isThai(r rune) bool { return r >= 'ก' && r <= '๛' }
+
+func isLeadingVowel(r rune) bool { return r >= 'เ' && r <= 'ไ' }
+
+func isAboveBelowVowel(r rune) bool {
+	return r == 'ั' || (r >= 'ิ' && r <= 'ฺ')
+}
+
+// isVowelSign reports a vowel written after (or above or below) its consonant.
+func isVowelSign(r rune) bool {
+	return r == 'ะ' || r == 'า' || r == 'ำ' || isAboveBelowVowel(r)
+}
+
+// Transliterate romanizes Thai text approximately, syllable by syllable, and passes anything
+// else through lower-cased. It expects tone marks to be removed already.
+func Transliterate(s string) string {
+	rs := []rune(s)
+	at := func(i int) rune {
+		if i < len(rs) {
+			return rs[i]
+		}
+		return 0
+	}
+
+	const (
+		none    = iota // Between syllables
+		initial        // Initial consonant written, vowel still to come
+		voweled        // Vowel written, a final consonant may follow
+	)
+	var b strings.Builder
+	state := none
+	var lead rune
+	afterFinal := false // The last thing written was a final consonant
+
+	for i := 0; i < len(rs); i++ {
+		r, next := rs[i], at(i+1)
+		wasAfterFinal := afterFinal
+		afterFinal = false
+		switch {
+		case isLeadingVowel(r):
+			if state == initial {
+				b.WriteString("a")
+			}
+			lead, state = r, none
+
+		case isConsonant(r) && state == initial:
+			switch {
+			case r == 'อ': // Vowel o between consonants, as in สอน
+				b.WriteString("o")
+				state = voweled
+			case r == 'ว' && isConsonant(next): // Vowel ua, as in สวน
+				b.WriteString("ua")
+				state = voweled
+			case r == 'ร' && next == 'ร': // รร reads an, as in สรร
+				b.WriteString("a")
+				i++
+				if !isConsonant(at(i + 1)) {
+					b.WriteString("n")
+					state = none
+				} else {
+					state = voweled
+				}
+			case (r == 'ร' || r == 'ล' || r == 'ว') && isVowelSign(next): // Cluster, as in ประ, ปลา
+				b.WriteString(consonants[r][0])
+			case isVowelSign(next) || isLeadingVowel(next): // Previous syllable had an implicit a, as in สบาย
+				b.WriteString("a" + consonants[r][0])
+			default: // Implicit o with this as the final, as in สม
+				b.WriteString("o" + consonants[r][1])
+				state, afterFinal = none, true
+			}
+
+		case isConsonant(r) && state == voweled && !isVowelSign(next):
+			b.WriteString(consonants[r][1])
+			state, afterFinal = none, true
+
+		case r == 'ร' && wasAfterFinal && !isThai(next): // Silent ร ending a word, as in เพชร
+			continue
+
+		case (r == 'ห' || r == 'อ') && strings.ContainsRune("งญนมยรลว", next) && (isVowelSign(at(i+2)) || lead != 0):
+			// Silent ห or อ leading a sonorant, as in หญิง, อยาก
+			continue
+
+		case isConsonant(r):
+			b.WriteString(consonants[r][0])
+			state = initial
+			if lead != 0 {
+				i += writeLeadingVowel(&b, lead, next, at(i+2))
+				lead, state = 0, voweled
+			}
+
+		case isVowelSign(r):
+			switch {
+			case r == 'ั' && next == 'ว': // ัว
+				b.WriteString("ua")
+				i++
+			case r == 'ั', r == 'ะ', r == 'า':
+				b.WriteString("a")
+			case r == 'ำ':
+				b.WriteString("am")
+			case r == 'ิ', r == 'ี':
+				b.WriteString("i")
+			case r == 'ึ', r == 'ื':
+				b.WriteString("ue")
+				if next == 'อ' {
+					i++
+				}
+			case r == 'ุ', r == 'ู':
+				b.WriteString("u")
+			}
+			state = voweled
+			if r == 'ำ' || r == 'ะ' {
+				state = none
+			}
+
+		default:
+			if state == initial {
+				b.WriteString("a")
+			}
+			b.WriteRune(unicode.ToLower(r))
+			state = none
+		}
+	}
+	if state == initial {
+		b.WriteString("a")
+	}
+	return b.String()
+}
+
+// writeLeadingVowel writes the vowel a leading vowel sign forms with the signs after the
+// consonant, returning how many of those it consumed.
+func writeLeadingVowel(b *strings.Builder, lead, next, after rune) int {
+	switch lead {
+	case 'เ':
+		switch {
+		case next == 'า':
+			b.WriteString("ao")
+			return 1
+		case next == 'ี' && after == 'ย':
+			b.WriteString("ia")
+			return 2
+		case next == 'ื' && after == 'อ':
+			b.WriteString("uea")
+			return 2
+		case next == 'อ', next == 'ิ':
+			b.WriteString("oe")
+			return 1
+		}
+		b.WriteString("e")
+	case 'แ':
+		b.WriteString("ae")
+	case 'โ':
+		b.WriteString("o")
+	default: // ใ, ไ
+		b.WriteString("ai")
+	}
+	return 0
+}
+
+// foldLatin maps romanization variants to one spelling: ee and oo become i and u, aspirating h
+// after a consonant is dropped (ph, th, kh, ch), j joins ch, v joins w, y after a vowel becomes
+// i and doubled letters collapse. Non-letters are removed.
+func foldLatin(w string) string {
+	w = strings.NewReplacer("ee", "i", "oo", "u").Replace(strings.ToLower(w))
+	var rs []rune
+	for _, r := range w {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if r == 'h' && len(rs) > 0 && !isLatinVowel(rs[len(rs)-1]) && unicode.IsLetter(rs[len(rs)-1]) {
+			continue
+		}
+		switch r {
+		case 'j':
+			r = 'c'
+		case 'v':
+			r = 'w'
+		case 'y':
+			if len(rs) > 0 && isLatinVowel(rs[len(rs)-1]) {
+				r = 'i'
+			}
+		}
+		if len(rs) > 0 && rs[len(rs)-1] == r {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	s := string(rs)
+	for _, p := range [][2]string{{"ue", "u"}, {"eu", "u"}, {"ou", "u"}, {"ie", "i"}} {
+		s = strings.ReplaceAll(s, p[0], p[1])
+	}
+	return s
+}
+
+func isLatinVowel(r rune) bool { return strings.ContainsRune("aeiou", r) }
+
+func nonEmpty(words []string) []string {
+	out := words[:0]
+	for _, w := range words {
+		if w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
@@ -0,0 +1,39 @@
+package textnorm
+
+import "testing"
+
+func TestKey_MatchesAcrossScripts(t *testing.T) {
+	groups := [][]string{
+		{"สมชาย", "Somchai", "Mr. Somchay", "นายสมชาย"},
+		{"นายสมชาย ใจดี", "Mr. Somchay Jaidee", "SOMCHAI JAIDEE"},
+		{"วิชัย", "Vichai", "Wichai"},
+		{"เพชร", "Phet", "Pet"},
+		{"สมศักดิ์", "Somsak"},
+		{"นางสาวสมหญิง", "Miss Somying", "คุณ สมหญิง"},
+	}
+	for _, g := range groups {
+		want := Key(g[0])
+		if want == "" {
+			t.Errorf("Key(%q) is empty", g[0])
+		}
+		for _, name := range g[1:] {
+			if got := Key(name); got != want {
+				t.Errorf("Key(%q) = %q, want %q as for %q", name, got, want, g[0])
+			}
+		}
+	}
+}
+
+func TestKey_DropsTitlesAndCompanySuffixes(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"Mr.", ""},
+		{"บริษัท ABC จำกัด", "abc"},
+		{"ABC Co., Ltd.", "abc"},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.in); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bankAccountLengths lists banks whose account numbers are not the usual ten digits.
+var bankAccountLengths = map[string]int{
+	"030": 12, // GSB
+	"033": 12, // GHB
+	"034": 12, // BAAC
+}
+
+var accountStripper = strings.NewReplacer(" ", "", "-", "")
+
+// NormalizeBankAccountNumber strips separators from a Thai account number ("123-4-56789-0")
+// and checks it has the number of digits used by bankCode.
+func NormalizeBankAccountNumber(bankCode, raw string) (string, error) {
+	s := accountStripper.Replace(strings.TrimSpace(raw))
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return "", fmt.Errorf("invalid account number: digits only")
+	}
+	want, ok := bankAccountLengths[bankCode]
+	if !ok {
+		want = 10
+	}
+	if len(s) != want {
+		return "", fmt.Errorf("invalid account number: bank %s uses %d digits, got %d", bankCode, want, len(s))
+	}
+	return s, nil
+}
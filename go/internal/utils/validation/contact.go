@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	linePattern   = regexp.MustCompile(`^@?[a-z0-9._-]{4,20}$`)
+	phoneStripper = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+// NormalizePhone converts a phone number to E.164. Numbers without an international prefix
+// are read as national numbers of defaultCountry (e.g. "66"), dropping the trunk 0:
+// "081-234-5678" becomes "+66812345678".
+func NormalizePhone(raw, defaultCountry string) (string, error) {
+	s := phoneStripper.Replace(strings.TrimSpace(raw))
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	case strings.HasPrefix(s, "0"):
+		s = defaultCountry + s[1:]
+	default:
+		return "", errors.New("invalid phone number: use a national number starting with 0 or an international number starting with +")
+	}
+
+	// E.164 allows at most 15 digits; anything under 8 cannot be a full subscriber number.
+	if len(s) < 8 || len(s) > 15 || strings.Trim(s, "0123456789") != "" || s[0] == '0' {
+		return "", errors.New("invalid phone number")
+	}
+	return "+" + s, nil
+}
+
+// NormalizeEmail lower-cases and validates an email address.
+func NormalizeEmail(raw string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if !emailPattern.MatchString(s) {
+		return "", errors.New("invalid email address")
+	}
+	return s, nil
+}
+
+// NormalizeLineID lower-cases a LINE ID. Official accounts keep their leading "@".
+func NormalizeLineID(raw string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if !linePattern.MatchString(s) {
+		return "", errors.New("invalid LINE ID")
+	}
+	return s, nil
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
 
+	"github.com/amnuaym/cic/go/internal/adapter/rpc"
 	"github.com/amnuaym/cic/go/internal/api"
 	"github.com/amnuaym/cic/go/internal/database"
+	"github.com/amnuaym/cic/go/internal/middleware"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	"github.com/rs/cors"
@@ -14,6 +17,8 @@ import (
 	_ "github.com/amnuaym/cic/go/docs" // data for swagger
 )
 
+//go:generate buf generate
+
 // @title CIC API
 // @version 1.0
 // @description This is the API for the Customer Information Center (CIC).
@@ -42,8 +47,9 @@ func main() {
 	// Initialize router
 	router := mux.NewRouter()
 
-	// Initialize API handlers
-	api.SetupRoutes(router, db)
+	// Initialize API handlers and the gRPC services
+	grpcServer := rpc.NewServer(middleware.APIKeyLookup(db))
+	api.SetupRoutes(router, grpcServer, db)
 
 	// Swagger
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
@@ -63,6 +69,22 @@ func main() {
 
 	handler := c.Handler(router)
 
+	// Start the gRPC server alongside HTTP
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	lis, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+	go func() {
+		log.Printf("gRPC server starting on port %s", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,21 @@
+-- Maker-checker (four-eyes) approval for sensitive customer operations
+CREATE TABLE change_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    operation VARCHAR(50) NOT NULL, -- DELETE_CUSTOMER, RESTORE_CUSTOMER, ANONYMIZE_CUSTOMER, BLACKLIST_CUSTOMER
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    payload JSONB, -- Serialized operation
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING', -- PENDING, APPROVED, APPLIED, REJECTED, EXPIRED, FAILED
+    maker_id UUID NOT NULL REFERENCES users(id),
+    checker_id UUID REFERENCES users(id),
+    comment TEXT,
+    error TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (checker_id IS NULL OR checker_id <> maker_id)
+);
+
+CREATE INDEX idx_change_requests_status ON change_requests(status, created_at DESC);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS delegations;
+DROP INDEX IF EXISTS idx_users_supervisor;
+ALTER TABLE users DROP COLUMN IF EXISTS branch_id;
+ALTER TABLE users DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS branches;
@@ -0,0 +1,36 @@
+-- Org hierarchy (branches, teams) and time-bound delegation of supervisor rights
+CREATE TABLE branches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE teams (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    branch_id UUID REFERENCES branches(id),
+    lead_id UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users ADD COLUMN team_id UUID REFERENCES teams(id);
+ALTER TABLE users ADD COLUMN branch_id UUID REFERENCES branches(id);
+
+CREATE TABLE delegations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    delegator_id UUID NOT NULL REFERENCES users(id),
+    delegate_id UUID NOT NULL REFERENCES users(id),
+    scope VARCHAR(50) NOT NULL DEFAULT '*', -- *, RESTORE, ACCESS_GRANT
+    reason TEXT,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (delegator_id <> delegate_id),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_users_supervisor ON users(supervisor_id);
+CREATE INDEX idx_delegations_delegate ON delegations(delegate_id, ends_at);
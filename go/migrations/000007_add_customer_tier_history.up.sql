@@ -0,0 +1,15 @@
+-- Derived tier / high-value changes, with the rule version that produced them
+CREATE TABLE customer_tier_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    old_tier VARCHAR(50),
+    new_tier VARCHAR(50),
+    old_high_value BOOLEAN NOT NULL DEFAULT false,
+    new_high_value BOOLEAN NOT NULL DEFAULT false,
+    rule_version VARCHAR(50) NOT NULL,
+    rule TEXT,
+    source VARCHAR(20) NOT NULL, -- CREATE, UPDATE, RECALC
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tier_history_customer ON customer_tier_history(customer_id, changed_at DESC);
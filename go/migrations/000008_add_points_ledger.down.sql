@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS points_allocations;
+DROP TABLE IF EXISTS points_transactions;
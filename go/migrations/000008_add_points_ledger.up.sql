@@ -0,0 +1,47 @@
+-- Loyalty points ledger. customers.points_balance becomes a cache maintained by ledger postings.
+CREATE TABLE points_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL, -- EARN, BURN, ADJUST, EXPIRE, REVERSE
+    points BIGINT NOT NULL,
+    remaining BIGINT NOT NULL DEFAULT 0, -- unspent points of a lot (EARN / positive ADJUST)
+    expires_at TIMESTAMP WITH TIME ZONE,
+    idempotency_key VARCHAR(255),
+    reference VARCHAR(255),
+    reason TEXT,
+    reverses_id UUID REFERENCES points_transactions(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (points <> 0),
+    CHECK (remaining >= 0 AND remaining <= GREATEST(points, 0))
+);
+
+CREATE TABLE points_allocations (
+    transaction_id UUID NOT NULL REFERENCES points_transactions(id) ON DELETE CASCADE,
+    lot_id UUID NOT NULL REFERENCES points_transactions(id) ON DELETE CASCADE,
+    points BIGINT NOT NULL,
+    PRIMARY KEY (transaction_id, lot_id)
+);
+
+CREATE UNIQUE INDEX idx_points_idempotency ON points_transactions(customer_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE UNIQUE INDEX idx_points_reverses ON points_transactions(reverses_id) WHERE reverses_id IS NOT NULL;
+CREATE INDEX idx_points_customer ON points_transactions(customer_id, created_at DESC);
+CREATE INDEX idx_points_open_lots ON points_transactions(customer_id, created_at) WHERE remaining > 0;
+
+-- The ledger counts whole points, so fractional balances are rounded down. The fraction cannot be
+-- posted as a ledger row; each rounded balance is recorded in the audit log instead, so the drop
+-- stays traceable.
+INSERT INTO audit_logs (entity_id, entity_type, action, performed_by, changes)
+SELECT id, 'CUSTOMER', 'POINTS_ROUNDING', 'SYSTEM',
+       'points_balance ' || points_balance || ' -> ' || FLOOR(points_balance) || ' (points ledger migration)'
+FROM customers
+WHERE points_balance > 0 AND points_balance <> FLOOR(points_balance);
+
+-- Carry existing balances over as a non-expiring opening lot
+INSERT INTO points_transactions (customer_id, type, points, remaining, reason)
+SELECT id, 'ADJUST', FLOOR(points_balance), FLOOR(points_balance), 'Opening balance'
+FROM customers
+WHERE FLOOR(points_balance) > 0;
+
+UPDATE customers SET points_balance = FLOOR(points_balance) WHERE points_balance > 0;
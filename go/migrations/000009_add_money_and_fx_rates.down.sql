@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS customer_portfolio_values;
+DROP TABLE IF EXISTS fx_rates;
+ALTER TABLE customers ALTER COLUMN portfolio_size TYPE DECIMAL(15, 2);
+ALTER TABLE customers ALTER COLUMN clv TYPE DECIMAL(15, 2);
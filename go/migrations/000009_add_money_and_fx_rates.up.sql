@@ -0,0 +1,17 @@
+-- Exact money amounts and local FX rates for multi-currency portfolio values
+ALTER TABLE customers ALTER COLUMN clv TYPE NUMERIC(19, 2);
+ALTER TABLE customers ALTER COLUMN portfolio_size TYPE NUMERIC(19, 2);
+
+CREATE TABLE fx_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate_to_base NUMERIC(19, 8) NOT NULL CHECK (rate_to_base > 0), -- THB per 1 unit of currency
+    as_of DATE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE customer_portfolio_values (
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(19, 2) NOT NULL,
+    PRIMARY KEY (customer_id, currency)
+);
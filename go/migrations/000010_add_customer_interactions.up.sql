@@ -0,0 +1,19 @@
+-- Contacts with the customer (calls, emails, branch visits...) and internal notes
+CREATE TABLE customer_interactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL, -- PHONE, EMAIL, BRANCH, CHAT, SMS, LETTER, NOTE
+    direction VARCHAR(20) NOT NULL, -- INBOUND, OUTBOUND, INTERNAL
+    subject VARCHAR(255) NOT NULL,
+    body TEXT,
+    staff_user_id UUID NOT NULL REFERENCES users(id),
+    pinned BOOLEAN NOT NULL DEFAULT false,
+    attachments JSONB NOT NULL DEFAULT '[]', -- [{name, url, content_type, size}] references into document storage
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_interactions_customer ON customer_interactions(customer_id, occurred_at DESC);
+CREATE INDEX idx_interactions_pinned ON customer_interactions(customer_id) WHERE pinned;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_verifications;
+DROP TABLE IF EXISTS customer_contact_points;
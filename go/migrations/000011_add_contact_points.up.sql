@@ -0,0 +1,27 @@
+-- Phones (E.164), emails and LINE IDs with verification state
+CREATE TABLE customer_contact_points (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL, -- MOBILE, LANDLINE, EMAIL, LINE
+    value VARCHAR(255) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    verification_status VARCHAR(20) NOT NULL DEFAULT 'UNVERIFIED', -- UNVERIFIED, PENDING, VERIFIED
+    verified_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (customer_id, type, value)
+);
+
+CREATE UNIQUE INDEX idx_contact_points_primary ON customer_contact_points(customer_id, type) WHERE is_primary;
+CREATE INDEX idx_contact_points_value ON customer_contact_points(value);
+
+-- Outstanding one-time codes; only the hash is stored
+CREATE TABLE contact_verifications (
+    contact_point_id UUID PRIMARY KEY REFERENCES customer_contact_points(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
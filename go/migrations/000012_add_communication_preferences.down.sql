@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_channel_preferences;
+DROP TABLE IF EXISTS customer_communication_preferences;
@@ -0,0 +1,18 @@
+-- Per-purpose channel preferences with allowed days and quiet hours (local time, see CONTACT_TZ)
+CREATE TABLE customer_communication_preferences (
+    customer_id UUID PRIMARY KEY REFERENCES customers(id) ON DELETE CASCADE,
+    language VARCHAR(2),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE customer_channel_preferences (
+    customer_id UUID NOT NULL REFERENCES customer_communication_preferences(customer_id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL, -- STATEMENT, PROMOTION, SERVICE, SECURITY
+    channel VARCHAR(20) NOT NULL, -- EMAIL, SMS, PHONE, LINE, POST
+    allowed BOOLEAN NOT NULL DEFAULT true,
+    priority INT NOT NULL DEFAULT 0,
+    days VARCHAR(27), -- Comma-separated MON..SUN; NULL means every day
+    quiet_start VARCHAR(5), -- HH:MM
+    quiet_end VARCHAR(5),
+    PRIMARY KEY (customer_id, purpose, channel)
+);
@@ -0,0 +1,19 @@
+-- Customer bank accounts for payouts and direct debits (legacy tclient_bank_accounts)
+CREATE TABLE customer_bank_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    bank_code VARCHAR(3) NOT NULL, -- Bank of Thailand bank code, e.g. 014
+    branch VARCHAR(100),
+    account_number VARCHAR(20) NOT NULL,
+    account_name VARCHAR(255),
+    account_type VARCHAR(20) NOT NULL, -- SAVINGS, CURRENT, FIXED_DEPOSIT
+    purpose VARCHAR(20) NOT NULL, -- PAYOUT, DIRECT_DEBIT
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (customer_id, bank_code, account_number, purpose)
+);
+
+CREATE UNIQUE INDEX idx_bank_accounts_primary ON customer_bank_accounts(customer_id, purpose) WHERE is_primary;
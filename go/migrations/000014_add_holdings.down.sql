@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_holdings;
+DROP TABLE IF EXISTS products;
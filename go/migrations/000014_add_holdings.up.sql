@@ -0,0 +1,33 @@
+-- Product catalog referenced by holdings
+CREATE TABLE products (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(20) NOT NULL, -- INSURANCE, DEPOSIT, INVESTMENT, LOAN
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Customer links to policies and accounts (legacy tclient_policy_links).
+-- customers.portfolio_size and customer_portfolio_values are derived from active OWNER holdings.
+CREATE TABLE customer_holdings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    product_code VARCHAR(50) NOT NULL REFERENCES products(code),
+    external_ref VARCHAR(100) NOT NULL, -- Policy or account number
+    role VARCHAR(20) NOT NULL, -- OWNER, INSURED, PAYER, BENEFICIARY
+    status VARCHAR(20) NOT NULL, -- PENDING, ACTIVE, LAPSED, MATURED, SURRENDERED, CLOSED
+    currency VARCHAR(3) NOT NULL DEFAULT 'THB',
+    sum_insured NUMERIC(19, 2),
+    balance NUMERIC(19, 2),
+    start_date DATE NOT NULL,
+    end_date DATE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (customer_id, product_code, external_ref, role)
+);
+
+CREATE INDEX idx_holdings_customer ON customer_holdings(customer_id, status);
+CREATE INDEX idx_holdings_external_ref ON customer_holdings(external_ref);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS import_rejects;
+DROP TABLE IF EXISTS import_runs;
+DROP TABLE IF EXISTS customer_external_refs;
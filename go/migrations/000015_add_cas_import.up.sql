@@ -0,0 +1,38 @@
+-- Identifiers of customers in other systems, e.g. the CAS cli_num
+CREATE TABLE customer_external_refs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    system VARCHAR(50) NOT NULL,
+    external_key VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (system, external_key)
+);
+
+CREATE INDEX idx_external_refs_customer ON customer_external_refs(customer_id);
+
+-- Importer runs, checkpointed by source key so they can be resumed
+CREATE TABLE import_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source VARCHAR(50) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL, -- RUNNING, COMPLETED, FAILED
+    cursor VARCHAR(100) NOT NULL DEFAULT '',
+    processed INT NOT NULL DEFAULT 0,
+    imported INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    rejected INT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_by VARCHAR(100) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE import_rejects (
+    run_id UUID NOT NULL REFERENCES import_runs(id) ON DELETE CASCADE,
+    source_key VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (run_id, source_key)
+);
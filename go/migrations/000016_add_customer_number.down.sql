@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_customers_customer_number;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS customer_number;
+
+DROP SEQUENCE IF EXISTS customer_number_seq;
@@ -0,0 +1,6 @@
+-- Human-readable customer number, formatted by the application from this sequence
+CREATE SEQUENCE customer_number_seq;
+
+ALTER TABLE customers ADD COLUMN customer_number VARCHAR(20);
+
+CREATE UNIQUE INDEX idx_customers_customer_number ON customers(customer_number);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_customers_search_name_trgm;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS search_name;
@@ -0,0 +1,6 @@
+-- Normalized name key for fuzzy search (see internal/utils/textnorm), written by the application
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE customers ADD COLUMN search_name TEXT;
+
+CREATE INDEX idx_customers_search_name_trgm ON customers USING gin (search_name gin_trgm_ops);
@@ -0,0 +1,2 @@
+-- Call-center search by postcode (zip: in customer search)
+CREATE INDEX idx_addresses_zip_code ON addresses(zip_code);
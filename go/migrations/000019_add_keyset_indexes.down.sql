@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_consents_keyset;
+DROP INDEX IF EXISTS idx_audit_logs_keyset;
+DROP INDEX IF EXISTS idx_customers_deleted_keyset;
+DROP INDEX IF EXISTS idx_customers_created_keyset;
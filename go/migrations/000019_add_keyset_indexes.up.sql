@@ -0,0 +1,5 @@
+-- Keyset pagination walks these lists by (sort key, id), newest first
+CREATE INDEX idx_customers_created_keyset ON customers(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_customers_deleted_keyset ON customers(deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_audit_logs_keyset ON audit_logs(timestamp DESC, id DESC);
+CREATE INDEX idx_consents_keyset ON consents(timestamp DESC, id DESC);
@@ -0,0 +1,37 @@
+-- Saved customer filters, shared by role and optionally materialized daily
+CREATE TABLE segments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    filter JSONB NOT NULL, -- domain.CustomerFilter
+    shared_with_roles VARCHAR(255), -- Comma-separated
+    schedule VARCHAR(20), -- DAILY or NULL for on demand
+    owner_id UUID NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE segment_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    size INT NOT NULL,
+    entered INT NOT NULL,
+    left_count INT NOT NULL,
+    triggered_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX idx_segment_runs_segment ON segment_runs(segment_id, run_at DESC);
+
+-- Membership intervals: a customer is a member at runs from entered_at until left_at
+CREATE TABLE segment_members (
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    entered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    left_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (segment_id, customer_id, entered_at)
+);
+
+CREATE INDEX idx_segment_members_current ON segment_members(segment_id, customer_id) WHERE left_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE consents DROP COLUMN IF EXISTS created_by;
+ALTER TABLE relationships DROP COLUMN IF EXISTS created_by;
+ALTER TABLE identities DROP COLUMN IF EXISTS created_by;
+ALTER TABLE addresses DROP COLUMN IF EXISTS created_by;
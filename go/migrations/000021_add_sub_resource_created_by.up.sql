@@ -0,0 +1,5 @@
+-- Who added each sub-resource, for the customer timeline; NULL for rows added before this column
+ALTER TABLE addresses ADD COLUMN created_by UUID REFERENCES users(id);
+ALTER TABLE identities ADD COLUMN created_by UUID REFERENCES users(id);
+ALTER TABLE relationships ADD COLUMN created_by UUID REFERENCES users(id);
+ALTER TABLE consents ADD COLUMN created_by UUID REFERENCES users(id);
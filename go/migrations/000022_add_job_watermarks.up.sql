@@ -0,0 +1,5 @@
+-- How far each incremental scheduled job has completed, so a missed run is caught up by the next
+CREATE TABLE job_watermarks (
+    job VARCHAR(50) PRIMARY KEY,
+    ran_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,171 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListOptions page an offset-paged list. Zero values take the server defaults.
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	setInt(q, "limit", o.Limit)
+	setInt(q, "offset", o.Offset)
+	return q
+}
+
+// --- Access grants ---
+
+// RequestAccess asks to read a protected customer. The grant is active at once unless the
+// server requires a supervisor's approval.
+func (c *Client) RequestAccess(ctx context.Context, customerID uuid.UUID, reason string) (*AccessGrant, error) {
+	body := map[string]string{"reason": reason}
+	return one[AccessGrant](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "access-requests"), body: body})
+}
+
+// ApproveAccess approves a pending access request.
+func (c *Client) ApproveAccess(ctx context.Context, grantID uuid.UUID) (*AccessGrant, error) {
+	return one[AccessGrant](ctx, c, &request{method: http.MethodPost, path: v1 + "/access-requests" + joinPath(grantID.String(), "approve")})
+}
+
+// RejectAccess rejects a pending access request.
+func (c *Client) RejectAccess(ctx context.Context, grantID uuid.UUID) (*AccessGrant, error) {
+	return one[AccessGrant](ctx, c, &request{method: http.MethodPost, path: v1 + "/access-requests" + joinPath(grantID.String(), "reject")})
+}
+
+// AccessGrantFilter selects access grants; nil IDs match all.
+type AccessGrantFilter struct {
+	CustomerID *uuid.UUID
+	UserID     *uuid.UUID
+	ListOptions
+}
+
+// ListAccessGrants lists access grants, newest first.
+func (c *Client) ListAccessGrants(ctx context.Context, f AccessGrantFilter) ([]*AccessGrant, error) {
+	q := f.query()
+	if f.CustomerID != nil {
+		q.Set("customer_id", f.CustomerID.String())
+	}
+	if f.UserID != nil {
+		q.Set("user_id", f.UserID.String())
+	}
+	return list[*AccessGrant](ctx, c, &request{method: http.MethodGet, path: v1 + "/access-requests", query: q})
+}
+
+// --- Change requests ---
+
+// ListChangeRequests lists maker-checker change requests, optionally of one status.
+func (c *Client) ListChangeRequests(ctx context.Context, status ChangeRequestStatus, opts ListOptions) ([]*ChangeRequest, error) {
+	q := opts.query()
+	if status != "" {
+		q.Set("status", string(status))
+	}
+	return list[*ChangeRequest](ctx, c, &request{method: http.MethodGet, path: v1 + "/change-requests", query: q})
+}
+
+// GetChangeRequest reads a change request.
+func (c *Client) GetChangeRequest(ctx context.Context, id uuid.UUID) (*ChangeRequest, error) {
+	return one[ChangeRequest](ctx, c, &request{method: http.MethodGet, path: v1 + "/change-requests" + joinPath(id.String())})
+}
+
+// GetChangeRequestAudit returns the audit trail of a change request.
+func (c *Client) GetChangeRequestAudit(ctx context.Context, id uuid.UUID) ([]*AuditLog, error) {
+	return list[*AuditLog](ctx, c, &request{method: http.MethodGet, path: v1 + "/change-requests" + joinPath(id.String(), "audit")})
+}
+
+// ApproveChangeRequest approves and applies a change request. When applying it fails the error
+// matches ErrUnprocessable and its body decodes to the failed ChangeRequest.
+func (c *Client) ApproveChangeRequest(ctx context.Context, id uuid.UUID, comment string) (*ChangeRequest, error) {
+	return one[ChangeRequest](ctx, c, &request{method: http.MethodPost, path: v1 + "/change-requests" + joinPath(id.String(), "approve"),
+		body: map[string]string{"comment": comment}})
+}
+
+// RejectChangeRequest rejects a change request.
+func (c *Client) RejectChangeRequest(ctx context.Context, id uuid.UUID, comment string) (*ChangeRequest, error) {
+	return one[ChangeRequest](ctx, c, &request{method: http.MethodPost, path: v1 + "/change-requests" + joinPath(id.String(), "reject"),
+		body: map[string]string{"comment": comment}})
+}
+
+// --- Audit logs ---
+
+// AuditLog is an audit log entry as the server returns it.
+type AuditLog struct {
+	ID          uuid.UUID
+	EntityID    uuid.UUID
+	EntityType  string
+	Action      string
+	PerformedBy string
+	Timestamp   time.Time
+	Changes     string
+	IPAddress   string
+}
+
+// AuditLogFilter selects audit log entries.
+type AuditLogFilter struct {
+	// Action is CREATE, UPDATE, DELETE, RESTORE, ANONYMIZE and so on; all when empty.
+	Action   string
+	EntityID *uuid.UUID
+	PageOptions
+}
+
+// ListAuditLogs returns a page of audit log entries, newest first.
+func (c *Client) ListAuditLogs(ctx context.Context, f AuditLogFilter) (*Page[*AuditLog], error) {
+	q := f.PageOptions.query()
+	if f.Action != "" {
+		q.Set("action", f.Action)
+	}
+	if f.EntityID != nil {
+		q.Set("entity_id", f.EntityID.String())
+	}
+	return one[Page[*AuditLog]](ctx, c, &request{method: http.MethodGet, path: v1 + "/audit-logs", query: q})
+}
+
+// AuditLogs iterates over every matching audit log entry.
+func (c *Client) AuditLogs(f AuditLogFilter) *Iterator[*AuditLog] {
+	return newIterator(func(ctx context.Context, cursor string) ([]*AuditLog, string, error) {
+		f.Cursor = cursor
+		page, err := c.ListAuditLogs(ctx, f)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Items, page.NextCursor, nil
+	})
+}
+
+// GetAuditLog reads an audit log entry.
+func (c *Client) GetAuditLog(ctx context.Context, id uuid.UUID) (*AuditLog, error) {
+	return one[AuditLog](ctx, c, &request{method: http.MethodGet, path: v1 + "/audit-logs" + joinPath(id.String())})
+}
+
+// --- Consents ---
+
+// ListConsents returns a page of every customer's consents.
+func (c *Client) ListConsents(ctx context.Context, opts PageOptions) (*Page[*Consent], error) {
+	q := opts.query()
+	q.Set("q", "*")
+	return one[Page[*Consent]](ctx, c, &request{method: http.MethodGet, path: v1 + "/consents", query: q})
+}
+
+// Consents iterates over every customer's consents.
+func (c *Client) Consents(limit int) *Iterator[*Consent] {
+	return newIterator(func(ctx context.Context, cursor string) ([]*Consent, string, error) {
+		page, err := c.ListConsents(ctx, PageOptions{Limit: limit, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Items, page.NextCursor, nil
+	})
+}
+
+// GetConsent reads a consent.
+func (c *Client) GetConsent(ctx context.Context, id uuid.UUID) (*Consent, error) {
+	return one[Consent](ctx, c, &request{method: http.MethodGet, path: v1 + "/consents" + joinPath(id.String())})
+}
@@ -0,0 +1,393 @@
+// Package cicclient is a Go client for the CIC REST API.
+//
+// A Client authenticates with a username and password, a JWT obtained elsewhere, or an API key:
+//
+//	c := cicclient.New("https://cic.example.com", cicclient.WithLogin("svc-crm", password))
+//	customer, err := c.GetCustomer(ctx, id)
+//	if errors.Is(err, cicclient.ErrAccessGrantRequired) {
+//		// protected customer: c.RequestAccess(ctx, id, reason)
+//	}
+//
+// Reads and other idempotent calls are retried with exponential backoff on network errors and
+// 429, 502, 503 and 504 answers. Lists that page by cursor have iterators, such as Customers.
+package cicclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client calls the CIC API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	apiKey     string
+
+	mu       sync.Mutex
+	token    string
+	expires  time.Time
+	username string
+	password string
+}
+
+// RetryPolicy controls retries of idempotent calls. The wait before retry n (from 1) is
+// MinBackoff doubled n-1 times, capped at MaxBackoff, with up to half of it taken off at
+// random. A Retry-After answer is honoured up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts, waiting about 200ms and then 400ms.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// tokenRefreshMargin is how long before a JWT expires the client logs in again.
+const tokenRefreshMargin = time.Minute
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy. MaxAttempts of 1 turns retries off.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithLogin logs in with the username and password before the first call, and again shortly
+// before the token expires or when the server rejects it.
+func WithLogin(username, password string) Option {
+	return func(c *Client) {
+		c.username, c.password = username, password
+	}
+}
+
+// WithToken authenticates with a JWT obtained elsewhere. It is not refreshed.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.setToken(token)
+	}
+}
+
+// WithAPIKey authenticates with an API key, sent as X-API-Key, instead of a JWT.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// New returns a client for the CIC server at baseURL, such as "https://cic.example.com".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c
+}
+
+// LoginResult is the answer to a successful login.
+type LoginResult struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+// Login exchanges a username and password for a JWT, which the client then uses. The
+// credentials are kept to log in again when the token expires.
+func (c *Client) Login(ctx context.Context, username, password string) (*LoginResult, error) {
+	c.mu.Lock()
+	c.username, c.password = username, password
+	c.mu.Unlock()
+	return c.login(ctx)
+}
+
+func (c *Client) login(ctx context.Context) (*LoginResult, error) {
+	c.mu.Lock()
+	body := map[string]string{"username": c.username, "password": c.password}
+	c.mu.Unlock()
+
+	var res LoginResult
+	if err := c.send(ctx, &request{method: http.MethodPost, path: "/api/auth/login", body: body, anonymous: true}, &res); err != nil {
+		return nil, err
+	}
+	c.setToken(res.Token)
+	return &res, nil
+}
+
+// Me returns the authenticated user.
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	return one[User](ctx, c, &request{method: http.MethodGet, path: "/api/users/me"})
+}
+
+func (c *Client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token, c.expires = token, tokenExpiry(token)
+}
+
+// tokenExpiry reads the exp claim of a JWT without verifying it; the server does that. It
+// returns the zero time when the token has none.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// authorize sets the request's credentials, logging in first when the client has a username
+// and no token that is good for a while yet.
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+		return nil
+	}
+	c.mu.Lock()
+	token, expires, canLogin := c.token, c.expires, c.username != ""
+	c.mu.Unlock()
+	stale := token == "" || (!expires.IsZero() && time.Until(expires) < tokenRefreshMargin)
+	if stale && canLogin {
+		res, err := c.login(ctx)
+		if err != nil {
+			return err
+		}
+		token = res.Token
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// request describes one API call.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	header http.Header
+	// idempotent marks a POST that is safe to repeat, such as one carrying an idempotency key.
+	idempotent bool
+	// anonymous requests carry no credentials.
+	anonymous bool
+}
+
+func (r *request) retryable() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return r.idempotent
+}
+
+// send performs the call and decodes a JSON answer into out, when out is not nil.
+func (c *Client) send(ctx context.Context, r *request, out interface{}) error {
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return decodeBody(resp, out)
+}
+
+// one performs a call answered with a single JSON document.
+func one[T any](ctx context.Context, c *Client, r *request) (*T, error) {
+	var out T
+	if err := c.send(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// list performs a call answered with a JSON array.
+func list[T any](ctx context.Context, c *Client, r *request) ([]T, error) {
+	var out []T
+	if err := c.send(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// raw performs a call and returns its body as is, such as a CSV export.
+func (c *Client) raw(ctx context.Context, r *request) ([]byte, error) {
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cicclient: reading %s %s: %w", r.method, r.path, err)
+	}
+	return body, nil
+}
+
+func decodeBody(resp *http.Response, v interface{}) error {
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("cicclient: decoding %s %s: %w", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// do performs the call with retries and returns a successful response, whose body the caller
+// must close. Failures are returned as *Error.
+func (c *Client) do(ctx context.Context, r *request) (*http.Response, error) {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("cicclient: encoding %s %s: %w", r.method, r.path, err)
+		}
+	}
+	attempts := 1
+	if r.retryable() {
+		attempts = c.retry.MaxAttempts
+	}
+	reauthorized := false
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, r, body)
+		if err == nil && resp.StatusCode < 300 {
+			return resp, nil
+		}
+		var wait time.Duration
+		if err == nil {
+			apiErr := newError(resp)
+			// A rejected token is refreshed once, without counting as an attempt.
+			if apiErr.StatusCode == http.StatusUnauthorized && !r.anonymous && !reauthorized && c.canLogin() {
+				reauthorized = true
+				c.setToken("")
+				attempt--
+				continue
+			}
+			if !retryStatus(apiErr.StatusCode) || attempt >= attempts {
+				return nil, apiErr
+			}
+			err, wait = apiErr, retryAfter(resp)
+		} else if ctx.Err() != nil || attempt >= attempts || !retryError(err) {
+			return nil, err
+		}
+		if wait == 0 || wait > c.retry.MaxBackoff {
+			wait = c.backoff(attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, r *request, body []byte) (*http.Response, error) {
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if !r.anonymous {
+		if err := c.authorize(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) canLogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.username != "" && c.apiKey == ""
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retry.MinBackoff << (attempt - 1)
+	if d <= 0 || d > c.retry.MaxBackoff {
+		d = c.retry.MaxBackoff
+	}
+	if half := int64(d / 2); half > 0 {
+		d -= time.Duration(rand.Int63n(half))
+	}
+	return d
+}
+
+func retryStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryError reports whether a failed attempt may be retried: a network error, or a login
+// answered with a retryable status.
+func retryError(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return retryStatus(apiErr.StatusCode)
+	}
+	return true
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
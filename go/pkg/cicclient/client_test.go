@@ -0,0 +1,277 @@
+package cicclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amnuaym/cic/go/internal/api"
+	"github.com/amnuaym/cic/go/internal/auth"
+	"github.com/amnuaym/cic/go/internal/database"
+	"github.com/amnuaym/cic/go/internal/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+)
+
+const testPassword = "s3cret"
+
+// testServer serves the routes SetupRoutes builds against the migrated database at
+// TEST_DATABASE_URL, counting the requests that reach them.
+type testServer struct {
+	*httptest.Server
+	db *sql.DB
+	// suffix keeps the users and customers of one run apart from earlier runs.
+	suffix string
+	// unavailable answers this many requests with 503 before serving again.
+	unavailable atomic.Int32
+	logins      atomic.Int32
+	requests    atomic.Int32
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set; the client tests need a migrated Postgres database")
+	}
+	db, err := database.Open(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("OTP_SENDER", "log")
+
+	s := &testServer{db: db, suffix: uuid.NewString()[:8]}
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.requests.Add(1)
+			if s.unavailable.Add(-1) >= 0 {
+				http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			if r.URL.Path == "/api/auth/login" {
+				s.logins.Add(1)
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.SetupRoutes(router, grpc.NewServer(), db)
+
+	s.Server = httptest.NewServer(router)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// user adds an active user with testPassword and the given role, returning its ID and username.
+func (s *testServer) user(t *testing.T, role string) (uuid.UUID, string) {
+	t.Helper()
+	hash, err := auth.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	username := role + "-" + uuid.NewString()[:8]
+	var id uuid.UUID
+	err = s.db.QueryRow(`INSERT INTO users (email, username, password_hash, role) VALUES ($1, $2, $3, $4) RETURNING id`,
+		username+"@example.com", username, hash, role).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id, username
+}
+
+// client returns a client logged in as a new user with the given role.
+func (s *testServer) client(t *testing.T, role string, opts ...Option) *Client {
+	t.Helper()
+	_, username := s.user(t, role)
+	return New(s.URL, append([]Option{WithLogin(username, testPassword)}, opts...)...)
+}
+
+// customer creates a personal customer through the API, named after this run.
+func (s *testServer) customer(t *testing.T, client *Client) *Customer {
+	t.Helper()
+	c := &Customer{Type: TypePersonal, FirstName: "Somchai", LastName: "Client" + s.suffix}
+	created, err := client.CreateCustomer(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+// protect marks a customer high value, so reading it needs an access grant.
+func (s *testServer) protect(t *testing.T, id uuid.UUID) {
+	t.Helper()
+	if _, err := s.db.Exec(`UPDATE customers SET is_high_value = true WHERE id = $1`, id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var fastRetries = WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+
+func TestClient_LogsInAndRefreshesToken(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	c := srv.customer(t, srv.client(t, middleware.RoleOperator))
+
+	id, username := srv.user(t, middleware.RoleViewer)
+	client := New(srv.URL, WithLogin(username, testPassword))
+	srv.logins.Store(0)
+	for i := 0; i < 2; i++ {
+		got, err := client.GetCustomer(ctx, c.ID)
+		if err != nil || got.FirstName != "Somchai" {
+			t.Fatalf("Expected the customer, got %v %v", got, err)
+		}
+	}
+	if n := srv.logins.Load(); n != 1 {
+		t.Errorf("Expected one login while the token is fresh, got %d", n)
+	}
+
+	// A token inside the refresh margin is replaced before the call.
+	claims := auth.JWTClaims{
+		UserID:   id.String(),
+		Username: username,
+		Role:     middleware.RoleViewer,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	expiring, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("your-secret-key-change-in-production"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.logins.Store(0)
+	client = New(srv.URL, WithToken(expiring), WithLogin(username, testPassword))
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCustomer(ctx, c.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := srv.logins.Load(); n != 1 {
+		t.Errorf("Expected the expiring token replaced once, got %d logins", n)
+	}
+
+	// A token the server rejects is replaced once.
+	srv.logins.Store(0)
+	client = New(srv.URL, WithToken("revoked"), WithLogin(username, testPassword))
+	if _, err := client.GetCustomer(ctx, c.ID); err != nil {
+		t.Fatalf("Expected a rejected token to be refreshed, got %v", err)
+	}
+	if n := srv.logins.Load(); n != 1 {
+		t.Errorf("Expected one login after the rejection, got %d", n)
+	}
+
+	_, err = New(srv.URL).Login(ctx, username, "wrong")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for a bad password, got %v", err)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	protected := srv.customer(t, srv.client(t, middleware.RoleOperator))
+	srv.protect(t, protected.ID)
+	viewer := srv.client(t, middleware.RoleViewer)
+
+	_, err := viewer.GetCustomer(ctx, uuid.New())
+	var apiErr *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 *Error matching ErrNotFound, got %v", err)
+	}
+
+	_, err = viewer.GetCustomer(ctx, protected.ID)
+	if !errors.Is(err, ErrAccessGrantRequired) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrAccessGrantRequired for a protected customer, got %v", err)
+	}
+
+	_, err = viewer.CreateCustomer(ctx, &Customer{Type: TypePersonal, FirstName: "Somsak"})
+	if !errors.Is(err, ErrForbidden) || errors.Is(err, ErrAccessGrantRequired) {
+		t.Errorf("Expected ErrForbidden for a viewer creating, got %v", err)
+	}
+}
+
+func TestClient_RetriesOnlyIdempotentCalls(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	client := srv.client(t, middleware.RoleOperator, fastRetries)
+	c := srv.customer(t, client)
+
+	srv.unavailable.Store(2)
+	srv.requests.Store(0)
+	if _, err := client.GetCustomer(ctx, c.ID); err != nil {
+		t.Fatalf("Expected the read to succeed on the third attempt, got %v", err)
+	}
+	if n := srv.requests.Load(); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+
+	srv.unavailable.Store(3)
+	if _, err := client.GetCustomer(ctx, c.ID); !errors.Is(err, ErrServer) {
+		t.Errorf("Expected ErrServer once the attempts run out, got %v", err)
+	}
+
+	srv.unavailable.Store(1)
+	srv.requests.Store(0)
+	_, err := client.CreateCustomer(ctx, &Customer{Type: TypePersonal, FirstName: "Somsak", LastName: "Client" + srv.suffix})
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("Expected the create to fail without a retry, got %v", err)
+	}
+	if n := srv.requests.Load(); n != 1 {
+		t.Errorf("Expected a single attempt, got %d", n)
+	}
+}
+
+func TestClient_IteratesCustomerPages(t *testing.T) {
+	srv := newTestServer(t)
+	operator := srv.client(t, middleware.RoleOperator)
+	want := map[uuid.UUID]bool{}
+	var customers []*Customer
+	for i := 0; i < 5; i++ {
+		c := srv.customer(t, operator)
+		customers = append(customers, c)
+		want[c.ID] = true
+	}
+	srv.protect(t, customers[3].ID)
+	viewer := srv.client(t, middleware.RoleViewer)
+
+	got, err := viewer.Customers(CustomerListOptions{Filter: CustomerFilter{Text: "Client" + srv.suffix}, Limit: 2}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(customers) {
+		t.Fatalf("Expected %d customers across the pages, got %d", len(customers), len(got))
+	}
+	for _, c := range got {
+		if !want[c.ID] {
+			t.Errorf("Unexpected or repeated customer %s", c.ID)
+		}
+		delete(want, c.ID)
+		if protected := c.ID == customers[3].ID; protected != (c.FirstName == "") {
+			t.Errorf("Expected only the protected customer withheld, got %+v", c)
+		}
+	}
+}
+
+func TestClient_SensitiveOperationReturnsChangeRequest(t *testing.T) {
+	srv := newTestServer(t)
+	c := srv.customer(t, srv.client(t, middleware.RoleOperator))
+	admin := srv.client(t, middleware.RoleAdmin)
+
+	cr, err := admin.AnonymizeCustomer(context.Background(), c.ID, "PDPA erasure request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr == nil || cr.Status != ChangePending || cr.CustomerID != c.ID || cr.Reason != "PDPA erasure request" {
+		t.Errorf("Expected a pending change request carrying the reason, got %+v", cr)
+	}
+}
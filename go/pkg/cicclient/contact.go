@@ -0,0 +1,90 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// --- Contact points ---
+
+// ListContactPoints returns the customer's phones, emails and other contact points.
+func (c *Client) ListContactPoints(ctx context.Context, customerID uuid.UUID) ([]*ContactPoint, error) {
+	return list[*ContactPoint](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "contact-points")})
+}
+
+// AddContactPoint adds a contact point to the customer.
+func (c *Client) AddContactPoint(ctx context.Context, customerID uuid.UUID, cp *ContactPoint) (*ContactPoint, error) {
+	return one[ContactPoint](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "contact-points"), body: cp})
+}
+
+// UpdateContactPoint replaces the contact point cp.ID.
+func (c *Client) UpdateContactPoint(ctx context.Context, customerID uuid.UUID, cp *ContactPoint) (*ContactPoint, error) {
+	return one[ContactPoint](ctx, c, &request{method: http.MethodPut, path: customerPath(customerID, "contact-points", cp.ID.String()), body: cp})
+}
+
+// RemoveContactPoint removes one of the customer's contact points.
+func (c *Client) RemoveContactPoint(ctx context.Context, customerID, contactID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "contact-points", contactID.String())}, nil)
+}
+
+// StartContactVerification sends a verification code to the contact point.
+func (c *Client) StartContactVerification(ctx context.Context, customerID, contactID uuid.UUID) (*ContactPoint, error) {
+	return one[ContactPoint](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "contact-points", contactID.String(), "verify")})
+}
+
+// ConfirmContactVerification marks the contact point verified when code matches the one sent.
+func (c *Client) ConfirmContactVerification(ctx context.Context, customerID, contactID uuid.UUID, code string) (*ContactPoint, error) {
+	return one[ContactPoint](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "contact-points", contactID.String(), "verify", "confirm"),
+		body: map[string]string{"code": code}})
+}
+
+// --- Bank accounts ---
+
+// ListBankAccounts returns the customer's bank accounts with masked account numbers.
+func (c *Client) ListBankAccounts(ctx context.Context, customerID uuid.UUID) ([]*BankAccount, error) {
+	return list[*BankAccount](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "bank-accounts")})
+}
+
+// AddBankAccount adds a bank account to the customer.
+func (c *Client) AddBankAccount(ctx context.Context, customerID uuid.UUID, b *BankAccount) (*BankAccount, error) {
+	return one[BankAccount](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "bank-accounts"), body: b})
+}
+
+// UpdateBankAccount replaces the bank account b.ID.
+func (c *Client) UpdateBankAccount(ctx context.Context, customerID uuid.UUID, b *BankAccount) (*BankAccount, error) {
+	return one[BankAccount](ctx, c, &request{method: http.MethodPut, path: customerPath(customerID, "bank-accounts", b.ID.String()), body: b})
+}
+
+// RemoveBankAccount removes one of the customer's bank accounts.
+func (c *Client) RemoveBankAccount(ctx context.Context, customerID, accountID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "bank-accounts", accountID.String())}, nil)
+}
+
+// RevealBankAccount returns the bank account with its full account number. The server audits
+// every reveal against the caller.
+func (c *Client) RevealBankAccount(ctx context.Context, customerID, accountID uuid.UUID) (*BankAccount, error) {
+	return one[BankAccount](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "bank-accounts", accountID.String(), "reveal")})
+}
+
+// --- Communication preferences ---
+
+// GetPreferences returns the customer's communication preferences.
+func (c *Client) GetPreferences(ctx context.Context, customerID uuid.UUID) (*CommunicationPreferences, error) {
+	return one[CommunicationPreferences](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "communication-preferences")})
+}
+
+// SavePreferences replaces the customer's communication preferences.
+func (c *Client) SavePreferences(ctx context.Context, customerID uuid.UUID, p *CommunicationPreferences) (*CommunicationPreferences, error) {
+	return one[CommunicationPreferences](ctx, c, &request{method: http.MethodPut, path: customerPath(customerID, "communication-preferences"), body: p})
+}
+
+// BestChannel picks the channel to reach the customer for purpose, now or at the given time.
+func (c *Client) BestChannel(ctx context.Context, customerID uuid.UUID, purpose CommPurpose, at *time.Time) (*ChannelDecision, error) {
+	q := url.Values{"purpose": {string(purpose)}}
+	setTime(q, "at", at)
+	return one[ChannelDecision](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "communication-preferences", "best-channel"), query: q})
+}
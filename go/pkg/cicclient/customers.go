@@ -0,0 +1,459 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const v1 = "/api/v1"
+
+func customerPath(id uuid.UUID, parts ...string) string {
+	return v1 + "/customers/" + id.String() + joinPath(parts...)
+}
+
+func joinPath(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(p))
+	}
+	return b.String()
+}
+
+// CustomerListOptions select, sort and page customers for ListCustomers and Customers.
+type CustomerListOptions struct {
+	Filter CustomerFilter
+	// All lists every customer when the filter is empty; otherwise an empty filter finds nothing.
+	All  bool
+	Sort []SortKey
+	// Limit is the page size, at most MaxCustomerPageSize.
+	Limit  int
+	Offset int
+	// Cursor is a NextCursor or PrevCursor of an earlier page. Cursor paging needs the default sort.
+	Cursor string
+	// Fields limits the customer fields returned, such as "id" and "first_name".
+	Fields []string
+}
+
+// searchText renders the filter's text and attribute matches as a typed search.
+func searchText(f CustomerFilter) string {
+	terms := []string{}
+	if f.Text != "" {
+		terms = append(terms, "name:"+quoteSearch(f.Text))
+	}
+	for _, t := range []struct{ field, value string }{
+		{"id", f.IdentityNumber}, {"phone", f.Phone}, {"email", f.Email},
+		{"zip", f.ZipCode}, {"province", f.Province}, {"district", f.District},
+	} {
+		if t.value != "" {
+			terms = append(terms, t.field+":"+quoteSearch(t.value))
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+func quoteSearch(v string) string {
+	if strings.ContainsAny(v, " \t") {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+func formatSort(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if k.Desc {
+			parts[i] = "-" + k.Field
+		} else {
+			parts[i] = k.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func setTime(q url.Values, key string, t *time.Time) {
+	if t != nil {
+		q.Set(key, t.Format(time.RFC3339))
+	}
+}
+
+func setInt(q url.Values, key string, n int) {
+	if n > 0 {
+		q.Set(key, strconv.Itoa(n))
+	}
+}
+
+func (o CustomerListOptions) query(cursorMode bool) url.Values {
+	q := url.Values{}
+	f := o.Filter
+	if text := searchText(f); text != "" {
+		q.Set("q", text)
+	} else if o.All {
+		q.Set("q", "*")
+	}
+	if f.Fuzzy {
+		q.Set("mode", "fuzzy")
+	}
+	for _, t := range f.Types {
+		q.Add("type", string(t))
+	}
+	for _, s := range f.Statuses {
+		q.Add("status", string(s))
+	}
+	for _, t := range f.Tiers {
+		q.Add("tier", t)
+	}
+	for _, n := range f.Nationalities {
+		q.Add("nationality", n)
+	}
+	if f.HighValue != nil {
+		q.Set("high_value", strconv.FormatBool(*f.HighValue))
+	}
+	setTime(q, "created_from", f.CreatedFrom)
+	setTime(q, "created_to", f.CreatedTo)
+	setTime(q, "updated_from", f.UpdatedFrom)
+	setTime(q, "updated_to", f.UpdatedTo)
+	setTime(q, "last_transaction_from", f.LastTransactionFrom)
+	setTime(q, "last_transaction_to", f.LastTransactionTo)
+	if len(o.Sort) > 0 {
+		q.Set("sort", formatSort(o.Sort))
+	}
+	setInt(q, "limit", o.Limit)
+	setInt(q, "offset", o.Offset)
+	if cursorMode {
+		q.Set("cursor", o.Cursor)
+	}
+	if len(o.Fields) > 0 {
+		q.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return q
+}
+
+// pageable reports whether the options page by cursor, which needs the default sort.
+func (o CustomerListOptions) pageable() bool {
+	if o.Filter.Fuzzy {
+		return false
+	}
+	return len(o.Sort) == 0 || (len(o.Sort) == 1 && o.Sort[0] == SortKey{Field: "created_at", Desc: true})
+}
+
+// ListCustomers returns one page of customers. Protected customers the caller may not read come
+// back withheld. The page has cursors when opts.Cursor is set or the default sort is used.
+func (c *Client) ListCustomers(ctx context.Context, opts CustomerListOptions) (*CustomerPage, error) {
+	cursorMode := opts.Cursor != "" || (opts.Offset == 0 && opts.pageable())
+	resp, err := c.do(ctx, &request{method: http.MethodGet, path: v1 + "/customers", query: opts.query(cursorMode)})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	page := &CustomerPage{}
+	if cursorMode {
+		if err := decodeBody(resp, page); err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+	if err := decodeBody(resp, &page.Customers); err != nil {
+		return nil, err
+	}
+	page.TotalCount, _ = strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	return page, nil
+}
+
+// Customers iterates over every customer matching opts, by cursor with the default sort and by
+// offset otherwise.
+func (c *Client) Customers(opts CustomerListOptions) *Iterator[*Customer] {
+	offset := opts.Offset
+	return newIterator(func(ctx context.Context, cursor string) ([]*Customer, string, error) {
+		o := opts
+		if opts.pageable() {
+			o.Offset, o.Cursor = 0, cursor
+			page, err := c.ListCustomers(ctx, o)
+			if err != nil {
+				return nil, "", err
+			}
+			return page.Customers, page.NextCursor, nil
+		}
+		o.Offset = offset
+		page, err := c.ListCustomers(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		offset += len(page.Customers)
+		next := ""
+		if len(page.Customers) > 0 && offset < page.TotalCount {
+			next = strconv.Itoa(offset)
+		}
+		return page.Customers, next, nil
+	})
+}
+
+// PageOptions page a cursor-paged list.
+type PageOptions struct {
+	Limit  int
+	Cursor string
+}
+
+func (p PageOptions) query() url.Values {
+	q := url.Values{}
+	setInt(q, "limit", p.Limit)
+	q.Set("cursor", p.Cursor)
+	return q
+}
+
+// ListDeletedCustomers returns a page of soft-deleted customers.
+func (c *Client) ListDeletedCustomers(ctx context.Context, opts PageOptions) (*Page[*Customer], error) {
+	q := opts.query()
+	q.Set("deleted", "true")
+	var page CustomerPage
+	if err := c.send(ctx, &request{method: http.MethodGet, path: v1 + "/customers", query: q}, &page); err != nil {
+		return nil, err
+	}
+	return &Page[*Customer]{Items: page.Customers, NextCursor: page.NextCursor, PrevCursor: page.PrevCursor}, nil
+}
+
+// DeletedCustomers iterates over every soft-deleted customer.
+func (c *Client) DeletedCustomers(limit int) *Iterator[*Customer] {
+	return newIterator(func(ctx context.Context, cursor string) ([]*Customer, string, error) {
+		page, err := c.ListDeletedCustomers(ctx, PageOptions{Limit: limit, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Items, page.NextCursor, nil
+	})
+}
+
+// SearchCustomers runs a typed search such as `zip:10110 name:somchai`.
+func (c *Client) SearchCustomers(ctx context.Context, query string) ([]*Customer, error) {
+	return list[*Customer](ctx, c, &request{method: http.MethodGet, path: v1 + "/customers/search", query: url.Values{"q": {query}}})
+}
+
+// FindCustomersByContact finds customers by phone number or email address.
+func (c *Client) FindCustomersByContact(ctx context.Context, phone, email string) ([]*Customer, error) {
+	q := url.Values{}
+	if phone != "" {
+		q.Set("phone", phone)
+	}
+	if email != "" {
+		q.Set("email", email)
+	}
+	return list[*Customer](ctx, c, &request{method: http.MethodGet, path: v1 + "/customers/by-contact", query: q})
+}
+
+func fieldsQuery(fields []string) url.Values {
+	if len(fields) == 0 {
+		return nil
+	}
+	return url.Values{"fields": {strings.Join(fields, ",")}}
+}
+
+// GetCustomer reads a customer. fields, when given, limits what is returned and may embed
+// sub-resources such as "addresses" or "addresses.zip_code"; embedded lists are not decoded
+// into Customer, use GetCustomerFields for them.
+func (c *Client) GetCustomer(ctx context.Context, id uuid.UUID, fields ...string) (*Customer, error) {
+	return one[Customer](ctx, c, &request{method: http.MethodGet, path: customerPath(id), query: fieldsQuery(fields)})
+}
+
+// GetCustomerFields reads the selected fields of a customer into v, for projections that embed
+// sub-resources.
+func (c *Client) GetCustomerFields(ctx context.Context, id uuid.UUID, fields []string, v interface{}) error {
+	return c.send(ctx, &request{method: http.MethodGet, path: customerPath(id), query: fieldsQuery(fields)}, v)
+}
+
+// GetCustomerByNumber reads a customer by customer number.
+func (c *Client) GetCustomerByNumber(ctx context.Context, number string) (*Customer, error) {
+	return one[Customer](ctx, c, &request{method: http.MethodGet, path: v1 + "/customers/by-number" + joinPath(number)})
+}
+
+// GetCustomerByRef reads a customer by its key in another system, such as CAS.
+func (c *Client) GetCustomerByRef(ctx context.Context, system, key string) (*Customer, error) {
+	return one[Customer](ctx, c, &request{method: http.MethodGet, path: v1 + "/customers/by-ref" + joinPath(system, key)})
+}
+
+// GetCustomer360 reads the customer with the given sections (addresses, identities,
+// relationships, consents, audit), or all of them when none are given.
+func (c *Client) GetCustomer360(ctx context.Context, id uuid.UUID, include ...string) (*Customer360, error) {
+	var q url.Values
+	if len(include) > 0 {
+		q = url.Values{"include": {strings.Join(include, ",")}}
+	}
+	return one[Customer360](ctx, c, &request{method: http.MethodGet, path: customerPath(id, "360"), query: q})
+}
+
+// TimelineOptions filter and page a customer timeline.
+type TimelineOptions struct {
+	// Types are event types such as "consent" or "address"; all when empty.
+	Types  []string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Cursor string
+}
+
+// GetTimeline returns a page of the customer's timeline, newest first.
+func (c *Client) GetTimeline(ctx context.Context, id uuid.UUID, opts TimelineOptions) (*TimelinePage, error) {
+	q := url.Values{}
+	if len(opts.Types) > 0 {
+		q.Set("type", strings.Join(opts.Types, ","))
+	}
+	setTime(q, "from", opts.From)
+	setTime(q, "to", opts.To)
+	setInt(q, "limit", opts.Limit)
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	return one[TimelinePage](ctx, c, &request{method: http.MethodGet, path: customerPath(id, "timeline"), query: q})
+}
+
+// Timeline iterates over the customer's whole timeline.
+func (c *Client) Timeline(id uuid.UUID, opts TimelineOptions) *Iterator[*TimelineEntry] {
+	return newIterator(func(ctx context.Context, cursor string) ([]*TimelineEntry, string, error) {
+		o := opts
+		if cursor != "" {
+			o.Cursor = cursor
+		}
+		page, err := c.GetTimeline(ctx, id, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Entries, page.NextCursor, nil
+	})
+}
+
+// CreateCustomer creates a customer and returns it as stored.
+func (c *Client) CreateCustomer(ctx context.Context, customer *Customer) (*Customer, error) {
+	return one[Customer](ctx, c, &request{method: http.MethodPost, path: v1 + "/customers", body: customer})
+}
+
+// UpdateCustomer replaces the customer with the given ID. Blacklisting may need approval: then
+// the customer is nil and the pending change request is returned instead.
+func (c *Client) UpdateCustomer(ctx context.Context, customer *Customer, reason string) (*Customer, *ChangeRequest, error) {
+	resp, err := c.do(ctx, &request{method: http.MethodPut, path: customerPath(customer.ID), query: reasonQuery(reason), body: customer})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusAccepted {
+		var cr ChangeRequest
+		if err := decodeBody(resp, &cr); err != nil {
+			return nil, nil, err
+		}
+		return nil, &cr, nil
+	}
+	var out Customer
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, nil, nil
+}
+
+// DeleteCustomer soft-deletes a customer. When deletion needs approval the pending change
+// request is returned; otherwise it is nil.
+func (c *Client) DeleteCustomer(ctx context.Context, id uuid.UUID, reason string) (*ChangeRequest, error) {
+	return c.sensitive(ctx, &request{method: http.MethodDelete, path: customerPath(id), query: reasonQuery(reason)})
+}
+
+// RestoreCustomer restores a soft-deleted customer. Records with no recorded deleter need a
+// justification. When restoring needs approval the pending change request is returned.
+func (c *Client) RestoreCustomer(ctx context.Context, id uuid.UUID, justification, reason string) (*ChangeRequest, error) {
+	r := &request{method: http.MethodPost, path: customerPath(id, "restore"), query: reasonQuery(reason)}
+	if justification != "" {
+		r.body = RestoreOverride{Justification: justification}
+	}
+	return c.sensitive(ctx, r)
+}
+
+// AnonymizeCustomer erases the customer's personal data. When this needs approval the pending
+// change request is returned.
+func (c *Client) AnonymizeCustomer(ctx context.Context, id uuid.UUID, reason string) (*ChangeRequest, error) {
+	return c.sensitive(ctx, &request{method: http.MethodPost, path: customerPath(id, "anonymize"), query: reasonQuery(reason)})
+}
+
+func reasonQuery(reason string) url.Values {
+	if reason == "" {
+		return nil
+	}
+	return url.Values{"reason": {reason}}
+}
+
+// sensitive performs a maker-checker operation, returning the change request when the server
+// answers 202 Accepted.
+func (c *Client) sensitive(ctx context.Context, r *request) (*ChangeRequest, error) {
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, nil
+	}
+	var cr ChangeRequest
+	if err := decodeBody(resp, &cr); err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// --- Sub-Resources ---
+
+// ListAddresses returns the customer's addresses.
+func (c *Client) ListAddresses(ctx context.Context, customerID uuid.UUID) ([]*Address, error) {
+	return list[*Address](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "addresses")})
+}
+
+// AddAddress adds an address to the customer.
+func (c *Client) AddAddress(ctx context.Context, customerID uuid.UUID, a *Address) (*Address, error) {
+	return one[Address](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "addresses"), body: a})
+}
+
+// RemoveAddress removes one of the customer's addresses.
+func (c *Client) RemoveAddress(ctx context.Context, customerID, addressID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "addresses", addressID.String())}, nil)
+}
+
+// ListIdentities returns the customer's identity documents.
+func (c *Client) ListIdentities(ctx context.Context, customerID uuid.UUID) ([]*Identity, error) {
+	return list[*Identity](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "identities")})
+}
+
+// AddIdentity adds an identity document to the customer.
+func (c *Client) AddIdentity(ctx context.Context, customerID uuid.UUID, i *Identity) (*Identity, error) {
+	return one[Identity](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "identities"), body: i})
+}
+
+// RemoveIdentity removes one of the customer's identity documents.
+func (c *Client) RemoveIdentity(ctx context.Context, customerID, identityID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "identities", identityID.String())}, nil)
+}
+
+// ListRelationships returns the relationships from the customer.
+func (c *Client) ListRelationships(ctx context.Context, customerID uuid.UUID) ([]*Relationship, error) {
+	return list[*Relationship](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "relationships")})
+}
+
+// AddRelationship relates the customer to rel.ToCustomerID.
+func (c *Client) AddRelationship(ctx context.Context, customerID uuid.UUID, rel *Relationship) (*Relationship, error) {
+	return one[Relationship](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "relationships"), body: rel})
+}
+
+// RemoveRelationship removes one of the customer's relationships.
+func (c *Client) RemoveRelationship(ctx context.Context, customerID, relID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "relationships", relID.String())}, nil)
+}
+
+// ListCustomerConsents returns the customer's consents.
+func (c *Client) ListCustomerConsents(ctx context.Context, customerID uuid.UUID) ([]*Consent, error) {
+	return list[*Consent](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "consents")})
+}
+
+// ManageConsent grants or withdraws consent for a topic.
+func (c *Client) ManageConsent(ctx context.Context, customerID uuid.UUID, consent *Consent) (*Consent, error) {
+	return one[Consent](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "consents"), body: consent})
+}
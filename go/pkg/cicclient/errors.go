@@ -0,0 +1,94 @@
+package cicclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Errors an *Error matches with errors.Is, by status code.
+var (
+	ErrBadRequest    = errors.New("bad request")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrRateLimited   = errors.New("rate limited")
+	ErrServer        = errors.New("server error")
+
+	// ErrAccessGrantRequired is the ErrForbidden of a protected customer read; request access
+	// with RequestAccess.
+	ErrAccessGrantRequired = errors.New("access grant required for protected customer")
+)
+
+// Error is an API call the server answered with an error status.
+type Error struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// Message is the server's error text.
+	Message string
+	// Body is the raw answer, for errors that carry a JSON document such as a failed change request.
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("cicclient: %s %s: %d %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrAccessGrantRequired:
+		return e.StatusCode == http.StatusForbidden && strings.Contains(e.Message, "protected customer")
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// Decode reads the error's JSON body into v.
+func (e *Error) Decode(v interface{}) error {
+	return json.Unmarshal(e.Body, v)
+}
+
+// newError reads and closes an error response. The server answers in plain text or as
+// {"error": "..."}.
+func newError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL.Path,
+		Body:       body,
+		Message:    strings.TrimSpace(string(body)),
+	}
+	var wrapped struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &wrapped) == nil && wrapped.Error != "" {
+		e.Message = wrapped.Error
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,74 @@
+package cicclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GraphQLRequest is a GraphQL query with its variables.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLError is one error in a GraphQL answer.
+type GraphQLError struct {
+	Message   string                   `json:"message"`
+	Path      []interface{}            `json:"path,omitempty"`
+	Locations []map[string]interface{} `json:"locations,omitempty"`
+}
+
+// GraphQLErrors are the errors of a GraphQL answer. A query rejected before it ran also matches
+// ErrBadRequest.
+type GraphQLErrors struct {
+	Errors []GraphQLError
+	// rejected is the 400 of a query that did not parse, validate or fit the limits.
+	rejected *Error
+}
+
+func (e *GraphQLErrors) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Message
+	}
+	return "cicclient: graphql: " + strings.Join(msgs, "; ")
+}
+
+func (e *GraphQLErrors) Unwrap() error {
+	if e.rejected == nil {
+		return nil
+	}
+	return e.rejected
+}
+
+// GraphQL runs a query and decodes its data into out. When the answer has errors it returns
+// *GraphQLErrors, having still decoded whatever data came back. The endpoint is read-only, so
+// the call is retried like a read.
+func (c *Client) GraphQL(ctx context.Context, req GraphQLRequest, out interface{}) error {
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	err := c.send(ctx, &request{method: http.MethodPost, path: v1 + "/graphql", body: req, idempotent: true}, &res)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && apiErr.Decode(&res) == nil && len(res.Errors) > 0 {
+		return &GraphQLErrors{Errors: res.Errors, rejected: apiErr}
+	}
+	if err != nil {
+		return err
+	}
+	if out != nil && len(res.Data) > 0 && string(res.Data) != "null" {
+		if err := json.Unmarshal(res.Data, out); err != nil {
+			return fmt.Errorf("cicclient: decoding graphql data: %w", err)
+		}
+	}
+	if len(res.Errors) > 0 {
+		return &GraphQLErrors{Errors: res.Errors}
+	}
+	return nil
+}
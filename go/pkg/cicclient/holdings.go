@@ -0,0 +1,91 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// --- Holdings and products ---
+
+// ListHoldings returns the customer's product holdings.
+func (c *Client) ListHoldings(ctx context.Context, customerID uuid.UUID) ([]*Holding, error) {
+	return list[*Holding](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "holdings")})
+}
+
+// AddHolding adds a product holding to the customer.
+func (c *Client) AddHolding(ctx context.Context, customerID uuid.UUID, h *Holding) (*Holding, error) {
+	return one[Holding](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "holdings"), body: h})
+}
+
+// UpdateHolding replaces the holding h.ID.
+func (c *Client) UpdateHolding(ctx context.Context, customerID uuid.UUID, h *Holding) (*Holding, error) {
+	return one[Holding](ctx, c, &request{method: http.MethodPut, path: customerPath(customerID, "holdings", h.ID.String()), body: h})
+}
+
+// RemoveHolding removes one of the customer's holdings.
+func (c *Client) RemoveHolding(ctx context.Context, customerID, holdingID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "holdings", holdingID.String())}, nil)
+}
+
+// ListProducts returns the products on offer, or every product when all is set.
+func (c *Client) ListProducts(ctx context.Context, all bool) ([]*Product, error) {
+	q := url.Values{}
+	if all {
+		q.Set("all", "true")
+	}
+	return list[*Product](ctx, c, &request{method: http.MethodGet, path: v1 + "/products", query: q})
+}
+
+// SaveProduct creates or replaces the product p.Code.
+func (c *Client) SaveProduct(ctx context.Context, p *Product) (*Product, error) {
+	return one[Product](ctx, c, &request{method: http.MethodPut, path: v1 + "/products" + joinPath(p.Code), body: p})
+}
+
+// ListFXRates returns the conversion rates to THB.
+func (c *Client) ListFXRates(ctx context.Context) ([]*FXRate, error) {
+	return list[*FXRate](ctx, c, &request{method: http.MethodGet, path: v1 + "/fx-rates"})
+}
+
+// SetFXRate creates or replaces the THB rate for rate.Currency.
+func (c *Client) SetFXRate(ctx context.Context, rate *FXRate) (*FXRate, error) {
+	return one[FXRate](ctx, c, &request{method: http.MethodPut, path: v1 + "/fx-rates" + joinPath(rate.Currency), body: rate})
+}
+
+// --- Points ---
+
+// ListPointsTransactions returns the customer's points ledger, newest first.
+func (c *Client) ListPointsTransactions(ctx context.Context, customerID uuid.UUID, opts ListOptions) ([]*PointsTransaction, error) {
+	return list[*PointsTransaction](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "points", "transactions"), query: opts.query()})
+}
+
+// PostPointsTransaction earns, burns, adjusts or reverses points. With an IdempotencyKey the
+// call is retried like a read, since the server answers a replay with the original transaction.
+func (c *Client) PostPointsTransaction(ctx context.Context, customerID uuid.UUID, req PointsRequest) (*PointsTransaction, error) {
+	r := &request{method: http.MethodPost, path: customerPath(customerID, "points", "transactions"), body: req}
+	if req.IdempotencyKey != "" {
+		r.header = http.Header{"Idempotency-Key": {req.IdempotencyKey}}
+		r.idempotent = true
+	}
+	return one[PointsTransaction](ctx, c, r)
+}
+
+// --- Tiers ---
+
+// GetTierRules returns the active tier and high-value rules.
+func (c *Client) GetTierRules(ctx context.Context) (*TierRules, error) {
+	return one[TierRules](ctx, c, &request{method: http.MethodGet, path: v1 + "/tiers/rules"})
+}
+
+// GetTierHistory returns the customer's tier changes, newest first.
+func (c *Client) GetTierHistory(ctx context.Context, customerID uuid.UUID, opts ListOptions) ([]*TierChange, error) {
+	return list[*TierChange](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "tier-history"), query: opts.query()})
+}
+
+// RecalculateTiers re-derives every customer's tier with the current rules. It fails with
+// ErrConflict while a recalculation is already running.
+func (c *Client) RecalculateTiers(ctx context.Context) (*TierRecalcResult, error) {
+	return one[TierRecalcResult](ctx, c, &request{method: http.MethodPost, path: v1 + "/tiers/recalculate"})
+}
@@ -0,0 +1,57 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// ImportOptions configure a CAS import run.
+type ImportOptions struct {
+	DryRun bool `json:"dry_run"`
+	// ResumeRunID continues a failed or interrupted run from its cursor instead of starting over.
+	ResumeRunID *uuid.UUID `json:"resume_run_id,omitempty"`
+	// Limit stops the run after this many source records; 0 means no limit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// StartCASImport starts importing customers from CAS. The run continues on the server; poll
+// it with GetImport. It fails with ErrConflict while another run is in progress.
+func (c *Client) StartCASImport(ctx context.Context, opts ImportOptions) (*ImportRun, error) {
+	return one[ImportRun](ctx, c, &request{method: http.MethodPost, path: v1 + "/imports/cas", body: opts})
+}
+
+// ListImports returns the import runs, newest first.
+func (c *Client) ListImports(ctx context.Context, opts ListOptions) ([]*ImportRun, error) {
+	return list[*ImportRun](ctx, c, &request{method: http.MethodGet, path: v1 + "/imports", query: opts.query()})
+}
+
+// GetImport reads an import run.
+func (c *Client) GetImport(ctx context.Context, id uuid.UUID) (*ImportRun, error) {
+	return one[ImportRun](ctx, c, &request{method: http.MethodGet, path: v1 + "/imports" + joinPath(id.String())})
+}
+
+// GetImportRejects returns the source records an import run could not load.
+func (c *Client) GetImportRejects(ctx context.Context, id uuid.UUID) ([]*ImportReject, error) {
+	return list[*ImportReject](ctx, c, &request{method: http.MethodGet, path: v1 + "/imports" + joinPath(id.String(), "rejects")})
+}
+
+// ExportImportRejects returns the source records an import run could not load as CSV.
+func (c *Client) ExportImportRejects(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	return c.raw(ctx, &request{method: http.MethodGet, path: v1 + "/imports" + joinPath(id.String(), "rejects"),
+		query: url.Values{"format": {"csv"}}, header: http.Header{"Accept": {"text/csv"}}})
+}
+
+// BackfillCustomerNumbers assigns customer numbers to customers without one and returns how
+// many it assigned.
+func (c *Client) BackfillCustomerNumbers(ctx context.Context) (int, error) {
+	var res struct {
+		Assigned int `json:"assigned"`
+	}
+	if err := c.send(ctx, &request{method: http.MethodPost, path: v1 + "/customer-numbers/backfill"}, &res); err != nil {
+		return 0, err
+	}
+	return res.Assigned, nil
+}
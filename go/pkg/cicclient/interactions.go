@@ -0,0 +1,45 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// ListInteractions returns the customer's interactions matching f.
+func (c *Client) ListInteractions(ctx context.Context, customerID uuid.UUID, f InteractionFilter) ([]*Interaction, error) {
+	q := url.Values{}
+	if f.Channel != "" {
+		q.Set("channel", string(f.Channel))
+	}
+	if f.PinnedOnly {
+		q.Set("pinned", "true")
+	}
+	setTime(q, "from", f.From)
+	setTime(q, "to", f.To)
+	setInt(q, "limit", f.Limit)
+	setInt(q, "offset", f.Offset)
+	return list[*Interaction](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "interactions"), query: q})
+}
+
+// GetInteraction reads one of the customer's interactions.
+func (c *Client) GetInteraction(ctx context.Context, customerID, interactionID uuid.UUID) (*Interaction, error) {
+	return one[Interaction](ctx, c, &request{method: http.MethodGet, path: customerPath(customerID, "interactions", interactionID.String())})
+}
+
+// CreateInteraction records an interaction with the customer.
+func (c *Client) CreateInteraction(ctx context.Context, customerID uuid.UUID, i *Interaction) (*Interaction, error) {
+	return one[Interaction](ctx, c, &request{method: http.MethodPost, path: customerPath(customerID, "interactions"), body: i})
+}
+
+// UpdateInteraction replaces the interaction i.ID.
+func (c *Client) UpdateInteraction(ctx context.Context, customerID uuid.UUID, i *Interaction) (*Interaction, error) {
+	return one[Interaction](ctx, c, &request{method: http.MethodPut, path: customerPath(customerID, "interactions", i.ID.String()), body: i})
+}
+
+// DeleteInteraction deletes one of the customer's interactions.
+func (c *Client) DeleteInteraction(ctx context.Context, customerID, interactionID uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: customerPath(customerID, "interactions", interactionID.String())}, nil)
+}
@@ -0,0 +1,61 @@
+package cicclient
+
+import "context"
+
+// Iterator walks a list page by page, fetching the next page when the current one is used up:
+//
+//	it := c.Customers(opts)
+//	for it.Next(ctx) {
+//		fmt.Println(it.Item().ID)
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, cursor string) (items []T, next string, err error)
+	items []T
+	item  T
+	next  string
+	done  bool
+	err   error
+}
+
+// newIterator pages with fetch, starting from an empty cursor and stopping when it returns no
+// next cursor.
+func newIterator[T any](fetch func(ctx context.Context, cursor string) ([]T, string, error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch}
+}
+
+// Next advances to the next item, fetching a page if needed. It returns false at the end of the
+// list or on error.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.items, it.next, it.err = it.fetch(ctx, it.next)
+		if it.err != nil {
+			return false
+		}
+		it.done = it.next == ""
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Item is the current item.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err is the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All drains the iterator into a slice.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var out []T
+	for it.Next(ctx) {
+		out = append(out, it.Item())
+	}
+	return out, it.Err()
+}
@@ -0,0 +1,107 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// --- Delegations ---
+
+// ListDelegations returns the delegations the caller gave or received.
+func (c *Client) ListDelegations(ctx context.Context) ([]*Delegation, error) {
+	return list[*Delegation](ctx, c, &request{method: http.MethodGet, path: v1 + "/delegations"})
+}
+
+// CreateDelegation delegates the caller's approvals to d.DelegateID for a period.
+func (c *Client) CreateDelegation(ctx context.Context, d *Delegation) (*Delegation, error) {
+	return one[Delegation](ctx, c, &request{method: http.MethodPost, path: v1 + "/delegations", body: d})
+}
+
+// RevokeDelegation ends a delegation early.
+func (c *Client) RevokeDelegation(ctx context.Context, id uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: v1 + "/delegations" + joinPath(id.String())}, nil)
+}
+
+// --- Branches and teams ---
+
+// ListBranches returns every branch.
+func (c *Client) ListBranches(ctx context.Context) ([]*Branch, error) {
+	return list[*Branch](ctx, c, &request{method: http.MethodGet, path: v1 + "/org/branches"})
+}
+
+// CreateBranch creates a branch.
+func (c *Client) CreateBranch(ctx context.Context, b *Branch) (*Branch, error) {
+	return one[Branch](ctx, c, &request{method: http.MethodPost, path: v1 + "/org/branches", body: b})
+}
+
+// ListTeams returns the teams, of one branch when branchID is not nil.
+func (c *Client) ListTeams(ctx context.Context, branchID *uuid.UUID) ([]*Team, error) {
+	q := url.Values{}
+	if branchID != nil {
+		q.Set("branch_id", branchID.String())
+	}
+	return list[*Team](ctx, c, &request{method: http.MethodGet, path: v1 + "/org/teams", query: q})
+}
+
+// CreateTeam creates a team.
+func (c *Client) CreateTeam(ctx context.Context, t *Team) (*Team, error) {
+	return one[Team](ctx, c, &request{method: http.MethodPost, path: v1 + "/org/teams", body: t})
+}
+
+// --- Users ---
+
+// CreateUserRequest is a new user account.
+type CreateUserRequest struct {
+	Email        string     `json:"email"`
+	Username     string     `json:"username"`
+	Password     string     `json:"password"`
+	Role         string     `json:"role"`
+	SupervisorID *uuid.UUID `json:"supervisor_id,omitempty"`
+}
+
+// UpdateUserRequest changes the fields that are not nil.
+type UpdateUserRequest struct {
+	Email        *string    `json:"email,omitempty"`
+	Role         *string    `json:"role,omitempty"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+	SupervisorID *uuid.UUID `json:"supervisor_id,omitempty"`
+}
+
+// ListUsers returns every user account.
+func (c *Client) ListUsers(ctx context.Context) ([]*User, error) {
+	return list[*User](ctx, c, &request{method: http.MethodGet, path: v1 + "/users"})
+}
+
+// GetUser reads a user account.
+func (c *Client) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	return one[User](ctx, c, &request{method: http.MethodGet, path: v1 + "/users" + joinPath(id.String())})
+}
+
+// CreateUser creates a user account. The answer carries the ID, email, username and role.
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*User, error) {
+	return one[User](ctx, c, &request{method: http.MethodPost, path: v1 + "/users", body: req})
+}
+
+// UpdateUser changes a user account.
+func (c *Client) UpdateUser(ctx context.Context, id uuid.UUID, req UpdateUserRequest) error {
+	return c.send(ctx, &request{method: http.MethodPut, path: v1 + "/users" + joinPath(id.String()), body: req}, nil)
+}
+
+// DeactivateUser disables a user account.
+func (c *Client) DeactivateUser(ctx context.Context, id uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: v1 + "/users" + joinPath(id.String())}, nil)
+}
+
+// AssignUser moves a user to a team and branch; nil clears either.
+func (c *Client) AssignUser(ctx context.Context, id uuid.UUID, teamID, branchID *uuid.UUID) error {
+	body := map[string]*uuid.UUID{"team_id": teamID, "branch_id": branchID}
+	return c.send(ctx, &request{method: http.MethodPut, path: v1 + "/users" + joinPath(id.String(), "org"), body: body}, nil)
+}
+
+// GetSupervisors returns the user's chain of supervisors, nearest first.
+func (c *Client) GetSupervisors(ctx context.Context, id uuid.UUID) ([]*User, error) {
+	return list[*User](ctx, c, &request{method: http.MethodGet, path: v1 + "/users" + joinPath(id.String(), "supervisors")})
+}
@@ -0,0 +1,101 @@
+package cicclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+func segmentPath(id uuid.UUID, parts ...string) string {
+	return v1 + "/segments/" + id.String() + joinPath(parts...)
+}
+
+// ListSegments returns the segments the caller can see.
+func (c *Client) ListSegments(ctx context.Context) ([]*Segment, error) {
+	return list[*Segment](ctx, c, &request{method: http.MethodGet, path: v1 + "/segments"})
+}
+
+// GetSegment reads a segment.
+func (c *Client) GetSegment(ctx context.Context, id uuid.UUID) (*Segment, error) {
+	return one[Segment](ctx, c, &request{method: http.MethodGet, path: segmentPath(id)})
+}
+
+// CreateSegment saves a segment.
+func (c *Client) CreateSegment(ctx context.Context, s *Segment) (*Segment, error) {
+	return one[Segment](ctx, c, &request{method: http.MethodPost, path: v1 + "/segments", body: s})
+}
+
+// UpdateSegment replaces the segment s.ID.
+func (c *Client) UpdateSegment(ctx context.Context, s *Segment) (*Segment, error) {
+	return one[Segment](ctx, c, &request{method: http.MethodPut, path: segmentPath(s.ID), body: s})
+}
+
+// DeleteSegment deletes a segment.
+func (c *Client) DeleteSegment(ctx context.Context, id uuid.UUID) error {
+	return c.send(ctx, &request{method: http.MethodDelete, path: segmentPath(id)}, nil)
+}
+
+// SegmentPageOptions page the customers of a segment. Cursors need the default sort.
+type SegmentPageOptions struct {
+	Sort   []SortKey
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+func (o SegmentPageOptions) query() url.Values {
+	q := url.Values{}
+	if len(o.Sort) > 0 {
+		q.Set("sort", formatSort(o.Sort))
+	}
+	setInt(q, "limit", o.Limit)
+	setInt(q, "offset", o.Offset)
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// EvaluateSegment runs the segment's filter now and returns a page of its customers. Protected
+// customers the caller may not read come back withheld.
+func (c *Client) EvaluateSegment(ctx context.Context, id uuid.UUID, opts SegmentPageOptions) (*CustomerPage, error) {
+	return one[CustomerPage](ctx, c, &request{method: http.MethodGet, path: segmentPath(id, "customers"), query: opts.query()})
+}
+
+// SegmentCustomers iterates over every customer currently in the segment.
+func (c *Client) SegmentCustomers(id uuid.UUID, limit int) *Iterator[*Customer] {
+	return newIterator(func(ctx context.Context, cursor string) ([]*Customer, string, error) {
+		page, err := c.EvaluateSegment(ctx, id, SegmentPageOptions{Limit: limit, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Customers, page.NextCursor, nil
+	})
+}
+
+// ExportSegment returns every customer currently in the segment as CSV.
+func (c *Client) ExportSegment(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	return c.raw(ctx, &request{method: http.MethodGet, path: segmentPath(id, "export"), header: http.Header{"Accept": {"text/csv"}}})
+}
+
+// MaterializeSegment records the segment's current members as a new run.
+func (c *Client) MaterializeSegment(ctx context.Context, id uuid.UUID) (*SegmentRun, error) {
+	return one[SegmentRun](ctx, c, &request{method: http.MethodPost, path: segmentPath(id, "materialize")})
+}
+
+// ListSegmentRuns returns the segment's runs, newest first.
+func (c *Client) ListSegmentRuns(ctx context.Context, id uuid.UUID, opts ListOptions) ([]*SegmentRun, error) {
+	return list[*SegmentRun](ctx, c, &request{method: http.MethodGet, path: segmentPath(id, "runs"), query: opts.query()})
+}
+
+// GetSegmentChanges returns who joined and left the segment in a run, compared with the run
+// from, or with the run before it when from is nil.
+func (c *Client) GetSegmentChanges(ctx context.Context, id, runID uuid.UUID, from *uuid.UUID) (*SegmentDiff, error) {
+	q := url.Values{}
+	if from != nil {
+		q.Set("from", from.String())
+	}
+	return one[SegmentDiff](ctx, c, &request{method: http.MethodGet, path: segmentPath(id, "runs", runID.String(), "changes"), query: q})
+}
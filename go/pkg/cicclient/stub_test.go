@@ -0,0 +1,225 @@
+package cicclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// These tests run the client against an httptest stub standing in for the server, so they need
+// no database. The tests in client_test.go run the real routes.
+
+// stubServer answers every request with handle, counting them.
+type stubServer struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+func newStubServer(t *testing.T, handle http.HandlerFunc) *stubServer {
+	t.Helper()
+	s := &stubServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		handle(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestStub_RetriesIdempotentCallsWithBackoff(t *testing.T) {
+	var failures atomic.Int32
+	id := uuid.New()
+	srv := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		writeJSON(w, Customer{ID: id, FirstName: "Somchai"})
+	})
+	client := New(srv.URL, WithToken("t"), fastRetries)
+	ctx := context.Background()
+
+	failures.Store(2)
+	if got, err := client.GetCustomer(ctx, id); err != nil || got.ID != id {
+		t.Fatalf("Expected the read to succeed on the third attempt, got %v %v", got, err)
+	}
+	if n := srv.requests.Load(); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+
+	failures.Store(3)
+	if _, err := client.GetCustomer(ctx, id); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited once the attempts run out, got %v", err)
+	}
+
+	failures.Store(1)
+	srv.requests.Store(0)
+	if _, err := client.CreateCustomer(ctx, &Customer{Type: TypePersonal}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected the create to fail without a retry, got %v", err)
+	}
+	if n := srv.requests.Load(); n != 1 {
+		t.Errorf("Expected a single attempt for a create, got %d", n)
+	}
+
+	// A cancelled context stops the retries.
+	failures.Store(100)
+	srv.requests.Store(0)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := New(srv.URL, WithToken("t")).GetCustomer(cancelled, id); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+	if n := srv.requests.Load(); n > 1 {
+		t.Errorf("Expected no retries after cancellation, got %d attempts", n)
+	}
+}
+
+func TestStub_Backoff(t *testing.T) {
+	c := New("http://cic.invalid", fastRetries)
+	for attempt := 1; attempt <= 8; attempt++ {
+		d := c.backoff(attempt)
+		if d <= 0 || d > c.retry.MaxBackoff {
+			t.Errorf("backoff(%d) = %v, want within (0, %v]", attempt, d, c.retry.MaxBackoff)
+		}
+	}
+	if d := c.backoff(1); d > c.retry.MinBackoff {
+		t.Errorf("backoff(1) = %v, want at most %v", d, c.retry.MinBackoff)
+	}
+}
+
+func TestStub_TypedErrors(t *testing.T) {
+	// Each customer ID is answered with its own status.
+	codes := map[string]int{}
+	srv := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		code := codes[path.Base(r.URL.Path)]
+		switch code {
+		case http.StatusForbidden:
+			http.Error(w, "access grant required for protected customer", code)
+		case http.StatusConflict:
+			w.WriteHeader(code)
+			writeJSON(w, map[string]string{"error": "customer number taken"})
+		default:
+			w.WriteHeader(code)
+		}
+	})
+	client := New(srv.URL, WithToken("t"), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+	get := func(code int) error {
+		id := uuid.New()
+		codes[id.String()] = code
+		_, err := client.GetCustomer(ctx, id)
+		return err
+	}
+
+	err := get(http.StatusNotFound)
+	var apiErr *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Not Found" {
+		t.Errorf("Expected a 404 *Error matching ErrNotFound, got %v", err)
+	}
+	if err := get(http.StatusForbidden); !errors.Is(err, ErrAccessGrantRequired) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrAccessGrantRequired for a protected customer, got %v", err)
+	}
+	if err := get(http.StatusConflict); !errors.Is(err, ErrConflict) || !errors.As(err, &apiErr) || apiErr.Message != "customer number taken" {
+		t.Errorf("Expected ErrConflict with the JSON error message, got %v", err)
+	}
+	if err := get(http.StatusInternalServerError); !errors.Is(err, ErrServer) || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrServer only, got %v", err)
+	}
+}
+
+func TestStub_LogsInAgainWhenTokenIsRejected(t *testing.T) {
+	var logins atomic.Int32
+	srv := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/auth/login" {
+			n := logins.Add(1)
+			writeJSON(w, LoginResult{Token: "token-" + strconv.Itoa(int(n))})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, User{Username: "somchai"})
+	})
+	client := New(srv.URL, WithLogin("somchai", "s3cret"))
+
+	me, err := client.Me(context.Background())
+	if err != nil || me.Username != "somchai" {
+		t.Fatalf("Expected the call to succeed after logging in again, got %v %v", me, err)
+	}
+	if n := logins.Load(); n != 2 {
+		t.Errorf("Expected two logins, got %d", n)
+	}
+
+	// Without credentials to log in with, a rejected token is final.
+	if _, err := New(srv.URL, WithToken("revoked")).Me(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestStub_IteratesCursorAndOffsetPages(t *testing.T) {
+	var all []*Customer
+	for i := 0; i < 5; i++ {
+		all = append(all, &Customer{ID: uuid.New()})
+	}
+	srv := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		start, _ := strconv.Atoi(q.Get("offset"))
+		if cursor := q.Get("cursor"); cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		if q.Has("cursor") {
+			page := CustomerPage{Customers: all[start:end]}
+			if end < len(all) {
+				page.NextCursor = strconv.Itoa(end)
+			}
+			writeJSON(w, page)
+			return
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(all)))
+		writeJSON(w, all[start:end])
+	})
+	client := New(srv.URL, WithToken("t"))
+	ctx := context.Background()
+
+	for _, opts := range []CustomerListOptions{
+		{All: true, Limit: 2},
+		{All: true, Limit: 2, Sort: []SortKey{{Field: "last_name"}}},
+	} {
+		srv.requests.Store(0)
+		got, err := client.Customers(opts).All(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(all) {
+			t.Fatalf("Expected %d customers across the pages, got %d", len(all), len(got))
+		}
+		for i, c := range got {
+			if c.ID != all[i].ID {
+				t.Errorf("Customer %d: got %s, want %s", i, c.ID, all[i].ID)
+			}
+		}
+		if n := srv.requests.Load(); n != 3 {
+			t.Errorf("Expected 3 page requests, got %d", n)
+		}
+	}
+}
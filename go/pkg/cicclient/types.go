@@ -0,0 +1,780 @@
+package cicclient
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// The types below are the API's JSON documents. They are copies rather than aliases of the
+// server's types because those live in internal/core/domain and internal/models, which Go does
+// not let code outside this module import. They mirror the server's wire format field for field
+// and carry no server logic; TestWireTypesMatchServer fails when the two drift apart.
+
+// MaxCustomerPageSize is the largest page ListCustomers may ask for.
+const MaxCustomerPageSize = 200
+
+type CustomerType string
+
+const (
+	TypePersonal CustomerType = "PERSONAL"
+	TypeJuristic CustomerType = "JURISTIC"
+)
+
+type CustomerStatus string
+
+const (
+	StatusActive    CustomerStatus = "ACTIVE"
+	StatusInactive  CustomerStatus = "INACTIVE"
+	StatusSuspended CustomerStatus = "SUSPENDED"
+	StatusDeceased  CustomerStatus = "DECEASED"
+	StatusBlacklist CustomerStatus = "BLACKLISTED"
+)
+
+type Customer struct {
+	ID             uuid.UUID    `json:"id"`
+	CustomerNumber string       `json:"customer_number,omitempty"` // Assigned on create, never changes
+	Type           CustomerType `json:"type"`
+
+	// Personal Fields
+	FirstName   string    `json:"first_name,omitempty"`
+	LastName    string    `json:"last_name,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	DateOfBirth time.Time `json:"date_of_birth,omitempty"`
+	Nationality string    `json:"nationality,omitempty"`
+
+	// Juristic Fields
+	CompanyName      string    `json:"company_name,omitempty"`
+	RegistrationDate time.Time `json:"registration_date,omitempty"`
+	IndustryCode     string    `json:"industry_code,omitempty"`
+
+	// Business Profile
+	Status              CustomerStatus  `json:"status"`
+	MembershipTier      string          `json:"membership_tier"`
+	PointsBalance       decimal.Decimal `json:"points_balance"`
+	CLV                 decimal.Decimal `json:"clv"`
+	PortfolioSize       decimal.Decimal `json:"portfolio_size"`
+	PortfolioValues     []Money         `json:"portfolio_values,omitempty"`
+	LastTransactionDate *time.Time      `json:"last_transaction_date"`
+	PreferredChannel    string          `json:"preferred_channel"`
+	IsHighValue         bool            `json:"is_high_value"`
+
+	// Currency is the currency of CLV and PortfolioSize; the server's base currency when empty.
+	Currency string `json:"currency,omitempty"`
+
+	// ExternalRefs are the customer's keys in other systems. Omitted on update to leave them unchanged.
+	ExternalRefs []ExternalRef `json:"external_refs,omitempty"`
+
+	// SearchScore is how closely the name matched a fuzzy search, from 0 to 1.
+	SearchScore float64 `json:"search_score,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *uuid.UUID `json:"deleted_by,omitempty"`
+}
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// ExternalRef records that a customer is known as Key in another system, such as CAS.
+type ExternalRef struct {
+	ID         uuid.UUID `json:"id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	System     string    `json:"system"`
+	Key        string    `json:"key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Address struct {
+	ID         uuid.UUID `json:"id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Type       string    `json:"type"` // Registered, Mailing, HQ
+
+	AddressLine1 string `json:"address_line1"`
+	AddressLine2 string `json:"address_line2"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	District     string `json:"district"`
+	SubDistrict  string `json:"sub_district"`
+	ZipCode      string `json:"zip_code"`
+	Country      string `json:"country"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+}
+
+type Identity struct {
+	ID              uuid.UUID `json:"id"`
+	CustomerID      uuid.UUID `json:"customer_id"`
+	Type            string    `json:"type"` // Passport, National ID, Tax ID
+	Number          string    `json:"number"`
+	IssuanceCountry string    `json:"issuance_country"`
+	ExpiryDate      time.Time `json:"expiry_date"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+}
+
+type Relationship struct {
+	ID             uuid.UUID `json:"id"`
+	FromCustomerID uuid.UUID `json:"from_customer_id"`
+	ToCustomerID   uuid.UUID `json:"to_customer_id"`
+	Role           string    `json:"role"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+}
+
+type Consent struct {
+	ID         uuid.UUID `json:"id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Topic      string    `json:"topic"`
+	Version    string    `json:"version"`
+	IsGranted  bool      `json:"is_granted"`
+	Timestamp  time.Time `json:"timestamp"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+}
+
+// CustomerFilter selects customers. Empty fields do not filter; list fields match any value
+// given. Ranges are inclusive of From and exclusive of To.
+type CustomerFilter struct {
+	Text          string           `json:"text,omitempty"`  // Name or customer number
+	Fuzzy         bool             `json:"fuzzy,omitempty"` // Match Text tolerating spelling and script
+	Types         []CustomerType   `json:"types,omitempty"`
+	Statuses      []CustomerStatus `json:"statuses,omitempty"`
+	Tiers         []string         `json:"tiers,omitempty"`
+	Nationalities []string         `json:"nationalities,omitempty"`
+	HighValue     *bool            `json:"high_value,omitempty"`
+
+	// Attribute matches against the customer's identities, contact points and addresses.
+	// Address fields must all match the same address.
+	IdentityNumber string `json:"identity_number,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	Email          string `json:"email,omitempty"`
+	ZipCode        string `json:"zip_code,omitempty"`
+	Province       string `json:"province,omitempty"`
+	District       string `json:"district,omitempty"`
+
+	CreatedFrom         *time.Time `json:"created_from,omitempty"`
+	CreatedTo           *time.Time `json:"created_to,omitempty"`
+	UpdatedFrom         *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo           *time.Time `json:"updated_to,omitempty"`
+	LastTransactionFrom *time.Time `json:"last_transaction_from,omitempty"`
+	LastTransactionTo   *time.Time `json:"last_transaction_to,omitempty"`
+}
+
+// SortKey orders a customer list by Field, descending when Desc.
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// Page is the standard list envelope. NextCursor and PrevCursor are omitted at either end.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// CustomerPage is one page of customers and how many match in total.
+type CustomerPage struct {
+	Customers  []*Customer `json:"items"`
+	TotalCount int         `json:"total_count"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+}
+
+// Customer360 is a customer with its related records in one document. Sections that were not
+// requested are omitted; sections that failed to load are reported in Errors instead.
+type Customer360 struct {
+	Customer      *Customer           `json:"customer"`
+	Addresses     []*Address          `json:"addresses,omitempty"`
+	Identities    []*Identity         `json:"identities,omitempty"`
+	Relationships []*RelationshipView `json:"relationships,omitempty"`
+	Consents      []*Consent          `json:"consents,omitempty"`
+	Audit         []*AuditEntry       `json:"audit,omitempty"`
+	Errors        map[string]string   `json:"errors,omitempty"`
+}
+
+// RelationshipView is a relationship with a summary of the customer on the other end.
+type RelationshipView struct {
+	*Relationship
+	Related *CustomerSummary `json:"related,omitempty"`
+}
+
+// CustomerSummary is the minimal view of a related customer. Names of protected customers are
+// withheld.
+type CustomerSummary struct {
+	ID             uuid.UUID      `json:"id"`
+	Type           CustomerType   `json:"type"`
+	DisplayName    string         `json:"display_name,omitempty"`
+	Status         CustomerStatus `json:"status"`
+	MembershipTier string         `json:"membership_tier,omitempty"`
+	Protected      bool           `json:"protected,omitempty"`
+}
+
+// AuditEntry is an audit log row in a Customer360.
+type AuditEntry struct {
+	ID          uuid.UUID `json:"id"`
+	Action      string    `json:"action"`
+	PerformedBy string    `json:"performed_by"`
+	Changes     string    `json:"changes,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TimelineEntry is one event in a customer's activity feed.
+type TimelineEntry struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Action     string    `json:"action"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor,omitempty"`
+	Summary    string    `json:"summary"`
+	Link       string    `json:"link,omitempty"`
+}
+
+type TimelinePage struct {
+	Entries    []*TimelineEntry `json:"entries"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type ChangeOperation string
+
+const (
+	OpDeleteCustomer    ChangeOperation = "DELETE_CUSTOMER"
+	OpRestoreCustomer   ChangeOperation = "RESTORE_CUSTOMER"
+	OpAnonymizeCustomer ChangeOperation = "ANONYMIZE_CUSTOMER"
+	OpBlacklistCustomer ChangeOperation = "BLACKLIST_CUSTOMER"
+	OpMergeCustomer     ChangeOperation = "MERGE_CUSTOMER"
+)
+
+type ChangeRequestStatus string
+
+const (
+	ChangePending  ChangeRequestStatus = "PENDING"
+	ChangeApproved ChangeRequestStatus = "APPROVED"
+	ChangeApplied  ChangeRequestStatus = "APPLIED"
+	ChangeRejected ChangeRequestStatus = "REJECTED"
+	ChangeExpired  ChangeRequestStatus = "EXPIRED"
+	ChangeFailed   ChangeRequestStatus = "FAILED"
+)
+
+// ChangeRequest is a sensitive customer operation held for four-eyes approval.
+type ChangeRequest struct {
+	ID         uuid.UUID           `json:"id"`
+	Operation  ChangeOperation     `json:"operation"`
+	CustomerID uuid.UUID           `json:"customer_id"`
+	Payload    json.RawMessage     `json:"payload,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+	Status     ChangeRequestStatus `json:"status"`
+	MakerID    uuid.UUID           `json:"maker_id"`
+	CheckerID  *uuid.UUID          `json:"checker_id,omitempty"`
+	Comment    string              `json:"comment,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+	DecidedAt  *time.Time          `json:"decided_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreOverride is the body of a restore of a record whose deleter was never recorded.
+type RestoreOverride struct {
+	Justification string `json:"justification"`
+}
+
+// CustomerMerge is the body of a merge: the customer is retired into IntoID.
+type CustomerMerge struct {
+	IntoID uuid.UUID `json:"into_id"`
+}
+
+type AccessGrantStatus string
+
+const (
+	GrantPending  AccessGrantStatus = "PENDING"
+	GrantApproved AccessGrantStatus = "APPROVED"
+	GrantRejected AccessGrantStatus = "REJECTED"
+)
+
+// AccessGrant is a time-limited permission for one user to read a protected customer.
+type AccessGrant struct {
+	ID          uuid.UUID         `json:"id"`
+	CustomerID  uuid.UUID         `json:"customer_id"`
+	RequestedBy uuid.UUID         `json:"requested_by"`
+	Reason      string            `json:"reason"`
+	Status      AccessGrantStatus `json:"status"`
+	ApprovedBy  *uuid.UUID        `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time        `json:"approved_at,omitempty"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+	UseCount    int               `json:"use_count"`
+	LastUsedAt  *time.Time        `json:"last_used_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// --- Contact ---
+
+type ContactType string
+
+const (
+	ContactMobile   ContactType = "MOBILE"
+	ContactLandline ContactType = "LANDLINE"
+	ContactEmail    ContactType = "EMAIL"
+	ContactLine     ContactType = "LINE"
+)
+
+type VerificationStatus string
+
+const (
+	VerificationUnverified VerificationStatus = "UNVERIFIED"
+	VerificationPending    VerificationStatus = "PENDING"
+	VerificationVerified   VerificationStatus = "VERIFIED"
+)
+
+// ContactPoint is a phone number, email address or LINE ID. Phone values are in E.164.
+type ContactPoint struct {
+	ID         uuid.UUID          `json:"id"`
+	CustomerID uuid.UUID          `json:"customer_id"`
+	Type       ContactType        `json:"type"`
+	Value      string             `json:"value"`
+	IsPrimary  bool               `json:"is_primary"`
+	Status     VerificationStatus `json:"verification_status"`
+	VerifiedAt *time.Time         `json:"verified_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BankAccountType string
+
+const (
+	BankAccountSavings      BankAccountType = "SAVINGS"
+	BankAccountCurrent      BankAccountType = "CURRENT"
+	BankAccountFixedDeposit BankAccountType = "FIXED_DEPOSIT"
+)
+
+type BankAccountPurpose string
+
+const (
+	BankAccountPayout      BankAccountPurpose = "PAYOUT"
+	BankAccountDirectDebit BankAccountPurpose = "DIRECT_DEBIT"
+)
+
+// BankAccount is a customer's account at a Thai bank. AccountNumber is masked except when
+// revealed.
+type BankAccount struct {
+	ID            uuid.UUID          `json:"id"`
+	CustomerID    uuid.UUID          `json:"customer_id"`
+	BankCode      string             `json:"bank_code"`
+	BankName      string             `json:"bank_name"`
+	Branch        string             `json:"branch"`
+	AccountNumber string             `json:"account_number"`
+	AccountName   string             `json:"account_name"`
+	AccountType   BankAccountType    `json:"account_type"`
+	Purpose       BankAccountPurpose `json:"purpose"`
+	IsPrimary     bool               `json:"is_primary"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommPurpose is why the bank is contacting the customer.
+type CommPurpose string
+
+const (
+	PurposeStatement CommPurpose = "STATEMENT"
+	PurposePromotion CommPurpose = "PROMOTION"
+	PurposeService   CommPurpose = "SERVICE"
+	PurposeSecurity  CommPurpose = "SECURITY"
+)
+
+// CommChannel is how the customer is contacted.
+type CommChannel string
+
+const (
+	CommEmail CommChannel = "EMAIL"
+	CommSMS   CommChannel = "SMS"
+	CommPhone CommChannel = "PHONE"
+	CommLine  CommChannel = "LINE"
+	CommPost  CommChannel = "POST"
+)
+
+// CommunicationPreferences is a customer's purpose × channel matrix plus preferred language.
+type CommunicationPreferences struct {
+	CustomerID uuid.UUID           `json:"customer_id"`
+	Language   string              `json:"language"` // ISO 639-1, e.g. "th", "en"
+	Channels   []ChannelPreference `json:"channels"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// ChannelPreference is one cell of the matrix. Lower Priority is preferred. Days are MON..SUN,
+// every day when empty; QuietStart and QuietEnd are HH:MM local times.
+type ChannelPreference struct {
+	Purpose    CommPurpose `json:"purpose"`
+	Channel    CommChannel `json:"channel"`
+	Allowed    bool        `json:"allowed"`
+	Priority   int         `json:"priority"`
+	Days       []string    `json:"days,omitempty"`
+	QuietStart string      `json:"quiet_start,omitempty"`
+	QuietEnd   string      `json:"quiet_end,omitempty"`
+}
+
+// ChannelDecision is how to contact the customer for Purpose at At. Channel is empty when no
+// channel is usable; Skipped says why each candidate was passed over.
+type ChannelDecision struct {
+	Purpose  CommPurpose      `json:"purpose"`
+	Channel  CommChannel      `json:"channel,omitempty"`
+	Contact  string           `json:"contact,omitempty"`
+	Language string           `json:"language,omitempty"`
+	At       time.Time        `json:"at"`
+	Skipped  []SkippedChannel `json:"skipped,omitempty"`
+}
+
+type SkippedChannel struct {
+	Channel CommChannel `json:"channel"`
+	Reason  string      `json:"reason"`
+}
+
+// --- Holdings, points and tiers ---
+
+type ProductCategory string
+
+const (
+	ProductInsurance  ProductCategory = "INSURANCE"
+	ProductDeposit    ProductCategory = "DEPOSIT"
+	ProductInvestment ProductCategory = "INVESTMENT"
+	ProductLoan       ProductCategory = "LOAN"
+)
+
+// Product is an entry in the product catalog that holdings refer to by Code.
+type Product struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Category  ProductCategory `json:"category"`
+	IsActive  bool            `json:"is_active"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type HoldingRole string
+
+const (
+	HoldingOwner       HoldingRole = "OWNER"
+	HoldingInsured     HoldingRole = "INSURED"
+	HoldingPayer       HoldingRole = "PAYER"
+	HoldingBeneficiary HoldingRole = "BENEFICIARY"
+)
+
+type HoldingStatus string
+
+const (
+	HoldingPending     HoldingStatus = "PENDING"
+	HoldingActive      HoldingStatus = "ACTIVE"
+	HoldingLapsed      HoldingStatus = "LAPSED"
+	HoldingMatured     HoldingStatus = "MATURED"
+	HoldingSurrendered HoldingStatus = "SURRENDERED"
+	HoldingClosed      HoldingStatus = "CLOSED"
+)
+
+// Holding links a customer to a policy or account. SumInsured and Balance are in Currency.
+type Holding struct {
+	ID          uuid.UUID        `json:"id"`
+	CustomerID  uuid.UUID        `json:"customer_id"`
+	ProductCode string           `json:"product_code"`
+	ProductName string           `json:"product_name,omitempty"`
+	ExternalRef string           `json:"external_ref"`
+	Role        HoldingRole      `json:"role"`
+	Status      HoldingStatus    `json:"status"`
+	Currency    string           `json:"currency"`
+	SumInsured  *decimal.Decimal `json:"sum_insured,omitempty"`
+	Balance     *decimal.Decimal `json:"balance,omitempty"`
+	StartDate   time.Time        `json:"start_date"`
+	EndDate     *time.Time       `json:"end_date,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FXRate is the conversion rate from Currency to the base currency: 1 Currency = RateToBase THB.
+type FXRate struct {
+	Currency   string          `json:"currency"`
+	RateToBase decimal.Decimal `json:"rate_to_base"`
+	AsOf       time.Time       `json:"as_of"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+type PointsTxType string
+
+const (
+	PointsEarn    PointsTxType = "EARN"
+	PointsBurn    PointsTxType = "BURN"
+	PointsAdjust  PointsTxType = "ADJUST"
+	PointsExpire  PointsTxType = "EXPIRE"
+	PointsReverse PointsTxType = "REVERSE"
+)
+
+// PointsTransaction is a ledger entry. Points is signed: credits are positive, debits negative.
+type PointsTransaction struct {
+	ID             uuid.UUID          `json:"id"`
+	CustomerID     uuid.UUID          `json:"customer_id"`
+	Type           PointsTxType       `json:"type"`
+	Points         int64              `json:"points"`
+	Remaining      int64              `json:"remaining"`
+	ExpiresAt      *time.Time         `json:"expires_at,omitempty"`
+	IdempotencyKey string             `json:"idempotency_key,omitempty"`
+	Reference      string             `json:"reference,omitempty"`
+	Reason         string             `json:"reason,omitempty"`
+	ReversesID     *uuid.UUID         `json:"reverses_id,omitempty"`
+	CreatedBy      *uuid.UUID         `json:"created_by,omitempty"`
+	Allocations    []PointsAllocation `json:"allocations,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointsAllocation is how much of a transaction was taken from (positive) or given back to
+// (negative) a lot.
+type PointsAllocation struct {
+	LotID  uuid.UUID `json:"lot_id"`
+	Points int64     `json:"points"`
+}
+
+// PointsRequest is a ledger posting. Points is a magnitude for EARN and BURN, and signed for
+// ADJUST; REVERSE takes its amount from the reversed transaction.
+type PointsRequest struct {
+	Type           PointsTxType `json:"type"`
+	Points         int64        `json:"points"`
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"`
+	IdempotencyKey string       `json:"idempotency_key,omitempty"`
+	Reference      string       `json:"reference,omitempty"`
+	Reason         string       `json:"reason,omitempty"`
+	ReversesID     *uuid.UUID   `json:"reverses_id,omitempty"`
+}
+
+// TierChange records a tier or high-value flag moving, and the rule version that moved it.
+type TierChange struct {
+	ID           uuid.UUID `json:"id"`
+	CustomerID   uuid.UUID `json:"customer_id"`
+	OldTier      string    `json:"old_tier"`
+	NewTier      string    `json:"new_tier"`
+	OldHighValue bool      `json:"old_high_value"`
+	NewHighValue bool      `json:"new_high_value"`
+	RuleVersion  string    `json:"rule_version"`
+	Rule         string    `json:"rule"`
+	Source       string    `json:"source"` // CREATE, UPDATE, RECALC, POINTS, PORTFOLIO or IMPORT
+	ChangedAt    time.Time `json:"changed_at"`
+}
+
+// TierRecalcResult summarises a full recalculation run.
+type TierRecalcResult struct {
+	RuleVersion string    `json:"rule_version"`
+	Scanned     int       `json:"scanned"`
+	Changed     int       `json:"changed"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// TierRules is the versioned tier rule set in force.
+type TierRules struct {
+	Version    string                     `json:"version"`
+	Thresholds map[string]decimal.Decimal `json:"thresholds"`
+	Tiers      []TierRule                 `json:"tiers"`
+	HighValue  string                     `json:"high_value"`
+}
+
+// TierRule assigns Name to customers matching the When expression.
+type TierRule struct {
+	Name string `json:"name"`
+	When string `json:"when"`
+}
+
+// --- Interactions ---
+
+type InteractionChannel string
+
+const (
+	ChannelPhone  InteractionChannel = "PHONE"
+	ChannelEmail  InteractionChannel = "EMAIL"
+	ChannelBranch InteractionChannel = "BRANCH"
+	ChannelChat   InteractionChannel = "CHAT"
+	ChannelSMS    InteractionChannel = "SMS"
+	ChannelLetter InteractionChannel = "LETTER"
+	ChannelNote   InteractionChannel = "NOTE" // Internal note, no customer contact
+)
+
+type InteractionDirection string
+
+const (
+	DirectionInbound  InteractionDirection = "INBOUND"
+	DirectionOutbound InteractionDirection = "OUTBOUND"
+	DirectionInternal InteractionDirection = "INTERNAL"
+)
+
+// Interaction records a contact with the customer or an internal note.
+type Interaction struct {
+	ID          uuid.UUID            `json:"id"`
+	CustomerID  uuid.UUID            `json:"customer_id"`
+	Channel     InteractionChannel   `json:"channel"`
+	Direction   InteractionDirection `json:"direction"`
+	Subject     string               `json:"subject"`
+	Body        string               `json:"body,omitempty"`
+	StaffUserID uuid.UUID            `json:"staff_user_id"`
+	Pinned      bool                 `json:"pinned"`
+	Attachments []AttachmentRef      `json:"attachments,omitempty"`
+	OccurredAt  time.Time            `json:"occurred_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AttachmentRef points at a file held in external document storage.
+type AttachmentRef struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// InteractionFilter narrows a customer's interaction list. From is inclusive, To exclusive,
+// both on OccurredAt.
+type InteractionFilter struct {
+	Channel    InteractionChannel
+	PinnedOnly bool
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// --- Segments and imports ---
+
+// Segment is a saved customer filter. Schedule is DAILY, or empty for on demand.
+type Segment struct {
+	ID              uuid.UUID      `json:"id"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description,omitempty"`
+	Filter          CustomerFilter `json:"filter"`
+	SharedWithRoles []string       `json:"shared_with_roles,omitempty"`
+	Schedule        string         `json:"schedule,omitempty"`
+	OwnerID         uuid.UUID      `json:"owner_id"`
+	LastRun         *SegmentRun    `json:"last_run,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// SegmentRun is one materialization of a segment.
+type SegmentRun struct {
+	ID          uuid.UUID `json:"id"`
+	SegmentID   uuid.UUID `json:"segment_id"`
+	RunAt       time.Time `json:"run_at"`
+	Size        int       `json:"size"`
+	Entered     int       `json:"entered"`
+	Left        int       `json:"left"`
+	TriggeredBy string    `json:"triggered_by"`
+}
+
+// SegmentChange is a customer entering or leaving a segment between two runs.
+type SegmentChange struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	Change     string    `json:"change"` // ENTERED or LEFT
+}
+
+// SegmentDiff compares membership at two runs. From is nil before the first run.
+type SegmentDiff struct {
+	From    *SegmentRun     `json:"from,omitempty"`
+	To      *SegmentRun     `json:"to"`
+	Changes []SegmentChange `json:"changes"`
+}
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "RUNNING"
+	ImportCompleted ImportStatus = "COMPLETED"
+	ImportFailed    ImportStatus = "FAILED"
+)
+
+// ImportRun is one pass of an importer. Cursor is the last source key handled.
+type ImportRun struct {
+	ID         uuid.UUID    `json:"id"`
+	Source     string       `json:"source"`
+	DryRun     bool         `json:"dry_run"`
+	Status     ImportStatus `json:"status"`
+	Cursor     string       `json:"cursor"`
+	Processed  int          `json:"processed"`
+	Imported   int          `json:"imported"`
+	Skipped    int          `json:"skipped"`
+	Rejected   int          `json:"rejected"`
+	Error      string       `json:"error,omitempty"`
+	StartedBy  string       `json:"started_by"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
+
+// ImportReject is a source record an import run could not take, with why.
+type ImportReject struct {
+	RunID     uuid.UUID `json:"run_id"`
+	SourceKey string    `json:"source_key"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// --- Users and organization ---
+
+type User struct {
+	ID            uuid.UUID  `json:"id"`
+	Email         string     `json:"email"`
+	Username      string     `json:"username"`
+	Role          string     `json:"role"`
+	SupervisorID  *uuid.UUID `json:"supervisor_id,omitempty"`
+	TeamID        *uuid.UUID `json:"team_id,omitempty"`
+	BranchID      *uuid.UUID `json:"branch_id,omitempty"`
+	OAuthProvider *string    `json:"oauth_provider,omitempty"`
+	OAuthID       *string    `json:"oauth_id,omitempty"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type Branch struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Team struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	BranchID  *uuid.UUID `json:"branch_id,omitempty"`
+	LeadID    *uuid.UUID `json:"lead_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Delegation scopes
+const (
+	DelegationScopeAll         = "*"
+	DelegationScopeRestore     = "RESTORE"
+	DelegationScopeAccessGrant = "ACCESS_GRANT"
+)
+
+// Delegation temporarily hands a user's supervisor rights to another user.
+type Delegation struct {
+	ID          uuid.UUID  `json:"id"`
+	DelegatorID uuid.UUID  `json:"delegator_id"`
+	DelegateID  uuid.UUID  `json:"delegate_id"`
+	Scope       string     `json:"scope"`
+	Reason      string     `json:"reason,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package cicclient
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/amnuaym/cic/go/internal/core/domain"
+	"github.com/amnuaym/cic/go/internal/core/tiering"
+	"github.com/amnuaym/cic/go/internal/models"
+)
+
+// wireTypes pairs each client type with the server type it mirrors.
+var wireTypes = []struct{ client, server interface{} }{
+	{Customer{}, domain.Customer{}},
+	{Money{}, domain.Money{}},
+	{ExternalRef{}, domain.ExternalRef{}},
+	{Address{}, domain.Address{}},
+	{Identity{}, domain.Identity{}},
+	{Relationship{}, domain.Relationship{}},
+	{Consent{}, domain.Consent{}},
+	{CustomerFilter{}, domain.CustomerFilter{}},
+	{SortKey{}, domain.SortKey{}},
+	{CustomerPage{}, domain.CustomerPage{}},
+	{Customer360{}, domain.Customer360{}},
+	{RelationshipView{}, domain.RelationshipView{}},
+	{CustomerSummary{}, domain.CustomerSummary{}},
+	{AuditEntry{}, domain.AuditEntry{}},
+	{TimelineEntry{}, domain.TimelineEntry{}},
+	{TimelinePage{}, domain.TimelinePage{}},
+	{ChangeRequest{}, domain.ChangeRequest{}},
+	{RestoreOverride{}, domain.RestoreOverride{}},
+	{CustomerMerge{}, domain.CustomerMerge{}},
+	{AccessGrant{}, domain.AccessGrant{}},
+	{ContactPoint{}, domain.ContactPoint{}},
+	{BankAccount{}, domain.BankAccount{}},
+	{CommunicationPreferences{}, domain.CommunicationPreferences{}},
+	{ChannelPreference{}, domain.ChannelPreference{}},
+	{ChannelDecision{}, domain.ChannelDecision{}},
+	{SkippedChannel{}, domain.SkippedChannel{}},
+	{Product{}, domain.Product{}},
+	{Holding{}, domain.Holding{}},
+	{FXRate{}, domain.FXRate{}},
+	{PointsTransaction{}, domain.PointsTransaction{}},
+	{PointsAllocation{}, domain.PointsAllocation{}},
+	{PointsRequest{}, domain.PointsRequest{}},
+	{TierChange{}, domain.TierChange{}},
+	{TierRecalcResult{}, domain.TierRecalcResult{}},
+	{TierRules{}, tiering.Config{}},
+	{TierRule{}, tiering.TierRule{}},
+	{Interaction{}, domain.Interaction{}},
+	{AttachmentRef{}, domain.AttachmentRef{}},
+	{InteractionFilter{}, domain.InteractionFilter{}},
+	{Segment{}, domain.Segment{}},
+	{SegmentRun{}, domain.SegmentRun{}},
+	{SegmentChange{}, domain.SegmentChange{}},
+	{SegmentDiff{}, domain.SegmentDiff{}},
+	{ImportRun{}, domain.ImportRun{}},
+	{ImportReject{}, domain.ImportReject{}},
+	{User{}, models.User{}},
+	{Branch{}, models.Branch{}},
+	{Team{}, models.Team{}},
+	{Delegation{}, models.Delegation{}},
+}
+
+// jsonFields lists the JSON names of a struct's fields, with those of embedded structs.
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			names = append(names, jsonFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWireTypesMatchServer(t *testing.T) {
+	for _, w := range wireTypes {
+		client, server := reflect.TypeOf(w.client), reflect.TypeOf(w.server)
+		if got, want := jsonFields(client), jsonFields(server); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s fields drifted from %s:\n client %v\n server %v", client.Name(), server, got, want)
+		}
+	}
+}